@@ -12,6 +12,8 @@ import (
 	"os/signal"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -21,8 +23,10 @@ import (
 	"github.com/umputun/ralphex/pkg/config"
 	"github.com/umputun/ralphex/pkg/git"
 	"github.com/umputun/ralphex/pkg/input"
+	"github.com/umputun/ralphex/pkg/notify"
 	"github.com/umputun/ralphex/pkg/processor"
 	"github.com/umputun/ralphex/pkg/progress"
+	"github.com/umputun/ralphex/pkg/trace"
 	"github.com/umputun/ralphex/pkg/web"
 )
 
@@ -39,6 +43,7 @@ type opts struct {
 	Port            int      `short:"p" long:"port" default:"8080" description:"web dashboard port"`
 	Watch           []string `short:"w" long:"watch" description:"directories to watch for progress files (repeatable)"`
 	Reset           bool     `long:"reset" description:"interactively reset global config to embedded defaults"`
+	Scope           []string `long:"scope" description:"limit tasks and reviews to repository subdirectories (repeatable)"`
 
 	PlanFile string `positional-arg-name:"plan-file" description:"path to plan file (optional, uses fzf if omitted)"`
 }
@@ -58,6 +63,17 @@ type startupInfo struct {
 	Mode          processor.Mode
 	MaxIterations int
 	ProgressPath  string
+	Repositories  []string // additional repositories of a multi-repo plan
+	Scope         []string // repository subdirectories the run is limited to
+}
+
+// runnerTarget holds the plan and the repository paths a runner works on.
+type runnerTarget struct {
+	PlanFile     string
+	Branch       string
+	Mode         processor.Mode
+	Repositories []string // additional repositories of a multi-repo plan
+	Scope        []string // repository subdirectories the run is limited to, relative to the root
 }
 
 // planSelector holds parameters for plan file selection.
@@ -75,6 +91,7 @@ type executePlanRequest struct {
 	GitOps   *git.Repo
 	Config   *config.Config
 	Colors   *progress.Colors
+	StartDir string // directory ralphex was started in, before switching to the repository root
 }
 
 // webDashboardParams holds parameters for web dashboard setup.
@@ -141,6 +158,10 @@ func run(ctx context.Context, o opts) error {
 		}
 	}
 
+	// discover the repository from any directory inside it and switch to its root, so plan, progress
+	// and .ralphex/ paths resolve the same wherever ralphex was started
+	gitOps, startDir, repoErr := enterRepoRoot(&o)
+
 	// load config first to get custom command paths
 	cfg, err := config.Load("") // empty string uses default location
 	if err != nil {
@@ -161,15 +182,8 @@ func run(ctx context.Context, o opts) error {
 		return depErr
 	}
 
-	// require running from repo root
-	if _, statErr := os.Stat(".git"); statErr != nil {
-		return errors.New("must run from repository root (no .git directory found)")
-	}
-
-	// open git repository
-	gitOps, err := git.Open(".")
-	if err != nil {
-		return fmt.Errorf("open git repo: %w", err)
+	if repoErr != nil {
+		return fmt.Errorf("must run inside a git repository: %w", repoErr)
 	}
 
 	// ensure repository has commits (prompts to create initial commit if empty)
@@ -178,15 +192,11 @@ func run(ctx context.Context, o opts) error {
 	}
 
 	mode := determineMode(o)
+	req := executePlanRequest{Mode: mode, GitOps: gitOps, Config: cfg, Colors: colors, StartDir: startDir}
 
 	// plan mode has different flow - doesn't require plan file selection
 	if mode == processor.ModePlan {
-		return runPlanMode(ctx, o, executePlanRequest{
-			Mode:   processor.ModePlan,
-			GitOps: gitOps,
-			Config: cfg,
-			Colors: colors,
-		})
+		return runPlanMode(ctx, o, req)
 	}
 
 	// select and prepare plan file (not needed for plan mode)
@@ -198,7 +208,7 @@ func run(ctx context.Context, o opts) error {
 	})
 	if err != nil {
 		// check for auto-plan-mode: no plans found on main/master branch
-		handled, autoPlanErr := tryAutoPlanMode(ctx, err, o, gitOps, cfg, colors)
+		handled, autoPlanErr := tryAutoPlanMode(ctx, err, o, req)
 		if handled {
 			return autoPlanErr
 		}
@@ -209,13 +219,47 @@ func run(ctx context.Context, o opts) error {
 		return setupErr
 	}
 
-	return executePlan(ctx, o, executePlanRequest{
-		PlanFile: planFile,
-		Mode:     mode,
-		GitOps:   gitOps,
-		Config:   cfg,
-		Colors:   colors,
-	})
+	req.PlanFile = planFile
+	return executePlan(ctx, o, req)
+}
+
+// enterRepoRoot opens the git repository containing the current directory and changes into its root.
+// relative watch directories and a relative plan file that exists from the start directory are resolved
+// against it first, other relative plan paths are taken as relative to the root. returns the repository
+// and the start directory. outside a repository the directory is left unchanged and the error returned.
+func enterRepoRoot(o *opts) (*git.Repo, string, error) {
+	startDir, err := os.Getwd()
+	if err != nil {
+		return nil, "", fmt.Errorf("get working directory: %w", err)
+	}
+	gitOps, err := git.Open(startDir)
+	if err != nil {
+		return nil, startDir, err
+	}
+	root := gitOps.Root()
+
+	if o.PlanFile != "" && !filepath.IsAbs(o.PlanFile) {
+		if _, statErr := os.Stat(o.PlanFile); statErr == nil {
+			abs := filepath.Join(startDir, o.PlanFile)
+			o.PlanFile = abs
+			if rel, relErr := filepath.Rel(root, abs); relErr == nil && !strings.HasPrefix(rel, "..") {
+				o.PlanFile = rel
+			}
+		}
+	}
+	watch := make([]string, 0, len(o.Watch))
+	for _, w := range o.Watch {
+		if !filepath.IsAbs(w) {
+			w = filepath.Join(startDir, w)
+		}
+		watch = append(watch, w)
+	}
+	o.Watch = watch
+
+	if err := os.Chdir(root); err != nil {
+		return nil, startDir, fmt.Errorf("change to repository root: %w", err)
+	}
+	return gitOps, startDir, nil
 }
 
 // getCurrentBranch returns the current git branch name or "unknown" if unavailable.
@@ -250,28 +294,24 @@ func promptPlanDescription(r io.Reader, colors *progress.Colors) string {
 
 // tryAutoPlanMode attempts to switch to plan mode when no plans are found on main/master.
 // returns (true, nil) if user canceled, (true, err) if plan mode was attempted, or (false, nil) if auto-plan-mode doesn't apply.
-func tryAutoPlanMode(ctx context.Context, err error, o opts, gitOps *git.Repo, cfg *config.Config, colors *progress.Colors) (bool, error) {
+func tryAutoPlanMode(ctx context.Context, err error, o opts, req executePlanRequest) (bool, error) {
 	if !errors.Is(err, errNoPlansFound) || o.Review || o.CodexOnly {
 		return false, nil
 	}
 
-	branch, branchErr := gitOps.CurrentBranch()
+	branch, branchErr := req.GitOps.CurrentBranch()
 	if branchErr != nil || !isMainBranch(branch) {
 		return false, nil //nolint:nilerr // branchErr is intentionally ignored - if we can't get branch, skip auto-plan-mode
 	}
 
-	description := promptPlanDescription(os.Stdin, colors)
+	description := promptPlanDescription(os.Stdin, req.Colors)
 	if description == "" {
 		return true, nil // user canceled
 	}
 
 	o.PlanDescription = description
-	return true, runPlanMode(ctx, o, executePlanRequest{
-		Mode:   processor.ModePlan,
-		GitOps: gitOps,
-		Config: cfg,
-		Colors: colors,
-	})
+	req.Mode = processor.ModePlan
+	return true, runPlanMode(ctx, o, req)
 }
 
 // setupRunnerLogger creates the appropriate logger for the runner.
@@ -293,11 +333,115 @@ func handlePostExecution(gitOps *git.Repo, planFile string, mode processor.Mode,
 	}
 }
 
+// rewriteBranchHistory reshapes the feature branch commits according to history_rewrite, in every
+// repository of the plan. the base is the branch each feature branch was created from.
+// failures are reported as warnings, the original history of that repository stays intact in that case.
+func rewriteBranchHistory(repos *git.Group, cfg *config.Config, planFile string, colors *progress.Colors) {
+	mode := git.HistoryMode(cfg.HistoryRewrite)
+	if mode == "" || mode == git.HistoryKeep {
+		return
+	}
+
+	msgs := historyMessages(planFile)
+	for i, repo := range append([]*git.Repo{repos.Primary()}, repos.Extra()...) {
+		where := ""
+		if i > 0 {
+			where = " in " + repo.Root()
+		}
+		res, err := rewriteRepoHistory(repo, mode, msgs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to rewrite branch history%s: %v\n", where, err)
+			continue
+		}
+		if res.BackupRef != "" {
+			colors.Info().Printf("rewrote %d commits into %d%s, original history kept at %s\n",
+				res.Original, res.Rewritten, where, res.BackupRef)
+		}
+	}
+}
+
+// rewriteRepoHistory rewrites the current branch of repo since it diverged from its recorded base branch.
+func rewriteRepoHistory(repo *git.Repo, mode git.HistoryMode, msgs git.HistoryMessages) (git.RewriteResult, error) {
+	branch, err := repo.CurrentBranch()
+	if err != nil {
+		return git.RewriteResult{}, fmt.Errorf("get current branch: %w", err)
+	}
+	if branch == "" {
+		return git.RewriteResult{}, errors.New("detached HEAD, refusing to rewrite history")
+	}
+	base, err := repo.BranchBase(branch)
+	if err != nil {
+		return git.RewriteResult{}, err
+	}
+	return repo.RewriteHistory(base, mode, msgs)
+}
+
+// historyMessages builds commit messages for rewritten history from the plan title and task headers.
+// the plan is looked up in completed/ first since it is moved there before the rewrite.
+func historyMessages(planFile string) git.HistoryMessages {
+	plan := &web.Plan{}
+	completedPath := filepath.Join(filepath.Dir(planFile), "completed", filepath.Base(planFile))
+	for _, p := range []string{completedPath, planFile} {
+		if parsed, err := web.ParsePlanFile(p); err == nil {
+			plan = parsed
+			break
+		}
+	}
+
+	title := plan.Title
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(planFile), filepath.Ext(planFile))
+	}
+	squash := "feat: " + title
+	taskTitles := make(map[string]string, len(plan.Tasks))
+	var tasks []string
+	for _, t := range plan.Tasks {
+		taskTitles[strconv.Itoa(t.Number)] = t.Title
+		tasks = append(tasks, fmt.Sprintf("- Task %d: %s", t.Number, t.Title))
+	}
+	if len(tasks) > 0 {
+		squash += "\n\n" + strings.Join(tasks, "\n")
+	}
+
+	return git.HistoryMessages{
+		Squash: squash,
+		Task: func(num string) string {
+			if t := taskTitles[num]; t != "" {
+				return "feat: task " + num + " - " + t
+			}
+			return "feat: task " + num
+		},
+		Phase: func(phase string) string { return "fix: address " + phase + " findings" },
+	}
+}
+
 // executePlan runs the main execution loop for a plan file.
 // handles progress logging, web dashboard, runner execution, and post-execution tasks.
 func executePlan(ctx context.Context, o opts, req executePlanRequest) error {
 	branch := getCurrentBranch(req.GitOps)
 
+	// additional repositories of a multi-repo plan follow the primary repository's feature branch
+	repos, err := openPlanRepos(req.GitOps, req.PlanFile)
+	if err != nil {
+		return err
+	}
+	if err = checkRepoRollback(req.Config, req.Mode, repos); err != nil {
+		return err
+	}
+	if req.Mode == processor.ModeFull {
+		if syncErr := syncRepoBranches(repos, branch, req.Colors); syncErr != nil {
+			return syncErr
+		}
+	}
+	scope, err := resolveScope(req.GitOps, req.StartDir, o.Scope, req.PlanFile)
+	if err != nil {
+		return err
+	}
+	if sub := startSubdir(req.GitOps, req.StartDir); scope == nil && sub != "" {
+		req.Colors.Info().Printf("started in %s, the run covers the whole repository; "+
+			"use --scope . or a plan Scope: line to limit it\n", sub)
+	}
+
 	// create progress logger
 	baseLog, err := progress.NewLogger(progress.Config{
 		PlanFile: req.PlanFile,
@@ -332,6 +476,15 @@ func executePlan(ctx context.Context, o opts, req executePlanRequest) error {
 		return err
 	}
 
+	// wrap logger with milestone notifications if any sink is configured
+	masker := secretMasker(req.Config)
+	runnerLog, finishNotify, err := wrapNotifyLogger(req.Config, masker, runnerLog, req.PlanFile, branch)
+	if err != nil {
+		return err
+	}
+	runnerLog, wrapExec, finishTrace := wrapTraceLogger(req.Config, masker, runnerLog, req.PlanFile, branch, req.Mode)
+	runnerLog = wrapMaskingLogger(masker, runnerLog)
+
 	// print startup info
 	printStartupInfo(startupInfo{
 		PlanFile:      req.PlanFile,
@@ -339,16 +492,30 @@ func executePlan(ctx context.Context, o opts, req executePlanRequest) error {
 		Mode:          req.Mode,
 		MaxIterations: o.MaxIterations,
 		ProgressPath:  baseLog.Path(),
+		Repositories:  repos.Roots(),
+		Scope:         scope,
 	}, req.Colors)
 
 	// create and run the runner
-	r := createRunner(req.Config, o, req.PlanFile, req.Mode, runnerLog)
-	if runErr := r.Run(ctx); runErr != nil {
+	r := createRunner(req.Config, o, runnerTarget{PlanFile: req.PlanFile, Branch: branch, Mode: req.Mode,
+		Repositories: repos.Roots(), Scope: scope}, runnerLog)
+	r.SetGitCommitter(repos)
+	r.SetGitRollbacker(req.GitOps)
+	r.SetGitHeadReader(req.GitOps)
+	r.WrapExecutors(wrapExec)
+	outcome, runErr := r.Run(ctx)
+	baseLog.SetOutcome(masker.MaskOutcome(outcome)) // the footer bypasses the masking logger
+	finishNotify(runErr)
+	finishTrace(outcome, runErr)
+	if runErr != nil {
 		return fmt.Errorf("runner: %w", runErr)
 	}
 
 	// handle post-execution tasks
 	handlePostExecution(req.GitOps, req.PlanFile, req.Mode, req.Colors)
+	if req.PlanFile != "" && req.Mode == processor.ModeFull {
+		rewriteBranchHistory(repos, req.Config, req.PlanFile, req.Colors)
+	}
 
 	elapsed := baseLog.Elapsed()
 	req.Colors.Info().Printf("\ncompleted in %s\n", elapsed)
@@ -428,16 +595,20 @@ func validateFlags(o opts) error {
 }
 
 // createRunner creates a processor.Runner with the given configuration.
-func createRunner(cfg *config.Config, o opts, planFile string, mode processor.Mode, log processor.Logger) *processor.Runner {
+func createRunner(cfg *config.Config, o opts, target runnerTarget, log processor.Logger) *processor.Runner {
 	// --codex-only mode forces codex enabled regardless of config
 	codexEnabled := cfg.CodexEnabled
-	if mode == processor.ModeCodexOnly {
+	if target.Mode == processor.ModeCodexOnly {
 		codexEnabled = true
 	}
 	return processor.New(processor.Config{
-		PlanFile:         planFile,
+		PlanFile:         target.PlanFile,
 		ProgressPath:     log.Path(),
-		Mode:             mode,
+		Branch:           target.Branch,
+		Repositories:     target.Repositories,
+		Scope:            target.Scope,
+		OwnFiles:         []string{".gitignore", log.Path(), progress.EventsPath(log.Path())}, // run starts at the root
+		Mode:             target.Mode,
 		MaxIterations:    o.MaxIterations,
 		Debug:            o.Debug,
 		NoColor:          o.NoColor,
@@ -599,6 +770,151 @@ func createBranchIfNeeded(gitOps *git.Repo, planFile string, colors *progress.Co
 	return nil
 }
 
+// openPlanRepos groups the primary repository with the additional repositories listed
+// in the plan's "## Repositories" section. without a plan or the section the group holds
+// the primary repository only.
+func openPlanRepos(gitOps *git.Repo, planFile string) (*git.Group, error) {
+	var paths []string
+	if planFile != "" {
+		plan, err := web.ParsePlanFile(planFile)
+		if err != nil {
+			return nil, fmt.Errorf("read plan repositories: %w", err)
+		}
+		paths = plan.Repositories
+	}
+	repos, err := git.OpenGroup(gitOps, paths)
+	if err != nil {
+		return nil, fmt.Errorf("open plan repositories: %w", err)
+	}
+	return repos, nil
+}
+
+// checkRepoRollback rejects task rollback for multi-repo plans in full mode. rollback resets the primary
+// repository only, a failed attempt would be left half-applied in the additional repositories.
+func checkRepoRollback(cfg *config.Config, mode processor.Mode, repos *git.Group) error {
+	if mode != processor.ModeFull || cfg == nil || !cfg.TaskRollback || len(repos.Extra()) == 0 {
+		return nil
+	}
+	return errors.New("task_rollback is not supported for multi-repo plans, it resets the primary repository only; " +
+		"set task_rollback = false or run each repository's part as a separate plan")
+}
+
+// resolveScope returns the repository subdirectories the run is limited to, relative to the root
+// with forward slashes. --scope paths are relative to startDir, the directory ralphex was started in,
+// the plan's "Scope:" paths are relative to the repository root. without either the run is not scoped,
+// wherever ralphex was started. returns nil if the scope covers the repository root.
+func resolveScope(gitOps *git.Repo, startDir string, flagScope []string, planFile string) ([]string, error) {
+	root, err := filepath.EvalSymlinks(gitOps.Root())
+	if err != nil {
+		return nil, fmt.Errorf("resolve repository root: %w", err)
+	}
+	var planScope []string
+	if planFile != "" {
+		if plan, parseErr := web.ParsePlanFile(planFile); parseErr == nil {
+			planScope = plan.Scope
+		}
+	}
+	var paths []string
+	base := startDir
+	switch {
+	case len(flagScope) > 0:
+		for _, f := range flagScope {
+			for p := range strings.SplitSeq(f, ",") {
+				if p = strings.TrimSpace(p); p != "" {
+					paths = append(paths, p)
+				}
+			}
+		}
+	case len(planScope) > 0:
+		paths, base = planScope, root
+	default:
+		return nil, nil
+	}
+
+	var result []string
+	for _, p := range paths {
+		abs := p
+		if !filepath.IsAbs(abs) {
+			abs = filepath.Join(base, p)
+		}
+		resolved, err := filepath.EvalSymlinks(abs)
+		if err != nil {
+			return nil, fmt.Errorf("scope %s: %w", p, err)
+		}
+		if info, statErr := os.Stat(resolved); statErr != nil || !info.IsDir() {
+			return nil, fmt.Errorf("scope %s is not a directory", p)
+		}
+		rel, err := filepath.Rel(root, resolved)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("scope %s is outside the repository %s", p, root)
+		}
+		if rel == "." {
+			return nil, nil // the whole repository
+		}
+		if rel = filepath.ToSlash(rel); !slices.Contains(result, rel) {
+			result = append(result, rel)
+		}
+	}
+	return result, nil
+}
+
+// startSubdir returns the directory ralphex was started in relative to the repository root,
+// with forward slashes. returns empty string if it was started at the root or outside the repository.
+func startSubdir(gitOps *git.Repo, startDir string) string {
+	if startDir == "" {
+		return ""
+	}
+	root, err := filepath.EvalSymlinks(gitOps.Root())
+	if err != nil {
+		return ""
+	}
+	start, err := filepath.EvalSymlinks(startDir)
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(root, start)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+// syncRepoBranches switches each additional repository to branch, creating it from the current
+// HEAD if missing. repositories with uncommitted changes can't be switched and fail the run.
+func syncRepoBranches(repos *git.Group, branch string, colors *progress.Colors) error {
+	if len(repos.Extra()) > 0 && branch == "unknown" {
+		return errors.New("cannot switch plan repositories: primary repository is not on a branch")
+	}
+	for _, repo := range repos.Extra() {
+		current, err := repo.CurrentBranch()
+		if err != nil {
+			return fmt.Errorf("%s: get current branch: %w", repo.Root(), err)
+		}
+		if current == branch {
+			continue
+		}
+		dirty, err := repo.HasUncommittedChanges()
+		if err != nil {
+			return fmt.Errorf("%s: check uncommitted files: %w", repo.Root(), err)
+		}
+		if dirty {
+			return fmt.Errorf("cannot switch %s to branch %q: worktree has uncommitted changes", repo.Root(), branch)
+		}
+		if repo.BranchExists(branch) {
+			colors.Info().Printf("switching %s to existing branch: %s\n", repo.Root(), branch)
+			if err := repo.CheckoutBranch(branch); err != nil {
+				return fmt.Errorf("%s: checkout branch %s: %w", repo.Root(), branch, err)
+			}
+			continue
+		}
+		colors.Info().Printf("creating branch %s in %s\n", branch, repo.Root())
+		if err := repo.CreateBranch(branch); err != nil {
+			return fmt.Errorf("%s: create branch %s: %w", repo.Root(), branch, err)
+		}
+	}
+	return nil
+}
+
 func movePlanToCompleted(gitOps *git.Repo, planFile string, colors *progress.Colors) error {
 	// create completed directory
 	completedDir := filepath.Join(filepath.Dir(planFile), "completed")
@@ -632,9 +948,17 @@ func movePlanToCompleted(gitOps *git.Repo, planFile string, colors *progress.Col
 }
 
 func ensureGitignore(gitOps *git.Repo, colors *progress.Colors) error {
-	// check if already ignored
-	ignored, err := gitOps.IsIgnored("progress-test.txt")
-	if err == nil && ignored {
+	// progress logs and their machine-readable event files
+	var missing []string
+	for _, p := range []struct{ pattern, probe string }{
+		{"progress*.txt", "progress-test.txt"},
+		{"progress*.jsonl", "progress-test.jsonl"},
+	} {
+		if ignored, err := gitOps.IsIgnored(p.probe); err != nil || !ignored {
+			missing = append(missing, p.pattern)
+		}
+	}
+	if len(missing) == 0 {
 		return nil // already ignored
 	}
 
@@ -645,7 +969,7 @@ func ensureGitignore(gitOps *git.Repo, colors *progress.Colors) error {
 		return fmt.Errorf("open .gitignore: %w", err)
 	}
 
-	if _, err := f.WriteString("\n# ralphex progress logs\nprogress*.txt\n"); err != nil {
+	if _, err := f.WriteString("\n# ralphex progress logs\n" + strings.Join(missing, "\n") + "\n"); err != nil {
 		f.Close()
 		return fmt.Errorf("write .gitignore: %w", err)
 	}
@@ -654,7 +978,7 @@ func ensureGitignore(gitOps *git.Repo, colors *progress.Colors) error {
 		return fmt.Errorf("close .gitignore: %w", err)
 	}
 
-	colors.Info().Println("added progress*.txt to .gitignore")
+	colors.Info().Printf("added %s to .gitignore\n", strings.Join(missing, ", "))
 	return nil
 }
 
@@ -705,6 +1029,12 @@ func printStartupInfo(info startupInfo, colors *progress.Colors) {
 	}
 	colors.Info().Printf("starting ralphex loop: %s (max %d iterations)%s\n", planStr, info.MaxIterations, modeStr)
 	colors.Info().Printf("branch: %s\n", info.Branch)
+	if len(info.Repositories) > 0 {
+		colors.Info().Printf("repositories: %s\n", strings.Join(info.Repositories, ", "))
+	}
+	if len(info.Scope) > 0 {
+		colors.Info().Printf("scope: %s\n", strings.Join(info.Scope, ", "))
+	}
 	colors.Info().Printf("progress log: %s\n\n", info.ProgressPath)
 }
 
@@ -865,6 +1195,15 @@ func runPlanMode(ctx context.Context, o opts, req executePlanRequest) error {
 		}
 	}()
 
+	// wrap logger with milestone notifications (question waiting, completion) if configured
+	masker := secretMasker(req.Config)
+	planLog, finishNotify, err := wrapNotifyLogger(req.Config, masker, baseLog, "", branch)
+	if err != nil {
+		return err
+	}
+	planLog, wrapExec, finishTrace := wrapTraceLogger(req.Config, masker, planLog, "", branch, processor.ModePlan)
+	planLog = wrapMaskingLogger(masker, planLog)
+
 	// print startup info for plan mode
 	printPlanModeInfo(o.PlanDescription, branch, o.MaxIterations, baseLog.Path(), req.Colors)
 
@@ -878,17 +1217,23 @@ func runPlanMode(ctx context.Context, o opts, req executePlanRequest) error {
 	r := processor.New(processor.Config{
 		PlanDescription:  o.PlanDescription,
 		ProgressPath:     baseLog.Path(),
+		Branch:           branch,
 		Mode:             processor.ModePlan,
 		MaxIterations:    o.MaxIterations,
 		Debug:            o.Debug,
 		NoColor:          o.NoColor,
 		IterationDelayMs: req.Config.IterationDelayMs,
 		AppConfig:        req.Config,
-	}, baseLog)
+	}, planLog)
 	r.SetInputCollector(collector)
+	r.WrapExecutors(wrapExec)
 
 	// run the plan creation loop
-	if runErr := r.Run(ctx); runErr != nil {
+	outcome, runErr := r.Run(ctx)
+	baseLog.SetOutcome(masker.MaskOutcome(outcome)) // the footer bypasses the masking logger
+	finishNotify(runErr)
+	finishTrace(outcome, runErr)
+	if runErr != nil {
 		return fmt.Errorf("plan creation: %w", runErr)
 	}
 
@@ -934,6 +1279,7 @@ func runPlanMode(ctx context.Context, o opts, req executePlanRequest) error {
 		GitOps:   req.GitOps,
 		Config:   req.Config,
 		Colors:   req.Colors,
+		StartDir: req.StartDir,
 	})
 }
 
@@ -1076,6 +1422,64 @@ func startWebDashboard(ctx context.Context, p webDashboardParams) (processor.Log
 	return broadcastLog, nil
 }
 
+// secretMasker returns the masker for secrets known from cfg and the environment, nil if mask_secrets is off.
+// a nil masker leaves text unchanged.
+func secretMasker(cfg *config.Config) *processor.SecretMasker {
+	if cfg == nil || !cfg.MaskSecrets {
+		return nil
+	}
+	return processor.NewSecretMasker(cfg, os.Environ())
+}
+
+// wrapMaskingLogger wraps log so secrets are redacted before reaching any other logger, unless masker is nil.
+func wrapMaskingLogger(masker *processor.SecretMasker, log processor.Logger) processor.Logger {
+	if masker == nil {
+		return log
+	}
+	return processor.NewMaskingLogger(log, masker)
+}
+
+// wrapNotifyLogger wraps log with a notification logger if any notification sink is configured.
+// returns the logger to use and a finish func that reports the run outcome and flushes pending notifications.
+// finish must be called exactly once after the runner returns. masker redacts secrets in the run error, nil leaves it as is.
+func wrapNotifyLogger(cfg *config.Config, masker *processor.SecretMasker, log processor.Logger,
+	planFile, branch string) (processor.Logger, func(error), error) {
+	notifier, err := notify.New(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("setup notifications: %w", err)
+	}
+	if !notifier.Enabled() {
+		return log, func(error) {}, nil
+	}
+
+	notifyLog := notify.NewLogger(log, notifier, planFile, branch)
+	if masker != nil {
+		notifyLog.SetMasker(masker.Mask)
+	}
+	finish := func(runErr error) {
+		notifyLog.Finish(runErr)
+		notifier.Close()
+	}
+	return notifyLog, finish, nil
+}
+
+// wrapTraceLogger wraps log with a tracing logger if trace_file or trace_endpoint is configured.
+// returns the logger to use, the executor wrapper for Runner.WrapExecutors and a finish func that
+// ends the run span and exports the remaining spans. finish must be called exactly once after the runner returns.
+// masker redacts secrets in span error messages, nil leaves them as is.
+func wrapTraceLogger(cfg *config.Config, masker *processor.SecretMasker, log processor.Logger, planFile, branch string,
+	mode processor.Mode) (processor.Logger, func(string, processor.Executor) processor.Executor, func(processor.Outcome, error)) {
+	tracer := trace.New(cfg)
+	if !tracer.Enabled() {
+		return log, func(_ string, e processor.Executor) processor.Executor { return e }, func(processor.Outcome, error) {}
+	}
+	if masker != nil {
+		tracer.SetMasker(masker.Mask)
+	}
+	traceLog := trace.NewLogger(log, tracer, planFile, branch, mode)
+	return traceLog, traceLog.WrapExecutor, traceLog.Finish
+}
+
 // runReset runs the interactive config reset flow.
 func runReset() error {
 	configDir := config.DefaultConfigDir()
@@ -3,10 +3,15 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -16,8 +21,10 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/umputun/ralphex/pkg/config"
+	"github.com/umputun/ralphex/pkg/executor"
 	"github.com/umputun/ralphex/pkg/git"
 	"github.com/umputun/ralphex/pkg/processor"
+	"github.com/umputun/ralphex/pkg/processor/mocks"
 	"github.com/umputun/ralphex/pkg/progress"
 )
 
@@ -148,18 +155,30 @@ func TestPlanFlagConflict(t *testing.T) {
 	})
 
 	t.Run("no_error_when_only_plan_flag_set", func(t *testing.T) {
+		// run outside of any repository, the root is searched upward from the current directory
+		origDir, err := os.Getwd()
+		require.NoError(t, err)
+		require.NoError(t, os.Chdir(t.TempDir()))
+		t.Cleanup(func() { _ = os.Chdir(origDir) })
+
 		// this test will fail at a later point (missing git repo etc), but not at validation
 		o := opts{PlanDescription: "add caching"}
-		err := run(context.Background(), o)
+		err = run(context.Background(), o)
 		// should fail at git repo check, not at validation
 		require.Error(t, err)
 		assert.NotContains(t, err.Error(), "--plan flag conflicts")
 	})
 
 	t.Run("no_error_when_only_planfile_set", func(t *testing.T) {
+		// run outside of any repository, the root is searched upward from the current directory
+		origDir, err := os.Getwd()
+		require.NoError(t, err)
+		require.NoError(t, os.Chdir(t.TempDir()))
+		t.Cleanup(func() { _ = os.Chdir(origDir) })
+
 		// this test will fail at a later point (file not found etc), but not at validation
 		o := opts{PlanFile: "nonexistent-plan.md"}
-		err := run(context.Background(), o)
+		err = run(context.Background(), o)
 		// should fail at git repo check, not at validation
 		require.Error(t, err)
 		assert.NotContains(t, err.Error(), "--plan flag conflicts")
@@ -184,7 +203,7 @@ func TestPlanModeIntegration(t *testing.T) {
 		o := opts{PlanDescription: "add caching feature"}
 		err = run(context.Background(), o)
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "no .git directory")
+		assert.Contains(t, err.Error(), "must run inside a git repository")
 	})
 
 	t.Run("plan_mode_runs_from_git_repo", func(t *testing.T) {
@@ -206,7 +225,7 @@ func TestPlanModeIntegration(t *testing.T) {
 		// should fail with context canceled, not validation errors
 		require.Error(t, err)
 		assert.NotContains(t, err.Error(), "--plan flag conflicts")
-		assert.NotContains(t, err.Error(), "no .git directory")
+		assert.NotContains(t, err.Error(), "must run inside a git repository")
 	})
 
 	t.Run("plan_mode_progress_file_naming", func(t *testing.T) {
@@ -399,11 +418,12 @@ func TestCreateRunner(t *testing.T) {
 
 		// create a dummy logger for the test
 		colors := testColors()
+		t.Chdir(t.TempDir()) // keep progress files out of the source tree
 		log, err := progress.NewLogger(progress.Config{PlanFile: "", Mode: "full", Branch: "test", NoColor: true}, colors)
 		require.NoError(t, err)
 		defer log.Close()
 
-		runner := createRunner(cfg, o, "/path/to/plan.md", processor.ModeFull, log)
+		runner := createRunner(cfg, o, runnerTarget{PlanFile: "/path/to/plan.md", Branch: "feature", Mode: processor.ModeFull}, log)
 		assert.NotNil(t, runner)
 	})
 
@@ -412,12 +432,13 @@ func TestCreateRunner(t *testing.T) {
 		o := opts{MaxIterations: 50}
 
 		colors := testColors()
+		t.Chdir(t.TempDir())
 		log, err := progress.NewLogger(progress.Config{PlanFile: "", Mode: "codex", Branch: "test", NoColor: true}, colors)
 		require.NoError(t, err)
 		defer log.Close()
 
 		// in codex-only mode, CodexEnabled should be forced to true
-		runner := createRunner(cfg, o, "", processor.ModeCodexOnly, log)
+		runner := createRunner(cfg, o, runnerTarget{Branch: "master", Mode: processor.ModeCodexOnly}, log)
 		assert.NotNil(t, runner)
 		// we can't directly check runner internals, but this tests the code path runs without panic
 	})
@@ -910,6 +931,7 @@ func TestEnsureGitignore(t *testing.T) {
 		content, err := os.ReadFile(filepath.Join(dir, ".gitignore")) //nolint:gosec // test file in temp dir
 		require.NoError(t, err)
 		assert.Contains(t, string(content), "progress*.txt")
+		assert.Contains(t, string(content), "progress*.jsonl")
 	})
 
 	t.Run("skips_when_already_ignored", func(t *testing.T) {
@@ -917,7 +939,7 @@ func TestEnsureGitignore(t *testing.T) {
 
 		// create gitignore with pattern already present
 		gitignore := filepath.Join(dir, ".gitignore")
-		err := os.WriteFile(gitignore, []byte("progress*.txt\n"), 0o600)
+		err := os.WriteFile(gitignore, []byte("progress*.txt\nprogress*.jsonl\n"), 0o600)
 		require.NoError(t, err)
 
 		repo, err := git.Open(dir)
@@ -938,7 +960,26 @@ func TestEnsureGitignore(t *testing.T) {
 		// verify content unchanged (no duplicate pattern)
 		content, err := os.ReadFile(gitignore) //nolint:gosec // test file in temp dir
 		require.NoError(t, err)
-		assert.Equal(t, "progress*.txt\n", string(content))
+		assert.Equal(t, "progress*.txt\nprogress*.jsonl\n", string(content))
+	})
+
+	t.Run("adds_only_missing_pattern", func(t *testing.T) {
+		dir := setupTestRepo(t)
+		gitignore := filepath.Join(dir, ".gitignore")
+		require.NoError(t, os.WriteFile(gitignore, []byte("progress*.txt\n"), 0o600))
+
+		repo, err := git.Open(dir)
+		require.NoError(t, err)
+		origDir, err := os.Getwd()
+		require.NoError(t, err)
+		require.NoError(t, os.Chdir(dir))
+		t.Cleanup(func() { _ = os.Chdir(origDir) })
+
+		require.NoError(t, ensureGitignore(repo, colors))
+
+		content, err := os.ReadFile(gitignore) //nolint:gosec // test file in temp dir
+		require.NoError(t, err)
+		assert.Equal(t, "progress*.txt\n\n# ralphex progress logs\nprogress*.jsonl\n", string(content))
 	})
 
 	t.Run("creates_gitignore_if_missing", func(t *testing.T) {
@@ -977,6 +1018,7 @@ func TestEnsureGitignore(t *testing.T) {
 func TestSetupRunnerLogger(t *testing.T) {
 	t.Run("returns_base_logger_when_serve_disabled", func(t *testing.T) {
 		colors := testColors()
+		t.Chdir(t.TempDir())
 		baseLog, err := progress.NewLogger(progress.Config{
 			PlanFile: "",
 			Mode:     "test",
@@ -1000,6 +1042,7 @@ func TestSetupRunnerLogger(t *testing.T) {
 
 	t.Run("returns_broadcast_logger_when_serve_enabled", func(t *testing.T) {
 		colors := testColors()
+		t.Chdir(t.TempDir())
 		baseLog, err := progress.NewLogger(progress.Config{
 			PlanFile: "",
 			Mode:     "test",
@@ -1025,6 +1068,146 @@ func TestSetupRunnerLogger(t *testing.T) {
 	})
 }
 
+func TestWrapMaskingLogger(t *testing.T) {
+	baseLog := &mocks.LoggerMock{PrintAlignedFunc: func(string) {}}
+	t.Setenv("RALPHEX_TEST_TOKEN", "tok-1234567890")
+
+	assert.Nil(t, secretMasker(&config.Config{MaskSecrets: false}))
+	assert.Equal(t, processor.Logger(baseLog), wrapMaskingLogger(secretMasker(&config.Config{MaskSecrets: false}), baseLog))
+
+	wrapped := wrapMaskingLogger(secretMasker(&config.Config{MaskSecrets: true}), baseLog)
+	wrapped.PrintAligned("using tok-1234567890")
+	require.Len(t, baseLog.PrintAlignedCalls(), 1)
+	assert.Equal(t, "using ***", baseLog.PrintAlignedCalls()[0].Text)
+}
+
+func TestWrapTraceLogger(t *testing.T) {
+	baseLog := &mocks.LoggerMock{
+		SetPhaseFunc:     func(processor.Phase) {},
+		PrintSectionFunc: func(processor.Section) {},
+	}
+	claude := &mocks.ExecutorMock{RunFunc: func(context.Context, string) executor.Result { return executor.Result{Output: "ok"} }}
+
+	t.Run("returns_base_logger_without_exporters", func(t *testing.T) {
+		result, wrap, finish := wrapTraceLogger(&config.Config{}, nil, baseLog, "plan.md", "main", processor.ModeFull)
+		assert.Equal(t, processor.Logger(baseLog), result)
+		assert.Equal(t, processor.Executor(claude), wrap("claude", claude))
+		finish(processor.Outcome{}, nil) // no-op, must not panic
+	})
+
+	t.Run("writes_spans_to_trace_file", func(t *testing.T) {
+		traceFile := filepath.Join(t.TempDir(), "trace.jsonl")
+		result, wrap, finish := wrapTraceLogger(&config.Config{TraceFile: traceFile}, nil, baseLog, "plan.md", "main", processor.ModeFull)
+		assert.NotEqual(t, processor.Logger(baseLog), result)
+
+		result.SetPhase(processor.PhaseTask)
+		result.PrintSection(processor.NewTaskIterationSection(1))
+		wrap("claude", claude).Run(context.Background(), "prompt")
+		finish(processor.Outcome{Status: processor.OutcomeSuccess}, nil)
+
+		data, err := os.ReadFile(traceFile) //nolint:gosec // test file
+		require.NoError(t, err)
+		for _, name := range []string{"ralphex run", "phase task", "task iteration 1", "claude run"} {
+			assert.Contains(t, string(data), `"name":"`+name+`"`)
+		}
+	})
+
+	t.Run("masks_secrets_in_span_errors", func(t *testing.T) {
+		t.Setenv("RALPHEX_TEST_TOKEN", "tok-1234567890")
+		traceFile := filepath.Join(t.TempDir(), "trace.jsonl")
+		cfg := &config.Config{TraceFile: traceFile, MaskSecrets: true}
+		result, wrap, finish := wrapTraceLogger(cfg, secretMasker(cfg), baseLog, "plan.md", "main", processor.ModeFull)
+
+		failing := &mocks.ExecutorMock{RunFunc: func(context.Context, string) executor.Result {
+			return executor.Result{Error: errors.New("auth failed for tok-1234567890")}
+		}}
+		result.SetPhase(processor.PhaseTask)
+		wrap("claude", failing).Run(context.Background(), "prompt")
+		finish(processor.Outcome{Status: processor.OutcomeFailed}, errors.New("run failed with tok-1234567890"))
+
+		data, err := os.ReadFile(traceFile) //nolint:gosec // test file
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "tok-1234567890")
+		assert.Contains(t, string(data), "auth failed for ***")
+		assert.Contains(t, string(data), "run failed with ***")
+	})
+}
+
+func TestWrapNotifyLogger(t *testing.T) {
+	baseLog := &mocks.LoggerMock{
+		SetPhaseFunc:     func(processor.Phase) {},
+		PrintSectionFunc: func(processor.Section) {},
+		PathFunc:         func() string { return "progress.txt" },
+	}
+
+	t.Run("returns_base_logger_without_sinks", func(t *testing.T) {
+		result, finish, err := wrapNotifyLogger(&config.Config{}, nil, baseLog, "plan.md", "main")
+		require.NoError(t, err)
+		assert.Equal(t, processor.Logger(baseLog), result)
+		finish(nil) // no-op, must not panic
+	})
+
+	t.Run("invalid_event_filter", func(t *testing.T) {
+		cfg := &config.Config{NotifyWebhookURL: "http://localhost/hook", NotifyWebhookEvents: []string{"bogus"}}
+		_, _, err := wrapNotifyLogger(cfg, nil, baseLog, "plan.md", "main")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "setup notifications")
+	})
+
+	t.Run("delivers_events_to_webhook", func(t *testing.T) {
+		var mu sync.Mutex
+		var events []string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var e struct {
+				Event string `json:"event"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&e))
+			mu.Lock()
+			events = append(events, e.Event)
+			mu.Unlock()
+		}))
+		defer srv.Close()
+
+		cfg := &config.Config{NotifyWebhookURL: srv.URL, NotifyWebhookEvents: []string{"task_done", "completed"}}
+		result, finish, err := wrapNotifyLogger(cfg, nil, baseLog, "plan.md", "feature")
+		require.NoError(t, err)
+
+		result.SetPhase(processor.PhaseTask)
+		result.PrintSection(processor.NewTaskIterationSection(1))
+		result.SetPhase(processor.PhaseReview)
+		finish(nil)
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, []string{"task_done", "completed"}, events)
+	})
+
+	t.Run("masks_secrets_in_run_error", func(t *testing.T) {
+		t.Setenv("RALPHEX_TEST_TOKEN", "tok-1234567890")
+		var mu sync.Mutex
+		var messages []string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var e struct {
+				Message string `json:"message"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&e))
+			mu.Lock()
+			messages = append(messages, e.Message)
+			mu.Unlock()
+		}))
+		defer srv.Close()
+
+		cfg := &config.Config{NotifyWebhookURL: srv.URL, NotifyWebhookEvents: []string{"aborted"}, MaskSecrets: true}
+		_, finish, err := wrapNotifyLogger(cfg, secretMasker(cfg), baseLog, "plan.md", "feature")
+		require.NoError(t, err)
+		finish(errors.New("pre_task hook failed: token tok-1234567890 rejected"))
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, []string{"pre_task hook failed: token *** rejected"}, messages)
+	})
+}
+
 func TestGetCurrentBranch(t *testing.T) {
 	t.Run("returns_branch_name", func(t *testing.T) {
 		dir := setupTestRepo(t)
@@ -1105,6 +1288,195 @@ func TestSetupGitForExecution(t *testing.T) {
 	})
 }
 
+func TestOpenPlanRepos(t *testing.T) {
+	dir := setupTestRepo(t)
+	repo, err := git.Open(dir)
+	require.NoError(t, err)
+	libDir := setupTestRepo(t)
+
+	t.Run("without_plan", func(t *testing.T) {
+		repos, err := openPlanRepos(repo, "")
+		require.NoError(t, err)
+		assert.Same(t, repo, repos.Primary())
+		assert.Empty(t, repos.Extra())
+	})
+
+	t.Run("plan_repositories_section", func(t *testing.T) {
+		planFile := filepath.Join(dir, "plan.md")
+		require.NoError(t, os.WriteFile(planFile, []byte("# Plan\n\n## Repositories\n- "+libDir+"\n\n### Task 1: x\n- [ ] y\n"), 0o600))
+		repos, err := openPlanRepos(repo, planFile)
+		require.NoError(t, err)
+		assert.Equal(t, []string{libDir}, repos.Roots())
+	})
+
+	t.Run("missing_repository", func(t *testing.T) {
+		planFile := filepath.Join(dir, "bad.md")
+		require.NoError(t, os.WriteFile(planFile, []byte("## Repositories\n- ../does-not-exist\n"), 0o600))
+		_, err := openPlanRepos(repo, planFile)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "open plan repositories")
+	})
+}
+
+func TestCheckRepoRollback(t *testing.T) {
+	dir := setupTestRepo(t)
+	repo, err := git.Open(dir)
+	require.NoError(t, err)
+	single, err := git.OpenGroup(repo, nil)
+	require.NoError(t, err)
+	multi, err := git.OpenGroup(repo, []string{setupTestRepo(t)})
+	require.NoError(t, err)
+	rollback := &config.Config{TaskRollback: true}
+
+	require.NoError(t, checkRepoRollback(rollback, processor.ModeFull, single))
+	require.NoError(t, checkRepoRollback(&config.Config{}, processor.ModeFull, multi))
+	require.NoError(t, checkRepoRollback(rollback, processor.ModeReview, multi), "review mode runs no tasks")
+
+	err = checkRepoRollback(rollback, processor.ModeFull, multi)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "task_rollback is not supported for multi-repo plans")
+}
+
+func TestResolveScope(t *testing.T) {
+	dir := setupTestRepo(t)
+	for _, d := range []string{"services/api", "services/web", "libs/common"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, d), 0o750))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("x"), 0o600))
+	planFile := filepath.Join(dir, "plan.md")
+	require.NoError(t, os.WriteFile(planFile, []byte("# Plan\n\nScope: services/api, libs/common\n"), 0o600))
+	noScopePlan := filepath.Join(dir, "plain.md")
+	require.NoError(t, os.WriteFile(noScopePlan, []byte("# Plan\n"), 0o600))
+
+	tests := []struct {
+		name    string
+		start   string
+		flag    []string
+		plan    string
+		want    []string
+		wantErr string
+	}{
+		{name: "root without scope", start: "", plan: noScopePlan},
+		{name: "subdirectory without scope", start: "services/api"},
+		{name: "subdirectory with plan without scope", start: "services/api", plan: noScopePlan},
+		{name: "subdirectory with flag", start: "services/api", flag: []string{"."}, want: []string{"services/api"}},
+		{name: "plan scope relative to root", start: "services/web", plan: planFile, want: []string{"services/api", "libs/common"}},
+		{name: "flag overrides plan", start: "services", flag: []string{"api,web", "api"}, plan: planFile,
+			want: []string{"services/api", "services/web"}},
+		{name: "flag with root", start: "", flag: []string{"services/api", "."}},
+		{name: "missing directory", start: "", flag: []string{"nope"}, wantErr: "scope nope"},
+		{name: "file", start: "", flag: []string{"file.txt"}, wantErr: "not a directory"},
+		{name: "outside repository", start: "", flag: []string{t.TempDir()}, wantErr: "outside the repository"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, err := git.Open(dir)
+			require.NoError(t, err)
+			got, err := resolveScope(repo, filepath.Join(dir, tc.start), tc.flag, tc.plan)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestStartSubdir(t *testing.T) {
+	dir := setupTestRepo(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "services", "api"), 0o750))
+	repo, err := git.Open(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "services/api", startSubdir(repo, filepath.Join(dir, "services", "api")))
+	assert.Empty(t, startSubdir(repo, dir))
+	assert.Empty(t, startSubdir(repo, ""))
+	assert.Empty(t, startSubdir(repo, t.TempDir()))
+}
+
+func TestEnterRepoRoot(t *testing.T) {
+	origDir, err := os.Getwd()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.Chdir(origDir) })
+
+	dir := setupTestRepo(t)
+	dir, err = filepath.EvalSymlinks(dir)
+	require.NoError(t, err)
+	sub := filepath.Join(dir, "services", "api")
+	require.NoError(t, os.MkdirAll(sub, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(sub, "local.md"), []byte("# Plan\n"), 0o600))
+
+	t.Run("from subdirectory", func(t *testing.T) {
+		require.NoError(t, os.Chdir(sub))
+		o := opts{PlanFile: "local.md", Watch: []string{"logs", "/var/log"}}
+		repo, startDir, err := enterRepoRoot(&o)
+		require.NoError(t, err)
+		assert.Equal(t, dir, repo.Root())
+		assert.Equal(t, sub, startDir)
+		cwd, err := os.Getwd()
+		require.NoError(t, err)
+		assert.Equal(t, dir, cwd)
+		assert.Equal(t, filepath.Join("services", "api", "local.md"), o.PlanFile, "found from the start directory")
+		assert.Equal(t, []string{filepath.Join(sub, "logs"), "/var/log"}, o.Watch)
+	})
+
+	t.Run("plan path relative to root", func(t *testing.T) {
+		require.NoError(t, os.Chdir(sub))
+		o := opts{PlanFile: "docs/plans/feature.md"}
+		_, _, err := enterRepoRoot(&o)
+		require.NoError(t, err)
+		assert.Equal(t, "docs/plans/feature.md", o.PlanFile)
+	})
+
+	t.Run("outside repository", func(t *testing.T) {
+		tmp, err := filepath.EvalSymlinks(t.TempDir())
+		require.NoError(t, err)
+		require.NoError(t, os.Chdir(tmp))
+		_, startDir, err := enterRepoRoot(&opts{})
+		require.Error(t, err)
+		assert.Equal(t, tmp, startDir)
+		cwd, err := os.Getwd()
+		require.NoError(t, err)
+		assert.Equal(t, tmp, cwd)
+	})
+}
+
+func TestSyncRepoBranches(t *testing.T) {
+	colors := testColors()
+	dir, libDir, protoDir := setupTestRepo(t), setupTestRepo(t), setupTestRepo(t)
+	repo, err := git.Open(dir)
+	require.NoError(t, err)
+	proto, err := git.Open(protoDir)
+	require.NoError(t, err)
+	require.NoError(t, proto.CreateBranch("feature"))
+	require.NoError(t, proto.CheckoutBranch("master"))
+
+	repos, err := git.OpenGroup(repo, []string{libDir, protoDir})
+	require.NoError(t, err)
+	require.NoError(t, syncRepoBranches(repos, "feature", colors))
+	for _, r := range repos.Extra() {
+		branch, err := r.CurrentBranch()
+		require.NoError(t, err)
+		assert.Equal(t, "feature", branch, r.Root())
+	}
+	require.NoError(t, syncRepoBranches(repos, "feature", colors), "already on the branch")
+
+	t.Run("dirty_repository", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(libDir, "wip.go"), []byte("package wip\n"), 0o600))
+		err := syncRepoBranches(repos, "other", colors)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "uncommitted changes")
+	})
+
+	t.Run("detached_primary", func(t *testing.T) {
+		err := syncRepoBranches(repos, "unknown", colors)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not on a branch")
+	})
+}
+
 func TestHandlePostExecution(t *testing.T) {
 	colors := testColors()
 
@@ -1185,6 +1557,115 @@ func TestHandlePostExecution(t *testing.T) {
 	})
 }
 
+func TestHistoryMessages(t *testing.T) {
+	t.Run("from completed plan", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "completed"), 0o750))
+		plan := "# Auth System\n\n### Task 1: Add middleware\n- [x] a\n\n### Task 2: Add login\n- [x] b\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "completed", "auth.md"), []byte(plan), 0o600))
+
+		msgs := historyMessages(filepath.Join(dir, "auth.md"))
+		assert.Equal(t, "feat: Auth System\n\n- Task 1: Add middleware\n- Task 2: Add login", msgs.Squash)
+		assert.Equal(t, "feat: task 2 - Add login", msgs.Task("2"))
+		assert.Equal(t, "feat: task 7", msgs.Task("7"))
+		assert.Equal(t, "fix: address codex findings", msgs.Phase("codex"))
+	})
+
+	t.Run("missing plan falls back to file name", func(t *testing.T) {
+		msgs := historyMessages(filepath.Join(t.TempDir(), "my-feature.md"))
+		assert.Equal(t, "feat: my-feature", msgs.Squash)
+	})
+}
+
+func TestRewriteBranchHistory(t *testing.T) {
+	// featureRepo creates a repository with a feature branch holding two commits, the plan is added by the first one
+	featureRepo := func(t *testing.T) (*git.Repo, string) {
+		t.Helper()
+		dir := setupTestRepo(t)
+		repo, err := git.Open(dir)
+		require.NoError(t, err)
+		require.NoError(t, repo.CreateBranch("feature"))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "feature.md"), []byte("# Feature\n\n### Task 1: Do it\n- [x] done\n"), 0o600))
+		require.NoError(t, repo.Add("feature.md"))
+		require.NoError(t, repo.Commit("add plan: feature"))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a\n"), 0o600))
+		require.NoError(t, repo.Add("a.go"))
+		require.NoError(t, repo.Commit("wip"))
+		return repo, dir
+	}
+	headCommit := func(t *testing.T, dir string) *object.Commit {
+		t.Helper()
+		r, err := gogit.PlainOpen(dir)
+		require.NoError(t, err)
+		head, err := r.Head()
+		require.NoError(t, err)
+		commit, err := r.CommitObject(head.Hash())
+		require.NoError(t, err)
+		return commit
+	}
+
+	t.Run("rewrites every repository", func(t *testing.T) {
+		repo, dir := featureRepo(t)
+		_, libDir := featureRepo(t)
+		repos, err := git.OpenGroup(repo, []string{libDir})
+		require.NoError(t, err)
+
+		rewriteBranchHistory(repos, &config.Config{HistoryRewrite: "squash"}, filepath.Join(dir, "feature.md"), testColors())
+
+		for _, d := range []string{dir, libDir} {
+			commit := headCommit(t, d)
+			assert.Equal(t, "feat: Feature\n\n- Task 1: Do it\n", commit.Message)
+			parent, err := commit.Parent(0)
+			require.NoError(t, err)
+			assert.Equal(t, "initial commit", parent.Message)
+		}
+	})
+
+	t.Run("base is the branch the feature branch was created from", func(t *testing.T) {
+		dir := setupTestRepo(t)
+		repo, err := git.Open(dir)
+		require.NoError(t, err)
+		require.NoError(t, repo.CreateBranch("develop"))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "dev.go"), []byte("package dev\n"), 0o600))
+		require.NoError(t, repo.Add("dev.go"))
+		require.NoError(t, repo.Commit("develop work"))
+		require.NoError(t, repo.CreateBranch("feature"))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a\n"), 0o600))
+		require.NoError(t, repo.Add("a.go"))
+		require.NoError(t, repo.Commit("wip 1"))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "b.go"), []byte("package a\n"), 0o600))
+		require.NoError(t, repo.Add("b.go"))
+		require.NoError(t, repo.Commit("wip 2"))
+		repos, err := git.OpenGroup(repo, nil)
+		require.NoError(t, err)
+
+		rewriteBranchHistory(repos, &config.Config{HistoryRewrite: "squash"}, filepath.Join(dir, "feature.md"), testColors())
+
+		parent, err := headCommit(t, dir).Parent(0)
+		require.NoError(t, err)
+		assert.Equal(t, "develop work", parent.Message, "develop's commits are not squashed")
+	})
+
+	t.Run("no recorded base leaves history", func(t *testing.T) {
+		dir := setupTestRepo(t)
+		repo, err := git.Open(dir)
+		require.NoError(t, err)
+		r, err := gogit.PlainOpen(dir)
+		require.NoError(t, err)
+		wt, err := r.Worktree()
+		require.NoError(t, err)
+		require.NoError(t, wt.Checkout(&gogit.CheckoutOptions{Branch: "refs/heads/manual", Create: true}))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a\n"), 0o600))
+		require.NoError(t, repo.Add("a.go"))
+		require.NoError(t, repo.Commit("wip"))
+		repos, err := git.OpenGroup(repo, nil)
+		require.NoError(t, err)
+
+		rewriteBranchHistory(repos, &config.Config{HistoryRewrite: "squash"}, filepath.Join(dir, "feature.md"), testColors())
+		assert.Equal(t, "wip", headCommit(t, dir).Message)
+	})
+}
+
 func TestValidateFlags(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -1,9 +1,11 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 )
@@ -80,7 +82,14 @@ func (al *agentLoader) loadFromDir(agentsDir string) ([]CustomAgent, error) {
 		}
 
 		name := strings.TrimSuffix(entry.Name(), ".txt")
-		agents = append(agents, CustomAgent{Name: name, Prompt: prompt})
+		agent, err := parseAgent(name, prompt)
+		if err != nil {
+			return nil, fmt.Errorf("agent file %s: %w", entry.Name(), err)
+		}
+		if agent.Prompt == "" {
+			continue
+		}
+		agents = append(agents, agent)
 	}
 
 	sort.Slice(agents, func(i, j int) bool {
@@ -99,3 +108,81 @@ func (al *agentLoader) loadFile(path string) (string, error) {
 	}
 	return strings.TrimSpace(stripComments(string(data))), nil
 }
+
+// agentSeverities lists valid severity thresholds, most severe first.
+var agentSeverities = []string{"critical", "major", "minor"}
+
+// agentPhases lists prompts an agent can be restricted to via the phases option.
+var agentPhases = []string{"task", "review_first", "review_second", "codex"}
+
+// parseAgent builds a CustomAgent from file content with an optional frontmatter header:
+//
+//	---
+//	model: opus
+//	tools: Read, Grep, Glob
+//	files: **/*.go, !**/*_test.go
+//	severity: major
+//	phases: review_first, review_second
+//	---
+//	prompt text...
+//
+// delimiters are whole "---" lines, CRLF line endings are accepted.
+func parseAgent(name, content string) (CustomAgent, error) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	agent := CustomAgent{Name: name, Prompt: content}
+	lines := strings.Split(content, "\n")
+	if !isFrontmatterDelimiter(lines[0]) {
+		return agent, nil
+	}
+	end := slices.IndexFunc(lines[1:], isFrontmatterDelimiter)
+	if end < 0 {
+		return CustomAgent{}, errors.New("frontmatter is not closed with ---")
+	}
+	header := lines[1 : end+1]
+	agent.Prompt = strings.TrimSpace(strings.Join(lines[end+2:], "\n"))
+
+	for _, line := range header {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		key, val, found := strings.Cut(line, ":")
+		if !found {
+			return CustomAgent{}, fmt.Errorf("invalid frontmatter line %q, expected key: value", line)
+		}
+		key, val = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(val)
+		switch key {
+		case "model":
+			agent.Model = val
+		case "tools":
+			agent.Tools = splitList(val)
+		case "files":
+			agent.Files = splitList(val)
+		case "severity":
+			val = strings.ToLower(val)
+			if !slices.Contains(agentSeverities, val) {
+				return CustomAgent{}, fmt.Errorf("invalid severity %q, must be one of %s", val, strings.Join(agentSeverities, ", "))
+			}
+			agent.Severity = val
+		case "phases":
+			agent.Phases = splitList(strings.ToLower(val))
+			for _, p := range agent.Phases {
+				if !slices.Contains(agentPhases, p) {
+					return CustomAgent{}, fmt.Errorf("invalid phase %q, must be one of %s", p, strings.Join(agentPhases, ", "))
+				}
+			}
+		default:
+			return CustomAgent{}, fmt.Errorf("unknown frontmatter key %q", key)
+		}
+	}
+	return agent, nil
+}
+
+// isFrontmatterDelimiter reports whether the line is a "---" frontmatter delimiter, trailing blanks allowed.
+func isFrontmatterDelimiter(line string) bool {
+	return strings.TrimRight(line, " \t") == "---"
+}
+
+// AppliesTo reports whether the agent is enabled for the given prompt, empty phases means all prompts.
+func (a CustomAgent) AppliesTo(phase string) bool {
+	return len(a.Phases) == 0 || phase == "" || slices.Contains(a.Phases, phase)
+}
@@ -362,3 +362,70 @@ func TestAgentLoader_loadFile_PermissionDenied(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "read agent file")
 }
+
+func TestParseAgent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    CustomAgent
+		wantErr string
+	}{
+		{name: "no frontmatter", content: "check things", want: CustomAgent{Name: "a", Prompt: "check things"}},
+		{
+			name: "full frontmatter",
+			content: "---\nmodel: opus\ntools: Read, Grep,Glob\nfiles: **/*.go, !**/*_test.go\nseverity: Major\n" +
+				"phases: review_first, REVIEW_SECOND\n---\ncheck handlers",
+			want: CustomAgent{Name: "a", Prompt: "check handlers", Model: "opus", Tools: []string{"Read", "Grep", "Glob"},
+				Files: []string{"**/*.go", "!**/*_test.go"}, Severity: "major", Phases: []string{"review_first", "review_second"}},
+		},
+		{name: "empty frontmatter", content: "---\n---\ncheck things", want: CustomAgent{Name: "a", Prompt: "check things"}},
+		{
+			name:    "crlf line endings",
+			content: "---\r\nmodel: opus\r\ntools: Read\r\n---\r\ncheck things\r\n",
+			want:    CustomAgent{Name: "a", Prompt: "check things", Model: "opus", Tools: []string{"Read"}},
+		},
+		{
+			name:    "dash lines in body",
+			content: "---\nmodel: opus\n---\ncheck things\n----\n---x\nmore",
+			want:    CustomAgent{Name: "a", Prompt: "check things\n----\n---x\nmore", Model: "opus"},
+		},
+		{name: "dash line in header is not a delimiter", content: "---\nmodel: opus\n----\ncheck", wantErr: "not closed"},
+		{name: "unclosed", content: "---\nmodel: opus\ncheck", wantErr: "not closed"},
+		{name: "unknown key", content: "---\ncolor: red\n---\ncheck", wantErr: `unknown frontmatter key "color"`},
+		{name: "bad line", content: "---\nmodel opus\n---\ncheck", wantErr: "expected key: value"},
+		{name: "bad severity", content: "---\nseverity: low\n---\ncheck", wantErr: `invalid severity "low"`},
+		{name: "bad phase", content: "---\nphases: plan\n---\ncheck", wantErr: `invalid phase "plan"`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseAgent("a", tc.content)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestAgentLoader_loadFromDir_Frontmatter(t *testing.T) {
+	agentsDir := t.TempDir()
+	content := "# docs reviewer\n---\nfiles: *.md\nphases: review_first\n---\ncheck documentation\n"
+	require.NoError(t, os.WriteFile(filepath.Join(agentsDir, "docs.txt"), []byte(content), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(agentsDir, "empty.txt"), []byte("---\nmodel: haiku\n---\n"), 0o600))
+
+	agents, err := newAgentLoader().loadFromDir(agentsDir)
+	require.NoError(t, err)
+	require.Len(t, agents, 1, "agent with empty prompt body is skipped")
+	assert.Equal(t, "check documentation", agents[0].Prompt)
+	assert.Equal(t, []string{"*.md"}, agents[0].Files)
+	assert.True(t, agents[0].AppliesTo("review_first"))
+	assert.False(t, agents[0].AppliesTo("review_second"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(agentsDir, "bad.txt"), []byte("---\nfoo: bar\n---\nx"), 0o600))
+	_, err = newAgentLoader().loadFromDir(agentsDir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "agent file bad.txt")
+}
@@ -18,6 +18,9 @@ const (
 	reviewSecondPromptFile = "review_second.txt"
 	codexPromptFile        = "codex.txt"
 	makePlanPromptFile     = "make_plan.txt"
+
+	reviewAgentPromptFile       = "review_agent.txt"
+	reviewConsolidatePromptFile = "review_consolidate.txt"
 )
 
 // Config holds all configuration settings for ralphex.
@@ -30,6 +33,16 @@ const (
 //   - CodexTimeoutMsSet: tracks if codex_timeout_ms was explicitly set
 //   - IterationDelayMsSet: tracks if iteration_delay_ms was explicitly set
 //   - TaskRetryCountSet: tracks if task_retry_count was explicitly set
+//   - NotifyDesktopSet: tracks if notify_desktop was explicitly set
+//   - HookTimeoutMsSet: tracks if hook_timeout_ms was explicitly set
+//   - AutoCommitSet: tracks if auto_commit was explicitly set
+//   - CommitTrailersSet: tracks if commit_trailers was explicitly set
+//   - TaskRollbackSet: tracks if task_rollback was explicitly set
+//   - ReviewAgentConcurrencySet: tracks if review_agent_concurrency was explicitly set
+//   - ClaudeSessionResumeSet: tracks if claude_session_resume was explicitly set
+//   - LimitRetryCountSet, LimitBackoffMsSet, LimitMaxWaitMsSet: track if limit_* values were explicitly set
+//   - StallTimeoutMsSet, StallRetryCountSet: track if stall_* values were explicitly set
+//   - MaskSecretsSet: tracks if mask_secrets was explicitly set
 type Config struct {
 	ClaudeCommand string `json:"claude_command"`
 	ClaudeArgs    string `json:"claude_args"`
@@ -43,6 +56,16 @@ type Config struct {
 	CodexTimeoutMsSet    bool   `json:"-"` // tracks if codex_timeout_ms was explicitly set in config
 	CodexSandbox         string `json:"codex_sandbox"`
 
+	ExternalReviewers []string `json:"external_reviewers"` // reviewers of the external review phase, empty means codex only
+
+	// command executors (shell commands usable in place of claude or codex)
+	CommandExecutors map[string]string `json:"command_executors"`
+	ClaudeExecutor   string            `json:"claude_executor"` // command:<name> or empty for claude
+	CodexExecutor    string            `json:"codex_executor"`  // command:<name> or empty for codex
+
+	ClaudeSessionResume    []string `json:"claude_session_resume"` // phases continuing the previous session: review, codex
+	ClaudeSessionResumeSet bool     `json:"-"`                     // tracks if claude_session_resume was explicitly set in config
+
 	IterationDelayMs    int  `json:"iteration_delay_ms"`
 	IterationDelayMsSet bool `json:"-"` // tracks if iteration_delay_ms was explicitly set in config
 	TaskRetryCount      int  `json:"task_retry_count"`
@@ -51,6 +74,87 @@ type Config struct {
 	PlansDir  string   `json:"plans_dir"`
 	WatchDirs []string `json:"watch_dirs"` // directories to watch for progress files
 
+	// notification sinks, each with an optional event filter (empty means all events)
+	NotifyWebhookURL    string   `json:"notify_webhook_url"`
+	NotifyWebhookEvents []string `json:"notify_webhook_events"`
+	NotifySlackURL      string   `json:"notify_slack_url"`
+	NotifySlackEvents   []string `json:"notify_slack_events"`
+	NotifyPushURL       string   `json:"notify_push_url"`
+	NotifyPushType      string   `json:"notify_push_type"`
+	NotifyPushEvents    []string `json:"notify_push_events"`
+	NotifyDesktop       bool     `json:"notify_desktop"`
+	NotifyDesktopSet    bool     `json:"-"` // tracks if notify_desktop was explicitly set in config
+	NotifyDesktopEvents []string `json:"notify_desktop_events"`
+	NotifyCommand       string   `json:"notify_command"`
+	NotifyCommandEvents []string `json:"notify_command_events"`
+
+	// lifecycle hooks (shell commands run by the runner at defined points)
+	HookPreRun       string `json:"hook_pre_run"`
+	HookPreTask      string `json:"hook_pre_task"`
+	HookPostTask     string `json:"hook_post_task"`
+	HookPreReview    string `json:"hook_pre_review"`
+	HookPostPhase    string `json:"hook_post_phase"`
+	HookOnFailure    string `json:"hook_on_failure"`
+	HookPostRun      string `json:"hook_post_run"`
+	HookOnError      string `json:"hook_on_error"` // warn or abort
+	HookTimeoutMs    int    `json:"hook_timeout_ms"`
+	HookTimeoutMsSet bool   `json:"-"` // tracks if hook_timeout_ms was explicitly set in config
+
+	// runner-managed commits (ralphex commits instead of the model)
+	AutoCommit           bool   `json:"auto_commit"`
+	AutoCommitSet        bool   `json:"-"` // tracks if auto_commit was explicitly set in config
+	CommitTaskTemplate   string `json:"commit_task_template"`
+	CommitReviewTemplate string `json:"commit_review_template"`
+	CommitTrailers       bool   `json:"commit_trailers"`
+	CommitTrailersSet    bool   `json:"-"`               // tracks if commit_trailers was explicitly set in config
+	HistoryRewrite       string `json:"history_rewrite"` // keep, squash or per-task
+	TaskRollback         bool   `json:"task_rollback"`
+	TaskRollbackSet      bool   `json:"-"` // tracks if task_rollback was explicitly set in config
+
+	// review agent execution
+	ReviewAgentMode           string `json:"review_agent_mode"` // task or process
+	ReviewAgentConcurrency    int    `json:"review_agent_concurrency"`
+	ReviewAgentConcurrencySet bool   `json:"-"` // tracks if review_agent_concurrency was explicitly set in config
+
+	// rate, usage and overload limit backoff
+	LimitRetryCount    int  `json:"limit_retry_count"`
+	LimitRetryCountSet bool `json:"-"` // tracks if limit_retry_count was explicitly set in config
+	LimitBackoffMs     int  `json:"limit_backoff_ms"`
+	LimitBackoffMsSet  bool `json:"-"` // tracks if limit_backoff_ms was explicitly set in config
+	LimitMaxWaitMs     int  `json:"limit_max_wait_ms"`
+	LimitMaxWaitMsSet  bool `json:"-"` // tracks if limit_max_wait_ms was explicitly set in config
+
+	// stall detection
+	StallTimeoutMs     int  `json:"stall_timeout_ms"`
+	StallTimeoutMsSet  bool `json:"-"` // tracks if stall_timeout_ms was explicitly set in config
+	StallRetryCount    int  `json:"stall_retry_count"`
+	StallRetryCountSet bool `json:"-"` // tracks if stall_retry_count was explicitly set in config
+
+	// container sandbox for claude
+	ContainerRuntime string   `json:"container_runtime"` // docker or podman, empty runs claude on the host
+	ContainerImage   string   `json:"container_image"`
+	ContainerNetwork string   `json:"container_network"`
+	ContainerEnv     []string `json:"container_env"`    // variable names passed through from the host
+	ContainerMounts  []string `json:"container_mounts"` // host:container[:options] bind mounts
+	ContainerArgs    string   `json:"container_args"`
+
+	// executor environment and secrets masking
+	ClaudeEnvAllow []string `json:"claude_env_allow"` // NAME or PREFIX* patterns, empty inherits all
+	ClaudeEnvDeny  []string `json:"claude_env_deny"`
+	ClaudeEnv      []string `json:"claude_env"` // extra KEY=VALUE pairs
+	CodexEnvAllow  []string `json:"codex_env_allow"`
+	CodexEnvDeny   []string `json:"codex_env_deny"`
+	CodexEnv       []string `json:"codex_env"`
+	EnvFile        string   `json:"env_file"`
+	EnvFileVars    []string `json:"-"` // KEY=VALUE pairs loaded from env_file, empty if the file does not exist
+	MaskSecrets    bool     `json:"mask_secrets"`
+	MaskSecretsSet bool     `json:"-"` // tracks if mask_secrets was explicitly set in config
+	MaskEnv        []string `json:"mask_env"`
+
+	// tracing export, disabled when both are empty
+	TraceFile     string `json:"trace_file"`
+	TraceEndpoint string `json:"trace_endpoint"` // OTLP/HTTP collector base URL
+
 	// output colors (RGB values as comma-separated strings)
 	Colors ColorConfig `json:"-"`
 
@@ -61,6 +165,9 @@ type Config struct {
 	CodexPrompt        string `json:"-"`
 	MakePlanPrompt     string `json:"-"`
 
+	ReviewAgentPrompt       string `json:"-"` // per-agent prompt for review_agent_mode = process
+	ReviewConsolidatePrompt string `json:"-"` // findings consolidation prompt for review_agent_mode = process
+
 	// custom agents (loaded separately from files)
 	CustomAgents []CustomAgent `json:"-"`
 
@@ -69,9 +176,15 @@ type Config struct {
 }
 
 // CustomAgent represents a user-defined review agent.
+// optional fields come from the agent file frontmatter and are empty when not set.
 type CustomAgent struct {
-	Name   string // filename without extension
-	Prompt string // contents of the agent file
+	Name     string   // filename without extension
+	Prompt   string   // contents of the agent file, without frontmatter
+	Model    string   // model for the launched agent, e.g. opus, sonnet, haiku
+	Tools    []string // tools the agent is allowed to use
+	Files    []string // file globs the agent should focus on
+	Severity string   // minimum severity to report: critical, major or minor
+	Phases   []string // prompts the agent applies to (task, review_first, review_second, codex)
 }
 
 // ColorConfig holds RGB values for output colors.
@@ -138,6 +251,12 @@ func loadWithLocal(globalDir, localDir string) (*Config, error) {
 		return nil, fmt.Errorf("load values: %w", err)
 	}
 
+	// load env file, relative paths are resolved from the project root
+	envFileVars, err := loadEnvFile(values.EnvFile)
+	if err != nil {
+		return nil, fmt.Errorf("load env file: %w", err)
+	}
+
 	// load colors
 	cl := newColorLoader(embedFS)
 	colors, err := cl.Load(localConfigPath, globalConfigPath)
@@ -181,21 +300,102 @@ func loadWithLocal(globalDir, localDir string) (*Config, error) {
 		CodexTimeoutMs:       values.CodexTimeoutMs,
 		CodexTimeoutMsSet:    values.CodexTimeoutMsSet,
 		CodexSandbox:         values.CodexSandbox,
+		ExternalReviewers:    values.ExternalReviewers,
+		CommandExecutors:     values.CommandExecutors,
+		ClaudeExecutor:       values.ClaudeExecutor,
+		CodexExecutor:        values.CodexExecutor,
+
+		ClaudeSessionResume:    values.ClaudeSessionResume,
+		ClaudeSessionResumeSet: values.ClaudeSessionResumeSet,
+
 		IterationDelayMs:     values.IterationDelayMs,
 		IterationDelayMsSet:  values.IterationDelayMsSet,
 		TaskRetryCount:       values.TaskRetryCount,
 		TaskRetryCountSet:    values.TaskRetryCountSet,
 		PlansDir:             values.PlansDir,
 		WatchDirs:            values.WatchDirs,
-		Colors:               colors,
-		TaskPrompt:           prompts.Task,
-		ReviewFirstPrompt:    prompts.ReviewFirst,
-		ReviewSecondPrompt:   prompts.ReviewSecond,
-		CodexPrompt:          prompts.Codex,
-		MakePlanPrompt:       prompts.MakePlan,
-		CustomAgents:         agents,
-		configDir:            globalDir,
-		localDir:             localDir,
+		NotifyWebhookURL:     values.NotifyWebhookURL,
+		NotifyWebhookEvents:  values.NotifyWebhookEvents,
+		NotifySlackURL:       values.NotifySlackURL,
+		NotifySlackEvents:    values.NotifySlackEvents,
+		NotifyPushURL:        values.NotifyPushURL,
+		NotifyPushType:       values.NotifyPushType,
+		NotifyPushEvents:     values.NotifyPushEvents,
+		NotifyDesktop:        values.NotifyDesktop,
+		NotifyDesktopSet:     values.NotifyDesktopSet,
+		NotifyDesktopEvents:  values.NotifyDesktopEvents,
+		NotifyCommand:        values.NotifyCommand,
+		NotifyCommandEvents:  values.NotifyCommandEvents,
+		HookPreRun:           values.HookPreRun,
+		HookPreTask:          values.HookPreTask,
+		HookPostTask:         values.HookPostTask,
+		HookPreReview:        values.HookPreReview,
+		HookPostPhase:        values.HookPostPhase,
+		HookOnFailure:        values.HookOnFailure,
+		HookPostRun:          values.HookPostRun,
+		HookOnError:          values.HookOnError,
+		HookTimeoutMs:        values.HookTimeoutMs,
+		HookTimeoutMsSet:     values.HookTimeoutMsSet,
+		AutoCommit:           values.AutoCommit,
+		AutoCommitSet:        values.AutoCommitSet,
+		CommitTaskTemplate:   values.CommitTaskTemplate,
+		CommitReviewTemplate: values.CommitReviewTemplate,
+		CommitTrailers:       values.CommitTrailers,
+		CommitTrailersSet:    values.CommitTrailersSet,
+		HistoryRewrite:       values.HistoryRewrite,
+		TaskRollback:         values.TaskRollback,
+		TaskRollbackSet:      values.TaskRollbackSet,
+
+		ReviewAgentMode:           values.ReviewAgentMode,
+		ReviewAgentConcurrency:    values.ReviewAgentConcurrency,
+		ReviewAgentConcurrencySet: values.ReviewAgentConcurrencySet,
+
+		LimitRetryCount:    values.LimitRetryCount,
+		LimitRetryCountSet: values.LimitRetryCountSet,
+		LimitBackoffMs:     values.LimitBackoffMs,
+		LimitBackoffMsSet:  values.LimitBackoffMsSet,
+		LimitMaxWaitMs:     values.LimitMaxWaitMs,
+		LimitMaxWaitMsSet:  values.LimitMaxWaitMsSet,
+
+		StallTimeoutMs:     values.StallTimeoutMs,
+		StallTimeoutMsSet:  values.StallTimeoutMsSet,
+		StallRetryCount:    values.StallRetryCount,
+		StallRetryCountSet: values.StallRetryCountSet,
+
+		ContainerRuntime: values.ContainerRuntime,
+		ContainerImage:   values.ContainerImage,
+		ContainerNetwork: values.ContainerNetwork,
+		ContainerEnv:     values.ContainerEnv,
+		ContainerMounts:  values.ContainerMounts,
+		ContainerArgs:    values.ContainerArgs,
+
+		ClaudeEnvAllow: values.ClaudeEnvAllow,
+		ClaudeEnvDeny:  values.ClaudeEnvDeny,
+		ClaudeEnv:      values.ClaudeEnv,
+		CodexEnvAllow:  values.CodexEnvAllow,
+		CodexEnvDeny:   values.CodexEnvDeny,
+		CodexEnv:       values.CodexEnv,
+		EnvFile:        values.EnvFile,
+		EnvFileVars:    envFileVars,
+		MaskSecrets:    values.MaskSecrets,
+		MaskSecretsSet: values.MaskSecretsSet,
+		MaskEnv:        values.MaskEnv,
+
+		TraceFile:     values.TraceFile,
+		TraceEndpoint: values.TraceEndpoint,
+
+		Colors:             colors,
+		TaskPrompt:         prompts.Task,
+		ReviewFirstPrompt:  prompts.ReviewFirst,
+		ReviewSecondPrompt: prompts.ReviewSecond,
+		CodexPrompt:        prompts.Codex,
+		MakePlanPrompt:     prompts.MakePlan,
+
+		ReviewAgentPrompt:       prompts.ReviewAgent,
+		ReviewConsolidatePrompt: prompts.ReviewConsolidate,
+		CustomAgents:            agents,
+		configDir:               globalDir,
+		localDir:                localDir,
 	}
 
 	return c, nil
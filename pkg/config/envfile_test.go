@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadEnvFile(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("parses variables", func(t *testing.T) {
+		path := filepath.Join(dir, ".env")
+		data := `# credentials
+API_TOKEN=abc123
+
+export DB_URL="postgres://u:p@db/app"
+QUOTED='single # not a comment'
+EMPTY=
+`
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+		vars, err := loadEnvFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"API_TOKEN=abc123", "DB_URL=postgres://u:p@db/app",
+			"QUOTED=single # not a comment", "EMPTY="}, vars)
+	})
+
+	t.Run("empty path and missing file", func(t *testing.T) {
+		vars, err := loadEnvFile("")
+		require.NoError(t, err)
+		assert.Nil(t, vars)
+
+		vars, err = loadEnvFile(filepath.Join(dir, "missing.env"))
+		require.NoError(t, err)
+		assert.Nil(t, vars)
+	})
+
+	t.Run("invalid line", func(t *testing.T) {
+		path := filepath.Join(dir, "bad.env")
+		require.NoError(t, os.WriteFile(path, []byte("OK=1\nnot a variable\n"), 0o600))
+		_, err := loadEnvFile(path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "bad.env:2")
+	})
+}
@@ -18,6 +18,9 @@ type Prompts struct {
 	ReviewSecond string
 	Codex        string
 	MakePlan     string
+
+	ReviewAgent       string
+	ReviewConsolidate string
 }
 
 // promptLoader implements PromptLoader with embedded filesystem fallback.
@@ -60,6 +63,16 @@ func (p *promptLoader) Load(localDir, globalDir string) (Prompts, error) {
 		return Prompts{}, fmt.Errorf("load make_plan prompt: %w", err)
 	}
 
+	prompts.ReviewAgent, err = p.loadPromptWithLocalFallback(localDir, globalDir, reviewAgentPromptFile)
+	if err != nil {
+		return Prompts{}, fmt.Errorf("load review_agent prompt: %w", err)
+	}
+
+	prompts.ReviewConsolidate, err = p.loadPromptWithLocalFallback(localDir, globalDir, reviewConsolidatePromptFile)
+	if err != nil {
+		return Prompts{}, fmt.Errorf("load review_consolidate prompt: %w", err)
+	}
+
 	return prompts, nil
 }
 
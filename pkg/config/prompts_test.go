@@ -430,3 +430,23 @@ func TestPromptLoader_Load_MakePlanPrompt_LocalOverridesGlobal(t *testing.T) {
 
 	assert.Equal(t, "local make plan", prompts.MakePlan)
 }
+
+func TestPromptLoader_Load_ReviewAgentPrompts(t *testing.T) {
+	tmpDir := t.TempDir()
+	globalDir := filepath.Join(tmpDir, "global")
+	require.NoError(t, os.MkdirAll(globalDir, 0o700))
+
+	loader := newPromptLoader(defaultsFS)
+	prompts, err := loader.Load("", globalDir)
+	require.NoError(t, err)
+	assert.Contains(t, prompts.ReviewAgent, "{{AGENT_NAME}}")
+	assert.Contains(t, prompts.ReviewAgent, "{{AGENT_PROMPT}}")
+	assert.Contains(t, prompts.ReviewAgent, "NO ISSUES FOUND")
+	assert.Contains(t, prompts.ReviewConsolidate, "{{AGENT_FINDINGS}}")
+	assert.Contains(t, prompts.ReviewConsolidate, "RALPHEX:REVIEW_DONE")
+
+	require.NoError(t, os.WriteFile(filepath.Join(globalDir, "review_agent.txt"), []byte("custom agent {{AGENT_NAME}}"), 0o600))
+	prompts, err = loader.Load("", globalDir)
+	require.NoError(t, err)
+	assert.Equal(t, "custom agent {{AGENT_NAME}}", prompts.ReviewAgent)
+}
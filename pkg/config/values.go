@@ -4,6 +4,8 @@ import (
 	"embed"
 	"fmt"
 	"os"
+	"regexp"
+	"slices"
 	"strings"
 
 	"gopkg.in/ini.v1"
@@ -24,12 +26,90 @@ type Values struct {
 	CodexTimeoutMs       int
 	CodexTimeoutMsSet    bool // tracks if codex_timeout_ms was explicitly set
 	CodexSandbox         string
+	ExternalReviewers    []string // external review backends (backend or backend:model), empty means codex only
 	IterationDelayMs     int
 	IterationDelayMsSet  bool // tracks if iteration_delay_ms was explicitly set
 	TaskRetryCount       int
 	TaskRetryCountSet    bool // tracks if task_retry_count was explicitly set
 	PlansDir             string
 	WatchDirs            []string // directories to watch for progress files
+
+	CommandExecutors map[string]string // named shell command executors from command_<name> keys
+	ClaudeExecutor   string            // command:<name> replaces claude for task and review sessions, empty keeps claude
+	CodexExecutor    string            // command:<name> replaces codex in the external review phase, empty keeps codex
+
+	ClaudeSessionResume    []string // phases whose iterations continue the previous claude session (review, codex)
+	ClaudeSessionResumeSet bool     // tracks if claude_session_resume was explicitly set (empty list means fresh sessions)
+
+	NotifyWebhookURL    string   // generic JSON webhook endpoint
+	NotifyWebhookEvents []string // event filter for webhook sink, empty means all
+	NotifySlackURL      string   // slack-compatible incoming webhook
+	NotifySlackEvents   []string // event filter for slack sink, empty means all
+	NotifyPushURL       string   // ntfy topic or gotify message endpoint
+	NotifyPushType      string   // push service flavor: ntfy or gotify
+	NotifyPushEvents    []string // event filter for push sink, empty means all
+	NotifyDesktop       bool     // send desktop notifications via notify-send
+	NotifyDesktopSet    bool     // tracks if notify_desktop was explicitly set
+	NotifyDesktopEvents []string // event filter for desktop sink, empty means all
+	NotifyCommand       string   // custom shell command invoked per event
+	NotifyCommandEvents []string // event filter for command sink, empty means all
+
+	HookPreRun       string // shell command run before the run starts
+	HookPreTask      string // shell command run before each task iteration
+	HookPostTask     string // shell command run after each task iteration
+	HookPreReview    string // shell command run before each review phase
+	HookPostPhase    string // shell command run after each phase completes
+	HookOnFailure    string // shell command run when the run fails
+	HookPostRun      string // shell command run after the run completes successfully
+	HookOnError      string // failing hook behavior: warn or abort
+	HookTimeoutMs    int    // per-hook timeout in milliseconds
+	HookTimeoutMsSet bool   // tracks if hook_timeout_ms was explicitly set
+
+	AutoCommit           bool   // runner commits changes after each iteration instead of the model
+	AutoCommitSet        bool   // tracks if auto_commit was explicitly set
+	CommitTaskTemplate   string // commit message template for task iterations
+	CommitReviewTemplate string // commit message template for review and codex iterations
+	CommitTrailers       bool   // append Ralphex-* trailers to runner-made commits
+	CommitTrailersSet    bool   // tracks if commit_trailers was explicitly set
+	HistoryRewrite       string // branch history reshaping on completion: keep, squash or per-task
+	TaskRollback         bool   // reset to the pre-attempt state before retrying a failed task
+	TaskRollbackSet      bool   // tracks if task_rollback was explicitly set
+
+	ReviewAgentMode           string // how review agents run: task (claude Task tool) or process (separate executor runs)
+	ReviewAgentConcurrency    int    // max review agent processes running in parallel
+	ReviewAgentConcurrencySet bool   // tracks if review_agent_concurrency was explicitly set
+
+	LimitRetryCount    int  // times a run hitting rate, usage or overload limits is retried, 0 fails immediately
+	LimitRetryCountSet bool // tracks if limit_retry_count was explicitly set
+	LimitBackoffMs     int  // initial backoff for limits without a reset time, doubled on each retry
+	LimitBackoffMsSet  bool // tracks if limit_backoff_ms was explicitly set
+	LimitMaxWaitMs     int  // caps a single limit wait, including waits until a reported reset time
+	LimitMaxWaitMsSet  bool // tracks if limit_max_wait_ms was explicitly set
+	StallTimeoutMs     int  // kill claude/codex after this long without output, 0 disables
+	StallTimeoutMsSet  bool // tracks if stall_timeout_ms was explicitly set
+	StallRetryCount    int  // times a stalled iteration is retried
+	StallRetryCountSet bool // tracks if stall_retry_count was explicitly set
+
+	ContainerRuntime string   // docker or podman runs claude in a container, empty runs it on the host
+	ContainerImage   string   // image with the claude CLI installed
+	ContainerNetwork string   // --network value for the container, empty uses the runtime default
+	ContainerEnv     []string // environment variable names passed through to the container
+	ContainerMounts  []string // extra bind mounts (host:container[:options]), e.g. claude credentials
+	ContainerArgs    string   // extra arguments for the container run command
+
+	ClaudeEnvAllow []string // variables (NAME or PREFIX*) claude inherits, empty inherits all
+	ClaudeEnvDeny  []string // variables (NAME or PREFIX*) removed from claude's environment
+	ClaudeEnv      []string // extra KEY=VALUE pairs for claude
+	CodexEnvAllow  []string // variables (NAME or PREFIX*) codex inherits, empty inherits all
+	CodexEnvDeny   []string // variables (NAME or PREFIX*) removed from codex's environment
+	CodexEnv       []string // extra KEY=VALUE pairs for codex
+	EnvFile        string   // .env file whose variables are added for claude and codex
+	MaskSecrets    bool     // redact secret values from output before it is logged or streamed
+	MaskSecretsSet bool     // tracks if mask_secrets was explicitly set
+	MaskEnv        []string // extra variable names whose values are redacted
+
+	TraceFile     string // file tracing spans are written to as OTLP/JSON, one export request per line
+	TraceEndpoint string // OTLP/HTTP collector base URL spans are posted to, e.g. http://localhost:4318
 }
 
 // valuesLoader implements ValuesLoader with embedded filesystem fallback.
@@ -70,6 +150,11 @@ func (vl *valuesLoader) Load(localConfigPath, globalConfigPath string) (Values,
 	result.mergeFrom(&global)
 	result.mergeFrom(&local)
 
+	// image may come from a different file than the runtime, so check the merged result
+	if result.ContainerRuntime != "" && result.ContainerImage == "" {
+		return Values{}, fmt.Errorf("container_runtime %s requires container_image", result.ContainerRuntime)
+	}
+
 	return result, nil
 }
 
@@ -120,6 +205,16 @@ func (vl *valuesLoader) parseValuesFromBytes(data []byte) (Values, error) {
 	if key, err := section.GetKey("claude_args"); err == nil {
 		values.ClaudeArgs = key.String()
 	}
+	if key, err := section.GetKey("claude_session_resume"); err == nil {
+		phases := splitList(strings.ToLower(key.String()))
+		for _, p := range phases {
+			if p != "review" && p != "codex" {
+				return Values{}, fmt.Errorf("invalid claude_session_resume: unknown phase %q, must be review or codex", p)
+			}
+		}
+		values.ClaudeSessionResume = phases
+		values.ClaudeSessionResumeSet = true
+	}
 
 	// codex settings
 	if key, err := section.GetKey("codex_enabled"); err == nil {
@@ -153,6 +248,20 @@ func (vl *valuesLoader) parseValuesFromBytes(data []byte) (Values, error) {
 	if key, err := section.GetKey("codex_sandbox"); err == nil {
 		values.CodexSandbox = key.String()
 	}
+	if key, err := section.GetKey("external_reviewers"); err == nil {
+		reviewers := splitList(key.String())
+		for _, r := range reviewers {
+			backend, name, _ := strings.Cut(r, ":")
+			if !slices.Contains(ReviewerBackends, backend) {
+				return Values{}, fmt.Errorf("invalid external_reviewers: unknown backend %q in %q, must be one of %s",
+					backend, r, strings.Join(ReviewerBackends, ", "))
+			}
+			if backend == "command" && name == "" {
+				return Values{}, fmt.Errorf("invalid external_reviewers: %q needs a command name (command:<name>)", r)
+			}
+		}
+		values.ExternalReviewers = reviewers
+	}
 
 	// timing settings
 	if key, err := section.GetKey("iteration_delay_ms"); err == nil {
@@ -185,17 +294,396 @@ func (vl *valuesLoader) parseValuesFromBytes(data []byte) (Values, error) {
 
 	// watch directories (comma-separated)
 	if key, err := section.GetKey("watch_dirs"); err == nil {
+		values.WatchDirs = splitList(key.String())
+	}
+
+	if err := parseNotifyValues(section, &values); err != nil {
+		return Values{}, err
+	}
+	if err := parseHookValues(section, &values); err != nil {
+		return Values{}, err
+	}
+	if err := parseCommitValues(section, &values); err != nil {
+		return Values{}, err
+	}
+	if err := parseReviewAgentValues(section, &values); err != nil {
+		return Values{}, err
+	}
+	if err := parseCommandExecutorValues(section, &values); err != nil {
+		return Values{}, err
+	}
+	if err := parseRecoveryValues(section, &values); err != nil {
+		return Values{}, err
+	}
+	if err := parseContainerValues(section, &values); err != nil {
+		return Values{}, err
+	}
+	if err := parseEnvValues(section, &values); err != nil {
+		return Values{}, err
+	}
+	if err := parseTraceValues(section, &values); err != nil {
+		return Values{}, err
+	}
+
+	return values, nil
+}
+
+// parseNotifyValues parses notification sink settings (notify_* keys) into values.
+func parseNotifyValues(section *ini.Section, values *Values) error {
+	if key, err := section.GetKey("notify_webhook_url"); err == nil {
+		values.NotifyWebhookURL = strings.TrimSpace(key.String())
+	}
+	if key, err := section.GetKey("notify_webhook_events"); err == nil {
+		values.NotifyWebhookEvents = splitList(key.String())
+	}
+	if key, err := section.GetKey("notify_slack_url"); err == nil {
+		values.NotifySlackURL = strings.TrimSpace(key.String())
+	}
+	if key, err := section.GetKey("notify_slack_events"); err == nil {
+		values.NotifySlackEvents = splitList(key.String())
+	}
+	if key, err := section.GetKey("notify_push_url"); err == nil {
+		values.NotifyPushURL = strings.TrimSpace(key.String())
+	}
+	if key, err := section.GetKey("notify_push_type"); err == nil {
+		val := strings.ToLower(strings.TrimSpace(key.String()))
+		if val != "" && val != "ntfy" && val != "gotify" {
+			return fmt.Errorf("invalid notify_push_type: must be ntfy or gotify, got %q", val)
+		}
+		values.NotifyPushType = val
+	}
+	if key, err := section.GetKey("notify_push_events"); err == nil {
+		values.NotifyPushEvents = splitList(key.String())
+	}
+	if key, err := section.GetKey("notify_desktop"); err == nil {
+		val, boolErr := key.Bool()
+		if boolErr != nil {
+			return fmt.Errorf("invalid notify_desktop: %w", boolErr)
+		}
+		values.NotifyDesktop = val
+		values.NotifyDesktopSet = true
+	}
+	if key, err := section.GetKey("notify_desktop_events"); err == nil {
+		values.NotifyDesktopEvents = splitList(key.String())
+	}
+	if key, err := section.GetKey("notify_command"); err == nil {
+		values.NotifyCommand = strings.TrimSpace(key.String())
+	}
+	if key, err := section.GetKey("notify_command_events"); err == nil {
+		values.NotifyCommandEvents = splitList(key.String())
+	}
+	return nil
+}
+
+// parseHookValues parses lifecycle hook settings (hook_* keys) into values.
+func parseHookValues(section *ini.Section, values *Values) error {
+	hooks := []struct {
+		key string
+		dst *string
+	}{
+		{"hook_pre_run", &values.HookPreRun},
+		{"hook_pre_task", &values.HookPreTask},
+		{"hook_post_task", &values.HookPostTask},
+		{"hook_pre_review", &values.HookPreReview},
+		{"hook_post_phase", &values.HookPostPhase},
+		{"hook_on_failure", &values.HookOnFailure},
+		{"hook_post_run", &values.HookPostRun},
+	}
+	for _, h := range hooks {
+		if key, err := section.GetKey(h.key); err == nil {
+			*h.dst = strings.TrimSpace(key.String())
+		}
+	}
+
+	if key, err := section.GetKey("hook_on_error"); err == nil {
+		val := strings.ToLower(strings.TrimSpace(key.String()))
+		if val != "" && val != "warn" && val != "abort" {
+			return fmt.Errorf("invalid hook_on_error: must be warn or abort, got %q", val)
+		}
+		values.HookOnError = val
+	}
+	if key, err := section.GetKey("hook_timeout_ms"); err == nil {
+		val, intErr := key.Int()
+		if intErr != nil {
+			return fmt.Errorf("invalid hook_timeout_ms: %w", intErr)
+		}
+		if val < 0 {
+			return fmt.Errorf("invalid hook_timeout_ms: must be non-negative, got %d", val)
+		}
+		values.HookTimeoutMs = val
+		values.HookTimeoutMsSet = true
+	}
+	return nil
+}
+
+// parseCommitValues parses runner-managed commit settings into values.
+func parseCommitValues(section *ini.Section, values *Values) error {
+	if key, err := section.GetKey("auto_commit"); err == nil {
+		val, boolErr := key.Bool()
+		if boolErr != nil {
+			return fmt.Errorf("invalid auto_commit: %w", boolErr)
+		}
+		values.AutoCommit = val
+		values.AutoCommitSet = true
+	}
+	if key, err := section.GetKey("commit_task_template"); err == nil {
+		values.CommitTaskTemplate = strings.TrimSpace(key.String())
+	}
+	if key, err := section.GetKey("commit_review_template"); err == nil {
+		values.CommitReviewTemplate = strings.TrimSpace(key.String())
+	}
+	if key, err := section.GetKey("commit_trailers"); err == nil {
+		val, boolErr := key.Bool()
+		if boolErr != nil {
+			return fmt.Errorf("invalid commit_trailers: %w", boolErr)
+		}
+		values.CommitTrailers = val
+		values.CommitTrailersSet = true
+	}
+	if key, err := section.GetKey("history_rewrite"); err == nil {
+		val := strings.ToLower(strings.TrimSpace(key.String()))
+		if val != "" && val != "keep" && val != "squash" && val != "per-task" {
+			return fmt.Errorf("invalid history_rewrite: must be keep, squash or per-task, got %q", val)
+		}
+		values.HistoryRewrite = val
+	}
+	if key, err := section.GetKey("task_rollback"); err == nil {
+		val, boolErr := key.Bool()
+		if boolErr != nil {
+			return fmt.Errorf("invalid task_rollback: %w", boolErr)
+		}
+		values.TaskRollback = val
+		values.TaskRollbackSet = true
+	}
+	return nil
+}
+
+// parseReviewAgentValues parses review agent execution settings into values.
+func parseReviewAgentValues(section *ini.Section, values *Values) error {
+	if key, err := section.GetKey("review_agent_mode"); err == nil {
+		val := strings.ToLower(strings.TrimSpace(key.String()))
+		if val != "" && val != "task" && val != "process" {
+			return fmt.Errorf("invalid review_agent_mode: must be task or process, got %q", val)
+		}
+		values.ReviewAgentMode = val
+	}
+	if key, err := section.GetKey("review_agent_concurrency"); err == nil {
+		val, intErr := key.Int()
+		if intErr != nil {
+			return fmt.Errorf("invalid review_agent_concurrency: %w", intErr)
+		}
+		if val < 1 {
+			return fmt.Errorf("invalid review_agent_concurrency: must be at least 1, got %d", val)
+		}
+		values.ReviewAgentConcurrency = val
+		values.ReviewAgentConcurrencySet = true
+	}
+	return nil
+}
+
+// parseRecoveryValues parses rate/usage limit backoff and stall detection settings into values.
+func parseRecoveryValues(section *ini.Section, values *Values) error {
+	limits := []struct {
+		key string
+		dst *int
+		set *bool
+	}{
+		{"limit_retry_count", &values.LimitRetryCount, &values.LimitRetryCountSet},
+		{"limit_backoff_ms", &values.LimitBackoffMs, &values.LimitBackoffMsSet},
+		{"limit_max_wait_ms", &values.LimitMaxWaitMs, &values.LimitMaxWaitMsSet},
+		{"stall_timeout_ms", &values.StallTimeoutMs, &values.StallTimeoutMsSet},
+		{"stall_retry_count", &values.StallRetryCount, &values.StallRetryCountSet},
+	}
+	for _, l := range limits {
+		key, err := section.GetKey(l.key)
+		if err != nil {
+			continue
+		}
+		val, intErr := key.Int()
+		if intErr != nil {
+			return fmt.Errorf("invalid %s: %w", l.key, intErr)
+		}
+		if val < 0 {
+			return fmt.Errorf("invalid %s: must be non-negative, got %d", l.key, val)
+		}
+		*l.dst = val
+		*l.set = true
+	}
+	return nil
+}
+
+// envNameRe matches valid environment variable names.
+var envNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// parseContainerValues parses container sandbox settings (container_* keys) into values.
+func parseContainerValues(section *ini.Section, values *Values) error {
+	if key, err := section.GetKey("container_runtime"); err == nil {
+		val := strings.TrimSpace(key.String())
+		if val != "" && val != "docker" && val != "podman" {
+			return fmt.Errorf("invalid container_runtime: must be docker or podman, got %q", val)
+		}
+		values.ContainerRuntime = val
+	}
+	if key, err := section.GetKey("container_image"); err == nil {
+		values.ContainerImage = strings.TrimSpace(key.String())
+	}
+	if key, err := section.GetKey("container_network"); err == nil {
 		val := strings.TrimSpace(key.String())
-		if val != "" {
-			for p := range strings.SplitSeq(val, ",") {
-				if t := strings.TrimSpace(p); t != "" {
-					values.WatchDirs = append(values.WatchDirs, t)
-				}
+		if strings.ContainsAny(val, " \t") {
+			return fmt.Errorf("invalid container_network: must be a single network name, got %q", val)
+		}
+		values.ContainerNetwork = val
+	}
+	if key, err := section.GetKey("container_env"); err == nil {
+		names := splitList(key.String())
+		for _, name := range names {
+			if !envNameRe.MatchString(name) {
+				return fmt.Errorf("invalid container_env: %q is not a variable name", name)
+			}
+		}
+		values.ContainerEnv = names
+	}
+	if key, err := section.GetKey("container_mounts"); err == nil {
+		mounts := splitList(key.String())
+		for _, m := range mounts {
+			if host, target, ok := strings.Cut(m, ":"); !ok || host == "" || target == "" {
+				return fmt.Errorf("invalid container_mounts: %q, expected host:container[:options]", m)
 			}
 		}
+		values.ContainerMounts = mounts
 	}
+	if key, err := section.GetKey("container_args"); err == nil {
+		values.ContainerArgs = strings.TrimSpace(key.String())
+	}
+	return nil
+}
 
-	return values, nil
+// parseEnvValues parses executor environment policies, env_file and secrets masking settings into values.
+func parseEnvValues(section *ini.Section, values *Values) error {
+	patterns := []struct {
+		key string
+		dst *[]string
+	}{
+		{"claude_env_allow", &values.ClaudeEnvAllow},
+		{"claude_env_deny", &values.ClaudeEnvDeny},
+		{"codex_env_allow", &values.CodexEnvAllow},
+		{"codex_env_deny", &values.CodexEnvDeny},
+		{"mask_env", &values.MaskEnv},
+	}
+	for _, p := range patterns {
+		key, err := section.GetKey(p.key)
+		if err != nil {
+			continue
+		}
+		names := splitList(key.String())
+		for _, name := range names {
+			if !envNameRe.MatchString(strings.TrimSuffix(name, "*")) {
+				return fmt.Errorf("invalid %s: %q is not a variable name or PREFIX* pattern", p.key, name)
+			}
+		}
+		*p.dst = names
+	}
+
+	pairs := []struct {
+		key string
+		dst *[]string
+	}{
+		{"claude_env", &values.ClaudeEnv},
+		{"codex_env", &values.CodexEnv},
+	}
+	for _, p := range pairs {
+		key, err := section.GetKey(p.key)
+		if err != nil {
+			continue
+		}
+		vars := splitList(key.String())
+		for _, v := range vars {
+			if name, _, ok := strings.Cut(v, "="); !ok || !envNameRe.MatchString(name) {
+				return fmt.Errorf("invalid %s: %q, expected KEY=VALUE", p.key, v)
+			}
+		}
+		*p.dst = vars
+	}
+
+	if key, err := section.GetKey("env_file"); err == nil {
+		values.EnvFile = strings.TrimSpace(key.String())
+	}
+	if key, err := section.GetKey("mask_secrets"); err == nil {
+		val, boolErr := key.Bool()
+		if boolErr != nil {
+			return fmt.Errorf("invalid mask_secrets: %w", boolErr)
+		}
+		values.MaskSecrets = val
+		values.MaskSecretsSet = true
+	}
+	return nil
+}
+
+// parseTraceValues parses tracing export settings (trace_* keys) into values.
+func parseTraceValues(section *ini.Section, values *Values) error {
+	if key, err := section.GetKey("trace_file"); err == nil {
+		values.TraceFile = strings.TrimSpace(key.String())
+	}
+	if key, err := section.GetKey("trace_endpoint"); err == nil {
+		val := strings.TrimSuffix(strings.TrimSpace(key.String()), "/")
+		if val != "" && !strings.HasPrefix(val, "http://") && !strings.HasPrefix(val, "https://") {
+			return fmt.Errorf("invalid trace_endpoint: must be an http or https URL, got %q", val)
+		}
+		values.TraceEndpoint = val
+	}
+	return nil
+}
+
+// parseCommandExecutorValues parses command_<name> executors and the claude/codex executor overrides.
+func parseCommandExecutorValues(section *ini.Section, values *Values) error {
+	for _, key := range section.Keys() {
+		name, ok := strings.CutPrefix(key.Name(), "command_")
+		if !ok {
+			continue
+		}
+		if name == "" || strings.TrimSpace(key.String()) == "" {
+			return fmt.Errorf("invalid %s: command name and value must not be empty", key.Name())
+		}
+		if values.CommandExecutors == nil {
+			values.CommandExecutors = make(map[string]string)
+		}
+		values.CommandExecutors[name] = strings.TrimSpace(key.String())
+	}
+	var err error
+	if values.ClaudeExecutor, err = parseCommandRef(section, "claude_executor"); err != nil {
+		return err
+	}
+	if values.CodexExecutor, err = parseCommandRef(section, "codex_executor"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// parseCommandRef reads an executor override key, which must be empty or command:<name>.
+func parseCommandRef(section *ini.Section, keyName string) (string, error) {
+	key, err := section.GetKey(keyName)
+	if err != nil {
+		return "", nil //nolint:nilerr // missing key means no override
+	}
+	val := strings.TrimSpace(key.String())
+	if name, ok := strings.CutPrefix(val, "command:"); val != "" && (!ok || name == "") {
+		return "", fmt.Errorf("invalid %s: must be empty or command:<name>, got %q", keyName, val)
+	}
+	return val, nil
+}
+
+// ReviewerBackends lists executor backends usable as external reviewers.
+var ReviewerBackends = []string{"codex", "claude", "command"}
+
+// splitList splits a comma-separated value into trimmed, non-empty items.
+func splitList(val string) []string {
+	var result []string
+	for p := range strings.SplitSeq(val, ",") {
+		if t := strings.TrimSpace(p); t != "" {
+			result = append(result, t)
+		}
+	}
+	return result
 }
 
 // mergeFrom merges non-empty values from src into dst.
@@ -206,6 +694,10 @@ func (dst *Values) mergeFrom(src *Values) {
 	if src.ClaudeArgs != "" {
 		dst.ClaudeArgs = src.ClaudeArgs
 	}
+	if src.ClaudeSessionResumeSet {
+		dst.ClaudeSessionResume = src.ClaudeSessionResume
+		dst.ClaudeSessionResumeSet = true
+	}
 	if src.CodexEnabledSet {
 		dst.CodexEnabled = src.CodexEnabled
 		dst.CodexEnabledSet = true
@@ -226,6 +718,9 @@ func (dst *Values) mergeFrom(src *Values) {
 	if src.CodexSandbox != "" {
 		dst.CodexSandbox = src.CodexSandbox
 	}
+	if len(src.ExternalReviewers) > 0 {
+		dst.ExternalReviewers = src.ExternalReviewers
+	}
 	if src.IterationDelayMsSet {
 		dst.IterationDelayMs = src.IterationDelayMs
 		dst.IterationDelayMsSet = true
@@ -240,4 +735,211 @@ func (dst *Values) mergeFrom(src *Values) {
 	if len(src.WatchDirs) > 0 {
 		dst.WatchDirs = src.WatchDirs
 	}
+	dst.mergeNotifyFrom(src)
+	dst.mergeHooksFrom(src)
+	dst.mergeCommitFrom(src)
+	dst.mergeReviewAgentFrom(src)
+	dst.mergeCommandExecutorFrom(src)
+	dst.mergeRecoveryFrom(src)
+	dst.mergeContainerFrom(src)
+	dst.mergeEnvFrom(src)
+	if src.TraceFile != "" {
+		dst.TraceFile = src.TraceFile
+	}
+	if src.TraceEndpoint != "" {
+		dst.TraceEndpoint = src.TraceEndpoint
+	}
+}
+
+// mergeEnvFrom merges executor environment and secrets masking settings from src into dst.
+func (dst *Values) mergeEnvFrom(src *Values) {
+	lists := []struct{ dst, src *[]string }{
+		{&dst.ClaudeEnvAllow, &src.ClaudeEnvAllow},
+		{&dst.ClaudeEnvDeny, &src.ClaudeEnvDeny},
+		{&dst.ClaudeEnv, &src.ClaudeEnv},
+		{&dst.CodexEnvAllow, &src.CodexEnvAllow},
+		{&dst.CodexEnvDeny, &src.CodexEnvDeny},
+		{&dst.CodexEnv, &src.CodexEnv},
+		{&dst.MaskEnv, &src.MaskEnv},
+	}
+	for _, l := range lists {
+		if len(*l.src) > 0 {
+			*l.dst = *l.src
+		}
+	}
+	if src.EnvFile != "" {
+		dst.EnvFile = src.EnvFile
+	}
+	if src.MaskSecretsSet {
+		dst.MaskSecrets = src.MaskSecrets
+		dst.MaskSecretsSet = true
+	}
+}
+
+// mergeContainerFrom merges non-empty container sandbox settings from src into dst.
+func (dst *Values) mergeContainerFrom(src *Values) {
+	if src.ContainerRuntime != "" {
+		dst.ContainerRuntime = src.ContainerRuntime
+	}
+	if src.ContainerImage != "" {
+		dst.ContainerImage = src.ContainerImage
+	}
+	if src.ContainerNetwork != "" {
+		dst.ContainerNetwork = src.ContainerNetwork
+	}
+	if len(src.ContainerEnv) > 0 {
+		dst.ContainerEnv = src.ContainerEnv
+	}
+	if len(src.ContainerMounts) > 0 {
+		dst.ContainerMounts = src.ContainerMounts
+	}
+	if src.ContainerArgs != "" {
+		dst.ContainerArgs = src.ContainerArgs
+	}
+}
+
+// mergeRecoveryFrom merges explicitly set limit backoff and stall settings from src into dst.
+func (dst *Values) mergeRecoveryFrom(src *Values) {
+	if src.LimitRetryCountSet {
+		dst.LimitRetryCount = src.LimitRetryCount
+		dst.LimitRetryCountSet = true
+	}
+	if src.LimitBackoffMsSet {
+		dst.LimitBackoffMs = src.LimitBackoffMs
+		dst.LimitBackoffMsSet = true
+	}
+	if src.LimitMaxWaitMsSet {
+		dst.LimitMaxWaitMs = src.LimitMaxWaitMs
+		dst.LimitMaxWaitMsSet = true
+	}
+	if src.StallTimeoutMsSet {
+		dst.StallTimeoutMs = src.StallTimeoutMs
+		dst.StallTimeoutMsSet = true
+	}
+	if src.StallRetryCountSet {
+		dst.StallRetryCount = src.StallRetryCount
+		dst.StallRetryCountSet = true
+	}
+}
+
+// mergeCommandExecutorFrom merges command executors from src into dst, src commands override same-named ones.
+func (dst *Values) mergeCommandExecutorFrom(src *Values) {
+	for name, command := range src.CommandExecutors {
+		if dst.CommandExecutors == nil {
+			dst.CommandExecutors = make(map[string]string)
+		}
+		dst.CommandExecutors[name] = command
+	}
+	if src.ClaudeExecutor != "" {
+		dst.ClaudeExecutor = src.ClaudeExecutor
+	}
+	if src.CodexExecutor != "" {
+		dst.CodexExecutor = src.CodexExecutor
+	}
+}
+
+// mergeReviewAgentFrom merges review agent settings from src into dst.
+func (dst *Values) mergeReviewAgentFrom(src *Values) {
+	if src.ReviewAgentMode != "" {
+		dst.ReviewAgentMode = src.ReviewAgentMode
+	}
+	if src.ReviewAgentConcurrencySet {
+		dst.ReviewAgentConcurrency = src.ReviewAgentConcurrency
+		dst.ReviewAgentConcurrencySet = true
+	}
+}
+
+// mergeHooksFrom merges non-empty hook settings from src into dst.
+func (dst *Values) mergeHooksFrom(src *Values) {
+	if src.HookPreRun != "" {
+		dst.HookPreRun = src.HookPreRun
+	}
+	if src.HookPreTask != "" {
+		dst.HookPreTask = src.HookPreTask
+	}
+	if src.HookPostTask != "" {
+		dst.HookPostTask = src.HookPostTask
+	}
+	if src.HookPreReview != "" {
+		dst.HookPreReview = src.HookPreReview
+	}
+	if src.HookPostPhase != "" {
+		dst.HookPostPhase = src.HookPostPhase
+	}
+	if src.HookOnFailure != "" {
+		dst.HookOnFailure = src.HookOnFailure
+	}
+	if src.HookPostRun != "" {
+		dst.HookPostRun = src.HookPostRun
+	}
+	if src.HookOnError != "" {
+		dst.HookOnError = src.HookOnError
+	}
+	if src.HookTimeoutMsSet {
+		dst.HookTimeoutMs = src.HookTimeoutMs
+		dst.HookTimeoutMsSet = true
+	}
+}
+
+// mergeNotifyFrom merges non-empty notification settings from src into dst.
+func (dst *Values) mergeNotifyFrom(src *Values) {
+	if src.NotifyWebhookURL != "" {
+		dst.NotifyWebhookURL = src.NotifyWebhookURL
+	}
+	if len(src.NotifyWebhookEvents) > 0 {
+		dst.NotifyWebhookEvents = src.NotifyWebhookEvents
+	}
+	if src.NotifySlackURL != "" {
+		dst.NotifySlackURL = src.NotifySlackURL
+	}
+	if len(src.NotifySlackEvents) > 0 {
+		dst.NotifySlackEvents = src.NotifySlackEvents
+	}
+	if src.NotifyPushURL != "" {
+		dst.NotifyPushURL = src.NotifyPushURL
+	}
+	if src.NotifyPushType != "" {
+		dst.NotifyPushType = src.NotifyPushType
+	}
+	if len(src.NotifyPushEvents) > 0 {
+		dst.NotifyPushEvents = src.NotifyPushEvents
+	}
+	if src.NotifyDesktopSet {
+		dst.NotifyDesktop = src.NotifyDesktop
+		dst.NotifyDesktopSet = true
+	}
+	if len(src.NotifyDesktopEvents) > 0 {
+		dst.NotifyDesktopEvents = src.NotifyDesktopEvents
+	}
+	if src.NotifyCommand != "" {
+		dst.NotifyCommand = src.NotifyCommand
+	}
+	if len(src.NotifyCommandEvents) > 0 {
+		dst.NotifyCommandEvents = src.NotifyCommandEvents
+	}
+}
+
+// mergeCommitFrom merges explicitly set commit settings from src into dst.
+func (dst *Values) mergeCommitFrom(src *Values) {
+	if src.AutoCommitSet {
+		dst.AutoCommit = src.AutoCommit
+		dst.AutoCommitSet = true
+	}
+	if src.CommitTaskTemplate != "" {
+		dst.CommitTaskTemplate = src.CommitTaskTemplate
+	}
+	if src.CommitReviewTemplate != "" {
+		dst.CommitReviewTemplate = src.CommitReviewTemplate
+	}
+	if src.CommitTrailersSet {
+		dst.CommitTrailers = src.CommitTrailers
+		dst.CommitTrailersSet = true
+	}
+	if src.HistoryRewrite != "" {
+		dst.HistoryRewrite = src.HistoryRewrite
+	}
+	if src.TaskRollbackSet {
+		dst.TaskRollback = src.TaskRollback
+		dst.TaskRollbackSet = true
+	}
 }
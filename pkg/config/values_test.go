@@ -449,6 +449,149 @@ plans_dir = custom/plans
 	})
 }
 
+func TestValuesLoader_parseValuesFromBytes_Notify(t *testing.T) {
+	vl := &valuesLoader{embedFS: defaultsFS}
+
+	t.Run("all notify settings", func(t *testing.T) {
+		data := []byte(`
+notify_webhook_url = http://localhost:9000/hook
+notify_webhook_events = task_done, completed
+notify_slack_url = https://hooks.slack.com/services/x
+notify_slack_events = failed,aborted
+notify_push_url = https://ntfy.sh/ralphex
+notify_push_type = Gotify
+notify_push_events = question
+notify_desktop = true
+notify_desktop_events = completed
+notify_command = echo $RALPHEX_EVENT
+notify_command_events = phase_change
+`)
+		values, err := vl.parseValuesFromBytes(data)
+		require.NoError(t, err)
+
+		assert.Equal(t, "http://localhost:9000/hook", values.NotifyWebhookURL)
+		assert.Equal(t, []string{"task_done", "completed"}, values.NotifyWebhookEvents)
+		assert.Equal(t, "https://hooks.slack.com/services/x", values.NotifySlackURL)
+		assert.Equal(t, []string{"failed", "aborted"}, values.NotifySlackEvents)
+		assert.Equal(t, "https://ntfy.sh/ralphex", values.NotifyPushURL)
+		assert.Equal(t, "gotify", values.NotifyPushType)
+		assert.Equal(t, []string{"question"}, values.NotifyPushEvents)
+		assert.True(t, values.NotifyDesktop)
+		assert.True(t, values.NotifyDesktopSet)
+		assert.Equal(t, []string{"completed"}, values.NotifyDesktopEvents)
+		assert.Equal(t, "echo $RALPHEX_EVENT", values.NotifyCommand)
+		assert.Equal(t, []string{"phase_change"}, values.NotifyCommandEvents)
+	})
+
+	t.Run("embedded defaults have no sinks", func(t *testing.T) {
+		values, err := vl.parseValuesFromEmbedded()
+		require.NoError(t, err)
+		assert.Empty(t, values.NotifyWebhookURL)
+		assert.Empty(t, values.NotifySlackURL)
+		assert.Empty(t, values.NotifyPushURL)
+		assert.False(t, values.NotifyDesktop)
+		assert.Empty(t, values.NotifyCommand)
+	})
+
+	t.Run("invalid push type", func(t *testing.T) {
+		_, err := vl.parseValuesFromBytes([]byte("notify_push_type = pushover"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid notify_push_type")
+	})
+
+	t.Run("invalid desktop bool", func(t *testing.T) {
+		_, err := vl.parseValuesFromBytes([]byte("notify_desktop = maybe"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid notify_desktop")
+	})
+}
+
+func TestValues_mergeNotifyFrom(t *testing.T) {
+	dst := Values{
+		NotifyWebhookURL:    "http://global/hook",
+		NotifyWebhookEvents: []string{"completed"},
+		NotifyDesktop:       true,
+		NotifyDesktopSet:    true,
+		NotifyCommand:       "global-cmd",
+	}
+	src := Values{
+		NotifyWebhookURL: "http://local/hook",
+		NotifySlackURL:   "http://local/slack",
+		NotifyDesktop:    false,
+		NotifyDesktopSet: true,
+	}
+	dst.mergeFrom(&src)
+
+	assert.Equal(t, "http://local/hook", dst.NotifyWebhookURL)
+	assert.Equal(t, []string{"completed"}, dst.NotifyWebhookEvents, "unset events keep global value")
+	assert.Equal(t, "http://local/slack", dst.NotifySlackURL)
+	assert.False(t, dst.NotifyDesktop, "explicit false overrides")
+	assert.Equal(t, "global-cmd", dst.NotifyCommand)
+}
+
+func TestValuesLoader_parseValuesFromBytes_Hooks(t *testing.T) {
+	vl := &valuesLoader{embedFS: defaultsFS}
+
+	t.Run("all hook settings", func(t *testing.T) {
+		data := []byte(`
+hook_pre_run = docker compose up -d
+hook_pre_task = echo pre $RALPHEX_TASK
+hook_post_task = make generate
+hook_pre_review = make lint
+hook_post_phase = echo $RALPHEX_PHASE
+hook_on_failure = notify-me
+hook_post_run = docker compose down
+hook_on_error = ABORT
+hook_timeout_ms = 0
+`)
+		values, err := vl.parseValuesFromBytes(data)
+		require.NoError(t, err)
+
+		assert.Equal(t, "docker compose up -d", values.HookPreRun)
+		assert.Equal(t, "echo pre $RALPHEX_TASK", values.HookPreTask)
+		assert.Equal(t, "make generate", values.HookPostTask)
+		assert.Equal(t, "make lint", values.HookPreReview)
+		assert.Equal(t, "echo $RALPHEX_PHASE", values.HookPostPhase)
+		assert.Equal(t, "notify-me", values.HookOnFailure)
+		assert.Equal(t, "docker compose down", values.HookPostRun)
+		assert.Equal(t, "abort", values.HookOnError)
+		assert.Equal(t, 0, values.HookTimeoutMs)
+		assert.True(t, values.HookTimeoutMsSet)
+	})
+
+	t.Run("embedded defaults", func(t *testing.T) {
+		values, err := vl.parseValuesFromEmbedded()
+		require.NoError(t, err)
+		assert.Empty(t, values.HookPreRun)
+		assert.Empty(t, values.HookPostTask)
+		assert.Equal(t, "warn", values.HookOnError)
+		assert.Equal(t, 600000, values.HookTimeoutMs)
+	})
+
+	t.Run("invalid on_error", func(t *testing.T) {
+		_, err := vl.parseValuesFromBytes([]byte("hook_on_error = ignore"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid hook_on_error")
+	})
+
+	t.Run("negative timeout", func(t *testing.T) {
+		_, err := vl.parseValuesFromBytes([]byte("hook_timeout_ms = -1"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must be non-negative")
+	})
+}
+
+func TestValues_mergeHooksFrom(t *testing.T) {
+	dst := Values{HookPreRun: "global-pre", HookPostRun: "global-post", HookOnError: "warn", HookTimeoutMs: 1000, HookTimeoutMsSet: true}
+	src := Values{HookPreRun: "local-pre", HookOnError: "abort", HookTimeoutMs: 0, HookTimeoutMsSet: true}
+	dst.mergeFrom(&src)
+
+	assert.Equal(t, "local-pre", dst.HookPreRun)
+	assert.Equal(t, "global-post", dst.HookPostRun)
+	assert.Equal(t, "abort", dst.HookOnError)
+	assert.Equal(t, 0, dst.HookTimeoutMs)
+}
+
 func TestValuesLoader_parseValuesFromFile_PermissionDenied(t *testing.T) {
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "config")
@@ -472,3 +615,440 @@ func TestValuesLoader_parseValuesFromBytes_InvalidINI(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "parse config")
 }
+
+func TestValuesLoader_parseValuesFromBytes_Commit(t *testing.T) {
+	vl := &valuesLoader{embedFS: defaultsFS}
+
+	t.Run("all commit settings", func(t *testing.T) {
+		data := []byte(`
+auto_commit = true
+commit_task_template = feat({{TASK_NUM}}): {{TASK_TITLE}}
+commit_review_template = chore: {{PHASE}} fixes
+commit_trailers = false
+history_rewrite = Per-Task
+task_rollback = true
+`)
+		values, err := vl.parseValuesFromBytes(data)
+		require.NoError(t, err)
+
+		assert.True(t, values.AutoCommit)
+		assert.True(t, values.AutoCommitSet)
+		assert.Equal(t, "feat({{TASK_NUM}}): {{TASK_TITLE}}", values.CommitTaskTemplate)
+		assert.Equal(t, "chore: {{PHASE}} fixes", values.CommitReviewTemplate)
+		assert.False(t, values.CommitTrailers)
+		assert.True(t, values.CommitTrailersSet)
+		assert.Equal(t, "per-task", values.HistoryRewrite)
+		assert.True(t, values.TaskRollback)
+		assert.True(t, values.TaskRollbackSet)
+	})
+
+	t.Run("embedded defaults", func(t *testing.T) {
+		values, err := vl.parseValuesFromEmbedded()
+		require.NoError(t, err)
+		assert.False(t, values.AutoCommit)
+		assert.Equal(t, "feat: task {{TASK_NUM}} - {{TASK_TITLE}}", values.CommitTaskTemplate)
+		assert.Equal(t, "fix: address {{PHASE}} findings", values.CommitReviewTemplate)
+		assert.True(t, values.CommitTrailers)
+		assert.Equal(t, "keep", values.HistoryRewrite)
+		assert.False(t, values.TaskRollback)
+	})
+
+	t.Run("invalid history_rewrite", func(t *testing.T) {
+		_, err := vl.parseValuesFromBytes([]byte("history_rewrite = rebase"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid history_rewrite")
+	})
+
+	t.Run("invalid auto_commit", func(t *testing.T) {
+		_, err := vl.parseValuesFromBytes([]byte("auto_commit = maybe"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid auto_commit")
+	})
+}
+
+func TestValues_mergeCommitFrom(t *testing.T) {
+	dst := Values{AutoCommit: true, AutoCommitSet: true, CommitTaskTemplate: "global", CommitTrailers: true, CommitTrailersSet: true}
+	src := Values{AutoCommit: false, AutoCommitSet: true, CommitReviewTemplate: "local-review"}
+	dst.mergeFrom(&src)
+
+	assert.False(t, dst.AutoCommit)
+	assert.Equal(t, "global", dst.CommitTaskTemplate)
+	assert.Equal(t, "local-review", dst.CommitReviewTemplate)
+	assert.True(t, dst.CommitTrailers)
+}
+
+func TestValuesLoader_parseValuesFromBytes_ReviewAgent(t *testing.T) {
+	vl := &valuesLoader{embedFS: defaultsFS}
+
+	t.Run("process mode", func(t *testing.T) {
+		values, err := vl.parseValuesFromBytes([]byte("review_agent_mode = Process\nreview_agent_concurrency = 5\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "process", values.ReviewAgentMode)
+		assert.Equal(t, 5, values.ReviewAgentConcurrency)
+		assert.True(t, values.ReviewAgentConcurrencySet)
+	})
+
+	t.Run("embedded defaults", func(t *testing.T) {
+		values, err := vl.parseValuesFromEmbedded()
+		require.NoError(t, err)
+		assert.Equal(t, "task", values.ReviewAgentMode)
+		assert.Equal(t, 3, values.ReviewAgentConcurrency)
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		_, err := vl.parseValuesFromBytes([]byte("review_agent_mode = fork"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid review_agent_mode")
+	})
+
+	t.Run("invalid concurrency", func(t *testing.T) {
+		_, err := vl.parseValuesFromBytes([]byte("review_agent_concurrency = 0"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid review_agent_concurrency")
+
+		_, err = vl.parseValuesFromBytes([]byte("review_agent_concurrency = many"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid review_agent_concurrency")
+	})
+}
+
+func TestValues_mergeReviewAgentFrom(t *testing.T) {
+	dst := Values{ReviewAgentMode: "task", ReviewAgentConcurrency: 3, ReviewAgentConcurrencySet: true}
+	src := Values{ReviewAgentMode: "process"}
+	dst.mergeFrom(&src)
+	assert.Equal(t, "process", dst.ReviewAgentMode)
+	assert.Equal(t, 3, dst.ReviewAgentConcurrency)
+
+	src = Values{ReviewAgentConcurrency: 8, ReviewAgentConcurrencySet: true}
+	dst.mergeFrom(&src)
+	assert.Equal(t, "process", dst.ReviewAgentMode)
+	assert.Equal(t, 8, dst.ReviewAgentConcurrency)
+}
+
+func TestValuesLoader_parseValuesFromBytes_ExternalReviewers(t *testing.T) {
+	vl := &valuesLoader{embedFS: defaultsFS}
+
+	values, err := vl.parseValuesFromBytes([]byte("external_reviewers = codex, codex:gpt-5.2-codex-preview , claude:opus"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"codex", "codex:gpt-5.2-codex-preview", "claude:opus"}, values.ExternalReviewers)
+
+	values, err = vl.parseValuesFromEmbedded()
+	require.NoError(t, err)
+	assert.Empty(t, values.ExternalReviewers)
+
+	_, err = vl.parseValuesFromBytes([]byte("external_reviewers = codex, gemini"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown backend "gemini"`)
+
+	dst := Values{ExternalReviewers: []string{"codex"}}
+	dst.mergeFrom(&Values{})
+	assert.Equal(t, []string{"codex"}, dst.ExternalReviewers)
+	dst.mergeFrom(&Values{ExternalReviewers: []string{"claude"}})
+	assert.Equal(t, []string{"claude"}, dst.ExternalReviewers)
+}
+
+func TestValuesLoader_parseValuesFromBytes_CommandExecutors(t *testing.T) {
+	vl := &valuesLoader{embedFS: defaultsFS}
+
+	t.Run("commands and overrides", func(t *testing.T) {
+		data := []byte(`
+command_lint = golangci-lint run ./... 2>&1
+command_llm = wrapper --prompt {{PROMPT_FILE}}
+claude_executor = command:llm
+codex_executor = command:lint
+external_reviewers = codex, command:lint
+`)
+		values, err := vl.parseValuesFromBytes(data)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"lint": "golangci-lint run ./... 2>&1", "llm": "wrapper --prompt {{PROMPT_FILE}}"},
+			values.CommandExecutors)
+		assert.Equal(t, "command:llm", values.ClaudeExecutor)
+		assert.Equal(t, "command:lint", values.CodexExecutor)
+		assert.Equal(t, []string{"codex", "command:lint"}, values.ExternalReviewers)
+	})
+
+	t.Run("embedded defaults", func(t *testing.T) {
+		values, err := vl.parseValuesFromEmbedded()
+		require.NoError(t, err)
+		assert.Empty(t, values.CommandExecutors)
+		assert.Empty(t, values.ClaudeExecutor)
+		assert.Empty(t, values.CodexExecutor)
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		for _, data := range []string{"claude_executor = codex", "codex_executor = command:", "command_x = ",
+			"external_reviewers = command"} {
+			_, err := vl.parseValuesFromBytes([]byte(data))
+			assert.Error(t, err, data)
+		}
+	})
+}
+
+func TestValues_mergeCommandExecutorFrom(t *testing.T) {
+	dst := Values{CommandExecutors: map[string]string{"lint": "global lint", "llm": "global llm"}, ClaudeExecutor: "command:llm"}
+	src := Values{CommandExecutors: map[string]string{"lint": "local lint"}, CodexExecutor: "command:lint"}
+	dst.mergeFrom(&src)
+	assert.Equal(t, map[string]string{"lint": "local lint", "llm": "global llm"}, dst.CommandExecutors)
+	assert.Equal(t, "command:llm", dst.ClaudeExecutor)
+	assert.Equal(t, "command:lint", dst.CodexExecutor)
+}
+
+func TestValuesLoader_parseValuesFromBytes_ClaudeSessionResume(t *testing.T) {
+	vl := &valuesLoader{embedFS: defaultsFS}
+
+	values, err := vl.parseValuesFromBytes([]byte("claude_session_resume = Review, codex"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"review", "codex"}, values.ClaudeSessionResume)
+	assert.True(t, values.ClaudeSessionResumeSet)
+
+	values, err = vl.parseValuesFromEmbedded()
+	require.NoError(t, err)
+	assert.Empty(t, values.ClaudeSessionResume)
+
+	_, err = vl.parseValuesFromBytes([]byte("claude_session_resume = task"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid claude_session_resume")
+
+	dst := Values{ClaudeSessionResume: []string{"review"}, ClaudeSessionResumeSet: true}
+	dst.mergeFrom(&Values{})
+	assert.Equal(t, []string{"review"}, dst.ClaudeSessionResume)
+	dst.mergeFrom(&Values{ClaudeSessionResumeSet: true})
+	assert.Empty(t, dst.ClaudeSessionResume, "explicit empty value resets to fresh sessions")
+}
+
+func TestValuesLoader_parseValuesFromBytes_Limit(t *testing.T) {
+	vl := &valuesLoader{embedFS: defaultsFS}
+
+	t.Run("explicit values", func(t *testing.T) {
+		values, err := vl.parseValuesFromBytes([]byte("limit_retry_count = 0\nlimit_backoff_ms = 5000\nlimit_max_wait_ms = 60000\n"))
+		require.NoError(t, err)
+		assert.Equal(t, 0, values.LimitRetryCount)
+		assert.True(t, values.LimitRetryCountSet)
+		assert.Equal(t, 5000, values.LimitBackoffMs)
+		assert.True(t, values.LimitBackoffMsSet)
+		assert.Equal(t, 60000, values.LimitMaxWaitMs)
+		assert.True(t, values.LimitMaxWaitMsSet)
+	})
+
+	t.Run("embedded defaults", func(t *testing.T) {
+		values, err := vl.parseValuesFromEmbedded()
+		require.NoError(t, err)
+		assert.Equal(t, 10, values.LimitRetryCount)
+		assert.Equal(t, 60000, values.LimitBackoffMs)
+		assert.Equal(t, 21600000, values.LimitMaxWaitMs)
+		assert.Equal(t, 1800000, values.StallTimeoutMs)
+		assert.Equal(t, 2, values.StallRetryCount)
+	})
+
+	t.Run("stall values", func(t *testing.T) {
+		values, err := vl.parseValuesFromBytes([]byte("stall_timeout_ms = 0\nstall_retry_count = 5\n"))
+		require.NoError(t, err)
+		assert.Equal(t, 0, values.StallTimeoutMs)
+		assert.True(t, values.StallTimeoutMsSet)
+		assert.Equal(t, 5, values.StallRetryCount)
+		assert.True(t, values.StallRetryCountSet)
+
+		_, err = vl.parseValuesFromBytes([]byte("stall_timeout_ms = -5"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid stall_timeout_ms")
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		_, err := vl.parseValuesFromBytes([]byte("limit_retry_count = -1"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid limit_retry_count")
+
+		_, err = vl.parseValuesFromBytes([]byte("limit_backoff_ms = soon"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid limit_backoff_ms")
+	})
+}
+
+func TestValues_mergeRecoveryFrom(t *testing.T) {
+	dst := Values{LimitRetryCount: 10, LimitRetryCountSet: true, LimitBackoffMs: 60000, LimitBackoffMsSet: true}
+	src := Values{LimitRetryCount: 0, LimitRetryCountSet: true}
+	dst.mergeFrom(&src)
+	assert.Equal(t, 0, dst.LimitRetryCount)
+	assert.Equal(t, 60000, dst.LimitBackoffMs)
+
+	src = Values{LimitMaxWaitMs: 1000, LimitMaxWaitMsSet: true}
+	dst.mergeFrom(&src)
+	assert.Equal(t, 0, dst.LimitRetryCount)
+	assert.Equal(t, 1000, dst.LimitMaxWaitMs)
+	assert.True(t, dst.LimitMaxWaitMsSet)
+
+	src = Values{StallTimeoutMs: 0, StallTimeoutMsSet: true, StallRetryCount: 4, StallRetryCountSet: true}
+	dst.StallTimeoutMs, dst.StallTimeoutMsSet = 1800000, true
+	dst.mergeFrom(&src)
+	assert.Equal(t, 0, dst.StallTimeoutMs)
+	assert.Equal(t, 4, dst.StallRetryCount)
+}
+
+func TestValuesLoader_parseValuesFromBytes_Container(t *testing.T) {
+	vl := &valuesLoader{embedFS: defaultsFS}
+
+	t.Run("embedded defaults disable container mode", func(t *testing.T) {
+		values, err := vl.parseValuesFromEmbedded()
+		require.NoError(t, err)
+		assert.Empty(t, values.ContainerRuntime)
+		assert.Empty(t, values.ContainerImage)
+	})
+
+	t.Run("explicit values", func(t *testing.T) {
+		data := `container_runtime = podman
+container_image = ghcr.io/acme/claude:1.0
+container_network = none
+container_env = CLAUDE_CODE_OAUTH_TOKEN, HTTPS_PROXY
+container_mounts = ~/.claude:/home/node/.claude, /etc/ca.pem:/etc/ca.pem:ro
+container_args = --memory 4g
+`
+		values, err := vl.parseValuesFromBytes([]byte(data))
+		require.NoError(t, err)
+		assert.Equal(t, "podman", values.ContainerRuntime)
+		assert.Equal(t, "ghcr.io/acme/claude:1.0", values.ContainerImage)
+		assert.Equal(t, "none", values.ContainerNetwork)
+		assert.Equal(t, []string{"CLAUDE_CODE_OAUTH_TOKEN", "HTTPS_PROXY"}, values.ContainerEnv)
+		assert.Equal(t, []string{"~/.claude:/home/node/.claude", "/etc/ca.pem:/etc/ca.pem:ro"}, values.ContainerMounts)
+		assert.Equal(t, "--memory 4g", values.ContainerArgs)
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		tests := map[string]string{
+			"container_runtime = lxc":        "invalid container_runtime",
+			"container_network = a b":        "invalid container_network",
+			"container_env = FOO, 1BAR":      "invalid container_env",
+			"container_mounts = /host/only":  "invalid container_mounts",
+			"container_mounts = :/container": "invalid container_mounts",
+		}
+		for data, want := range tests {
+			_, err := vl.parseValuesFromBytes([]byte(data))
+			require.Error(t, err, data)
+			assert.Contains(t, err.Error(), want)
+		}
+	})
+}
+
+func TestValuesLoader_Load_ContainerRequiresImage(t *testing.T) {
+	tmpDir := t.TempDir()
+	globalConfig := filepath.Join(tmpDir, "global")
+	localConfig := filepath.Join(tmpDir, "local")
+	require.NoError(t, os.WriteFile(globalConfig, []byte("container_image = acme/claude\n"), 0o600))
+	require.NoError(t, os.WriteFile(localConfig, []byte("container_runtime = docker\n"), 0o600))
+
+	loader := newValuesLoader(defaultsFS)
+	values, err := loader.Load(localConfig, globalConfig)
+	require.NoError(t, err)
+	assert.Equal(t, "docker", values.ContainerRuntime)
+	assert.Equal(t, "acme/claude", values.ContainerImage)
+
+	_, err = loader.Load(localConfig, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "container_runtime docker requires container_image")
+}
+
+func TestValues_mergeContainerFrom(t *testing.T) {
+	dst := Values{ContainerRuntime: "docker", ContainerImage: "a", ContainerEnv: []string{"A"}}
+	src := Values{ContainerImage: "b", ContainerMounts: []string{"/x:/y"}}
+	dst.mergeFrom(&src)
+	assert.Equal(t, "docker", dst.ContainerRuntime)
+	assert.Equal(t, "b", dst.ContainerImage)
+	assert.Equal(t, []string{"A"}, dst.ContainerEnv)
+	assert.Equal(t, []string{"/x:/y"}, dst.ContainerMounts)
+}
+
+func TestValuesLoader_parseValuesFromBytes_Env(t *testing.T) {
+	vl := &valuesLoader{embedFS: defaultsFS}
+
+	t.Run("embedded defaults", func(t *testing.T) {
+		values, err := vl.parseValuesFromEmbedded()
+		require.NoError(t, err)
+		assert.True(t, values.MaskSecrets)
+		assert.True(t, values.MaskSecretsSet)
+		assert.Empty(t, values.ClaudeEnvAllow)
+		assert.Empty(t, values.EnvFile)
+	})
+
+	t.Run("explicit values", func(t *testing.T) {
+		data := `claude_env_allow = PATH, HOME, GIT_*
+claude_env_deny = AWS_*
+claude_env = MAX_THINKING_TOKENS=16000, FOO=a=b
+codex_env_deny = GITHUB_TOKEN
+codex_env = OPENAI_BASE_URL=http://proxy
+env_file = .env.local
+mask_secrets = false
+mask_env = DATABASE_URL
+`
+		values, err := vl.parseValuesFromBytes([]byte(data))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"PATH", "HOME", "GIT_*"}, values.ClaudeEnvAllow)
+		assert.Equal(t, []string{"AWS_*"}, values.ClaudeEnvDeny)
+		assert.Equal(t, []string{"MAX_THINKING_TOKENS=16000", "FOO=a=b"}, values.ClaudeEnv)
+		assert.Equal(t, []string{"GITHUB_TOKEN"}, values.CodexEnvDeny)
+		assert.Equal(t, []string{"OPENAI_BASE_URL=http://proxy"}, values.CodexEnv)
+		assert.Equal(t, ".env.local", values.EnvFile)
+		assert.False(t, values.MaskSecrets)
+		assert.True(t, values.MaskSecretsSet)
+		assert.Equal(t, []string{"DATABASE_URL"}, values.MaskEnv)
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		tests := map[string]string{
+			"claude_env_allow = PATH, *": "invalid claude_env_allow",
+			"codex_env_deny = BAD-NAME":  "invalid codex_env_deny",
+			"claude_env = NOVALUE":       "invalid claude_env",
+			"codex_env = 1X=2":           "invalid codex_env",
+			"mask_secrets = sometimes":   "invalid mask_secrets",
+			"mask_env = DATABASE URL":    "invalid mask_env",
+		}
+		for data, want := range tests {
+			_, err := vl.parseValuesFromBytes([]byte(data))
+			require.Error(t, err, data)
+			assert.Contains(t, err.Error(), want)
+		}
+	})
+}
+
+func TestValues_mergeEnvFrom(t *testing.T) {
+	dst := Values{ClaudeEnvDeny: []string{"AWS_*"}, EnvFile: ".env", MaskSecrets: true, MaskSecretsSet: true}
+	src := Values{ClaudeEnv: []string{"A=1"}, MaskSecrets: false, MaskSecretsSet: true}
+	dst.mergeFrom(&src)
+	assert.Equal(t, []string{"AWS_*"}, dst.ClaudeEnvDeny)
+	assert.Equal(t, []string{"A=1"}, dst.ClaudeEnv)
+	assert.Equal(t, ".env", dst.EnvFile)
+	assert.False(t, dst.MaskSecrets)
+}
+
+func TestValuesLoader_parseValuesFromBytes_Trace(t *testing.T) {
+	vl := &valuesLoader{embedFS: defaultsFS}
+
+	t.Run("embedded defaults disable tracing", func(t *testing.T) {
+		values, err := vl.parseValuesFromEmbedded()
+		require.NoError(t, err)
+		assert.Empty(t, values.TraceFile)
+		assert.Empty(t, values.TraceEndpoint)
+	})
+
+	t.Run("explicit values", func(t *testing.T) {
+		data := "trace_file = .ralphex/trace.jsonl\ntrace_endpoint = http://localhost:4318/\n"
+		values, err := vl.parseValuesFromBytes([]byte(data))
+		require.NoError(t, err)
+		assert.Equal(t, ".ralphex/trace.jsonl", values.TraceFile)
+		assert.Equal(t, "http://localhost:4318", values.TraceEndpoint, "trailing slash trimmed")
+	})
+
+	t.Run("invalid endpoint", func(t *testing.T) {
+		for _, data := range []string{"trace_endpoint = localhost:4318", "trace_endpoint = grpc://localhost:4317"} {
+			_, err := vl.parseValuesFromBytes([]byte(data))
+			require.Error(t, err, data)
+			assert.Contains(t, err.Error(), "invalid trace_endpoint")
+		}
+	})
+}
+
+func TestValues_mergeTraceFrom(t *testing.T) {
+	dst := Values{TraceFile: "a.jsonl", TraceEndpoint: "http://a:4318"}
+	src := Values{TraceEndpoint: "https://b:4318"}
+	dst.mergeFrom(&src)
+	assert.Equal(t, "a.jsonl", dst.TraceFile)
+	assert.Equal(t, "https://b:4318", dst.TraceEndpoint)
+}
@@ -0,0 +1,130 @@
+package executor
+
+import (
+	"cmp"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+)
+
+// maxActivityDetail limits the length of activity details such as bash commands.
+const maxActivityDetail = 120
+
+// fileTools are claude tools whose activity target is a file path.
+var fileTools = map[string]bool{"Read": true, "Edit": true, "MultiEdit": true, "Write": true, "NotebookEdit": true}
+
+// modifyingTools are file tools that change the file.
+var modifyingTools = map[string]bool{"Edit": true, "MultiEdit": true, "Write": true, "NotebookEdit": true}
+
+// Activity is a compact description of a claude tool call, e.g. "Edit pkg/foo.go" or "Bash: go test ./...".
+type Activity struct {
+	Tool   string // tool name, "Error" for failed tool results
+	Detail string // file path for file tools, otherwise command, pattern, description or error text
+}
+
+// String formats the activity as "Tool path" for file tools, "Tool: detail" for other tools with
+// details and "Tool" otherwise. ParseActivity reverses it.
+func (a Activity) String() string {
+	switch {
+	case a.Detail == "":
+		return a.Tool
+	case fileTools[a.Tool]:
+		return a.Tool + " " + a.Detail
+	default:
+		return a.Tool + ": " + a.Detail
+	}
+}
+
+// File returns the file path for file tools, empty otherwise.
+func (a Activity) File() string {
+	if fileTools[a.Tool] {
+		return a.Detail
+	}
+	return ""
+}
+
+// Modifies returns true when the activity changes a file.
+func (a Activity) Modifies() bool {
+	return modifyingTools[a.Tool] && a.Detail != ""
+}
+
+// ParseActivity parses text produced by Activity.String.
+func ParseActivity(text string) Activity {
+	tool, rest, found := strings.Cut(strings.TrimSpace(text), " ")
+	if !found {
+		return Activity{Tool: strings.TrimSuffix(tool, ":")}
+	}
+	if name, ok := strings.CutSuffix(tool, ":"); ok {
+		return Activity{Tool: name, Detail: rest}
+	}
+	return Activity{Tool: tool, Detail: rest}
+}
+
+// toolInput holds tool_use input fields used to describe the call.
+type toolInput struct {
+	FilePath     string `json:"file_path"`
+	NotebookPath string `json:"notebook_path"`
+	Command      string `json:"command"`
+	Pattern      string `json:"pattern"`
+	Description  string `json:"description"`
+	SubagentType string `json:"subagent_type"`
+	URL          string `json:"url"`
+	Query        string `json:"query"`
+}
+
+// toolUseActivity builds the activity for a tool_use content block.
+func toolUseActivity(name string, input json.RawMessage) Activity {
+	var in toolInput
+	if len(input) > 0 {
+		_ = json.Unmarshal(input, &in) //nolint:errcheck // unknown input shape just means no detail
+	}
+	var detail string
+	switch {
+	case fileTools[name]:
+		detail = relPath(cmp.Or(in.FilePath, in.NotebookPath))
+	case name == "Task":
+		detail = cmp.Or(in.Description, in.SubagentType)
+	default:
+		detail = cmp.Or(in.Command, in.Pattern, in.Description, in.URL, in.Query)
+	}
+	return Activity{Tool: name, Detail: compactDetail(detail)}
+}
+
+// toolErrorActivity builds the activity for a failed tool_result content block.
+func toolErrorActivity(content json.RawMessage) Activity {
+	var text string
+	if err := json.Unmarshal(content, &text); err != nil {
+		var blocks []struct {
+			Text string `json:"text"`
+		}
+		if json.Unmarshal(content, &blocks) == nil && len(blocks) > 0 {
+			text = blocks[0].Text
+		}
+	}
+	return Activity{Tool: "Error", Detail: compactDetail(text)}
+}
+
+// compactDetail keeps the first line of s, truncated to maxActivityDetail runes.
+func compactDetail(s string) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	s = strings.TrimSpace(s)
+	if r := []rune(s); len(r) > maxActivityDetail {
+		return string(r[:maxActivityDetail-3]) + "..."
+	}
+	return s
+}
+
+// relPath returns path relative to the working directory when it is inside it.
+func relPath(path string) string {
+	if path == "" || !filepath.IsAbs(path) {
+		return path
+	}
+	wd, err := filepath.Abs(".")
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
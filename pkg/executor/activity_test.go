@@ -0,0 +1,72 @@
+package executor
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaudeExecutor_parseStream_Activities(t *testing.T) {
+	wd, err := filepath.Abs(".")
+	require.NoError(t, err)
+	input := strings.Join([]string{
+		`{"type":"assistant","message":{"content":[{"type":"text","text":"fixing"},` +
+			`{"type":"tool_use","name":"Edit","input":{"file_path":"` + filepath.Join(wd, "pkg", "foo.go") + `","old_string":"a"}}]}}`,
+		`{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Bash","input":{"command":"go test ./...\ngo vet ./..."}}]}}`,
+		`{"type":"user","message":{"content":[{"type":"tool_result","is_error":true,"content":"exit status 1\nFAIL"}]}}`,
+		`{"type":"user","message":{"content":[{"type":"tool_result","content":"ok"}]}}`,
+		`{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Task","input":{"description":"quality agent","prompt":"..."}}]}}`,
+		`{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Grep","input":{"pattern":"func New"}}]}}`,
+		`{"type":"assistant","message":{"content":[{"type":"tool_use","name":"TodoWrite","input":{"todos":[]}}]}}`,
+		`{"type":"user","message":{"content":[{"type":"tool_result","is_error":true,"content":[{"type":"text","text":"file not found"}]}]}}`,
+	}, "\n")
+
+	var activities []string
+	e := &ClaudeExecutor{ActivityHandler: func(a Activity) { activities = append(activities, a.String()) }}
+	result, _ := e.parseStream(strings.NewReader(input))
+
+	assert.Equal(t, "fixing", result.Output)
+	assert.Equal(t, []string{
+		"Edit " + filepath.Join("pkg", "foo.go"),
+		"Bash: go test ./...",
+		"Error: exit status 1",
+		"Task: quality agent",
+		"Grep: func New",
+		"TodoWrite",
+		"Error: file not found",
+	}, activities)
+}
+
+func TestActivity(t *testing.T) {
+	tests := []struct {
+		activity Activity
+		text     string
+		file     string
+		modifies bool
+	}{
+		{activity: Activity{Tool: "Edit", Detail: "pkg/foo.go"}, text: "Edit pkg/foo.go", file: "pkg/foo.go", modifies: true},
+		{activity: Activity{Tool: "Read", Detail: "my dir/a.go"}, text: "Read my dir/a.go", file: "my dir/a.go"},
+		{activity: Activity{Tool: "Bash", Detail: "go test ./..."}, text: "Bash: go test ./..."},
+		{activity: Activity{Tool: "TodoWrite"}, text: "TodoWrite"},
+		{activity: Activity{Tool: "Error", Detail: "exit status 1"}, text: "Error: exit status 1"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.text, func(t *testing.T) {
+			assert.Equal(t, tc.text, tc.activity.String())
+			assert.Equal(t, tc.activity, ParseActivity(tc.text))
+			assert.Equal(t, tc.file, tc.activity.File())
+			assert.Equal(t, tc.modifies, tc.activity.Modifies())
+		})
+	}
+}
+
+func TestCompactDetail(t *testing.T) {
+	assert.Equal(t, "first", compactDetail("  first\nsecond"))
+	long := strings.Repeat("x", 200)
+	got := compactDetail(long)
+	assert.Len(t, got, maxActivityDetail)
+	assert.True(t, strings.HasSuffix(got, "..."))
+}
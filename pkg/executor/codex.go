@@ -6,8 +6,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
 	"strings"
+	"time"
 )
 
 // CodexStreams holds both stderr and stdout from codex command.
@@ -24,7 +26,9 @@ type CodexRunner interface {
 
 // execCodexRunner is the default command runner using os/exec for codex.
 // codex outputs streaming progress to stderr, final response to stdout.
-type execCodexRunner struct{}
+type execCodexRunner struct {
+	env EnvPolicy // applied to the inherited environment
+}
 
 func (r *execCodexRunner) Run(ctx context.Context, name string, args ...string) (CodexStreams, func() error, error) {
 	// check context before starting to avoid spawning a process that will be immediately killed
@@ -35,6 +39,7 @@ func (r *execCodexRunner) Run(ctx context.Context, name string, args ...string)
 	// use exec.Command (not CommandContext) because we handle cancellation ourselves
 	// to ensure the entire process group is killed, not just the direct child
 	cmd := exec.Command(name, args...) //nolint:noctx // intentional: we handle context cancellation via process group kill
+	cmd.Env = r.env.Apply(os.Environ())
 
 	// create new process group so we can kill all descendants on cleanup
 	setupProcessGroup(cmd)
@@ -68,6 +73,8 @@ type CodexExecutor struct {
 	Sandbox         string            // sandbox mode, defaults to "read-only"
 	ProjectDoc      string            // path to project documentation file
 	OutputHandler   func(text string) // called for each filtered output line in real-time
+	IdleTimeout     time.Duration     // kill the process after this long without output, 0 disables
+	Env             EnvPolicy         // environment filtering and extra variables
 	Debug           bool              // enable debug output
 	runner          CodexRunner       // for testing, nil uses default
 }
@@ -123,10 +130,16 @@ func (e *CodexExecutor) Run(ctx context.Context, prompt string) Result {
 
 	runner := e.runner
 	if runner == nil {
-		runner = &execCodexRunner{}
+		runner = &execCodexRunner{env: e.Env}
 	}
 
-	streams, wait, err := runner.Run(ctx, cmd, args...)
+	// the watchdog cancels runCtx, which kills the process group through the runner's cleanup
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	watchdog := newIdleWatchdog(e.IdleTimeout, cancel)
+	defer watchdog.stop()
+
+	streams, wait, err := runner.Run(runCtx, cmd, args...)
 	if err != nil {
 		return Result{Error: fmt.Errorf("start codex: %w", err)}
 	}
@@ -134,11 +147,11 @@ func (e *CodexExecutor) Run(ctx context.Context, prompt string) Result {
 	// process stderr for progress display (header block + bold summaries)
 	stderrDone := make(chan error, 1)
 	go func() {
-		stderrDone <- e.processStderr(ctx, streams.Stderr)
+		stderrDone <- e.processStderr(ctx, watchdog.watch(streams.Stderr))
 	}()
 
 	// read stdout entirely as final response
-	stdoutContent, stdoutErr := e.readStdout(streams.Stdout)
+	stdoutContent, stdoutErr := e.readStdout(watchdog.watch(streams.Stdout))
 
 	// wait for stderr processing to complete
 	stderrErr := <-stderrDone
@@ -146,9 +159,18 @@ func (e *CodexExecutor) Run(ctx context.Context, prompt string) Result {
 	// wait for command completion
 	waitErr := wait()
 
-	// determine final error (prefer stderr/stdout errors over wait error)
+	// a limit codex reported on stderr but recovered from (clean exit) doesn't fail the run
+	var limitErr *LimitError
+	if waitErr == nil && errors.As(stderrErr, &limitErr) {
+		stderrErr = nil
+	}
+
+	// determine final error (prefer stall, stderr and stdout errors over wait error)
 	var finalErr error
+	stallErr := watchdog.stallError()
 	switch {
+	case stallErr != nil && ctx.Err() == nil:
+		finalErr = stallErr
 	case stderrErr != nil && !errors.Is(stderrErr, context.Canceled):
 		finalErr = stderrErr
 	case stdoutErr != nil:
@@ -170,8 +192,10 @@ func (e *CodexExecutor) Run(ctx context.Context, prompt string) Result {
 
 // processStderr reads stderr line-by-line, filters for progress display.
 // shows header block (between first two "--------" separators) and bold summaries.
+// returns *LimitError when codex reports a rate limit, usage limit or overload.
 func (e *CodexExecutor) processStderr(ctx context.Context, r io.Reader) error {
 	state := &codexFilterState{}
+	var limit *LimitError
 	scanner := bufio.NewScanner(r)
 	// increase buffer size for large output lines
 	buf := make([]byte, 0, 64*1024)
@@ -190,11 +214,19 @@ func (e *CodexExecutor) processStderr(ctx context.Context, r io.Reader) error {
 				e.OutputHandler(filtered + "\n")
 			}
 		}
+		if isCodexErrorLine(line) {
+			if l := detectLimit(line, time.Now()); l != nil {
+				limit = l
+			}
+		}
 	}
 
 	if err := scanner.Err(); err != nil {
 		return fmt.Errorf("read stderr: %w", err)
 	}
+	if limit != nil {
+		return limit
+	}
 	return nil
 }
 
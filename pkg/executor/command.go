@@ -0,0 +1,155 @@
+package executor
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// promptFilePlaceholder in a command line is replaced with the path of a temp file holding the prompt.
+const promptFilePlaceholder = "{{PROMPT_FILE}}"
+
+// commandWaitDelay bounds the wait for output after the command exits, background processes it
+// started may keep the output pipe open.
+const commandWaitDelay = 5 * time.Second
+
+// CommandExecutor runs an arbitrary shell command (via sh -c) as an executor, e.g. an in-house
+// static analyser or LLM wrapper. the prompt goes to stdin, or when the command contains
+// {{PROMPT_FILE}} it is written to a temp file whose path replaces the placeholder and is also
+// exported as RALPHEX_PROMPT_FILE. stdout and stderr are merged and streamed line by line.
+// the environment is filtered by Env and the command is killed after IdleTimeout without output.
+type CommandExecutor struct {
+	Command       string            // shell command to execute
+	OutputHandler func(text string) // called for each output line in real-time, can be nil
+	IdleTimeout   time.Duration     // kill the command after this long without output, 0 disables
+	Env           EnvPolicy         // environment filtering and extra variables
+	Debug         bool              // enable debug output
+	waitDelay     time.Duration     // for testing, zero uses commandWaitDelay
+}
+
+// Run executes the command with the given prompt and collects its output.
+// a non-zero exit is reported as an error only when the command printed nothing,
+// analysers commonly exit non-zero when they report findings.
+func (e *CommandExecutor) Run(ctx context.Context, prompt string) Result {
+	if strings.TrimSpace(e.Command) == "" {
+		return Result{Error: fmt.Errorf("command executor: empty command")}
+	}
+	if err := ctx.Err(); err != nil {
+		return Result{Error: fmt.Errorf("context already canceled: %w", err)}
+	}
+
+	command := e.Command
+	env := e.Env.Apply(os.Environ())
+	var stdin io.Reader = strings.NewReader(prompt)
+	if strings.Contains(command, promptFilePlaceholder) {
+		path, err := writePromptFile(prompt)
+		if err != nil {
+			return Result{Error: err}
+		}
+		defer os.Remove(path)
+		command = strings.ReplaceAll(command, promptFilePlaceholder, shellQuote(path))
+		env = append(env, "RALPHEX_PROMPT_FILE="+path)
+		stdin = nil
+	}
+	if e.Debug {
+		fmt.Printf("[debug] command executor: %s\n", command)
+	}
+
+	// use exec.Command (not CommandContext) because we handle cancellation ourselves
+	// to ensure the entire process group is killed, not just the shell
+	cmd := exec.Command("sh", "-c", command) //nolint:noctx,gosec // command is from user config, cancellation via process group kill
+	cmd.Env = env
+	cmd.Stdin = stdin
+	cmd.WaitDelay = cmp.Or(e.waitDelay, commandWaitDelay)
+	setupProcessGroup(cmd)
+
+	// the watchdog cancels runCtx, which kills the process group through the cleanup
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	watchdog := newIdleWatchdog(e.IdleTimeout, cancel)
+	defer watchdog.stop()
+
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	if err := cmd.Start(); err != nil {
+		return Result{Error: fmt.Errorf("start command: %w", err)}
+	}
+	cleanup := newProcessGroupCleanup(cmd, runCtx.Done())
+	go func() {
+		pw.CloseWithError(cleanup.Wait())
+	}()
+
+	result := e.readOutput(watchdog.watch(pr))
+	waitErr := result.Error
+	result.Error = nil
+	if stallErr := watchdog.stallError(); stallErr != nil && ctx.Err() == nil {
+		return Result{Output: result.Output, Error: stallErr}
+	}
+	if waitErr != nil {
+		if ctx.Err() != nil {
+			return Result{Output: result.Output, Signal: result.Signal, Error: ctx.Err()}
+		}
+		if result.Output == "" {
+			return Result{Error: fmt.Errorf("command exited with error: %w", waitErr)}
+		}
+	}
+	return result
+}
+
+// readOutput streams lines to OutputHandler, accumulating output and detecting signals.
+// the returned error is the one the writer closed the pipe with, i.e. the command exit status.
+func (e *CommandExecutor) readOutput(r io.Reader) Result {
+	var output strings.Builder
+	var signal string
+
+	scanner := bufio.NewScanner(r)
+	buf := make([]byte, 0, 64*1024)
+	scanner.Buffer(buf, maxScannerBuffer)
+	for scanner.Scan() {
+		line := scanner.Text()
+		output.WriteString(line)
+		output.WriteString("\n")
+		if e.OutputHandler != nil {
+			e.OutputHandler(line + "\n")
+		}
+		if sig := detectSignal(line); sig != "" {
+			signal = sig
+		}
+	}
+	err := scanner.Err()
+	if err != nil {
+		// drain the rest so the command is not blocked writing to a full pipe
+		_, _ = io.Copy(io.Discard, r) //nolint:errcheck // best-effort drain, error already captured
+	}
+	return Result{Output: strings.TrimRight(output.String(), "\n"), Signal: signal, Error: err}
+}
+
+// writePromptFile stores the prompt in a temp file and returns its path.
+func writePromptFile(prompt string) (string, error) {
+	f, err := os.CreateTemp("", "ralphex-prompt-*.txt")
+	if err != nil {
+		return "", fmt.Errorf("create prompt file: %w", err)
+	}
+	if _, err := f.WriteString(prompt); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return "", fmt.Errorf("write prompt file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return "", fmt.Errorf("close prompt file: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// shellQuote wraps s in single quotes for safe use in a sh command line.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
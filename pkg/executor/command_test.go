@@ -0,0 +1,102 @@
+//go:build unix
+
+package executor
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandExecutor_Run(t *testing.T) {
+	t.Run("prompt on stdin with streamed output", func(t *testing.T) {
+		var lines []string
+		e := &CommandExecutor{Command: `tr a-z A-Z; echo "<<<RALPHEX:REVIEW_DONE>>>"`,
+			OutputHandler: func(text string) { lines = append(lines, text) }}
+		res := e.Run(context.Background(), "review this\nplease\n")
+		require.NoError(t, res.Error)
+		assert.Equal(t, "REVIEW THIS\nPLEASE\n<<<RALPHEX:REVIEW_DONE>>>", res.Output)
+		assert.Equal(t, "<<<RALPHEX:REVIEW_DONE>>>", res.Signal)
+		assert.Equal(t, []string{"REVIEW THIS\n", "PLEASE\n", "<<<RALPHEX:REVIEW_DONE>>>\n"}, lines)
+	})
+
+	t.Run("prompt in temp file", func(t *testing.T) {
+		e := &CommandExecutor{Command: `cat {{PROMPT_FILE}}; test "$RALPHEX_PROMPT_FILE" != "" && echo env-set`}
+		res := e.Run(context.Background(), "from file\n")
+		require.NoError(t, res.Error)
+		assert.Equal(t, "from file\nenv-set", res.Output)
+	})
+
+	t.Run("temp file removed after run", func(t *testing.T) {
+		e := &CommandExecutor{Command: `echo {{PROMPT_FILE}}`}
+		res := e.Run(context.Background(), "x")
+		require.NoError(t, res.Error)
+		_, err := os.Stat(res.Output)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("stderr merged, non-zero exit with output is not an error", func(t *testing.T) {
+		e := &CommandExecutor{Command: `echo "main.go:1 finding" >&2; exit 1`}
+		res := e.Run(context.Background(), "")
+		require.NoError(t, res.Error)
+		assert.Equal(t, "main.go:1 finding", res.Output)
+	})
+
+	t.Run("non-zero exit without output", func(t *testing.T) {
+		e := &CommandExecutor{Command: `exit 3`}
+		res := e.Run(context.Background(), "")
+		require.Error(t, res.Error)
+		assert.Contains(t, res.Error.Error(), "command exited with error")
+	})
+
+	t.Run("empty command", func(t *testing.T) {
+		res := (&CommandExecutor{}).Run(context.Background(), "")
+		require.Error(t, res.Error)
+	})
+
+	t.Run("canceled context kills command", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		res := (&CommandExecutor{Command: `echo started; sleep 30`}).Run(ctx, "")
+		require.ErrorIs(t, res.Error, context.DeadlineExceeded)
+		assert.Equal(t, "started", res.Output)
+		assert.Less(t, time.Since(start), 10*time.Second)
+	})
+
+	t.Run("env policy applied", func(t *testing.T) {
+		t.Setenv("RALPHEX_TEST_SECRET", "s3cret")
+		e := &CommandExecutor{Command: `echo "secret=$RALPHEX_TEST_SECRET extra=$RALPHEX_TEST_EXTRA"`,
+			Env: EnvPolicy{Deny: []string{"RALPHEX_TEST_SECRET"}, Extra: []string{"RALPHEX_TEST_EXTRA=1"}}}
+		res := e.Run(context.Background(), "")
+		require.NoError(t, res.Error)
+		assert.Equal(t, "secret= extra=1", res.Output)
+	})
+
+	t.Run("idle command killed", func(t *testing.T) {
+		start := time.Now()
+		res := (&CommandExecutor{Command: `echo started; sleep 30`, IdleTimeout: 200 * time.Millisecond}).
+			Run(context.Background(), "")
+		var stall *StallError
+		require.ErrorAs(t, res.Error, &stall)
+		assert.Equal(t, "started", res.Output)
+		assert.Less(t, time.Since(start), 10*time.Second)
+	})
+
+	t.Run("background process holding output does not block", func(t *testing.T) {
+		start := time.Now()
+		res := (&CommandExecutor{Command: `sleep 5 & echo done`, waitDelay: 200 * time.Millisecond}).
+			Run(context.Background(), "")
+		assert.Equal(t, "done", res.Output)
+		assert.Less(t, time.Since(start), 3*time.Second)
+	})
+}
+
+func TestShellQuote(t *testing.T) {
+	assert.Equal(t, `'plain'`, shellQuote("plain"))
+	assert.Equal(t, `'it'\''s'`, shellQuote("it's"))
+}
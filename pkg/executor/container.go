@@ -0,0 +1,144 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// containerStopTimeout bounds the container removal after the run is canceled.
+const containerStopTimeout = 30 * time.Second
+
+// containerSeq makes container names unique within the process.
+var containerSeq atomic.Int64
+
+// ContainerConfig describes the container claude runs in when container mode is enabled.
+type ContainerConfig struct {
+	Runtime string   // container CLI, docker or podman (or a compatible binary)
+	Image   string   // image with the claude CLI installed
+	Network string   // value for --network (none, bridge, host or a named network), empty uses the runtime default
+	Env     []string // names of environment variables passed through from the host
+	Mounts  []string // extra bind mounts as host:container[:options], e.g. credentials, ~ expands to the home dir
+	Args    string   // extra arguments for the run command, e.g. --user or --memory
+	Workdir string   // host directory mounted at the same path and used as working directory, empty uses cwd
+	Repos   []string // roots of additional repositories of a multi-repo plan, mounted at the same paths
+}
+
+// containerRunner runs commands inside a fresh container via "<runtime> run --rm".
+// the repository is bind-mounted at the same path, so file paths in prompts and output stay valid.
+// cancellation kills the runtime client's process group and force-removes the container,
+// which the client alone would leave running after SIGKILL.
+type containerRunner struct {
+	cfg    ContainerConfig
+	env    EnvPolicy     // applied to the runtime client, extra variables are passed into the container
+	runner CommandRunner // runs the container CLI, execClaudeRunner if nil
+}
+
+// Run starts name with args in a new container and streams its merged output.
+func (r *containerRunner) Run(ctx context.Context, name string, args ...string) (io.Reader, func() error, error) {
+	runArgs, containerName, err := r.runArgs(name, args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	runner := r.runner
+	if runner == nil {
+		runner = &execClaudeRunner{env: r.env}
+	}
+	output, wait, err := runner.Run(ctx, r.cfg.Runtime, runArgs...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("start container: %w", err)
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-ctx.Done():
+			r.remove(containerName)
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	return output, func() error {
+		err := wait()
+		once.Do(func() { close(done) })
+		<-stopped
+		return err
+	}, nil
+}
+
+// runArgs builds the runtime's run arguments for name and args and returns them with the container name.
+func (r *containerRunner) runArgs(name string, args []string) (runArgs []string, containerName string, err error) {
+	if r.cfg.Image == "" {
+		return nil, "", fmt.Errorf("container mode: no image configured")
+	}
+	workdir := r.cfg.Workdir
+	if workdir == "" {
+		if workdir, err = os.Getwd(); err != nil {
+			return nil, "", fmt.Errorf("get working directory: %w", err)
+		}
+	}
+
+	containerName = fmt.Sprintf("ralphex-%d-%d", os.Getpid(), containerSeq.Add(1))
+	runArgs = []string{"run", "--rm", "--init", "--name", containerName,
+		"-v", workdir + ":" + workdir, "-w", workdir}
+	for _, repo := range r.cfg.Repos {
+		runArgs = append(runArgs, "-v", repo+":"+repo)
+	}
+	if r.cfg.Network != "" {
+		runArgs = append(runArgs, "--network", r.cfg.Network)
+	}
+	for _, key := range r.cfg.Env {
+		// value comes from the runtime client's environment, which already has ANTHROPIC_API_KEY removed
+		runArgs = append(runArgs, "-e", key)
+	}
+	for _, key := range r.env.ExtraNames() {
+		if !slices.Contains(r.cfg.Env, key) {
+			runArgs = append(runArgs, "-e", key)
+		}
+	}
+	for _, m := range r.cfg.Mounts {
+		mount, mountErr := expandMount(m)
+		if mountErr != nil {
+			return nil, "", mountErr
+		}
+		runArgs = append(runArgs, "-v", mount)
+	}
+	runArgs = append(runArgs, splitArgs(r.cfg.Args)...)
+	runArgs = append(runArgs, r.cfg.Image, name)
+	return append(runArgs, args...), containerName, nil
+}
+
+// remove force-removes the container, stopping it if the runtime client could not.
+func (r *containerRunner) remove(containerName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), containerStopTimeout)
+	defer cancel()
+	_ = exec.CommandContext(ctx, r.cfg.Runtime, "rm", "-f", containerName).Run() //nolint:gosec // runtime is from user config
+}
+
+// expandMount validates a host:container[:options] bind mount and expands ~ in the host path.
+func expandMount(mount string) (string, error) {
+	host, rest, ok := strings.Cut(mount, ":")
+	if !ok || host == "" || rest == "" {
+		return "", fmt.Errorf("container mode: invalid mount %q, expected host:container[:options]", mount)
+	}
+	if host == "~" || strings.HasPrefix(host, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("container mode: expand %q: %w", host, err)
+		}
+		host = filepath.Join(home, strings.TrimPrefix(host, "~"))
+	}
+	return host + ":" + rest, nil
+}
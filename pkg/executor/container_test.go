@@ -0,0 +1,131 @@
+//go:build unix
+
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeContainerCLI writes a docker-like stub script that logs each invocation to a file.
+// "run" prints a claude stream-json result, or hangs when FAKE_CONTAINER_HANG is set.
+func fakeContainerCLI(t *testing.T) (runtime, logFile string) {
+	t.Helper()
+	dir := t.TempDir()
+	logFile = filepath.Join(dir, "calls.log")
+	runtime = filepath.Join(dir, "fake-docker")
+	script := `#!/bin/sh
+echo "$*" >> "` + logFile + `"
+if [ "$1" = "run" ]; then
+  if [ -n "$FAKE_CONTAINER_HANG" ]; then
+    echo '{"type":"content_block_delta","delta":{"type":"text_delta","text":"started"}}'
+    sleep 30
+  fi
+  echo '{"type":"content_block_delta","delta":{"type":"text_delta","text":"ran in container"}}'
+  echo '{"type":"result","result":"ran in container"}'
+fi
+`
+	require.NoError(t, os.WriteFile(runtime, []byte(script), 0o700)) //nolint:gosec // test script must be executable
+	return runtime, logFile
+}
+
+// containerCalls returns logged invocations of the fake container CLI.
+func containerCalls(t *testing.T, logFile string) []string {
+	t.Helper()
+	data, err := os.ReadFile(logFile) //nolint:gosec // test file
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestClaudeExecutor_Run_Container(t *testing.T) {
+	runtime, logFile := fakeContainerCLI(t)
+	workdir := t.TempDir()
+	e := &ClaudeExecutor{Command: "claude", Args: "--output-format stream-json", Container: &ContainerConfig{
+		Runtime: runtime, Image: "ralphex/claude:latest", Network: "none", Workdir: workdir,
+		Env: []string{"CLAUDE_CODE_OAUTH_TOKEN"}, Mounts: []string{"/host/creds:/home/node/.claude:ro"},
+		Args: "--memory 4g", Repos: []string{"/src/shared-lib"},
+	}}
+
+	result := e.Run(context.Background(), "do task")
+
+	require.NoError(t, result.Error)
+	assert.Equal(t, "ran in container", result.Output)
+	calls := containerCalls(t, logFile)
+	require.Len(t, calls, 1)
+	assert.Regexp(t, `^run --rm --init --name ralphex-\d+-\d+ `, calls[0])
+	assert.Contains(t, calls[0], "-v "+workdir+":"+workdir+" -w "+workdir+" -v /src/shared-lib:/src/shared-lib")
+	assert.Contains(t, calls[0], "--network none -e CLAUDE_CODE_OAUTH_TOKEN -v /host/creds:/home/node/.claude:ro --memory 4g")
+	assert.True(t, strings.HasSuffix(calls[0], "ralphex/claude:latest claude --output-format stream-json -p do task"), calls[0])
+}
+
+func TestClaudeExecutor_Run_ContainerCanceled(t *testing.T) {
+	runtime, logFile := fakeContainerCLI(t)
+	t.Setenv("FAKE_CONTAINER_HANG", "1")
+	e := &ClaudeExecutor{Container: &ContainerConfig{Runtime: runtime, Image: "img", Workdir: t.TempDir()}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	result := e.Run(ctx, "do task")
+
+	require.ErrorIs(t, result.Error, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 10*time.Second)
+	calls := containerCalls(t, logFile)
+	require.Len(t, calls, 2, "run followed by rm")
+	name := strings.Fields(calls[0])[4]
+	assert.Equal(t, "rm -f "+name, calls[1])
+}
+
+func TestContainerRunner_runArgs(t *testing.T) {
+	t.Run("defaults to cwd and runtime network", func(t *testing.T) {
+		r := &containerRunner{cfg: ContainerConfig{Runtime: "podman", Image: "img"}}
+		args, name, err := r.runArgs("claude", []string{"-p", "x"})
+		require.NoError(t, err)
+		cwd, err := os.Getwd()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"run", "--rm", "--init", "--name", name, "-v", cwd + ":" + cwd, "-w", cwd,
+			"img", "claude", "-p", "x"}, args)
+	})
+
+	t.Run("extra env variables are passed into the container", func(t *testing.T) {
+		r := &containerRunner{cfg: ContainerConfig{Runtime: "docker", Image: "img", Env: []string{"A"}},
+			env: EnvPolicy{Extra: []string{"A=1", "B=2"}}}
+		args, _, err := r.runArgs("claude", nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"-e", "A", "-e", "B", "img", "claude"}, args[len(args)-6:])
+	})
+
+	t.Run("missing image", func(t *testing.T) {
+		_, _, err := (&containerRunner{cfg: ContainerConfig{Runtime: "docker"}}).runArgs("claude", nil)
+		require.Error(t, err)
+	})
+
+	t.Run("invalid mount", func(t *testing.T) {
+		r := &containerRunner{cfg: ContainerConfig{Runtime: "docker", Image: "img", Mounts: []string{"/only/host"}}}
+		_, _, err := r.runArgs("claude", nil)
+		require.ErrorContains(t, err, "invalid mount")
+	})
+}
+
+func TestExpandMount(t *testing.T) {
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	mount, err := expandMount("~/.claude:/home/node/.claude")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".claude")+":/home/node/.claude", mount)
+
+	mount, err = expandMount("/etc/gitconfig:/etc/gitconfig:ro")
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/gitconfig:/etc/gitconfig:ro", mount)
+
+	_, err = expandMount(":/x")
+	require.Error(t, err)
+}
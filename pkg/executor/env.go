@@ -0,0 +1,57 @@
+package executor
+
+import (
+	"slices"
+	"strings"
+)
+
+// EnvPolicy filters the environment a claude or codex process inherits and adds extra variables.
+// patterns are variable names or PREFIX* prefixes.
+type EnvPolicy struct {
+	Allow []string // inherited variables must match one of these, empty inherits all
+	Deny  []string // variables matching these are removed, applied after Allow
+	Extra []string // KEY=VALUE pairs added after filtering, overriding inherited values, later pairs win
+}
+
+// Apply returns env filtered by the policy with extra variables appended.
+func (p EnvPolicy) Apply(env []string) []string {
+	result := make([]string, 0, len(env)+len(p.Extra))
+	for _, kv := range env {
+		name, _, _ := strings.Cut(kv, "=")
+		if len(p.Allow) > 0 && !matchEnvName(name, p.Allow) {
+			continue
+		}
+		if matchEnvName(name, p.Deny) || p.hasExtra(name) {
+			continue
+		}
+		result = append(result, kv)
+	}
+	return append(result, p.Extra...)
+}
+
+// ExtraNames returns the names of the extra variables.
+func (p EnvPolicy) ExtraNames() []string {
+	names := make([]string, 0, len(p.Extra))
+	for _, kv := range p.Extra {
+		name, _, _ := strings.Cut(kv, "=")
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// hasExtra reports whether an extra variable named name is set.
+func (p EnvPolicy) hasExtra(name string) bool {
+	return slices.ContainsFunc(p.Extra, func(kv string) bool { return strings.HasPrefix(kv, name+"=") })
+}
+
+// matchEnvName reports whether name equals one of patterns or starts with a PREFIX* pattern's prefix.
+func matchEnvName(name string, patterns []string) bool {
+	for _, p := range patterns {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok && strings.HasPrefix(name, prefix) || p == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvPolicy_Apply(t *testing.T) {
+	env := []string{"PATH=/bin", "HOME=/home/u", "AWS_SECRET=x", "AWS_REGION=eu", "GIT_AUTHOR_NAME=me", "FOO=old"}
+
+	tests := []struct {
+		name   string
+		policy EnvPolicy
+		want   []string
+	}{
+		{name: "empty policy keeps all", policy: EnvPolicy{}, want: env},
+		{name: "allow list with prefix", policy: EnvPolicy{Allow: []string{"PATH", "GIT_*"}},
+			want: []string{"PATH=/bin", "GIT_AUTHOR_NAME=me"}},
+		{name: "deny list with prefix", policy: EnvPolicy{Deny: []string{"AWS_*", "HOME"}},
+			want: []string{"PATH=/bin", "GIT_AUTHOR_NAME=me", "FOO=old"}},
+		{name: "deny applies after allow", policy: EnvPolicy{Allow: []string{"AWS_*"}, Deny: []string{"AWS_SECRET"}},
+			want: []string{"AWS_REGION=eu"}},
+		{name: "extra overrides inherited", policy: EnvPolicy{Allow: []string{"PATH"}, Extra: []string{"FOO=new", "BAR=1"}},
+			want: []string{"PATH=/bin", "FOO=new", "BAR=1"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.policy.Apply(env))
+		})
+	}
+}
+
+func TestEnvPolicy_ExtraNames(t *testing.T) {
+	p := EnvPolicy{Extra: []string{"A=1", "B=x=y", "A=2"}}
+	assert.Equal(t, []string{"A", "B"}, p.ExtraNames())
+	assert.Empty(t, EnvPolicy{}.ExtraNames())
+}
@@ -5,11 +5,13 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 )
 
 //go:generate moq -out mocks/command_runner.go -pkg mocks -skip-ensure -fmt goimports . CommandRunner
@@ -20,9 +22,11 @@ const maxScannerBuffer = 64 * 1024 * 1024
 
 // Result holds execution result with output and detected signal.
 type Result struct {
-	Output string // accumulated text output
-	Signal string // detected signal (COMPLETED, FAILED, etc.) or empty
-	Error  error  // execution error if any
+	Output    string // accumulated text output
+	Signal    string // detected signal (COMPLETED, FAILED, etc.) or empty
+	SessionID string // claude session id from stream-json output, empty if not reported
+	Usage     Usage  // token usage and cost from the stream's result event, zero if not reported
+	Error     error  // execution error if any
 }
 
 // CommandRunner abstracts command execution for testing.
@@ -32,7 +36,9 @@ type CommandRunner interface {
 }
 
 // execClaudeRunner is the default command runner using os/exec.
-type execClaudeRunner struct{}
+type execClaudeRunner struct {
+	env EnvPolicy // applied to the inherited environment
+}
 
 func (r *execClaudeRunner) Run(ctx context.Context, name string, args ...string) (io.Reader, func() error, error) {
 	// check context before starting to avoid spawning a process that will be immediately killed
@@ -44,8 +50,9 @@ func (r *execClaudeRunner) Run(ctx context.Context, name string, args ...string)
 	// to ensure the entire process group is killed, not just the direct child
 	cmd := exec.Command(name, args...) //nolint:noctx // intentional: we handle context cancellation via process group kill
 
-	// filter out ANTHROPIC_API_KEY from environment (claude uses different auth)
-	cmd.Env = filterEnv(os.Environ(), "ANTHROPIC_API_KEY")
+	// filter out ANTHROPIC_API_KEY from environment (claude uses different auth),
+	// the env policy can still add it back as an extra variable
+	cmd.Env = r.env.Apply(filterEnv(os.Environ(), "ANTHROPIC_API_KEY"))
 
 	// create new process group so we can kill all descendants on cleanup
 	setupProcessGroup(cmd)
@@ -134,14 +141,22 @@ func filterEnv(env []string, keysToRemove ...string) []string {
 	return result
 }
 
+// contentBlock is an entry of message content in claude stream events.
+type contentBlock struct {
+	Type    string          `json:"type"`
+	Text    string          `json:"text"`
+	Name    string          `json:"name"`     // tool_use: tool name
+	Input   json.RawMessage `json:"input"`    // tool_use: tool arguments
+	IsError bool            `json:"is_error"` // tool_result: tool call failed
+	Content json.RawMessage `json:"content"`  // tool_result: string or text blocks
+}
+
 // streamEvent represents a JSON event from claude CLI stream output.
 type streamEvent struct {
-	Type    string `json:"type"`
-	Message struct {
-		Content []struct {
-			Type string `json:"type"`
-			Text string `json:"text"`
-		} `json:"content"`
+	Type      string `json:"type"`
+	SessionID string `json:"session_id"`
+	Message   struct {
+		Content []contentBlock `json:"content"`
 	} `json:"message"`
 	ContentBlock struct {
 		Type string `json:"type"`
@@ -151,20 +166,39 @@ type streamEvent struct {
 		Type string `json:"type"`
 		Text string `json:"text"`
 	} `json:"delta"`
-	Result json.RawMessage `json:"result"` // can be string or object with "output" field
+	Result       json.RawMessage `json:"result"`         // can be string or object with "output" field
+	IsError      bool            `json:"is_error"`       // result: session ended with an error
+	Usage        *Usage          `json:"usage"`          // result: token usage of the session
+	TotalCostUSD float64         `json:"total_cost_usd"` // result: session cost
 }
 
 // ClaudeExecutor runs claude CLI commands with streaming JSON parsing.
 type ClaudeExecutor struct {
-	Command       string            // command to execute, defaults to "claude"
-	Args          string            // additional arguments (space-separated), defaults to standard args
-	OutputHandler func(text string) // called for each text chunk, can be nil
-	Debug         bool              // enable debug output
-	cmdRunner     CommandRunner     // for testing, nil uses default
+	Command         string                  // command to execute, defaults to "claude"
+	Args            string                  // additional arguments (space-separated), defaults to standard args
+	Model           string                  // passed as --model, empty keeps the claude default
+	AllowedTools    []string                // passed as --allowedTools, empty allows all tools
+	OutputHandler   func(text string)       // called for each text chunk, can be nil
+	ActivityHandler func(activity Activity) // called for each tool call and failed tool result, can be nil
+	IdleTimeout     time.Duration           // kill the process after this long without output, 0 disables
+	Container       *ContainerConfig        // run claude inside a container, nil runs it on the host
+	Env             EnvPolicy               // environment filtering and extra variables
+	Debug           bool                    // enable debug output
+	cmdRunner       CommandRunner           // for testing, nil uses default
 }
 
 // Run executes claude CLI with the given prompt and parses streaming JSON output.
 func (e *ClaudeExecutor) Run(ctx context.Context, prompt string) Result {
+	return e.run(ctx, prompt, "")
+}
+
+// Resume continues an earlier claude session (claude --resume) with a new prompt.
+func (e *ClaudeExecutor) Resume(ctx context.Context, sessionID, prompt string) Result {
+	return e.run(ctx, prompt, sessionID)
+}
+
+// run executes claude CLI, resuming sessionID when it is not empty.
+func (e *ClaudeExecutor) run(ctx context.Context, prompt, sessionID string) Result {
 	cmd := e.Command
 	if cmd == "" {
 		cmd = "claude"
@@ -181,38 +215,71 @@ func (e *ClaudeExecutor) Run(ctx context.Context, prompt string) Result {
 			"--verbose",
 		}
 	}
+	if e.Model != "" {
+		args = append(args, "--model", e.Model)
+	}
+	if len(e.AllowedTools) > 0 {
+		args = append(args, "--allowedTools", strings.Join(e.AllowedTools, ","))
+	}
+	if sessionID != "" {
+		args = append(args, "--resume", sessionID)
+	}
 	args = append(args, "-p", prompt)
 
 	runner := e.cmdRunner
-	if runner == nil {
-		runner = &execClaudeRunner{}
+	switch {
+	case runner == nil && e.Container != nil:
+		runner = &containerRunner{cfg: *e.Container, env: e.Env}
+	case runner == nil:
+		runner = &execClaudeRunner{env: e.Env}
 	}
 
-	stdout, wait, err := runner.Run(ctx, cmd, args...)
+	// the watchdog cancels runCtx, which kills the process group through the runner's cleanup
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	watchdog := newIdleWatchdog(e.IdleTimeout, cancel)
+	defer watchdog.stop()
+
+	stdout, wait, err := runner.Run(runCtx, cmd, args...)
 	if err != nil {
 		return Result{Error: err}
 	}
 
-	result := e.parseStream(stdout)
+	result, ended := e.parseStream(watchdog.watch(stdout))
 
-	if err := wait(); err != nil {
+	waitErr := wait()
+	if stallErr := watchdog.stallError(); stallErr != nil && ctx.Err() == nil {
+		return Result{Output: result.Output, SessionID: result.SessionID, Usage: result.Usage, Error: stallErr}
+	}
+	// without a result event the exit status tells whether the session failed, a clean exit is a success
+	var limitErr *LimitError
+	if !ended && waitErr == nil && errors.As(result.Error, &limitErr) {
+		result.Error = nil
+	}
+	if err := waitErr; err != nil {
 		// check if it was context cancellation
 		if ctx.Err() != nil {
-			return Result{Output: result.Output, Signal: result.Signal, Error: ctx.Err()}
+			return Result{Output: result.Output, Signal: result.Signal, SessionID: result.SessionID, Usage: result.Usage,
+				Error: ctx.Err()}
 		}
 		// non-zero exit might still have useful output
 		if result.Output == "" {
-			return Result{Error: fmt.Errorf("claude exited with error: %w", err)}
+			return Result{SessionID: result.SessionID, Error: fmt.Errorf("claude exited with error: %w", err)}
 		}
 	}
 
 	return result
 }
 
-// parseStream reads and parses the JSON stream from claude CLI.
-func (e *ClaudeExecutor) parseStream(r io.Reader) Result {
+// parseStream reads and parses the JSON stream from claude CLI. ended reports whether the stream
+// had a result event. limits are detected in non-JSON lines (stderr is merged into stdout), api error
+// text and error result events, and are reported only if the session did not end successfully.
+func (e *ClaudeExecutor) parseStream(r io.Reader) (result Result, ended bool) {
 	var output strings.Builder
-	var signal string
+	var signal, sessionID string
+	var limit *LimitError
+	var usage Usage
+	var failed bool
 
 	scanner := bufio.NewScanner(r)
 	// increase buffer size for large JSON lines (large diffs with parallel agents)
@@ -236,9 +303,32 @@ func (e *ClaudeExecutor) parseStream(r io.Reader) Result {
 			if e.OutputHandler != nil {
 				e.OutputHandler(line + "\n")
 			}
+			if l := detectLimit(line, time.Now()); l != nil {
+				limit = l
+			}
 			continue
 		}
 
+		if event.SessionID != "" {
+			sessionID = event.SessionID
+		}
+		if l := e.extractLimit(&event); l != nil {
+			limit = l
+		}
+		if event.Type == "result" {
+			ended, failed = true, event.IsError
+			if event.Usage != nil {
+				usage = *event.Usage
+				usage.CostUSD = event.TotalCostUSD
+			}
+		}
+
+		if e.ActivityHandler != nil {
+			for _, a := range e.extractActivities(&event) {
+				e.ActivityHandler(a)
+			}
+		}
+
 		text := e.extractText(&event)
 		if text != "" {
 			output.WriteString(text)
@@ -253,11 +343,51 @@ func (e *ClaudeExecutor) parseStream(r io.Reader) Result {
 		}
 	}
 
+	result = Result{Output: output.String(), Signal: signal, SessionID: sessionID, Usage: usage}
 	if err := scanner.Err(); err != nil {
-		return Result{Output: output.String(), Signal: signal, Error: fmt.Errorf("stream read: %w", err)}
+		result.Error = fmt.Errorf("stream read: %w", err)
+		return result, ended
 	}
+	if limit != nil && (!ended || failed) {
+		result.Error = limit
+	}
+	return result, ended
+}
 
-	return Result{Output: output.String(), Signal: signal}
+// extractLimit returns the rate, usage or overload limit reported by an api error in the event.
+// only error text emitted by claude CLI is checked, model output mentioning limits is ignored.
+func (e *ClaudeExecutor) extractLimit(event *streamEvent) *LimitError {
+	switch event.Type {
+	case "assistant":
+		for _, c := range event.Message.Content {
+			if c.Type != "text" || !isClaudeErrorText(c.Text) {
+				continue
+			}
+			if l := detectLimit(c.Text, time.Now()); l != nil {
+				return l
+			}
+		}
+	case "result":
+		var resultStr string
+		if event.IsError && json.Unmarshal(event.Result, &resultStr) == nil {
+			return detectLimit(resultStr, time.Now())
+		}
+	}
+	return nil
+}
+
+// extractActivities returns tool calls from assistant events and failed tool results from user events.
+func (e *ClaudeExecutor) extractActivities(event *streamEvent) []Activity {
+	var activities []Activity
+	for _, c := range event.Message.Content {
+		switch {
+		case event.Type == "assistant" && c.Type == "tool_use":
+			activities = append(activities, toolUseActivity(c.Name, c.Input))
+		case event.Type == "user" && c.Type == "tool_result" && c.IsError:
+			activities = append(activities, toolErrorActivity(c.Content))
+		}
+	}
+	return activities
 }
 
 // extractText extracts text content from various event types.
@@ -198,7 +198,7 @@ func TestClaudeExecutor_parseStream(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			e := &ClaudeExecutor{}
-			result := e.parseStream(strings.NewReader(tc.input))
+			result, _ := e.parseStream(strings.NewReader(tc.input))
 
 			assert.Equal(t, tc.wantOutput, result.Output)
 			assert.Equal(t, tc.wantSignal, result.Signal)
@@ -217,18 +217,33 @@ func TestClaudeExecutor_parseStream_withHandler(t *testing.T) {
 		},
 	}
 
-	result := e.parseStream(strings.NewReader(input))
+	result, _ := e.parseStream(strings.NewReader(input))
 
 	assert.Equal(t, "chunk1chunk2", result.Output)
 	assert.Equal(t, []string{"chunk1", "chunk2"}, chunks)
 }
 
+func TestClaudeExecutor_parseStream_usage(t *testing.T) {
+	input := `{"type":"content_block_delta","delta":{"type":"text_delta","text":"done"}}
+{"type":"result","result":"done","total_cost_usd":0.042,"usage":{"input_tokens":1200,"output_tokens":350,"cache_read_input_tokens":8000,"cache_creation_input_tokens":100}}`
+
+	result, _ := (&ClaudeExecutor{}).parseStream(strings.NewReader(input))
+
+	want := Usage{InputTokens: 1200, OutputTokens: 350, CacheReadTokens: 8000, CacheCreationTokens: 100, CostUSD: 0.042}
+	assert.Equal(t, want, result.Usage)
+	assert.Equal(t, "1200 in, 350 out, 8100 cached, $0.0420", result.Usage.String())
+
+	result, _ = (&ClaudeExecutor{}).parseStream(strings.NewReader(`{"type":"result","result":"done"}`))
+	assert.True(t, result.Usage.IsZero())
+	assert.Equal(t, "0 in, 0 out", result.Usage.String())
+}
+
 func TestClaudeExecutor_parseStream_withDebug(t *testing.T) {
 	// non-json lines should be printed as-is (with debug message)
 	input := "not json\n" + `{"type":"content_block_delta","delta":{"type":"text_delta","text":"valid"}}`
 
 	e := &ClaudeExecutor{Debug: true}
-	result := e.parseStream(strings.NewReader(input))
+	result, _ := e.parseStream(strings.NewReader(input))
 
 	assert.Equal(t, "not json\nvalid", result.Output)
 }
@@ -238,19 +253,13 @@ func TestClaudeExecutor_extractText(t *testing.T) {
 
 	t.Run("assistant event with text", func(t *testing.T) {
 		event := streamEvent{Type: "assistant"}
-		event.Message.Content = []struct {
-			Type string `json:"type"`
-			Text string `json:"text"`
-		}{{Type: "text", Text: "assistant message"}}
+		event.Message.Content = []contentBlock{{Type: "text", Text: "assistant message"}}
 		assert.Equal(t, "assistant message", e.extractText(&event))
 	})
 
 	t.Run("assistant event with multiple text blocks", func(t *testing.T) {
 		event := streamEvent{Type: "assistant"}
-		event.Message.Content = []struct {
-			Type string `json:"type"`
-			Text string `json:"text"`
-		}{{Type: "text", Text: "first"}, {Type: "text", Text: "second"}}
+		event.Message.Content = []contentBlock{{Type: "text", Text: "first"}, {Type: "text", Text: "second"}}
 		assert.Equal(t, "firstsecond", e.extractText(&event))
 	})
 
@@ -288,10 +297,7 @@ func TestClaudeExecutor_extractText(t *testing.T) {
 
 	t.Run("message_stop with text content", func(t *testing.T) {
 		event := streamEvent{Type: "message_stop"}
-		event.Message.Content = []struct {
-			Type string `json:"type"`
-			Text string `json:"text"`
-		}{
+		event.Message.Content = []contentBlock{
 			{Type: "text", Text: "final message"},
 		}
 		assert.Equal(t, "final message", e.extractText(&event))
@@ -299,10 +305,7 @@ func TestClaudeExecutor_extractText(t *testing.T) {
 
 	t.Run("message_stop with non-text content", func(t *testing.T) {
 		event := streamEvent{Type: "message_stop"}
-		event.Message.Content = []struct {
-			Type string `json:"type"`
-			Text string `json:"text"`
-		}{
+		event.Message.Content = []contentBlock{
 			{Type: "tool_use", Text: "ignored"},
 		}
 		assert.Empty(t, e.extractText(&event))
@@ -406,6 +409,27 @@ func TestClaudeExecutor_Run_WithCustomCommandAndArgs(t *testing.T) {
 	assert.Equal(t, []string{"--skip-perms", "--verbose", "-p", "the prompt"}, capturedArgs)
 }
 
+func TestClaudeExecutor_Run_WithModelAndTools(t *testing.T) {
+	var capturedArgs []string
+	mock := &mocks.CommandRunnerMock{
+		RunFunc: func(_ context.Context, _ string, args ...string) (io.Reader, func() error, error) {
+			capturedArgs = args
+			return strings.NewReader(`{"type":"content_block_delta","delta":{"type":"text_delta","text":"ok"}}`), func() error { return nil }, nil
+		},
+	}
+	e := &ClaudeExecutor{
+		cmdRunner:    mock,
+		Args:         "--verbose",
+		Model:        "opus",
+		AllowedTools: []string{"Read", "Grep"},
+	}
+
+	result := e.Run(context.Background(), "the prompt")
+
+	require.NoError(t, result.Error)
+	assert.Equal(t, []string{"--verbose", "--model", "opus", "--allowedTools", "Read,Grep", "-p", "the prompt"}, capturedArgs)
+}
+
 func TestSplitArgs(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -499,7 +523,7 @@ func TestClaudeExecutor_parseStream_largeLines(t *testing.T) {
 			jsonLine := `{"type":"content_block_delta","delta":{"type":"text_delta","text":"` + largeText + `"}}`
 
 			e := &ClaudeExecutor{}
-			result := e.parseStream(strings.NewReader(jsonLine))
+			result, _ := e.parseStream(strings.NewReader(jsonLine))
 
 			require.NoError(t, result.Error, "should handle %d byte line without error", tc.size)
 			assert.Len(t, result.Output, tc.size, "output should contain full text")
@@ -520,8 +544,33 @@ func TestClaudeExecutor_parseStream_multipleLargeLines(t *testing.T) {
 	input := strings.Join(lines, "\n")
 
 	e := &ClaudeExecutor{}
-	result := e.parseStream(strings.NewReader(input))
+	result, _ := e.parseStream(strings.NewReader(input))
 
 	require.NoError(t, result.Error)
 	assert.Len(t, result.Output, lineSize*numLines, "should contain all output from all lines")
 }
+
+func TestClaudeExecutor_SessionID(t *testing.T) {
+	jsonStream := `{"type":"system","subtype":"init","session_id":"sess-1"}
+{"type":"content_block_delta","delta":{"type":"text_delta","text":"done"}}
+{"type":"result","result":"summary","session_id":"sess-1"}`
+
+	var gotArgs []string
+	mock := &mocks.CommandRunnerMock{
+		RunFunc: func(_ context.Context, _ string, args ...string) (io.Reader, func() error, error) {
+			gotArgs = args
+			return strings.NewReader(jsonStream), func() error { return nil }, nil
+		},
+	}
+	e := &ClaudeExecutor{Args: "--verbose", cmdRunner: mock}
+
+	result := e.Run(context.Background(), "first")
+	require.NoError(t, result.Error)
+	assert.Equal(t, "sess-1", result.SessionID)
+	assert.Equal(t, []string{"--verbose", "-p", "first"}, gotArgs)
+
+	result = e.Resume(context.Background(), "sess-1", "again")
+	require.NoError(t, result.Error)
+	assert.Equal(t, "done", result.Output)
+	assert.Equal(t, []string{"--verbose", "--resume", "sess-1", "-p", "again"}, gotArgs)
+}
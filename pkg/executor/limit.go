@@ -0,0 +1,185 @@
+package executor
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxLimitMessage limits the length of the message kept in LimitError.
+const maxLimitMessage = 200
+
+// LimitKind classifies why the backend refused to work.
+type LimitKind string
+
+// limit kinds reported by LimitError.
+const (
+	LimitRate     LimitKind = "rate limit"  // too many requests, short backoff helps
+	LimitUsage    LimitKind = "usage limit" // plan or quota exhausted until a reset time
+	LimitOverload LimitKind = "overloaded"  // api overloaded, short backoff helps
+)
+
+// LimitError is returned in Result.Error when claude or codex reports a rate limit, an exhausted
+// usage limit or api overload. the run did no useful work and can be retried once the limit clears.
+type LimitError struct {
+	Kind    LimitKind
+	Message string    // reported message, first line only
+	ResetAt time.Time // when the limit resets, zero if not reported
+}
+
+// Error returns the limit kind and message, with the reset time if known.
+func (e *LimitError) Error() string {
+	if e.ResetAt.IsZero() {
+		return fmt.Sprintf("%s: %s", e.Kind, e.Message)
+	}
+	return fmt.Sprintf("%s: %s (resets %s)", e.Kind, e.Message, e.ResetAt.Format(time.DateTime))
+}
+
+// limitPatterns map lowercase message fragments to limit kinds, checked in order.
+var limitPatterns = []struct {
+	fragment string
+	kind     LimitKind
+}{
+	{"usage limit", LimitUsage},
+	{"limit reached", LimitUsage}, // "5-hour limit reached ∙ resets 3pm"
+	{"insufficient_quota", LimitUsage},
+	{"quota exceeded", LimitUsage},
+	{"overloaded", LimitOverload},
+	{"rate limit", LimitRate},
+	{"rate_limit", LimitRate},
+	{"too many requests", LimitRate},
+}
+
+var (
+	// unix reset timestamp appended by claude, e.g. "Claude AI usage limit reached|1760000000"
+	resetUnixRe = regexp.MustCompile(`\|(\d{10})\b`)
+	// relative reset, e.g. "try again in 2 days 3 hours 5 minutes" or "retry after 30 seconds"
+	resetInRe  = regexp.MustCompile(`(?i)(?:try again|retry|resets?) (?:in|after)\s+((?:\d+\s*(?:days?|d|hours?|hrs?|h|minutes?|mins?|m|seconds?|secs?|s)\b[\s,]*(?:and\s+)?)+)`)
+	durationRe = regexp.MustCompile(`(?i)(\d+)\s*(days?|d|hours?|hrs?|h|minutes?|mins?|m|seconds?|secs?|s)\b`)
+	// clock reset, e.g. "resets 3pm", "resets at 3:45 PM (Europe/Berlin)" or "try again at 15:30"
+	resetClockRe = regexp.MustCompile(`(?i)(?:resets?|try again)(?: at)?\s+(\d{1,2})(?::(\d{2}))?\s*(am|pm)?(?:\s*\(([^)]+)\))?`)
+)
+
+// detectLimit returns a LimitError when text reports a rate limit, usage limit or overload.
+// now is used to resolve relative and clock reset times.
+func detectLimit(text string, now time.Time) *LimitError {
+	lower := strings.ToLower(text)
+	for _, p := range limitPatterns {
+		if !strings.Contains(lower, p.fragment) {
+			continue
+		}
+		return &LimitError{Kind: p.kind, Message: compactLimitMessage(text), ResetAt: parseResetTime(text, now)}
+	}
+	return nil
+}
+
+// parseResetTime extracts the limit reset time from text, zero if none is given.
+func parseResetTime(text string, now time.Time) time.Time {
+	if m := resetUnixRe.FindStringSubmatch(text); m != nil {
+		if sec, err := strconv.ParseInt(m[1], 10, 64); err == nil {
+			return time.Unix(sec, 0)
+		}
+	}
+
+	if m := resetInRe.FindStringSubmatch(text); m != nil {
+		var d time.Duration
+		for _, part := range durationRe.FindAllStringSubmatch(m[1], -1) {
+			n, _ := strconv.Atoi(part[1]) //nolint:errcheck // regex guarantees digits
+			d += time.Duration(n) * durationUnit(part[2])
+		}
+		if d > 0 {
+			return now.Add(d)
+		}
+	}
+
+	if m := resetClockRe.FindStringSubmatch(text); m != nil {
+		return nextClockTime(m[1], m[2], m[3], m[4], now)
+	}
+	return time.Time{}
+}
+
+// durationUnit maps a unit word such as "hours" or "m" to its duration.
+func durationUnit(unit string) time.Duration {
+	switch u := strings.ToLower(unit); {
+	case strings.HasPrefix(u, "d"):
+		return 24 * time.Hour
+	case strings.HasPrefix(u, "h"):
+		return time.Hour
+	case strings.HasPrefix(u, "m"):
+		return time.Minute
+	default:
+		return time.Second
+	}
+}
+
+// nextClockTime returns the first occurrence of hour:minute (with optional am/pm and time zone name)
+// after now. returns zero time for invalid values.
+func nextClockTime(hourStr, minStr, ampm, zone string, now time.Time) time.Time {
+	hour, err := strconv.Atoi(hourStr)
+	if err != nil {
+		return time.Time{}
+	}
+	minute := 0
+	if minStr != "" {
+		if minute, err = strconv.Atoi(minStr); err != nil {
+			return time.Time{}
+		}
+	}
+	switch strings.ToLower(ampm) {
+	case "am":
+		if hour == 12 {
+			hour = 0
+		}
+	case "pm":
+		if hour < 12 {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute > 59 {
+		return time.Time{}
+	}
+
+	loc := now.Location()
+	if zone != "" {
+		if l, err := time.LoadLocation(strings.TrimSpace(zone)); err == nil {
+			loc = l
+		}
+	}
+	local := now.In(loc)
+	t := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, loc)
+	if !t.After(local) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// compactLimitMessage returns the first non-empty line of text, truncated to maxLimitMessage.
+func compactLimitMessage(text string) string {
+	msg := strings.TrimSpace(text)
+	if first, _, found := strings.Cut(msg, "\n"); found {
+		msg = strings.TrimSpace(first)
+	}
+	if len(msg) > maxLimitMessage {
+		msg = msg[:maxLimitMessage] + "..."
+	}
+	return msg
+}
+
+// isClaudeErrorText returns true for assistant text that claude CLI emits in place of a model
+// response when the api call fails, e.g. "API Error: 529 ..." or "Claude AI usage limit reached|...".
+// regular model output mentioning limits is not an error.
+func isClaudeErrorText(text string) bool {
+	t := strings.TrimSpace(text)
+	if strings.HasPrefix(t, "API Error") || strings.HasPrefix(t, "Claude AI usage limit reached") {
+		return true
+	}
+	return len(t) < 300 && !strings.Contains(t, "\n") && strings.Contains(strings.ToLower(t), "limit reached")
+}
+
+// isCodexErrorLine returns true for codex stderr lines reporting an error rather than model progress.
+func isCodexErrorLine(line string) bool {
+	t := strings.ToLower(strings.TrimSpace(line))
+	return strings.Contains(line, "ERROR") || strings.HasPrefix(t, "error") || strings.HasPrefix(t, "stream error")
+}
@@ -0,0 +1,178 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/umputun/ralphex/pkg/executor/mocks"
+)
+
+func TestDetectLimit(t *testing.T) {
+	now := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		text      string
+		wantKind  LimitKind
+		wantReset time.Time
+	}{
+		{name: "claude usage limit with unix reset", text: "Claude AI usage limit reached|1769079600",
+			wantKind: LimitUsage, wantReset: time.Unix(1769079600, 0)},
+		{name: "claude limit with clock reset", text: "5-hour limit reached ∙ resets 3pm",
+			wantKind: LimitUsage, wantReset: time.Date(2026, 1, 22, 15, 0, 0, 0, time.UTC)},
+		{name: "clock reset already passed today", text: "Session limit reached ∙ resets 9:30am",
+			wantKind: LimitUsage, wantReset: time.Date(2026, 1, 23, 9, 30, 0, 0, time.UTC)},
+		{name: "clock reset with zone", text: "weekly limit reached ∙ resets 11pm (Europe/Berlin)",
+			wantKind: LimitUsage, wantReset: time.Date(2026, 1, 22, 22, 0, 0, 0, time.UTC)},
+		{name: "codex usage limit with relative reset",
+			text:     "ERROR: You've hit your usage limit. Upgrade to Pro or try again in 1 day 2 hours 5 minutes.",
+			wantKind: LimitUsage, wantReset: now.Add(26*time.Hour + 5*time.Minute)},
+		{name: "overloaded", text: `API Error: 529 {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`,
+			wantKind: LimitOverload},
+		{name: "rate limit", text: `API Error: 429 {"type":"error","error":{"type":"rate_limit_error"}}`, wantKind: LimitRate},
+		{name: "too many requests with retry after", text: "stream error: last status: 429 Too Many Requests, retry after 30s",
+			wantKind: LimitRate, wantReset: now.Add(30 * time.Second)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			l := detectLimit(tc.text, now)
+			require.NotNil(t, l)
+			assert.Equal(t, tc.wantKind, l.Kind)
+			assert.True(t, tc.wantReset.Equal(l.ResetAt), "want %v, got %v", tc.wantReset, l.ResetAt)
+			assert.NotEmpty(t, l.Message)
+		})
+	}
+
+	t.Run("no limit", func(t *testing.T) {
+		assert.Nil(t, detectLimit("API Error: 500 internal server error", now))
+		assert.Nil(t, detectLimit("all tests passed", now))
+	})
+}
+
+func TestLimitError_Error(t *testing.T) {
+	l := &LimitError{Kind: LimitRate, Message: "too many requests"}
+	assert.Equal(t, "rate limit: too many requests", l.Error())
+
+	l.ResetAt = time.Date(2026, 1, 22, 15, 0, 0, 0, time.Local)
+	assert.Equal(t, "rate limit: too many requests (resets 2026-01-22 15:00:00)", l.Error())
+}
+
+func TestCompactLimitMessage(t *testing.T) {
+	assert.Equal(t, "first line", compactLimitMessage("  first line\nsecond line"))
+	long := compactLimitMessage(strings.Repeat("x", 300))
+	assert.Len(t, long, maxLimitMessage+3)
+}
+
+func TestIsClaudeErrorText(t *testing.T) {
+	assert.True(t, isClaudeErrorText("API Error: 529 overloaded"))
+	assert.True(t, isClaudeErrorText("Claude AI usage limit reached|1769079600"))
+	assert.True(t, isClaudeErrorText("5-hour limit reached ∙ resets 3pm"))
+	assert.False(t, isClaudeErrorText("I added a rate limit middleware to the handler"))
+	assert.False(t, isClaudeErrorText("the retry limit reached check is now covered\nby a unit test"))
+}
+
+// streamRunner returns a command runner mock producing output and exiting cleanly.
+func streamRunner(output string) *mocks.CommandRunnerMock {
+	return &mocks.CommandRunnerMock{
+		RunFunc: func(_ context.Context, _ string, _ ...string) (io.Reader, func() error, error) {
+			return strings.NewReader(output), func() error { return nil }, nil
+		},
+	}
+}
+
+func TestClaudeExecutor_Run_Limit(t *testing.T) {
+	t.Run("usage limit in assistant text", func(t *testing.T) {
+		stream := `{"type":"assistant","message":{"content":[{"type":"text","text":"Claude AI usage limit reached|1769079600"}]}}
+{"type":"result","is_error":true,"result":"Claude AI usage limit reached|1769079600"}`
+		e := &ClaudeExecutor{cmdRunner: streamRunner(stream)}
+
+		result := e.Run(context.Background(), "do task")
+
+		var limitErr *LimitError
+		require.ErrorAs(t, result.Error, &limitErr)
+		assert.Equal(t, LimitUsage, limitErr.Kind)
+		assert.Equal(t, time.Unix(1769079600, 0), limitErr.ResetAt)
+	})
+
+	t.Run("overload in non-json line", func(t *testing.T) {
+		e := &ClaudeExecutor{cmdRunner: &mocks.CommandRunnerMock{
+			RunFunc: func(_ context.Context, _ string, _ ...string) (io.Reader, func() error, error) {
+				return strings.NewReader("API Error: 529 Overloaded\n"), func() error { return errors.New("exit status 1") }, nil
+			},
+		}}
+
+		result := e.Run(context.Background(), "do task")
+
+		var limitErr *LimitError
+		require.ErrorAs(t, result.Error, &limitErr)
+		assert.Equal(t, LimitOverload, limitErr.Kind)
+	})
+
+	t.Run("limit text with clean exit is not an error", func(t *testing.T) {
+		e := &ClaudeExecutor{cmdRunner: streamRunner("API Error: 529 Overloaded\nretried, all done\n")}
+
+		result := e.Run(context.Background(), "do task")
+
+		require.NoError(t, result.Error)
+		assert.Contains(t, result.Output, "all done")
+	})
+
+	t.Run("successful result is not overridden", func(t *testing.T) {
+		stream := `{"type":"assistant","message":{"content":[{"type":"text","text":"API Error: 529 Overloaded"}]}}
+{"type":"assistant","message":{"content":[{"type":"text","text":"task done"}]}}
+{"type":"result","is_error":false,"result":"task done"}`
+		e := &ClaudeExecutor{cmdRunner: streamRunner(stream)}
+
+		result := e.Run(context.Background(), "do task")
+
+		require.NoError(t, result.Error)
+	})
+
+	t.Run("model text about limits is not an error", func(t *testing.T) {
+		stream := `{"type":"assistant","message":{"content":[{"type":"text","text":"Implemented the rate limit middleware.\nAll tests pass."}]}}
+{"type":"result","result":"done"}`
+		e := &ClaudeExecutor{cmdRunner: streamRunner(stream)}
+
+		result := e.Run(context.Background(), "do task")
+
+		require.NoError(t, result.Error)
+	})
+}
+
+func TestCodexExecutor_Run_Limit(t *testing.T) {
+	mock := &mockCodexRunner{
+		runFunc: func(_ context.Context, _ string, _ ...string) (CodexStreams, func() error, error) {
+			stderr := "--------\nmodel: gpt-5\n--------\n**Discussing rate limits**\n" +
+				"[2026-01-22T10:00:00] ERROR: You've hit your usage limit. Try again in 3 hours.\n"
+			return mockStreams(stderr, ""), mockWaitError(errors.New("exit status 1")), nil
+		},
+	}
+	e := &CodexExecutor{runner: mock}
+
+	result := e.Run(context.Background(), "review")
+
+	var limitErr *LimitError
+	require.ErrorAs(t, result.Error, &limitErr)
+	assert.Equal(t, LimitUsage, limitErr.Kind)
+	assert.WithinDuration(t, time.Now().Add(3*time.Hour), limitErr.ResetAt, time.Minute)
+
+	t.Run("recovered limit with clean exit is not an error", func(t *testing.T) {
+		mock := &mockCodexRunner{
+			runFunc: func(_ context.Context, _ string, _ ...string) (CodexStreams, func() error, error) {
+				stderr := "ERROR: stream error: rate limit exceeded, retrying in 2s\n"
+				return mockStreams(stderr, "no issues"), mockWaitError(nil), nil
+			},
+		}
+		result := (&CodexExecutor{runner: mock}).Run(context.Background(), "review")
+		require.NoError(t, result.Error)
+		assert.Equal(t, "no issues", result.Output)
+	})
+}
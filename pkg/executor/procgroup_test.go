@@ -80,6 +80,24 @@ func TestProcessGroupCleanup_Idempotent(t *testing.T) {
 	assert.Equal(t, err2, err3, "repeated Wait() calls should return same error")
 }
 
+func TestExecClaudeRunner_EnvPolicy(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "sk-host")
+	t.Setenv("RALPHEX_TEST_DENIED", "1")
+	t.Setenv("RALPHEX_TEST_KEPT", "1")
+
+	runner := &execClaudeRunner{env: EnvPolicy{Deny: []string{"RALPHEX_TEST_DENIED"}, Extra: []string{"RALPHEX_TEST_EXTRA=yes"}}}
+	stdout, wait, err := runner.Run(t.Context(), "env")
+	require.NoError(t, err)
+	out, err := io.ReadAll(stdout)
+	require.NoError(t, err)
+	require.NoError(t, wait())
+
+	assert.NotContains(t, string(out), "ANTHROPIC_API_KEY=")
+	assert.NotContains(t, string(out), "RALPHEX_TEST_DENIED=")
+	assert.Contains(t, string(out), "RALPHEX_TEST_KEPT=1")
+	assert.Contains(t, string(out), "RALPHEX_TEST_EXTRA=yes")
+}
+
 // readChildPID reads from stdout until it finds "CHILD_PID:N" and returns N.
 func readChildPID(t *testing.T, r io.Reader) int {
 	t.Helper()
@@ -0,0 +1,29 @@
+package executor
+
+import "fmt"
+
+// Usage is the token usage and cost claude reports in the stream's final result event.
+type Usage struct {
+	InputTokens         int     `json:"input_tokens"`
+	OutputTokens        int     `json:"output_tokens"`
+	CacheReadTokens     int     `json:"cache_read_input_tokens,omitempty"`
+	CacheCreationTokens int     `json:"cache_creation_input_tokens,omitempty"`
+	CostUSD             float64 `json:"cost_usd,omitempty"`
+}
+
+// IsZero returns true when nothing was reported.
+func (u Usage) IsZero() bool {
+	return u == Usage{}
+}
+
+// String formats usage as "1200 in, 350 out, 8000 cached, $0.0420", omitting zero cache and cost.
+func (u Usage) String() string {
+	s := fmt.Sprintf("%d in, %d out", u.InputTokens, u.OutputTokens)
+	if cached := u.CacheReadTokens + u.CacheCreationTokens; cached > 0 {
+		s += fmt.Sprintf(", %d cached", cached)
+	}
+	if u.CostUSD > 0 {
+		s += fmt.Sprintf(", $%.4f", u.CostUSD)
+	}
+	return s
+}
@@ -0,0 +1,84 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// StallError is returned in Result.Error when a process produced no output for the executor's
+// IdleTimeout and was killed, e.g. while waiting on a stuck tool call.
+type StallError struct {
+	Idle time.Duration // idle time after which the process was killed
+}
+
+// Error describes the stall.
+func (e *StallError) Error() string {
+	return fmt.Sprintf("no output for %s, process killed", e.Idle)
+}
+
+// idleWatchdog calls kill when the watched output stays idle for longer than timeout.
+// a zero timeout disables the watchdog.
+type idleWatchdog struct {
+	timeout time.Duration
+	timer   *time.Timer
+	stalled atomic.Bool
+}
+
+// newIdleWatchdog starts a watchdog calling kill after timeout without output.
+func newIdleWatchdog(timeout time.Duration, kill func()) *idleWatchdog {
+	w := &idleWatchdog{timeout: timeout}
+	if timeout > 0 {
+		w.timer = time.AfterFunc(timeout, func() {
+			w.stalled.Store(true)
+			kill()
+		})
+	}
+	return w
+}
+
+// watch returns r wrapped so that every read delivering data restarts the idle timer.
+func (w *idleWatchdog) watch(r io.Reader) io.Reader {
+	if w.timer == nil {
+		return r
+	}
+	return &watchedReader{r: r, w: w}
+}
+
+// touch restarts the idle timer unless the watchdog already fired.
+func (w *idleWatchdog) touch() {
+	if w.timer != nil && !w.stalled.Load() {
+		w.timer.Reset(w.timeout)
+	}
+}
+
+// stop disarms the watchdog.
+func (w *idleWatchdog) stop() {
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+}
+
+// stallError returns a StallError if the watchdog fired, nil otherwise.
+func (w *idleWatchdog) stallError() error {
+	if !w.stalled.Load() {
+		return nil
+	}
+	return &StallError{Idle: w.timeout}
+}
+
+// watchedReader reports reads to its watchdog.
+type watchedReader struct {
+	r io.Reader
+	w *idleWatchdog
+}
+
+// Read reads from the underlying reader and touches the watchdog when data arrives.
+func (wr *watchedReader) Read(p []byte) (int, error) {
+	n, err := wr.r.Read(p)
+	if n > 0 {
+		wr.w.touch()
+	}
+	return n, err //nolint:wrapcheck // transparent reader wrapper
+}
@@ -0,0 +1,121 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/umputun/ralphex/pkg/executor/mocks"
+)
+
+// hangingOutput writes output, then blocks until ctx is canceled, like a process stuck on a tool call
+// that is killed by process group cleanup.
+func hangingOutput(ctx context.Context, output string) (io.Reader, func() error) {
+	pr, pw := io.Pipe()
+	go func() {
+		_, _ = pw.Write([]byte(output))
+		<-ctx.Done()
+		_ = pw.Close()
+	}()
+	return pr, func() error {
+		<-ctx.Done()
+		return errors.New("signal: killed")
+	}
+}
+
+func TestIdleWatchdog(t *testing.T) {
+	t.Run("fires after idle timeout", func(t *testing.T) {
+		killed := make(chan struct{})
+		w := newIdleWatchdog(20*time.Millisecond, func() { close(killed) })
+		defer w.stop()
+
+		select {
+		case <-killed:
+		case <-time.After(time.Second):
+			t.Fatal("watchdog did not fire")
+		}
+		var stallErr *StallError
+		require.ErrorAs(t, w.stallError(), &stallErr)
+		assert.Equal(t, 20*time.Millisecond, stallErr.Idle)
+	})
+
+	t.Run("reads keep it alive", func(t *testing.T) {
+		w := newIdleWatchdog(50*time.Millisecond, func() {})
+		defer w.stop()
+		r := w.watch(strings.NewReader(strings.Repeat("x", 20)))
+
+		buf := make([]byte, 1)
+		for range 10 {
+			_, err := r.Read(buf)
+			require.NoError(t, err)
+			time.Sleep(15 * time.Millisecond)
+		}
+		assert.NoError(t, w.stallError())
+	})
+
+	t.Run("zero timeout disables", func(t *testing.T) {
+		w := newIdleWatchdog(0, func() { t.Fatal("should not fire") })
+		defer w.stop()
+		src := strings.NewReader("data")
+		assert.Same(t, src, w.watch(src))
+		assert.NoError(t, w.stallError())
+	})
+}
+
+func TestStallError_Error(t *testing.T) {
+	assert.Equal(t, "no output for 30m0s, process killed", (&StallError{Idle: 30 * time.Minute}).Error())
+}
+
+func TestClaudeExecutor_Run_Stall(t *testing.T) {
+	mock := &mocks.CommandRunnerMock{
+		RunFunc: func(ctx context.Context, _ string, _ ...string) (io.Reader, func() error, error) {
+			r, wait := hangingOutput(ctx, `{"type":"content_block_delta","delta":{"type":"text_delta","text":"running tests"}}`+"\n")
+			return r, wait, nil
+		},
+	}
+	e := &ClaudeExecutor{cmdRunner: mock, IdleTimeout: 50 * time.Millisecond}
+
+	result := e.Run(context.Background(), "do task")
+
+	var stallErr *StallError
+	require.ErrorAs(t, result.Error, &stallErr)
+	assert.Equal(t, 50*time.Millisecond, stallErr.Idle)
+	assert.Equal(t, "running tests", result.Output)
+}
+
+func TestClaudeExecutor_Run_NoStallWithinTimeout(t *testing.T) {
+	mock := &mocks.CommandRunnerMock{
+		RunFunc: func(_ context.Context, _ string, _ ...string) (io.Reader, func() error, error) {
+			return strings.NewReader(`{"type":"content_block_delta","delta":{"type":"text_delta","text":"done"}}`),
+				func() error { return nil }, nil
+		},
+	}
+	e := &ClaudeExecutor{cmdRunner: mock, IdleTimeout: time.Second}
+
+	result := e.Run(context.Background(), "do task")
+
+	require.NoError(t, result.Error)
+	assert.Equal(t, "done", result.Output)
+}
+
+func TestCodexExecutor_Run_Stall(t *testing.T) {
+	mock := &mockCodexRunner{
+		runFunc: func(ctx context.Context, _ string, _ ...string) (CodexStreams, func() error, error) {
+			stderr, wait := hangingOutput(ctx, "--------\nmodel: gpt-5\n--------\n")
+			stdout, _ := hangingOutput(ctx, "")
+			return CodexStreams{Stderr: stderr, Stdout: stdout}, wait, nil
+		},
+	}
+	e := &CodexExecutor{runner: mock, IdleTimeout: 50 * time.Millisecond}
+
+	result := e.Run(context.Background(), "review")
+
+	var stallErr *StallError
+	require.ErrorAs(t, result.Error, &stallErr)
+}
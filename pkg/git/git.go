@@ -57,10 +57,14 @@ func (r *Repo) toRelative(path string) (string, error) {
 	return rel, nil
 }
 
-// Open opens a git repository at the given path.
-// Supports both regular repositories and git worktrees.
+// Open opens the git repository containing the given path.
+// path may be the repository root or any directory inside it, the root is found by searching upward
+// and the nearest .git wins, so a path inside a submodule opens the submodule.
+// Supports regular repositories and linked worktrees (where .git is a file), including worktrees
+// of a bare repository. A bare repository itself has no worktree and is rejected.
 func Open(path string) (*Repo, error) {
 	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{
+		DetectDotGit:          true,
 		EnableDotGitCommonDir: true,
 	})
 	if err != nil {
@@ -69,6 +73,12 @@ func Open(path string) (*Repo, error) {
 
 	// get the worktree root path
 	wt, err := repo.Worktree()
+	if err == nil && isBareGitDir(repo, wt.Filesystem.Root()) {
+		err = git.ErrIsBareRepository
+	}
+	if errors.Is(err, git.ErrIsBareRepository) {
+		return nil, fmt.Errorf("open repository: %s is a bare repository, run inside one of its worktrees", path)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("get worktree: %w", err)
 	}
@@ -76,6 +86,27 @@ func Open(path string) (*Repo, error) {
 	return &Repo{repo: repo, path: wt.Filesystem.Root()}, nil
 }
 
+// isBareGitDir reports whether the .git file at root points to a bare repository instead of a worktree's
+// own git directory, as in the bare-repo layout where the project directory holds "gitdir: ./.bare" next to
+// the worktrees. go-git treats the directory holding such a file as a worktree. linked worktrees share
+// core.bare=true with their bare repository, but their git directory has a commondir file.
+func isBareGitDir(repo *git.Repository, root string) bool {
+	cfg, err := repo.Config()
+	if err != nil || !cfg.Core.IsBare {
+		return false
+	}
+	data, err := os.ReadFile(filepath.Join(root, git.GitDirName)) //nolint:gosec // path inside the repository
+	if err != nil {
+		return false // .git is a directory
+	}
+	gitDir := strings.TrimSpace(strings.TrimPrefix(string(data), "gitdir:"))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(root, gitDir)
+	}
+	_, err = os.Stat(filepath.Join(gitDir, "commondir"))
+	return err != nil
+}
+
 // HasCommits returns true if the repository has at least one commit.
 func (r *Repo) HasCommits() (bool, error) {
 	_, err := r.repo.Head()
@@ -153,7 +184,8 @@ func (r *Repo) CurrentBranch() (string, error) {
 	return head.Name().Short(), nil
 }
 
-// CreateBranch creates a new branch and switches to it.
+// CreateBranch creates a new branch and switches to it. The branch HEAD is on is recorded as
+// the base of the new branch, see BranchBase.
 // Returns error if branch already exists to prevent data loss.
 func (r *Repo) CreateBranch(name string) error {
 	wt, err := r.repo.Worktree()
@@ -189,6 +221,11 @@ func (r *Repo) CreateBranch(name string) error {
 			return fmt.Errorf("create branch config: %w", err)
 		}
 	}
+	if head.Name().IsBranch() {
+		if err := r.setBranchBase(name, head.Name().Short()); err != nil {
+			return fmt.Errorf("record base branch: %w", err)
+		}
+	}
 
 	// checkout the new branch, Keep preserves untracked files
 	if err := wt.Checkout(&git.CheckoutOptions{Branch: branchRef, Keep: true}); err != nil {
@@ -276,6 +313,14 @@ func (r *Repo) Add(path string) error {
 		return fmt.Errorf("get worktree: %w", err)
 	}
 
+	rules, err := r.lfsRules()
+	if err != nil {
+		return err
+	}
+	if err := r.checkLFSStage(rules, filepath.ToSlash(rel)); err != nil {
+		return fmt.Errorf("add file: %w", err)
+	}
+
 	if _, err := wt.Add(rel); err != nil {
 		return fmt.Errorf("add file: %w", err)
 	}
@@ -283,14 +328,106 @@ func (r *Repo) Add(path string) error {
 	return nil
 }
 
+// StageAll stages all worktree changes: modified and new non-ignored files are added,
+// deleted files are removed from the index. Paths are staged in sorted order.
+// A submodule that moved to another commit is staged at that commit. Changed files tracked by
+// git LFS are refused, staging them without the LFS filter would store the raw content.
+func (r *Repo) StageAll() error {
+	wt, err := r.repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("get worktree: %w", err)
+	}
+
+	status, rules, err := r.status()
+	if err != nil {
+		return err
+	}
+	subs, err := r.submodules()
+	if err != nil {
+		return err
+	}
+
+	return r.stageStatus(wt, status, rules, subs)
+}
+
+// stageStatus stages every path of status whose worktree state differs from the index, in sorted order.
+// deleted paths are removed, untracked paths are added unless ignored, and all other states (modified,
+// added, renamed, copied and unmerged) are staged with their worktree content, which also resolves
+// a conflict like git add. the old path of a rename is removed when it's gone from the worktree.
+func (r *Repo) stageStatus(wt *git.Worktree, status git.Status, rules []lfsRule, subs map[string]*Repo) error {
+	paths := make([]string, 0, len(status))
+	for path := range status {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		s := status[path]
+		switch s.Worktree {
+		case git.Unmodified:
+			// unmodified in worktree, already staged or nothing to do
+		case git.Deleted:
+			if _, err := wt.Remove(path); err != nil {
+				return fmt.Errorf("stage removal of %s: %w", path, err)
+			}
+		case git.Untracked:
+			ignored, ignoreErr := r.IsIgnored(path)
+			if ignoreErr != nil {
+				return fmt.Errorf("check ignored %s: %w", path, ignoreErr)
+			}
+			if ignored {
+				continue
+			}
+			if err := r.checkLFSStage(rules, path); err != nil {
+				return fmt.Errorf("stage %s: %w", path, err)
+			}
+			if _, err := wt.Add(path); err != nil {
+				return fmt.Errorf("stage %s: %w", path, err)
+			}
+		default:
+			if sub, ok := subs[path]; ok {
+				if err := r.stageSubmodule(path, sub); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := r.checkLFSStage(rules, path); err != nil {
+				return fmt.Errorf("stage %s: %w", path, err)
+			}
+			if _, err := wt.Add(path); err != nil {
+				return fmt.Errorf("stage %s: %w", path, err)
+			}
+			if s.Worktree == git.Renamed && s.Extra != "" {
+				if _, statErr := os.Lstat(filepath.Join(r.path, filepath.FromSlash(s.Extra))); os.IsNotExist(statErr) {
+					if _, err := wt.Remove(s.Extra); err != nil {
+						return fmt.Errorf("stage removal of %s: %w", s.Extra, err)
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
 // Commit creates a commit with the given message.
-// Returns error if no changes are staged.
+// Returns error if no changes are staged or if a file tracked by git LFS is staged as a raw blob
+// instead of an LFS pointer. Submodules are committed at their staged commit, uncommitted changes
+// inside them are left out, see DirtySubmodules.
 func (r *Repo) Commit(msg string) error {
 	wt, err := r.repo.Worktree()
 	if err != nil {
 		return fmt.Errorf("get worktree: %w", err)
 	}
 
+	status, rules, err := r.status()
+	if err != nil {
+		return err
+	}
+	if err := r.checkLFSStaged(rules, status); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
 	author := r.getAuthor()
 	_, err = wt.Commit(msg, &git.CommitOptions{Author: author})
 	if err != nil {
@@ -300,6 +437,126 @@ func (r *Repo) Commit(msg string) error {
 	return nil
 }
 
+// HeadHash returns the hash of the commit HEAD points to.
+func (r *Repo) HeadHash() (string, error) {
+	head, err := r.repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("get HEAD: %w", err)
+	}
+	return head.Hash().String(), nil
+}
+
+// Diff returns the unified diff from commit from to commit to, like git diff from..to.
+func (r *Repo) Diff(from, to string) (string, error) {
+	fromCommit, err := r.commitObject(from)
+	if err != nil {
+		return "", err
+	}
+	toCommit, err := r.commitObject(to)
+	if err != nil {
+		return "", err
+	}
+	patch, err := fromCommit.Patch(toCommit)
+	if err != nil {
+		return "", fmt.Errorf("diff %s..%s: %w", from, to, err)
+	}
+	return patch.String(), nil
+}
+
+// commitObject returns the commit with the full hex hash.
+func (r *Repo) commitObject(hash string) (*object.Commit, error) {
+	if !plumbing.IsHash(hash) {
+		return nil, fmt.Errorf("invalid commit hash %q", hash)
+	}
+	commit, err := r.repo.CommitObject(plumbing.NewHash(hash))
+	if err != nil {
+		return nil, fmt.Errorf("get commit %s: %w", hash, err)
+	}
+	return commit, nil
+}
+
+// SaveAndReset preserves the current state on a side branch and hard-resets the current branch to target.
+// uncommitted changes (including untracked, non-ignored files) are committed first so the side branch
+// holds everything done since target. files in keep get their current content back after the reset.
+// Returns false if there was nothing to save and no reset was needed. An existing sideBranch is never
+// overwritten, it holds an earlier failed state.
+func (r *Repo) SaveAndReset(target, sideBranch, msg string, keep ...string) (bool, error) {
+	if r.BranchExists(sideBranch) {
+		return false, fmt.Errorf("branch %s already exists", sideBranch)
+	}
+	kept, err := readFiles(keep)
+	if err != nil {
+		return false, err
+	}
+	if err := r.StageAll(); err != nil {
+		return false, fmt.Errorf("stage changes: %w", err)
+	}
+	dirty, err := r.IsDirty()
+	if err != nil {
+		return false, err
+	}
+	if dirty {
+		if err := r.Commit(msg); err != nil {
+			return false, fmt.Errorf("commit failed state: %w", err)
+		}
+	}
+
+	head, err := r.repo.Head()
+	if err != nil {
+		return false, fmt.Errorf("get HEAD: %w", err)
+	}
+	targetHash := plumbing.NewHash(target)
+	if head.Hash() == targetHash {
+		return false, nil
+	}
+
+	sideRef := plumbing.NewHashReference(plumbing.NewBranchReferenceName(sideBranch), head.Hash())
+	if err := r.repo.Storer.SetReference(sideRef); err != nil {
+		return false, fmt.Errorf("create branch %s: %w", sideBranch, err)
+	}
+
+	wt, err := r.repo.Worktree()
+	if err != nil {
+		return false, fmt.Errorf("get worktree: %w", err)
+	}
+	if err := wt.Reset(&git.ResetOptions{Commit: targetHash, Mode: git.HardReset}); err != nil {
+		return false, fmt.Errorf("reset to %s: %w", target, err)
+	}
+	for _, f := range kept {
+		if err := os.WriteFile(f.path, f.data, f.mode); err != nil {
+			return false, fmt.Errorf("restore %s: %w", f.path, err)
+		}
+	}
+	return true, nil
+}
+
+// keptFile is the content of a file restored after a reset.
+type keptFile struct {
+	path string
+	data []byte
+	mode os.FileMode
+}
+
+// readFiles reads the given files, missing files are skipped.
+func readFiles(paths []string) ([]keptFile, error) {
+	var files []keptFile
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("stat %s: %w", p, err)
+		}
+		data, err := os.ReadFile(p) //nolint:gosec // paths supplied by the caller
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", p, err)
+		}
+		files = append(files, keptFile{path: p, data: data, mode: info.Mode().Perm()})
+	}
+	return files, nil
+}
+
 // getAuthor returns the commit author from git config or a fallback.
 // checks repository config first (.git/config), then falls back to global config,
 // and finally to default values.
@@ -403,17 +660,46 @@ func loadXDGGlobalPatterns() []gitignore.Pattern {
 	return patterns
 }
 
-// IsDirty returns true if the worktree has uncommitted changes
-// (staged or modified tracked files).
-func (r *Repo) IsDirty() (bool, error) {
+// status returns the worktree status and the LFS filter rules of the repository. files tracked by git LFS
+// that hold the content their staged pointer refers to are left out, go-git doesn't run the LFS filters
+// and would report every checked out LFS file as modified.
+func (r *Repo) status() (git.Status, []lfsRule, error) {
 	wt, err := r.repo.Worktree()
 	if err != nil {
-		return false, fmt.Errorf("get worktree: %w", err)
+		return nil, nil, fmt.Errorf("get worktree: %w", err)
 	}
 
 	status, err := wt.Status()
 	if err != nil {
-		return false, fmt.Errorf("get status: %w", err)
+		return nil, nil, fmt.Errorf("get status: %w", err)
+	}
+
+	rules, err := r.lfsRules()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(rules) == 0 {
+		return status, nil, nil
+	}
+	idx, err := r.repo.Storer.Index()
+	if err != nil {
+		return nil, nil, fmt.Errorf("read index: %w", err)
+	}
+	for path, s := range status {
+		if s.Staging == git.Unmodified && s.Worktree == git.Modified && isLFSTracked(rules, path) && r.lfsUnchanged(idx, path) {
+			delete(status, path)
+		}
+	}
+	return status, rules, nil
+}
+
+// IsDirty returns true if the worktree has uncommitted changes
+// (staged or modified tracked files, or a submodule with another commit checked out).
+// Uncommitted changes inside a submodule don't count, see DirtySubmodules.
+func (r *Repo) IsDirty() (bool, error) {
+	status, _, err := r.status()
+	if err != nil {
+		return false, err
 	}
 
 	for _, s := range status {
@@ -426,31 +712,40 @@ func (r *Repo) IsDirty() (bool, error) {
 			return true, nil
 		}
 	}
-
 	return false, nil
 }
 
-// HasChangesOtherThan returns true if there are uncommitted changes to files other than the given file.
+// HasChangesOtherThan returns true if there are uncommitted changes to files other than the given files.
 // this includes modified/deleted tracked files, staged changes, and untracked files (excluding gitignored).
-func (r *Repo) HasChangesOtherThan(filePath string) (bool, error) {
-	wt, err := r.repo.Worktree()
-	if err != nil {
-		return false, fmt.Errorf("get worktree: %w", err)
+func (r *Repo) HasChangesOtherThan(filePaths ...string) (bool, error) {
+	skip := make(map[string]bool, len(filePaths))
+	for _, filePath := range filePaths {
+		relPath, err := r.normalizeToRelative(filePath)
+		if err != nil {
+			return false, err
+		}
+		skip[relPath] = true
 	}
+	return r.hasChanges(skip)
+}
 
-	status, err := wt.Status()
-	if err != nil {
-		return false, fmt.Errorf("get status: %w", err)
-	}
+// HasUncommittedChanges returns true if there are any uncommitted changes,
+// including untracked files that are not gitignored.
+func (r *Repo) HasUncommittedChanges() (bool, error) {
+	return r.hasChanges(nil)
+}
 
-	relPath, err := r.normalizeToRelative(filePath)
+// hasChanges reports uncommitted changes, skipping the paths in skip. a submodule counts as changed
+// when it has another commit checked out, not for uncommitted changes inside it.
+func (r *Repo) hasChanges(skip map[string]bool) (bool, error) {
+	status, _, err := r.status()
 	if err != nil {
 		return false, err
 	}
 
 	for path, s := range status {
-		if path == relPath {
-			continue // skip the target file
+		if skip[path] {
+			continue // skip the target files
 		}
 		if !r.fileHasChanges(s) {
 			continue
@@ -468,21 +763,15 @@ func (r *Repo) HasChangesOtherThan(filePath string) (bool, error) {
 		}
 		return true, nil
 	}
-
 	return false, nil
 }
 
 // FileHasChanges returns true if the given file has uncommitted changes.
 // this includes untracked, modified, deleted, or staged states.
 func (r *Repo) FileHasChanges(filePath string) (bool, error) {
-	wt, err := r.repo.Worktree()
+	status, _, err := r.status()
 	if err != nil {
-		return false, fmt.Errorf("get worktree: %w", err)
-	}
-
-	status, err := wt.Status()
-	if err != nil {
-		return false, fmt.Errorf("get status: %w", err)
+		return false, err
 	}
 
 	relPath, err := r.normalizeToRelative(filePath)
@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/go-git/go-git/v5"
@@ -20,6 +21,15 @@ func TestOpen(t *testing.T) {
 		assert.NotNil(t, repo)
 	})
 
+	t.Run("opens from subdirectory", func(t *testing.T) {
+		dir := setupTestRepo(t)
+		sub := filepath.Join(dir, "services", "api")
+		require.NoError(t, os.MkdirAll(sub, 0o750))
+		repo, err := Open(sub)
+		require.NoError(t, err)
+		assert.Equal(t, dir, repo.Root())
+	})
+
 	t.Run("fails on non-repo", func(t *testing.T) {
 		dir := t.TempDir()
 		_, err := Open(dir)
@@ -42,9 +52,75 @@ func TestOpen(t *testing.T) {
 		branch, err := repo.CurrentBranch()
 		require.NoError(t, err)
 		assert.Equal(t, "wt-branch", branch)
+
+		sub := filepath.Join(wtDir, "docs")
+		require.NoError(t, os.MkdirAll(sub, 0o750))
+		repo, err = Open(sub)
+		require.NoError(t, err)
+		assert.Equal(t, wtDir, repo.Root(), "worktree root found from subdirectory")
+	})
+
+	t.Run("opens submodule as its own repository", func(t *testing.T) {
+		libDir := setupTestRepo(t)
+		mainDir := setupTestRepo(t)
+		runGit(t, mainDir, "-c", "protocol.file.allow=always", "submodule", "add", libDir, "lib")
+		require.NoError(t, os.MkdirAll(filepath.Join(mainDir, "lib", "pkg"), 0o750))
+
+		repo, err := Open(filepath.Join(mainDir, "lib", "pkg"))
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(mainDir, "lib"), repo.Root())
+		head, err := repo.HeadHash()
+		require.NoError(t, err)
+		libHead := runGit(t, libDir, "rev-parse", "HEAD")
+		assert.Equal(t, libHead, head, "submodule history, not the superproject's")
+
+		repo, err = Open(mainDir)
+		require.NoError(t, err)
+		assert.Equal(t, mainDir, repo.Root())
+	})
+
+	t.Run("bare repository with worktrees", func(t *testing.T) {
+		src := setupTestRepo(t)
+		project := t.TempDir()
+		bareDir := filepath.Join(project, ".bare")
+		runGit(t, project, "clone", "--bare", src, bareDir)
+		require.NoError(t, os.WriteFile(filepath.Join(project, ".git"), []byte("gitdir: ./.bare\n"), 0o600))
+		wtDir := filepath.Join(project, "feature")
+		runGit(t, project, "worktree", "add", "-b", "feature", wtDir)
+		require.NoError(t, os.MkdirAll(filepath.Join(wtDir, "cmd"), 0o750))
+
+		repo, err := Open(filepath.Join(wtDir, "cmd"))
+		require.NoError(t, err)
+		assert.Equal(t, wtDir, repo.Root())
+		branch, err := repo.CurrentBranch()
+		require.NoError(t, err)
+		assert.Equal(t, "feature", branch)
+
+		require.NoError(t, os.WriteFile(filepath.Join(wtDir, "new.txt"), []byte("new\n"), 0o600))
+		require.NoError(t, repo.Add("new.txt"))
+		require.NoError(t, repo.Commit("add new"))
+		out := runGit(t, wtDir, "log", "-1", "--format=%s")
+		assert.Equal(t, "add new", out)
+
+		_, err = Open(bareDir)
+		require.ErrorContains(t, err, "is a bare repository")
+		_, err = Open(project)
+		require.ErrorContains(t, err, "is a bare repository")
 	})
 }
 
+// runGit runs the git CLI in dir and returns its trimmed output, for fixtures go-git can't create.
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@test.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@test.com")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "git %v: %s", args, out)
+	return strings.TrimSpace(string(out))
+}
+
 func TestRepo_toRelative(t *testing.T) {
 	t.Run("returns repo-relative path unchanged", func(t *testing.T) {
 		dir := setupTestRepo(t)
@@ -313,6 +389,280 @@ func TestRepo_Commit(t *testing.T) {
 	})
 }
 
+func TestRepo_StageAll(t *testing.T) {
+	t.Run("stages modified, new and deleted files", func(t *testing.T) {
+		dir := setupTestRepo(t)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "old.txt"), []byte("old\n"), 0o600))
+		repo, err := Open(dir)
+		require.NoError(t, err)
+		require.NoError(t, repo.Add("old.txt"))
+		require.NoError(t, repo.Commit("add old"))
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Modified\n"), 0o600))
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "pkg"), 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "pkg", "new.go"), []byte("package pkg\n"), 0o600))
+		require.NoError(t, os.Remove(filepath.Join(dir, "old.txt")))
+
+		require.NoError(t, repo.StageAll())
+		require.NoError(t, repo.Commit("stage all"))
+
+		dirty, err := repo.IsDirty()
+		require.NoError(t, err)
+		assert.False(t, dirty)
+
+		head, err := repo.repo.Head()
+		require.NoError(t, err)
+		commit, err := repo.repo.CommitObject(head.Hash())
+		require.NoError(t, err)
+		_, err = commit.File("pkg/new.go")
+		require.NoError(t, err)
+		_, err = commit.File("old.txt")
+		require.Error(t, err, "deleted file should be removed from tree")
+		readme, err := commit.File("README.md")
+		require.NoError(t, err)
+		content, err := readme.Contents()
+		require.NoError(t, err)
+		assert.Equal(t, "# Modified\n", content)
+	})
+
+	t.Run("skips ignored files", func(t *testing.T) {
+		dir := setupTestRepo(t)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("progress*.txt\n"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "progress-plan.txt"), []byte("log\n"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o600))
+		repo, err := Open(dir)
+		require.NoError(t, err)
+
+		require.NoError(t, repo.StageAll())
+		require.NoError(t, repo.Commit("stage all"))
+
+		head, err := repo.repo.Head()
+		require.NoError(t, err)
+		commit, err := repo.repo.CommitObject(head.Hash())
+		require.NoError(t, err)
+		_, err = commit.File("main.go")
+		require.NoError(t, err)
+		_, err = commit.File("progress-plan.txt")
+		require.Error(t, err, "ignored file should not be committed")
+	})
+
+	t.Run("stages renamed, copied and unmerged states", func(t *testing.T) {
+		dir := setupTestRepo(t)
+		repo, err := Open(dir)
+		require.NoError(t, err)
+		require.NoError(t, os.Rename(filepath.Join(dir, "README.md"), filepath.Join(dir, "DOCS.md")))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "copy.md"), []byte("# Test\n"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "merged.go"), []byte("package merged\n"), 0o600))
+
+		// go-git's status doesn't detect these states itself, other git tools leave them in the index
+		wt, err := repo.repo.Worktree()
+		require.NoError(t, err)
+		status := git.Status{
+			"DOCS.md":   {Staging: git.Unmodified, Worktree: git.Renamed, Extra: "README.md"},
+			"copy.md":   {Staging: git.Unmodified, Worktree: git.Copied},
+			"merged.go": {Staging: git.UpdatedButUnmerged, Worktree: git.UpdatedButUnmerged},
+		}
+		require.NoError(t, repo.stageStatus(wt, status, nil, nil))
+		require.NoError(t, repo.Commit("stage all"))
+
+		files := runGit(t, dir, "ls-tree", "--name-only", "HEAD")
+		assert.Equal(t, "DOCS.md\ncopy.md\nmerged.go", files, "old name of the rename is removed")
+	})
+
+	t.Run("resolves merge conflict", func(t *testing.T) {
+		dir := setupTestRepo(t)
+		runGit(t, dir, "checkout", "-q", "-b", "other")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Other\n"), 0o600))
+		runGit(t, dir, "commit", "-q", "-am", "other")
+		runGit(t, dir, "checkout", "-q", "master")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Master\n"), 0o600))
+		runGit(t, dir, "commit", "-q", "-am", "master")
+		cmd := exec.Command("git", "merge", "other")
+		cmd.Dir = dir
+		require.Error(t, cmd.Run(), "merge conflicts")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Resolved\n"), 0o600))
+
+		repo, err := Open(dir)
+		require.NoError(t, err)
+		require.NoError(t, repo.StageAll())
+		require.NoError(t, repo.Commit("resolve"))
+		assert.Equal(t, "# Resolved", runGit(t, dir, "show", "HEAD:README.md"))
+		assert.Empty(t, runGit(t, dir, "diff", "--name-only", "--diff-filter=U"))
+	})
+
+	t.Run("clean worktree is a no-op", func(t *testing.T) {
+		dir := setupTestRepo(t)
+		repo, err := Open(dir)
+		require.NoError(t, err)
+
+		require.NoError(t, repo.StageAll())
+		dirty, err := repo.IsDirty()
+		require.NoError(t, err)
+		assert.False(t, dirty)
+	})
+}
+
+func TestRepo_SaveAndReset(t *testing.T) {
+	t.Run("saves failed state and resets", func(t *testing.T) {
+		dir := setupTestRepo(t)
+		repo, err := Open(dir)
+		require.NoError(t, err)
+		target, err := repo.HeadHash()
+		require.NoError(t, err)
+
+		// failed attempt: one commit, then uncommitted edits and a new file
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "committed.go"), []byte("package a\n"), 0o600))
+		require.NoError(t, repo.Add("committed.go"))
+		require.NoError(t, repo.Commit("half done"))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Broken\n"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "new.go"), []byte("package broken\n"), 0o600))
+
+		saved, err := repo.SaveAndReset(target, "ralphex/failed/test", "failed attempt")
+		require.NoError(t, err)
+		assert.True(t, saved)
+
+		head, err := repo.HeadHash()
+		require.NoError(t, err)
+		assert.Equal(t, target, head)
+		content, err := os.ReadFile(filepath.Join(dir, "README.md")) //nolint:gosec // test file
+		require.NoError(t, err)
+		assert.Equal(t, "# Test\n", string(content))
+		assert.NoFileExists(t, filepath.Join(dir, "new.go"))
+		assert.NoFileExists(t, filepath.Join(dir, "committed.go"))
+		changes, err := repo.HasUncommittedChanges()
+		require.NoError(t, err)
+		assert.False(t, changes)
+
+		// side branch keeps everything from the failed attempt
+		ref, err := repo.repo.Reference("refs/heads/ralphex/failed/test", true)
+		require.NoError(t, err)
+		side, err := repo.repo.CommitObject(ref.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, "failed attempt", side.Message)
+		_, err = side.File("new.go")
+		require.NoError(t, err)
+		_, err = side.File("committed.go")
+		require.NoError(t, err)
+	})
+
+	t.Run("keeps own files", func(t *testing.T) {
+		dir := setupTestRepo(t)
+		repo, err := Open(dir)
+		require.NoError(t, err)
+
+		gitignore := filepath.Join(dir, ".gitignore")
+		require.NoError(t, os.WriteFile(gitignore, []byte("*.log\n"), 0o600))
+		require.NoError(t, repo.Add(".gitignore"))
+		require.NoError(t, repo.Commit("add gitignore"))
+		target, err := repo.HeadHash()
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(gitignore, []byte("*.log\n.ralphex/progress/\n"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Broken\n"), 0o600))
+
+		saved, err := repo.SaveAndReset(target, "ralphex/failed/test", "failed attempt", gitignore,
+			filepath.Join(dir, "missing.txt"))
+		require.NoError(t, err)
+		assert.True(t, saved)
+
+		content, err := os.ReadFile(gitignore) //nolint:gosec // test file
+		require.NoError(t, err)
+		assert.Equal(t, "*.log\n.ralphex/progress/\n", string(content))
+		content, err = os.ReadFile(filepath.Join(dir, "README.md")) //nolint:gosec // test file
+		require.NoError(t, err)
+		assert.Equal(t, "# Test\n", string(content))
+		changes, err := repo.HasChangesOtherThan(gitignore)
+		require.NoError(t, err)
+		assert.False(t, changes)
+	})
+
+	t.Run("nothing to save", func(t *testing.T) {
+		dir := setupTestRepo(t)
+		repo, err := Open(dir)
+		require.NoError(t, err)
+		target, err := repo.HeadHash()
+		require.NoError(t, err)
+
+		saved, err := repo.SaveAndReset(target, "ralphex/failed/test", "failed attempt")
+		require.NoError(t, err)
+		assert.False(t, saved)
+		assert.False(t, repo.BranchExists("ralphex/failed/test"))
+	})
+
+	t.Run("existing side branch", func(t *testing.T) {
+		dir := setupTestRepo(t)
+		repo, err := Open(dir)
+		require.NoError(t, err)
+		target, err := repo.HeadHash()
+		require.NoError(t, err)
+		require.NoError(t, repo.CreateBranch("ralphex/failed/test"))
+		require.NoError(t, repo.CheckoutBranch("master"))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Broken\n"), 0o600))
+
+		_, err = repo.SaveAndReset(target, "ralphex/failed/test", "failed attempt")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "branch ralphex/failed/test already exists")
+		content, err := os.ReadFile(filepath.Join(dir, "README.md")) //nolint:gosec // test file
+		require.NoError(t, err)
+		assert.Equal(t, "# Broken\n", string(content), "failed state is left in place")
+	})
+}
+
+func TestRepo_Diff(t *testing.T) {
+	dir := setupTestRepo(t)
+	repo, err := Open(dir)
+	require.NoError(t, err)
+	from, err := repo.HeadHash()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Test\nmore\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new.go"), []byte("package a\n"), 0o600))
+	require.NoError(t, repo.Add("README.md"))
+	require.NoError(t, repo.Add("new.go"))
+	require.NoError(t, repo.Commit("iteration"))
+	to, err := repo.HeadHash()
+	require.NoError(t, err)
+
+	t.Run("changes between commits", func(t *testing.T) {
+		diff, err := repo.Diff(from, to)
+		require.NoError(t, err)
+		assert.Contains(t, diff, "diff --git a/README.md b/README.md\n")
+		assert.Contains(t, diff, " # Test\n+more\n")
+		assert.Contains(t, diff, "diff --git a/new.go b/new.go\nnew file mode 100644\n")
+		assert.Contains(t, diff, "+package a\n")
+	})
+
+	t.Run("same commit", func(t *testing.T) {
+		diff, err := repo.Diff(to, to)
+		require.NoError(t, err)
+		assert.Empty(t, diff)
+	})
+
+	t.Run("invalid hash", func(t *testing.T) {
+		_, err := repo.Diff("HEAD", to)
+		require.ErrorContains(t, err, `invalid commit hash "HEAD"`)
+	})
+
+	t.Run("unknown commit", func(t *testing.T) {
+		_, err := repo.Diff(strings.Repeat("a", 40), to)
+		require.ErrorContains(t, err, "get commit aaaa")
+	})
+}
+
+func TestRepo_HasUncommittedChanges(t *testing.T) {
+	dir := setupTestRepo(t)
+	repo, err := Open(dir)
+	require.NoError(t, err)
+
+	changes, err := repo.HasUncommittedChanges()
+	require.NoError(t, err)
+	assert.False(t, changes)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "untracked.txt"), []byte("x"), 0o600))
+	changes, err = repo.HasUncommittedChanges()
+	require.NoError(t, err)
+	assert.True(t, changes, "untracked files count as changes")
+}
+
 func TestRepo_getAuthor(t *testing.T) {
 	t.Run("returns valid signature", func(t *testing.T) {
 		dir := setupTestRepo(t)
@@ -884,6 +1234,19 @@ func TestRepo_HasChangesOtherThan(t *testing.T) {
 		assert.True(t, hasOther)
 	})
 
+	t.Run("skips every given file", func(t *testing.T) {
+		dir := setupTestRepo(t)
+		repo, err := Open(dir)
+		require.NoError(t, err)
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "progress.txt"), []byte("log"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Modified"), 0o600))
+
+		hasOther, err := repo.HasChangesOtherThan(filepath.Join(dir, "progress.txt"), filepath.Join(dir, "README.md"))
+		require.NoError(t, err)
+		assert.False(t, hasOther)
+	})
+
 	t.Run("returns true when tracked file is modified", func(t *testing.T) {
 		dir := setupTestRepo(t)
 		repo, err := Open(dir)
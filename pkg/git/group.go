@@ -0,0 +1,134 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+// Group is the primary repository plus the additional repositories of a multi-repo plan.
+// it stages and commits across all of them, so runner-managed commits land in every repository
+// the iteration changed, each with the same message.
+type Group struct {
+	primary *Repo
+	extra   []*Repo
+}
+
+// OpenGroup opens the additional repositories at paths and groups them with primary.
+// relative paths are resolved against the primary repository root. each path must be
+// a repository root, listed once, and differ from the primary repository.
+func OpenGroup(primary *Repo, paths []string) (*Group, error) {
+	g := &Group{primary: primary}
+	seen := map[string]bool{primary.Root(): true}
+	for _, p := range paths {
+		abs := p
+		if !filepath.IsAbs(abs) {
+			abs = filepath.Join(primary.Root(), p)
+		}
+		abs = filepath.Clean(abs)
+		if seen[abs] {
+			return nil, fmt.Errorf("repository %s is listed more than once", p)
+		}
+		seen[abs] = true
+
+		repo, err := Open(abs)
+		if err != nil {
+			return nil, fmt.Errorf("repository %s: %w", p, err)
+		}
+		if repo.Root() != abs {
+			return nil, fmt.Errorf("repository %s: not a repository root, the root is %s", p, repo.Root())
+		}
+		g.extra = append(g.extra, repo)
+	}
+	return g, nil
+}
+
+// Primary returns the primary repository, the one ralphex runs in.
+func (g *Group) Primary() *Repo {
+	return g.primary
+}
+
+// Extra returns the additional repositories, empty for a single-repo plan.
+func (g *Group) Extra() []*Repo {
+	return g.extra
+}
+
+// Roots returns the absolute root paths of the additional repositories.
+func (g *Group) Roots() []string {
+	roots := make([]string, 0, len(g.extra))
+	for _, r := range g.extra {
+		roots = append(roots, r.Root())
+	}
+	return roots
+}
+
+// all returns the primary repository followed by the additional ones.
+func (g *Group) all() []*Repo {
+	return append([]*Repo{g.primary}, g.extra...)
+}
+
+// StageAll stages all worktree changes in every repository.
+func (g *Group) StageAll() error {
+	for _, r := range g.all() {
+		if err := r.StageAll(); err != nil {
+			return fmt.Errorf("%s: %w", r.Root(), err)
+		}
+	}
+	return nil
+}
+
+// IsDirty returns true if any repository has uncommitted changes.
+func (g *Group) IsDirty() (bool, error) {
+	for _, r := range g.all() {
+		dirty, err := r.IsDirty()
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", r.Root(), err)
+		}
+		if dirty {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// DirtySubmodules returns the submodules with uncommitted changes inside them across all repositories.
+// submodules of additional repositories are prefixed with the repository root.
+func (g *Group) DirtySubmodules() ([]string, error) {
+	var result []string
+	for i, r := range g.all() {
+		dirty, err := r.DirtySubmodules()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", r.Root(), err)
+		}
+		for _, d := range dirty {
+			if i > 0 {
+				d = filepath.Join(r.Root(), filepath.FromSlash(d))
+			}
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
+// Commit commits staged changes with msg in every repository that has them.
+// clean repositories are skipped. returns an error if no repository had changes.
+func (g *Group) Commit(msg string) error {
+	committed := false
+	for _, r := range g.all() {
+		dirty, err := r.IsDirty()
+		if err != nil {
+			return fmt.Errorf("%s: %w", r.Root(), err)
+		}
+		if !dirty {
+			continue
+		}
+		if err := r.Commit(msg); err != nil {
+			return fmt.Errorf("%s: %w", r.Root(), err)
+		}
+		committed = true
+	}
+	if !committed {
+		return errors.New("commit: no changes in any repository")
+	}
+	return nil
+}
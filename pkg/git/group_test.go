@@ -0,0 +1,107 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenGroup(t *testing.T) {
+	primaryDir := setupTestRepo(t)
+	primary, err := Open(primaryDir)
+	require.NoError(t, err)
+	libDir := setupTestRepo(t)
+
+	t.Run("absolute and relative paths", func(t *testing.T) {
+		rel, err := filepath.Rel(primaryDir, libDir)
+		require.NoError(t, err)
+		g, err := OpenGroup(primary, []string{rel})
+		require.NoError(t, err)
+		assert.Same(t, primary, g.Primary())
+		require.Len(t, g.Extra(), 1)
+		assert.Equal(t, []string{libDir}, g.Roots())
+	})
+
+	t.Run("no additional repositories", func(t *testing.T) {
+		g, err := OpenGroup(primary, nil)
+		require.NoError(t, err)
+		assert.Empty(t, g.Extra())
+		assert.Empty(t, g.Roots())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		sub := filepath.Join(libDir, "sub")
+		require.NoError(t, os.MkdirAll(sub, 0o750))
+		tests := map[string][]string{
+			"listed more than once":     {libDir, libDir + "/"},
+			"primary repository":        {primaryDir},
+			"not a git repository":      {t.TempDir()},
+			"missing directory":         {filepath.Join(t.TempDir(), "missing")},
+			"inside another repository": {sub},
+		}
+		for name, paths := range tests {
+			_, err := OpenGroup(primary, paths)
+			assert.Error(t, err, name)
+		}
+	})
+}
+
+func TestGroup_Commit(t *testing.T) {
+	primaryDir, libDir, docsDir := setupTestRepo(t), setupTestRepo(t), setupTestRepo(t)
+	primary, err := Open(primaryDir)
+	require.NoError(t, err)
+	g, err := OpenGroup(primary, []string{libDir, docsDir})
+	require.NoError(t, err)
+
+	dirty, err := g.IsDirty()
+	require.NoError(t, err)
+	assert.False(t, dirty)
+	require.NoError(t, g.StageAll())
+	require.Error(t, g.Commit("nothing"), "no repository has changes")
+
+	require.NoError(t, os.WriteFile(filepath.Join(primaryDir, "app.go"), []byte("package app\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(libDir, "lib.go"), []byte("package lib\n"), 0o600))
+	require.NoError(t, g.StageAll())
+	dirty, err = g.IsDirty()
+	require.NoError(t, err)
+	assert.True(t, dirty)
+	require.NoError(t, g.Commit("feat: task 1"))
+
+	for dir, want := range map[string]string{primaryDir: "feat: task 1", libDir: "feat: task 1", docsDir: "initial commit"} {
+		repo, err := Open(dir)
+		require.NoError(t, err)
+		head, err := repo.repo.Head()
+		require.NoError(t, err)
+		c, err := repo.repo.CommitObject(head.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, want, c.Message, dir)
+	}
+	dirty, err = g.IsDirty()
+	require.NoError(t, err)
+	assert.False(t, dirty)
+}
+
+func TestGroup_DirtySubmodules(t *testing.T) {
+	primaryDir, _ := setupSubmoduleRepo(t)
+	primary, err := Open(primaryDir)
+	require.NoError(t, err)
+	extraDir, _ := setupSubmoduleRepo(t)
+	g, err := OpenGroup(primary, []string{extraDir})
+	require.NoError(t, err)
+
+	subs, err := g.DirtySubmodules()
+	require.NoError(t, err)
+	assert.Empty(t, subs)
+
+	require.NoError(t, os.WriteFile(filepath.Join(primaryDir, "lib", "new.go"), []byte("package lib\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(extraDir, "lib", "new.go"), []byte("package lib\n"), 0o600))
+	subs, err = g.DirtySubmodules()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"lib", filepath.Join(extraDir, "lib")}, subs)
+	dirty, err := g.IsDirty()
+	require.NoError(t, err)
+	assert.False(t, dirty, "changes inside submodules don't make the group dirty")
+}
@@ -0,0 +1,252 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// HistoryMode selects how RewriteHistory reshapes the branch commits.
+type HistoryMode string
+
+// history rewrite modes.
+const (
+	HistoryKeep    HistoryMode = "keep"     // leave history untouched
+	HistorySquash  HistoryMode = "squash"   // squash all branch commits into one
+	HistoryPerTask HistoryMode = "per-task" // one commit per task plus one per review phase
+)
+
+// branchBaseKey is the branch config option holding the branch a branch was created from.
+const branchBaseKey = "ralphex-base"
+
+// BackupRefPrefix is the namespace of refs preserving the original branch tip before a rewrite.
+const BackupRefPrefix = "refs/ralphex/backup/"
+
+// trailer keys written by runner-managed commits, used to group commits per task or phase.
+const (
+	trailerTask  = "Ralphex-Task:"
+	trailerPhase = "Ralphex-Phase:"
+)
+
+// HistoryMessages builds commit messages for rewritten history.
+type HistoryMessages struct {
+	Squash string                    // message for the squashed commit, also used when commits can't be grouped
+	Task   func(num string) string   // message for a task group, num is the Ralphex-Task trailer value
+	Phase  func(phase string) string // message for a review phase group, phase is the Ralphex-Phase trailer value
+}
+
+// RewriteResult describes a completed history rewrite.
+type RewriteResult struct {
+	BackupRef string // ref holding the original branch tip, empty if nothing was rewritten
+	Original  int    // number of branch commits before rewrite
+	Rewritten int    // number of branch commits after rewrite
+}
+
+// commitGroup is a run of consecutive branch commits collapsed into one.
+type commitGroup struct {
+	key     string // "task:<n>", "phase:<name>" or empty for ungrouped commits
+	commits []*object.Commit
+}
+
+// BranchBase returns the branch the given branch was created from, recorded by CreateBranch as
+// branch.<name>.ralphex-base in the repository config. for branches created outside ralphex it can be
+// set with git config. returns an error if no base is recorded, guessing one could squash foreign commits.
+func (r *Repo) BranchBase(branch string) (string, error) {
+	cfg, err := r.repo.Config()
+	if err != nil {
+		return "", fmt.Errorf("read config: %w", err)
+	}
+	base := cfg.Raw.Section("branch").Subsection(branch).Option(branchBaseKey)
+	if base == "" {
+		return "", fmt.Errorf("no base branch recorded for %s, set it with git config branch.%s.%s <base>",
+			branch, branch, branchBaseKey)
+	}
+	return base, nil
+}
+
+// setBranchBase records base as the branch the given branch was created from.
+func (r *Repo) setBranchBase(branch, base string) error {
+	cfg, err := r.repo.Config()
+	if err != nil {
+		return fmt.Errorf("read config: %w", err)
+	}
+	cfg.Raw.Section("branch").Subsection(branch).SetOption(branchBaseKey, base)
+	if err := r.repo.SetConfig(cfg); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	return nil
+}
+
+// RewriteHistory reshapes commits made on the current branch since it diverged from base.
+// the final tree is unchanged, only the commit sequence is replaced, so the worktree and index stay as is.
+// the original tip is saved under BackupRefPrefix/<branch>/<timestamp> before the branch is moved.
+// per-task grouping relies on Ralphex-Task and Ralphex-Phase trailers, commits without trailers
+// are folded into the neighboring group.
+func (r *Repo) RewriteHistory(base string, mode HistoryMode, msgs HistoryMessages) (RewriteResult, error) {
+	switch mode {
+	case HistoryKeep, "":
+		return RewriteResult{}, nil
+	case HistorySquash, HistoryPerTask:
+	default:
+		return RewriteResult{}, fmt.Errorf("unknown history mode %q", mode)
+	}
+
+	head, err := r.repo.Head()
+	if err != nil {
+		return RewriteResult{}, fmt.Errorf("get HEAD: %w", err)
+	}
+	if !head.Name().IsBranch() {
+		return RewriteResult{}, errors.New("detached HEAD, refusing to rewrite history")
+	}
+	branch := head.Name().Short()
+	if branch == base {
+		return RewriteResult{}, fmt.Errorf("current branch is %s, refusing to rewrite history", base)
+	}
+
+	commits, forkPoint, err := r.branchCommits(head.Hash(), base)
+	if err != nil {
+		return RewriteResult{}, err
+	}
+	if len(commits) == 0 {
+		return RewriteResult{}, nil
+	}
+
+	groups := []commitGroup{{commits: commits}}
+	if mode == HistoryPerTask {
+		groups = groupCommits(commits)
+	}
+
+	parent := forkPoint
+	for _, g := range groups {
+		if parent, err = r.writeGroupCommit(g, parent, groupMessage(g.key, msgs)); err != nil {
+			return RewriteResult{}, err
+		}
+	}
+
+	backupRef := plumbing.ReferenceName(BackupRefPrefix + branch + "/" + time.Now().Format("20060102-150405"))
+	if err := r.repo.Storer.SetReference(plumbing.NewHashReference(backupRef, head.Hash())); err != nil {
+		return RewriteResult{}, fmt.Errorf("create backup ref: %w", err)
+	}
+	if err := r.repo.Storer.SetReference(plumbing.NewHashReference(head.Name(), parent)); err != nil {
+		return RewriteResult{}, fmt.Errorf("update branch %s: %w", branch, err)
+	}
+
+	return RewriteResult{BackupRef: backupRef.String(), Original: len(commits), Rewritten: len(groups)}, nil
+}
+
+// branchCommits returns commits reachable from tip but not from base, oldest first, and the fork point hash.
+// only linear history is supported, merge commits on the branch are rejected.
+func (r *Repo) branchCommits(tip plumbing.Hash, base string) ([]*object.Commit, plumbing.Hash, error) {
+	baseRef, err := r.repo.Reference(plumbing.NewBranchReferenceName(base), true)
+	if err != nil {
+		return nil, plumbing.ZeroHash, fmt.Errorf("get base branch %s: %w", base, err)
+	}
+	baseCommit, err := r.repo.CommitObject(baseRef.Hash())
+	if err != nil {
+		return nil, plumbing.ZeroHash, fmt.Errorf("get base commit: %w", err)
+	}
+	tipCommit, err := r.repo.CommitObject(tip)
+	if err != nil {
+		return nil, plumbing.ZeroHash, fmt.Errorf("get tip commit: %w", err)
+	}
+
+	bases, err := tipCommit.MergeBase(baseCommit)
+	if err != nil {
+		return nil, plumbing.ZeroHash, fmt.Errorf("find merge base: %w", err)
+	}
+	if len(bases) == 0 {
+		return nil, plumbing.ZeroHash, fmt.Errorf("branch has no common history with %s", base)
+	}
+	forkPoint := bases[0].Hash
+
+	var commits []*object.Commit
+	for c := tipCommit; c.Hash != forkPoint; {
+		if c.NumParents() != 1 {
+			return nil, plumbing.ZeroHash, fmt.Errorf("commit %s is a merge or root commit, refusing to rewrite history", c.Hash.String()[:7])
+		}
+		commits = append(commits, c)
+		if c, err = c.Parent(0); err != nil {
+			return nil, plumbing.ZeroHash, fmt.Errorf("get parent: %w", err)
+		}
+	}
+
+	// reverse to oldest first
+	for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+		commits[i], commits[j] = commits[j], commits[i]
+	}
+	return commits, forkPoint, nil
+}
+
+// groupCommits splits commits into consecutive runs sharing the same task or phase trailer.
+// commits without trailers join the current group, leading ones join the first keyed group.
+func groupCommits(commits []*object.Commit) []commitGroup {
+	var groups []commitGroup
+	var pending []*object.Commit
+	for _, c := range commits {
+		key := commitKey(c.Message)
+		switch {
+		case key == "" && len(groups) == 0:
+			pending = append(pending, c)
+		case len(groups) > 0 && (key == "" || key == groups[len(groups)-1].key):
+			groups[len(groups)-1].commits = append(groups[len(groups)-1].commits, c)
+		default:
+			groups = append(groups, commitGroup{key: key, commits: append(pending, c)})
+			pending = nil
+		}
+	}
+	if len(pending) > 0 {
+		groups = append(groups, commitGroup{commits: pending})
+	}
+	return groups
+}
+
+// commitKey extracts the grouping key from Ralphex-Task or Ralphex-Phase trailers.
+func commitKey(msg string) string {
+	for line := range strings.SplitSeq(msg, "\n") {
+		line = strings.TrimSpace(line)
+		if v, ok := strings.CutPrefix(line, trailerTask); ok {
+			return "task:" + strings.TrimSpace(v)
+		}
+		if v, ok := strings.CutPrefix(line, trailerPhase); ok {
+			return "phase:" + strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+// groupMessage returns the commit message for a group key.
+func groupMessage(key string, msgs HistoryMessages) string {
+	if v, ok := strings.CutPrefix(key, "task:"); ok && msgs.Task != nil {
+		return msgs.Task(v)
+	}
+	if v, ok := strings.CutPrefix(key, "phase:"); ok && msgs.Phase != nil {
+		return msgs.Phase(v)
+	}
+	return msgs.Squash
+}
+
+// writeGroupCommit stores a commit with the group's final tree on top of parent and returns its hash.
+// the author of the first commit in the group is preserved.
+func (r *Repo) writeGroupCommit(g commitGroup, parent plumbing.Hash, msg string) (plumbing.Hash, error) {
+	last := g.commits[len(g.commits)-1]
+	commit := &object.Commit{
+		Author:       g.commits[0].Author,
+		Committer:    *r.getAuthor(),
+		Message:      strings.TrimRight(msg, "\n") + "\n",
+		TreeHash:     last.TreeHash,
+		ParentHashes: []plumbing.Hash{parent},
+	}
+	obj := r.repo.Storer.NewEncodedObject()
+	if err := commit.Encode(obj); err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("encode commit: %w", err)
+	}
+	hash, err := r.repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("store commit: %w", err)
+	}
+	return hash, nil
+}
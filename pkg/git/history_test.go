@@ -0,0 +1,166 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// commitFile writes content to name and commits it with msg.
+func commitFile(t *testing.T, repo *Repo, name, content, msg string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(repo.Root(), name), []byte(content), 0o600))
+	require.NoError(t, repo.Add(name))
+	require.NoError(t, repo.Commit(msg))
+}
+
+// branchLog returns commit messages from HEAD back to the master branch tip, newest first.
+func branchLog(t *testing.T, repo *Repo) []string {
+	t.Helper()
+	master, err := repo.repo.Reference(plumbing.NewBranchReferenceName("master"), true)
+	require.NoError(t, err)
+	head, err := repo.repo.Head()
+	require.NoError(t, err)
+
+	var msgs []string
+	c, err := repo.repo.CommitObject(head.Hash())
+	require.NoError(t, err)
+	for c.Hash != master.Hash() {
+		msgs = append(msgs, strings.TrimSpace(c.Message))
+		c, err = c.Parent(0)
+		require.NoError(t, err)
+	}
+	return msgs
+}
+
+// setupFeatureBranch creates a feature branch with plan, task, review and plan-move commits.
+func setupFeatureBranch(t *testing.T) *Repo {
+	t.Helper()
+	repo, err := Open(setupTestRepo(t))
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateBranch("feature"))
+
+	commitFile(t, repo, "plan.md", "# Plan\n", "add plan: feature")
+	commitFile(t, repo, "a.go", "package a\n", "feat: task 1 - A\n\nRalphex-Task: 1")
+	commitFile(t, repo, "a.go", "package a\n\n// A\n", "feat: task 1 - A\n\nRalphex-Task: 1")
+	commitFile(t, repo, "b.go", "package b\n", "feat: task 2 - B\n\nRalphex-Task: 2")
+	commitFile(t, repo, "b.go", "package b\n\n// fixed\n", "fix: address review findings\n\nRalphex-Phase: review")
+	commitFile(t, repo, "a.go", "package a\n\n// codex\n", "fix: address codex findings\n\nRalphex-Phase: codex")
+	commitFile(t, repo, "plan.md", "# Plan done\n", "move completed plan: plan.md")
+	return repo
+}
+
+func testHistoryMessages() HistoryMessages {
+	return HistoryMessages{
+		Squash: "feat: plan",
+		Task:   func(num string) string { return "task " + num },
+		Phase:  func(phase string) string { return "phase " + phase },
+	}
+}
+
+func TestRepo_RewriteHistory(t *testing.T) {
+	t.Run("squash", func(t *testing.T) {
+		repo := setupFeatureBranch(t)
+		origHead, err := repo.repo.Head()
+		require.NoError(t, err)
+
+		res, err := repo.RewriteHistory("master", HistorySquash, testHistoryMessages())
+		require.NoError(t, err)
+		assert.Equal(t, 7, res.Original)
+		assert.Equal(t, 1, res.Rewritten)
+		assert.True(t, strings.HasPrefix(res.BackupRef, BackupRefPrefix+"feature/"))
+
+		assert.Equal(t, []string{"feat: plan"}, branchLog(t, repo))
+
+		// tree is unchanged and backup ref points to the original tip
+		head, err := repo.repo.Head()
+		require.NoError(t, err)
+		newCommit, err := repo.repo.CommitObject(head.Hash())
+		require.NoError(t, err)
+		origCommit, err := repo.repo.CommitObject(origHead.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, origCommit.TreeHash, newCommit.TreeHash)
+
+		backup, err := repo.repo.Reference(plumbing.ReferenceName(res.BackupRef), true)
+		require.NoError(t, err)
+		assert.Equal(t, origHead.Hash(), backup.Hash())
+
+		dirty, err := repo.IsDirty()
+		require.NoError(t, err)
+		assert.False(t, dirty)
+	})
+
+	t.Run("per task", func(t *testing.T) {
+		repo := setupFeatureBranch(t)
+
+		res, err := repo.RewriteHistory("master", HistoryPerTask, testHistoryMessages())
+		require.NoError(t, err)
+		assert.Equal(t, 4, res.Rewritten)
+		assert.Equal(t, []string{"phase codex", "phase review", "task 2", "task 1"}, branchLog(t, repo))
+	})
+
+	t.Run("per task without trailers falls back to squash message", func(t *testing.T) {
+		repo, err := Open(setupTestRepo(t))
+		require.NoError(t, err)
+		require.NoError(t, repo.CreateBranch("feature"))
+		commitFile(t, repo, "a.go", "package a\n", "feat: a")
+		commitFile(t, repo, "b.go", "package b\n", "feat: b")
+
+		res, err := repo.RewriteHistory("master", HistoryPerTask, testHistoryMessages())
+		require.NoError(t, err)
+		assert.Equal(t, 1, res.Rewritten)
+		assert.Equal(t, []string{"feat: plan"}, branchLog(t, repo))
+	})
+
+	t.Run("keep is a no-op", func(t *testing.T) {
+		repo := setupFeatureBranch(t)
+		res, err := repo.RewriteHistory("master", HistoryKeep, testHistoryMessages())
+		require.NoError(t, err)
+		assert.Empty(t, res.BackupRef)
+		assert.Len(t, branchLog(t, repo), 7)
+	})
+
+	t.Run("refuses to rewrite base branch", func(t *testing.T) {
+		repo, err := Open(setupTestRepo(t))
+		require.NoError(t, err)
+		_, err = repo.RewriteHistory("master", HistorySquash, testHistoryMessages())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "refusing to rewrite")
+	})
+
+	t.Run("unknown mode", func(t *testing.T) {
+		repo := setupFeatureBranch(t)
+		_, err := repo.RewriteHistory("master", "rebase", testHistoryMessages())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown history mode")
+	})
+}
+
+func TestRepo_BranchBase(t *testing.T) {
+	dir := setupTestRepo(t)
+	repo, err := Open(dir)
+	require.NoError(t, err)
+
+	runGit(t, dir, "branch", "develop")
+	require.NoError(t, repo.CheckoutBranch("develop"))
+	require.NoError(t, repo.CreateBranch("feature"))
+	base, err := repo.BranchBase("feature")
+	require.NoError(t, err)
+	assert.Equal(t, "develop", base, "the branch the feature branch was created from")
+	assert.Equal(t, "develop", runGit(t, dir, "config", "branch.feature.ralphex-base"), "readable by git")
+
+	runGit(t, dir, "checkout", "-q", "-b", "manual")
+	_, err = repo.BranchBase("manual")
+	require.Error(t, err, "no guessing for branches created outside ralphex")
+	assert.Contains(t, err.Error(), "git config branch.manual.ralphex-base <base>")
+
+	runGit(t, dir, "config", "branch.manual.ralphex-base", "master")
+	base, err = repo.BranchBase("manual")
+	require.NoError(t, err)
+	assert.Equal(t, "master", base)
+}
@@ -0,0 +1,249 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+)
+
+// go-git doesn't run the git LFS clean and smudge filters. a checked out LFS file holds the real content
+// while the index holds its pointer, so go-git sees it as modified, and staging it would store the raw
+// content as a regular blob. the helpers here detect LFS-tracked paths from .gitattributes and treat
+// them explicitly.
+
+// lfsPointerVersion is the first line of a git LFS pointer file.
+const lfsPointerVersion = "version https://git-lfs.github.com/spec/v1"
+
+// lfsPointerMaxSize is the size limit of a pointer file, larger files are never pointers.
+const lfsPointerMaxSize = 1024
+
+// lfsRule is a .gitattributes line setting or unsetting the lfs filter.
+type lfsRule struct {
+	pattern gitignore.Pattern
+	lfs     bool
+}
+
+// lfsRules returns the filter rules of the .gitattributes files known to the index, read from the worktree.
+// shallower files come first and later rules win, so deeper files and later lines override, as in git.
+// a repository without LFS-tracked patterns returns no rules.
+func (r *Repo) lfsRules() ([]lfsRule, error) {
+	idx, err := r.repo.Storer.Index()
+	if err != nil {
+		return nil, fmt.Errorf("read index: %w", err)
+	}
+	var files []string
+	for _, e := range idx.Entries {
+		if path.Base(e.Name) == ".gitattributes" {
+			files = append(files, e.Name)
+		}
+	}
+	if _, err := idx.Entry(".gitattributes"); err != nil {
+		files = append(files, ".gitattributes") // new, not yet staged root file
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return strings.Count(files[i], "/") < strings.Count(files[j], "/")
+	})
+
+	var rules []lfsRule
+	hasLFS := false
+	for _, f := range files {
+		data, err := os.ReadFile(filepath.Join(r.path, filepath.FromSlash(f))) //nolint:gosec // path from the index
+		if err != nil {
+			continue // deleted in the worktree
+		}
+		var domain []string
+		if dir := path.Dir(f); dir != "." {
+			domain = strings.Split(dir, "/")
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			rule, ok := parseLFSRule(scanner.Text(), domain)
+			if !ok {
+				continue
+			}
+			hasLFS = hasLFS || rule.lfs
+			rules = append(rules, rule)
+		}
+	}
+	if !hasLFS {
+		return nil, nil
+	}
+	return rules, nil
+}
+
+// parseLFSRule parses a .gitattributes line, ok is false if the line doesn't touch the filter attribute.
+func parseLFSRule(line string, domain []string) (lfsRule, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+		return lfsRule{}, false
+	}
+	rule, ok := lfsRule{}, false
+	for _, attr := range fields[1:] {
+		switch {
+		case attr == "filter=lfs":
+			rule.lfs, ok = true, true
+		case strings.HasPrefix(attr, "filter=") || attr == "-filter" || attr == "!filter":
+			rule.lfs, ok = false, true
+		}
+	}
+	if !ok {
+		return lfsRule{}, false
+	}
+	rule.pattern = gitignore.ParsePattern(fields[0], domain)
+	return rule, true
+}
+
+// isLFSTracked reports whether the slash-separated repository path is stored with git LFS.
+func isLFSTracked(rules []lfsRule, relPath string) bool {
+	tracked := false
+	parts := strings.Split(relPath, "/")
+	for _, rule := range rules {
+		if rule.pattern.Match(parts, false) == gitignore.Exclude {
+			tracked = rule.lfs
+		}
+	}
+	return tracked
+}
+
+// lfsPointer is the content of a git LFS pointer file.
+type lfsPointer struct {
+	oid  string // sha256 of the content, hex
+	size int64
+}
+
+// parseLFSPointer parses a git LFS pointer, ok is false if data isn't a pointer.
+func parseLFSPointer(data []byte) (p lfsPointer, ok bool) {
+	if len(data) > lfsPointerMaxSize || !bytes.HasPrefix(data, []byte(lfsPointerVersion+"\n")) {
+		return lfsPointer{}, false
+	}
+	p.size = -1
+	for line := range strings.SplitSeq(string(data), "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "oid":
+			p.oid = strings.TrimPrefix(value, "sha256:")
+		case "size":
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return lfsPointer{}, false
+			}
+			p.size = n
+		}
+	}
+	return p, p.oid != "" && p.size >= 0
+}
+
+// isLFSPointerFile reports whether the worktree file at the repository path holds an LFS pointer.
+func (r *Repo) isLFSPointerFile(relPath string) (bool, error) {
+	f, err := os.Open(filepath.Join(r.path, filepath.FromSlash(relPath))) //nolint:gosec // path inside the repository
+	if err != nil {
+		return false, fmt.Errorf("open %s: %w", relPath, err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, lfsPointerMaxSize+1))
+	if err != nil {
+		return false, fmt.Errorf("read %s: %w", relPath, err)
+	}
+	_, ok := parseLFSPointer(data)
+	return ok, nil
+}
+
+// indexPointer returns the LFS pointer staged in the index for the repository path,
+// ok is false if the path isn't in the index or its blob isn't a pointer.
+func (r *Repo) indexPointer(idx *index.Index, relPath string) (p lfsPointer, ok bool, err error) {
+	e, err := idx.Entry(relPath)
+	if err != nil {
+		return lfsPointer{}, false, nil //nolint:nilerr // not in the index, nothing staged
+	}
+	blob, err := r.repo.BlobObject(e.Hash)
+	if err != nil {
+		return lfsPointer{}, false, fmt.Errorf("read staged %s: %w", relPath, err)
+	}
+	if blob.Size > lfsPointerMaxSize {
+		return lfsPointer{}, false, nil
+	}
+	reader, err := blob.Reader()
+	if err != nil {
+		return lfsPointer{}, false, fmt.Errorf("read staged %s: %w", relPath, err)
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return lfsPointer{}, false, fmt.Errorf("read staged %s: %w", relPath, err)
+	}
+	p, ok = parseLFSPointer(data)
+	return p, ok, nil
+}
+
+// lfsUnchanged reports whether the worktree file at the repository path holds the content the LFS pointer
+// in the index refers to, i.e. it is a checked out LFS file git wouldn't see as modified.
+func (r *Repo) lfsUnchanged(idx *index.Index, relPath string) bool {
+	p, ok, err := r.indexPointer(idx, relPath)
+	if err != nil || !ok {
+		return false
+	}
+	f, err := os.Open(filepath.Join(r.path, filepath.FromSlash(relPath))) //nolint:gosec // path from the index
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	return err == nil && n == p.size && hex.EncodeToString(h.Sum(nil)) == p.oid
+}
+
+// checkLFSStage returns an error if staging the worktree file at the repository path would store
+// raw content for an LFS-tracked path. staging a pointer file is fine.
+func (r *Repo) checkLFSStage(rules []lfsRule, relPath string) error {
+	if !isLFSTracked(rules, relPath) {
+		return nil
+	}
+	pointer, err := r.isLFSPointerFile(relPath)
+	if err != nil {
+		return err
+	}
+	if !pointer {
+		return fmt.Errorf("%s is tracked by git LFS and can't be staged without the LFS filter, "+
+			"it would be committed as a raw blob; commit it with git and git-lfs instead", relPath)
+	}
+	return nil
+}
+
+// checkLFSStaged returns an error if a staged LFS-tracked path holds raw content instead of an LFS pointer.
+func (r *Repo) checkLFSStaged(rules []lfsRule, status git.Status) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	idx, err := r.repo.Storer.Index()
+	if err != nil {
+		return fmt.Errorf("read index: %w", err)
+	}
+	for relPath, s := range status {
+		if (s.Staging != git.Added && s.Staging != git.Modified) || !isLFSTracked(rules, relPath) {
+			continue
+		}
+		_, ok, err := r.indexPointer(idx, relPath)
+		if err != nil {
+			return err
+		}
+		if ok {
+			continue
+		}
+		return fmt.Errorf("%s is tracked by git LFS but staged as a raw blob instead of an LFS pointer; "+
+			"unstage it and commit it with git and git-lfs instead", relPath)
+	}
+	return nil
+}
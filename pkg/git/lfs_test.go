@@ -0,0 +1,150 @@
+package git
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lfsPointerFor returns the git LFS pointer file content for data.
+func lfsPointerFor(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return fmt.Appendf(nil, "%s\noid sha256:%s\nsize %d\n", lfsPointerVersion, hex.EncodeToString(sum[:]), len(data))
+}
+
+// setupLFSRepo creates a repository tracking *.bin with git LFS, with data.bin committed as a pointer
+// and checked out with its content, as git-lfs would leave it. returns the repository and the content.
+func setupLFSRepo(t *testing.T) (repo *Repo, content []byte) {
+	t.Helper()
+	dir := setupTestRepo(t)
+	repo, err := Open(dir)
+	require.NoError(t, err)
+
+	content = []byte("binary \x00 content of the large file")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitattributes"),
+		[]byte("*.bin filter=lfs diff=lfs merge=lfs -text\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data.bin"), lfsPointerFor(content), 0o600))
+	require.NoError(t, repo.StageAll())
+	require.NoError(t, repo.Commit("add data"))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data.bin"), content, 0o600)) // smudged checkout
+	return repo, content
+}
+
+func TestIsLFSTracked(t *testing.T) {
+	var rules []lfsRule
+	for _, f := range []struct{ lines, domain string }{
+		{"# comment\n*.bin filter=lfs diff=lfs merge=lfs -text\n*.psd filter=lfs\ndocs/** text\n", ""},
+		{"keep.bin -filter\n*.png filter=lfs\n", "assets"},
+		{"*.psd filter=other\n", "raw"},
+	} {
+		var domain []string
+		if f.domain != "" {
+			domain = strings.Split(f.domain, "/")
+		}
+		for line := range strings.SplitSeq(f.lines, "\n") {
+			if rule, ok := parseLFSRule(line, domain); ok {
+				rules = append(rules, rule)
+			}
+		}
+	}
+	require.Len(t, rules, 5, "comment and non-filter lines are skipped")
+
+	tests := map[string]bool{
+		"data.bin":          true,
+		"nested/deep/x.bin": true,
+		"design.psd":        true,
+		"raw/design.psd":    false,
+		"assets/keep.bin":   false,
+		"assets/icon.png":   true,
+		"icon.png":          false,
+		"main.go":           false,
+		"docs/readme.md":    false,
+	}
+	for path, want := range tests {
+		assert.Equal(t, want, isLFSTracked(rules, path), path)
+	}
+}
+
+func TestParseLFSPointer(t *testing.T) {
+	p, ok := parseLFSPointer(lfsPointerFor([]byte("abc")))
+	require.True(t, ok)
+	assert.Equal(t, int64(3), p.size)
+	assert.Len(t, p.oid, 64)
+
+	for name, data := range map[string]string{
+		"raw content":  "abc",
+		"missing oid":  lfsPointerVersion + "\nsize 3\n",
+		"missing size": lfsPointerVersion + "\noid sha256:abc\n",
+		"bad size":     lfsPointerVersion + "\noid sha256:abc\nsize x\n",
+		"too large":    lfsPointerVersion + "\n" + strings.Repeat("x", lfsPointerMaxSize),
+	} {
+		_, ok := parseLFSPointer([]byte(data))
+		assert.False(t, ok, name)
+	}
+}
+
+func TestRepo_LFSCheckout(t *testing.T) {
+	repo, _ := setupLFSRepo(t)
+
+	dirty, err := repo.IsDirty()
+	require.NoError(t, err)
+	assert.False(t, dirty, "checked out LFS content matching its pointer is not a change")
+	changed, err := repo.HasUncommittedChanges()
+	require.NoError(t, err)
+	assert.False(t, changed)
+	changed, err = repo.FileHasChanges(filepath.Join(repo.Root(), "data.bin"))
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	require.NoError(t, repo.StageAll())
+	dirty, err = repo.IsDirty()
+	require.NoError(t, err)
+	assert.False(t, dirty, "nothing staged")
+}
+
+func TestRepo_LFSChangedContent(t *testing.T) {
+	repo, _ := setupLFSRepo(t)
+	dataPath := filepath.Join(repo.Root(), "data.bin")
+	require.NoError(t, os.WriteFile(dataPath, []byte("new binary content"), 0o600))
+
+	dirty, err := repo.IsDirty()
+	require.NoError(t, err)
+	assert.True(t, dirty)
+
+	err = repo.StageAll()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "data.bin is tracked by git LFS and can't be staged without the LFS filter")
+	err = repo.Add("data.bin")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tracked by git LFS")
+
+	t.Run("new raw file refused", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(dataPath, lfsPointerFor([]byte("new binary content")), 0o600))
+		newPath := filepath.Join(repo.Root(), "more.bin")
+		require.NoError(t, os.WriteFile(newPath, []byte("more raw content"), 0o600))
+		t.Cleanup(func() { _ = os.Remove(newPath) })
+		require.ErrorContains(t, repo.StageAll(), "more.bin is tracked by git LFS")
+	})
+
+	t.Run("pointer is staged and committed", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(dataPath, lfsPointerFor([]byte("new binary content")), 0o600))
+		require.NoError(t, repo.StageAll())
+		require.NoError(t, repo.Commit("update data"))
+		assert.True(t, strings.HasPrefix(runGit(t, repo.Root(), "show", "HEAD:data.bin"), lfsPointerVersion))
+	})
+
+	t.Run("raw blob staged by git refused on commit", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(dataPath, []byte("raw again"), 0o600))
+		runGit(t, repo.Root(), "add", "data.bin") // no git-lfs filter configured, git stores the raw content
+		err := repo.Commit("raw data")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "data.bin is tracked by git LFS but staged as a raw blob instead of an LFS pointer")
+	})
+}
@@ -0,0 +1,96 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+)
+
+// go-git's status compares only the commit a submodule has checked out with the one recorded in the
+// superproject, like git itself for commits, and its Add doesn't update the recorded commit. the helpers
+// here open each submodule as a repository of its own to stage that commit and to report uncommitted
+// changes inside a submodule, which callers warn about.
+
+// submodules returns the initialized submodules by slash-separated path. uninitialized submodules
+// have no checkout to change and are skipped.
+func (r *Repo) submodules() (map[string]*Repo, error) {
+	wt, err := r.repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("get worktree: %w", err)
+	}
+	subs, err := wt.Submodules()
+	if err != nil {
+		return nil, fmt.Errorf("read submodules: %w", err)
+	}
+
+	result := make(map[string]*Repo, len(subs))
+	for _, sub := range subs {
+		subPath := sub.Config().Path
+		dir := filepath.Join(r.path, filepath.FromSlash(subPath))
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+			continue // not initialized
+		}
+		repo, err := Open(dir)
+		if err != nil {
+			return nil, fmt.Errorf("open submodule %s: %w", subPath, err)
+		}
+		if repo.Root() != dir {
+			continue // Open found the superproject, the submodule isn't checked out
+		}
+		result[subPath] = repo
+	}
+	return result, nil
+}
+
+// DirtySubmodules returns the sorted paths of submodules with uncommitted changes inside them,
+// including untracked files that are not gitignored and changes in nested submodules. these changes
+// are not changes of the superproject, a commit in it records only the commit a submodule has checked out.
+func (r *Repo) DirtySubmodules() ([]string, error) {
+	subs, err := r.submodules()
+	if err != nil {
+		return nil, err
+	}
+	var dirty []string
+	for subPath, sub := range subs {
+		changed, err := sub.HasUncommittedChanges()
+		if err == nil && !changed {
+			var nested []string
+			nested, err = sub.DirtySubmodules()
+			changed = len(nested) > 0
+		}
+		if err != nil {
+			return nil, fmt.Errorf("submodule %s: %w", subPath, err)
+		}
+		if changed {
+			dirty = append(dirty, subPath)
+		}
+	}
+	sort.Strings(dirty)
+	return dirty, nil
+}
+
+// stageSubmodule records the commit the submodule at subPath has checked out in the index,
+// what git add does for a submodule.
+func (r *Repo) stageSubmodule(subPath string, sub *Repo) error {
+	head, err := sub.repo.Head()
+	if err != nil {
+		return fmt.Errorf("submodule %s: get HEAD: %w", subPath, err)
+	}
+	idx, err := r.repo.Storer.Index()
+	if err != nil {
+		return fmt.Errorf("read index: %w", err)
+	}
+	e, err := idx.Entry(subPath)
+	if err != nil {
+		e = idx.Add(subPath)
+	}
+	e.Hash, e.Mode, e.ModifiedAt = head.Hash(), filemode.Submodule, time.Now()
+	if err := r.repo.Storer.SetIndex(idx); err != nil {
+		return fmt.Errorf("stage submodule %s: %w", subPath, err)
+	}
+	return nil
+}
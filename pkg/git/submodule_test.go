@@ -0,0 +1,121 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupSubmoduleRepo creates a repository with the repository libDir added as submodule "lib".
+func setupSubmoduleRepo(t *testing.T) (mainDir, libDir string) {
+	t.Helper()
+	libDir = setupTestRepo(t)
+	mainDir = setupTestRepo(t)
+	runGit(t, mainDir, "-c", "protocol.file.allow=always", "submodule", "add", libDir, "lib")
+	runGit(t, mainDir, "commit", "-m", "add lib")
+	return mainDir, libDir
+}
+
+func TestRepo_SubmoduleChanges(t *testing.T) {
+	mainDir, _ := setupSubmoduleRepo(t)
+	repo, err := Open(mainDir)
+	require.NoError(t, err)
+
+	dirty, err := repo.IsDirty()
+	require.NoError(t, err)
+	assert.False(t, dirty, "clean checkout")
+
+	t.Run("untracked file inside", func(t *testing.T) {
+		path := filepath.Join(mainDir, "lib", "new.go")
+		require.NoError(t, os.WriteFile(path, []byte("package lib\n"), 0o600))
+		t.Cleanup(func() { _ = os.Remove(path) })
+
+		subs, err := repo.DirtySubmodules()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"lib"}, subs)
+		changed, err := repo.HasUncommittedChanges()
+		require.NoError(t, err)
+		assert.False(t, changed, "changes inside a submodule are not superproject changes")
+		dirty, err := repo.IsDirty()
+		require.NoError(t, err)
+		assert.False(t, dirty)
+
+		// the plan commit still succeeds and records the submodule's checked out commit
+		planFile := filepath.Join(mainDir, "plan.md")
+		require.NoError(t, os.WriteFile(planFile, []byte("# Plan\n"), 0o600))
+		t.Cleanup(func() { runGit(t, mainDir, "reset", "-q", "--hard", "HEAD~1") })
+		changed, err = repo.HasChangesOtherThan(planFile)
+		require.NoError(t, err)
+		assert.False(t, changed)
+		require.NoError(t, repo.Add(planFile))
+		require.NoError(t, repo.Commit("add plan"))
+		assert.Contains(t, runGit(t, mainDir, "show", "--stat", "--format=", "HEAD"), "plan.md")
+		assert.Empty(t, runGit(t, mainDir, "diff", "HEAD~1", "HEAD", "--", "lib"))
+	})
+
+	t.Run("modified file inside", func(t *testing.T) {
+		path := filepath.Join(mainDir, "lib", "README.md")
+		require.NoError(t, os.WriteFile(path, []byte("# Changed\n"), 0o600))
+		t.Cleanup(func() { runGit(t, filepath.Join(mainDir, "lib"), "checkout", "README.md") })
+
+		subs, err := repo.DirtySubmodules()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"lib"}, subs)
+		changed, err := repo.HasChangesOtherThan(filepath.Join(mainDir, "plan.md"))
+		require.NoError(t, err)
+		assert.False(t, changed)
+
+		require.NoError(t, os.WriteFile(filepath.Join(mainDir, "app.go"), []byte("package app\n"), 0o600))
+		t.Cleanup(func() { runGit(t, mainDir, "reset", "-q", "--hard", "HEAD~1") })
+		require.NoError(t, repo.StageAll())
+		require.NoError(t, repo.Commit("add app"))
+		assert.Equal(t, "app.go", runGit(t, mainDir, "show", "--name-only", "--format=", "HEAD"))
+	})
+
+	subs, err := repo.DirtySubmodules()
+	require.NoError(t, err)
+	assert.Empty(t, subs, "clean again")
+}
+
+func TestRepo_StageAll_SubmoduleCommit(t *testing.T) {
+	mainDir, _ := setupSubmoduleRepo(t)
+	repo, err := Open(mainDir)
+	require.NoError(t, err)
+
+	subDir := filepath.Join(mainDir, "lib")
+	require.NoError(t, os.WriteFile(filepath.Join(subDir, "lib.go"), []byte("package lib\n"), 0o600))
+	runGit(t, subDir, "add", "lib.go")
+	runGit(t, subDir, "commit", "-m", "add lib.go")
+	subHead := runGit(t, subDir, "rev-parse", "HEAD")
+
+	require.NoError(t, repo.StageAll())
+	dirty, err := repo.IsDirty()
+	require.NoError(t, err)
+	assert.True(t, dirty, "moved submodule is a change")
+	require.NoError(t, repo.Commit("bump lib"))
+
+	assert.Contains(t, runGit(t, mainDir, "ls-tree", "HEAD", "lib"), "160000 commit "+subHead)
+	assert.Empty(t, runGit(t, mainDir, "status", "--porcelain"))
+	dirty, err = repo.IsDirty()
+	require.NoError(t, err)
+	assert.False(t, dirty)
+}
+
+func TestRepo_UninitializedSubmodule(t *testing.T) {
+	mainDir, _ := setupSubmoduleRepo(t)
+	cloneDir := filepath.Join(t.TempDir(), "clone")
+	runGit(t, filepath.Dir(cloneDir), "clone", mainDir, cloneDir)
+	repo, err := Open(cloneDir)
+	require.NoError(t, err)
+
+	subs, err := repo.submodules()
+	require.NoError(t, err)
+	assert.Empty(t, subs)
+	dirty, err := repo.IsDirty()
+	require.NoError(t, err)
+	assert.False(t, dirty)
+	require.NoError(t, repo.StageAll())
+}
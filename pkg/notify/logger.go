@@ -0,0 +1,167 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/umputun/ralphex/pkg/executor"
+	"github.com/umputun/ralphex/pkg/processor"
+)
+
+// Logger wraps a processor.Logger and emits notifications on run milestones.
+// all calls are forwarded to the inner logger; events are derived from phase changes,
+// task iteration sections, FAILED signals and questions.
+//
+// Thread safety: Logger is NOT goroutine-safe, same as the loggers it wraps.
+type Logger struct {
+	inner       processor.Logger
+	notifier    *Notifier
+	plan        string
+	branch      string
+	phase       processor.Phase
+	currentTask int                 // task iteration in progress, 0 if none
+	mask        func(string) string // applied to the run error in the aborted event, nil keeps it as is
+}
+
+// NewLogger creates a logger that wraps inner and sends events through notifier.
+// plan and branch are attached to every event for context.
+func NewLogger(inner processor.Logger, notifier *Notifier, plan, branch string) *Logger {
+	return &Logger{inner: inner, notifier: notifier, plan: plan, branch: branch}
+}
+
+// SetMasker sets the func applied to the run error before it is sent, e.g. to redact secrets.
+// the run error comes straight from the runner, bypassing the masking logger.
+func (l *Logger) SetMasker(mask func(string) string) {
+	l.mask = mask
+}
+
+// SetPhase forwards the phase and emits phase_change (and task_done when leaving the task phase).
+func (l *Logger) SetPhase(phase processor.Phase) {
+	l.inner.SetPhase(phase)
+	if phase == l.phase {
+		return
+	}
+	if l.phase == processor.PhaseTask && l.currentTask > 0 {
+		l.notifyTaskDone()
+	}
+	prev := l.phase
+	l.phase = phase
+	// claude-eval is an inner step of the codex loop, not a phase users care about
+	if phase == processor.PhaseClaudeEval || prev == processor.PhaseClaudeEval {
+		return
+	}
+	l.send(EventPhaseChange, "phase: "+string(phase), fmt.Sprintf("entered %s phase", phase))
+}
+
+// Print forwards to the inner logger.
+func (l *Logger) Print(format string, args ...any) {
+	l.inner.Print(format, args...)
+}
+
+// PrintRaw forwards to the inner logger.
+func (l *Logger) PrintRaw(format string, args ...any) {
+	l.inner.PrintRaw(format, args...)
+}
+
+// PrintSection forwards the section and emits task_done for the previous task iteration.
+func (l *Logger) PrintSection(section processor.Section) {
+	l.inner.PrintSection(section)
+	if section.Type != processor.SectionTaskIteration {
+		return
+	}
+	if l.currentTask > 0 {
+		l.notifyTaskDone()
+	}
+	l.currentTask = section.Iteration
+}
+
+// PrintAligned forwards the text and emits failed when it carries the FAILED signal.
+func (l *Logger) PrintAligned(text string) {
+	l.inner.PrintAligned(text)
+	if strings.Contains(text, processor.SignalFailed) {
+		l.send(EventFailed, "task failed", fmt.Sprintf("FAILED signal received in %s phase", l.phase))
+	}
+}
+
+// LogQuestion forwards the question and emits question so the user knows input is needed.
+func (l *Logger) LogQuestion(question string, options []string) {
+	l.inner.LogQuestion(question, options)
+	l.send(EventQuestion, "question waiting", fmt.Sprintf("%s\noptions: %s", question, strings.Join(options, ", ")))
+}
+
+// LogAnswer forwards to the inner logger.
+func (l *Logger) LogAnswer(answer string) {
+	l.inner.LogAnswer(answer)
+}
+
+// LogActivity forwards to the inner logger.
+func (l *Logger) LogActivity(activity executor.Activity) {
+	l.inner.LogActivity(activity)
+}
+
+// LogStall forwards to the inner logger.
+func (l *Logger) LogStall(idle time.Duration) {
+	l.inner.LogStall(idle)
+}
+
+// LogUsage forwards to the inner logger.
+func (l *Logger) LogUsage(usage executor.Usage) {
+	l.inner.LogUsage(usage)
+}
+
+// LogCommits forwards to the inner logger.
+func (l *Logger) LogCommits(commits processor.IterationCommits) {
+	l.inner.LogCommits(commits)
+}
+
+// Path returns the progress file path.
+func (l *Logger) Path() string {
+	return l.inner.Path()
+}
+
+// Finish emits completed when err is nil and aborted otherwise.
+// call it once after the runner returns.
+func (l *Logger) Finish(err error) {
+	if err == nil {
+		if l.currentTask > 0 && l.phase == processor.PhaseTask {
+			l.notifyTaskDone()
+		}
+		l.send(EventCompleted, "run completed", "all phases completed successfully")
+		return
+	}
+	msg := err.Error()
+	if l.mask != nil {
+		msg = l.mask(msg)
+	}
+	if errors.Is(err, context.Canceled) {
+		msg = "interrupted: " + msg
+	}
+	l.send(EventAborted, "run aborted", msg)
+}
+
+func (l *Logger) notifyTaskDone() {
+	l.send(EventTaskDone, fmt.Sprintf("task iteration %d done", l.currentTask),
+		fmt.Sprintf("task iteration %d finished", l.currentTask))
+	l.currentTask = 0
+}
+
+// send builds an event with run context and hands it to the notifier.
+func (l *Logger) send(t EventType, title, msg string) {
+	planName := ""
+	if l.plan != "" {
+		planName = filepath.Base(l.plan)
+		title = planName + ": " + title
+	}
+	l.notifier.Notify(Event{
+		Type:    t,
+		Title:   "ralphex " + title,
+		Message: msg,
+		Plan:    planName,
+		Branch:  l.branch,
+		Phase:   string(l.phase),
+	})
+}
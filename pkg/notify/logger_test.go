@@ -0,0 +1,123 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/umputun/ralphex/pkg/executor"
+	"github.com/umputun/ralphex/pkg/processor"
+	"github.com/umputun/ralphex/pkg/processor/mocks"
+)
+
+func newTestLogger() (*Logger, *recordingSink, *mocks.LoggerMock) {
+	inner := &mocks.LoggerMock{
+		SetPhaseFunc:     func(processor.Phase) {},
+		PrintFunc:        func(string, ...any) {},
+		PrintRawFunc:     func(string, ...any) {},
+		PrintSectionFunc: func(processor.Section) {},
+		PrintAlignedFunc: func(string) {},
+		LogQuestionFunc:  func(string, []string) {},
+		LogAnswerFunc:    func(string) {},
+		LogActivityFunc:  func(executor.Activity) {},
+		LogStallFunc:     func(time.Duration) {},
+		LogUsageFunc:     func(executor.Usage) {},
+		LogCommitsFunc:   func(processor.IterationCommits) {},
+		PathFunc:         func() string { return "progress-plan.txt" },
+	}
+	sink := &recordingSink{}
+	n := &Notifier{timeout: defaultTimeout}
+	n.Add(sink)
+	return NewLogger(inner, n, "/repo/docs/plans/plan.md", "feature"), sink, inner
+}
+
+func TestLogger_TaskAndPhaseEvents(t *testing.T) {
+	l, sink, inner := newTestLogger()
+
+	l.SetPhase(processor.PhaseTask)
+	l.PrintSection(processor.NewTaskIterationSection(1))
+	l.PrintSection(processor.NewTaskIterationSection(2))
+	l.SetPhase(processor.PhaseReview)
+	l.SetPhase(processor.PhaseCodex)
+	l.SetPhase(processor.PhaseClaudeEval)
+	l.SetPhase(processor.PhaseCodex)
+	l.Finish(nil)
+	l.notifier.Close()
+
+	assert.Equal(t, []EventType{
+		EventPhaseChange, // task
+		EventTaskDone,    // iteration 1
+		EventTaskDone,    // iteration 2, on leaving task phase
+		EventPhaseChange, // review
+		EventPhaseChange, // codex
+		EventCompleted,
+	}, sink.types())
+	assert.Len(t, inner.SetPhaseCalls(), 5)
+	assert.Len(t, inner.PrintSectionCalls(), 2)
+
+	e := sink.events[1]
+	assert.Equal(t, "ralphex plan.md: task iteration 1 done", e.Title)
+	assert.Equal(t, "plan.md", e.Plan)
+	assert.Equal(t, "feature", e.Branch)
+	assert.Equal(t, "task", e.Phase)
+}
+
+func TestLogger_FailedAndQuestion(t *testing.T) {
+	l, sink, inner := newTestLogger()
+
+	l.PrintAligned("some output")
+	l.PrintAligned("giving up " + processor.SignalFailed)
+	l.LogQuestion("which db?", []string{"postgres", "sqlite"})
+	l.LogAnswer("sqlite")
+	l.notifier.Close()
+
+	require.Equal(t, []EventType{EventFailed, EventQuestion}, sink.types())
+	assert.Equal(t, "which db?\noptions: postgres, sqlite", sink.events[1].Message)
+	assert.Len(t, inner.PrintAlignedCalls(), 2)
+	assert.Len(t, inner.LogAnswerCalls(), 1)
+}
+
+func TestLogger_FinishAborted(t *testing.T) {
+	l, sink, _ := newTestLogger()
+
+	l.Finish(fmt.Errorf("runner: %w", context.Canceled))
+	l.Finish(errors.New("max iterations reached"))
+	l.notifier.Close()
+
+	require.Equal(t, []EventType{EventAborted, EventAborted}, sink.types())
+	assert.Equal(t, "interrupted: runner: context canceled", sink.events[0].Message)
+	assert.Equal(t, "max iterations reached", sink.events[1].Message)
+}
+
+func TestLogger_FinishMasked(t *testing.T) {
+	l, sink, _ := newTestLogger()
+	l.SetMasker(func(s string) string { return strings.ReplaceAll(s, "secret-value", "***") })
+
+	l.Finish(errors.New("hook failed with secret-value"))
+	l.notifier.Close()
+
+	require.Len(t, sink.events, 1)
+	assert.Equal(t, "hook failed with ***", sink.events[0].Message)
+}
+
+func TestLogger_Passthrough(t *testing.T) {
+	l, sink, inner := newTestLogger()
+
+	l.Print("hello %s", "world")
+	l.PrintRaw("raw")
+	l.LogActivity(executor.Activity{Tool: "Edit", Detail: "main.go"})
+	assert.Equal(t, "progress-plan.txt", l.Path())
+	l.notifier.Close()
+
+	assert.Empty(t, sink.types())
+	require.Len(t, inner.PrintCalls(), 1)
+	assert.Equal(t, "hello %s", inner.PrintCalls()[0].Format)
+	assert.Len(t, inner.PrintRawCalls(), 1)
+	assert.Len(t, inner.LogActivityCalls(), 1)
+}
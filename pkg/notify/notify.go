@@ -0,0 +1,210 @@
+// Package notify delivers run milestone notifications to external sinks.
+// supported sinks are generic JSON webhooks, slack-compatible webhooks, ntfy/gotify push,
+// desktop notifications via notify-send and custom shell commands.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/umputun/ralphex/pkg/config"
+)
+
+// EventType identifies a run milestone.
+type EventType string
+
+// event types emitted during a run.
+const (
+	EventTaskDone    EventType = "task_done"    // task iteration finished
+	EventPhaseChange EventType = "phase_change" // execution moved to another phase
+	EventFailed      EventType = "failed"       // FAILED signal received
+	EventQuestion    EventType = "question"     // plan creation is waiting for an answer
+	EventCompleted   EventType = "completed"    // run finished successfully
+	EventAborted     EventType = "aborted"      // run stopped with an error or was canceled
+)
+
+// allEvents lists all known event types, used to validate filters.
+var allEvents = []EventType{EventTaskDone, EventPhaseChange, EventFailed, EventQuestion, EventCompleted, EventAborted}
+
+// defaultTimeout limits how long a single sink delivery may take.
+const defaultTimeout = 10 * time.Second
+
+// queueSize is the number of pending events buffered per sink before new events are dropped.
+const queueSize = 100
+
+// Event is a single notification payload delivered to sinks.
+type Event struct {
+	Type    EventType `json:"event"`
+	Title   string    `json:"title"`
+	Message string    `json:"message"`
+	Plan    string    `json:"plan,omitempty"`
+	Branch  string    `json:"branch,omitempty"`
+	Phase   string    `json:"phase,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// Sink delivers events to a single destination.
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, e Event) error
+}
+
+// route binds a sink to its event filter and delivery queue.
+type route struct {
+	sink   Sink
+	events map[EventType]bool // empty means all events
+	queue  chan Event
+}
+
+// Notifier fans out events to configured sinks.
+// delivery is asynchronous so slow endpoints never block the execution loop.
+// each sink has its own queue and worker, so events reach a sink in the order they were emitted.
+// Close drains the queues and waits for in-flight deliveries. Notify and Close are safe for concurrent use.
+type Notifier struct {
+	routes  []*route
+	timeout time.Duration
+	wg      sync.WaitGroup
+	mu      sync.Mutex // guards closed and sends to the route queues
+	closed  bool
+}
+
+// New creates a Notifier with sinks built from application config.
+// returns a notifier without sinks if nothing is configured.
+func New(cfg *config.Config) (*Notifier, error) {
+	n := &Notifier{timeout: defaultTimeout}
+	if cfg == nil {
+		return n, nil
+	}
+
+	client := &http.Client{Timeout: defaultTimeout}
+	type sinkDef struct {
+		enabled bool
+		sink    Sink
+		events  []string
+		name    string
+	}
+	defs := []sinkDef{
+		{cfg.NotifyWebhookURL != "", &WebhookSink{URL: cfg.NotifyWebhookURL, Client: client}, cfg.NotifyWebhookEvents, "webhook"},
+		{cfg.NotifySlackURL != "", &SlackSink{URL: cfg.NotifySlackURL, Client: client}, cfg.NotifySlackEvents, "slack"},
+		{cfg.NotifyPushURL != "", &PushSink{URL: cfg.NotifyPushURL, Type: cfg.NotifyPushType, Client: client},
+			cfg.NotifyPushEvents, "push"},
+		{cfg.NotifyDesktop, &DesktopSink{}, cfg.NotifyDesktopEvents, "desktop"},
+		{cfg.NotifyCommand != "", &CommandSink{Command: cfg.NotifyCommand}, cfg.NotifyCommandEvents, "command"},
+	}
+
+	for _, d := range defs {
+		if !d.enabled {
+			continue
+		}
+		events, err := ParseEvents(d.events)
+		if err != nil {
+			return nil, fmt.Errorf("notify_%s_events: %w", d.name, err)
+		}
+		n.Add(d.sink, events...)
+	}
+	return n, nil
+}
+
+// ParseEvents converts event names to EventType values, rejecting unknown names.
+func ParseEvents(names []string) ([]EventType, error) {
+	result := make([]EventType, 0, len(names))
+	for _, name := range names {
+		et := EventType(strings.ToLower(strings.TrimSpace(name)))
+		known := false
+		for _, e := range allEvents {
+			if e == et {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown event %q", name)
+		}
+		result = append(result, et)
+	}
+	return result, nil
+}
+
+// Add registers a sink for the given events and starts its delivery worker.
+// no events means the sink receives everything.
+func (n *Notifier) Add(s Sink, events ...EventType) {
+	r := &route{sink: s, events: make(map[EventType]bool, len(events)), queue: make(chan Event, queueSize)}
+	for _, e := range events {
+		r.events[e] = true
+	}
+	n.routes = append(n.routes, r)
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		for e := range r.queue {
+			n.deliver(r.sink, e)
+		}
+	}()
+}
+
+// Enabled returns true if at least one sink is registered.
+func (n *Notifier) Enabled() bool {
+	return n != nil && len(n.routes) > 0
+}
+
+// Notify queues the event for all sinks subscribed to its type.
+// never blocks: if a sink's queue is full the event is dropped with a warning.
+// events sent after Close are ignored.
+func (n *Notifier) Notify(e Event) {
+	if !n.Enabled() {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.closed {
+		return
+	}
+
+	for _, r := range n.routes {
+		if len(r.events) > 0 && !r.events[e.Type] {
+			continue
+		}
+		select {
+		case r.queue <- e:
+		default:
+			log.Printf("[WARN] %s notification queue full, dropping %s event", r.sink.Name(), e.Type)
+		}
+	}
+}
+
+// Close stops accepting events and waits for queued deliveries to finish.
+func (n *Notifier) Close() {
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	if n.closed {
+		n.mu.Unlock()
+		return
+	}
+	n.closed = true
+	for _, r := range n.routes {
+		close(r.queue)
+	}
+	n.mu.Unlock()
+	n.wg.Wait()
+}
+
+// deliver sends a single event with timeout, logging failures.
+func (n *Notifier) deliver(s Sink, e Event) {
+	ctx, cancel := context.WithTimeout(context.Background(), n.timeout)
+	defer cancel()
+	if err := s.Send(ctx, e); err != nil {
+		log.Printf("[WARN] %s notification for %s failed: %v", s.Name(), e.Type, err)
+	}
+}
@@ -0,0 +1,121 @@
+package notify
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/umputun/ralphex/pkg/config"
+)
+
+// recordingSink collects delivered events for assertions.
+type recordingSink struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (s *recordingSink) Name() string { return "recording" }
+
+func (s *recordingSink) Send(_ context.Context, e Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, e)
+	return nil
+}
+
+func (s *recordingSink) types() []EventType {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make([]EventType, 0, len(s.events))
+	for _, e := range s.events {
+		res = append(res, e.Type)
+	}
+	return res
+}
+
+func TestNew(t *testing.T) {
+	t.Run("nil config", func(t *testing.T) {
+		n, err := New(nil)
+		require.NoError(t, err)
+		assert.False(t, n.Enabled())
+	})
+
+	t.Run("no sinks configured", func(t *testing.T) {
+		n, err := New(&config.Config{})
+		require.NoError(t, err)
+		assert.False(t, n.Enabled())
+	})
+
+	t.Run("all sinks configured", func(t *testing.T) {
+		n, err := New(&config.Config{
+			NotifyWebhookURL:    "http://localhost/hook",
+			NotifyWebhookEvents: []string{"completed", "Aborted"},
+			NotifySlackURL:      "http://localhost/slack",
+			NotifyPushURL:       "http://localhost/ntfy",
+			NotifyDesktop:       true,
+			NotifyCommand:       "true",
+		})
+		require.NoError(t, err)
+		require.Len(t, n.routes, 5)
+		assert.Equal(t, "webhook", n.routes[0].sink.Name())
+		assert.Equal(t, map[EventType]bool{EventCompleted: true, EventAborted: true}, n.routes[0].events)
+		assert.Empty(t, n.routes[1].events)
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		_, err := New(&config.Config{NotifySlackURL: "http://localhost/slack", NotifySlackEvents: []string{"done"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "notify_slack_events")
+		assert.Contains(t, err.Error(), `unknown event "done"`)
+	})
+}
+
+func TestNotifier_Notify(t *testing.T) {
+	all, filtered := &recordingSink{}, &recordingSink{}
+	n := &Notifier{timeout: defaultTimeout}
+	n.Add(all)
+	n.Add(filtered, EventCompleted, EventFailed)
+
+	n.Notify(Event{Type: EventTaskDone})
+	n.Notify(Event{Type: EventFailed})
+	n.Notify(Event{Type: EventCompleted})
+	n.Close()
+
+	assert.Equal(t, []EventType{EventTaskDone, EventFailed, EventCompleted}, all.types())
+	assert.Equal(t, []EventType{EventFailed, EventCompleted}, filtered.types())
+	for _, e := range all.events {
+		assert.False(t, e.Time.IsZero(), "time should be set")
+	}
+}
+
+func TestNotifier_NilSafe(t *testing.T) {
+	var n *Notifier
+	assert.False(t, n.Enabled())
+	n.Notify(Event{Type: EventCompleted})
+	n.Close()
+}
+
+func TestNotifier_ConcurrentClose(t *testing.T) {
+	sink := &recordingSink{}
+	n := &Notifier{timeout: defaultTimeout}
+	n.Add(sink)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				n.Notify(Event{Type: EventTaskDone})
+			}
+		}()
+	}
+	n.Close()
+	wg.Wait()
+	n.Notify(Event{Type: EventCompleted}) // after close, ignored
+	n.Close()
+	assert.NotContains(t, sink.types(), EventCompleted)
+}
@@ -0,0 +1,170 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// WebhookSink posts the event as JSON to a generic HTTP endpoint.
+type WebhookSink struct {
+	URL    string
+	Client *http.Client // nil uses http.DefaultClient
+}
+
+// Name returns the sink name for logging.
+func (s *WebhookSink) Name() string { return "webhook" }
+
+// Send posts the event as JSON.
+func (s *WebhookSink) Send(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+	return post(ctx, s.Client, s.URL, "application/json", body, nil)
+}
+
+// SlackSink posts the event to a slack-compatible incoming webhook.
+type SlackSink struct {
+	URL    string
+	Client *http.Client // nil uses http.DefaultClient
+}
+
+// Name returns the sink name for logging.
+func (s *SlackSink) Name() string { return "slack" }
+
+// Send posts the event as a slack message with a bold title line.
+func (s *SlackSink) Send(ctx context.Context, e Event) error {
+	body, err := json.Marshal(map[string]string{"text": fmt.Sprintf("*%s*\n%s", e.Title, e.Message)})
+	if err != nil {
+		return fmt.Errorf("marshal slack message: %w", err)
+	}
+	return post(ctx, s.Client, s.URL, "application/json", body, nil)
+}
+
+// PushSink sends the event to an ntfy topic or a gotify server.
+type PushSink struct {
+	URL    string
+	Type   string       // "ntfy" (default) or "gotify"
+	Client *http.Client // nil uses http.DefaultClient
+}
+
+// Name returns the sink name for logging.
+func (s *PushSink) Name() string { return "push" }
+
+// Send delivers the event using the configured push flavor.
+// ntfy receives the message as plain body with Title and Tags headers,
+// gotify receives a JSON message with title, message and priority.
+func (s *PushSink) Send(ctx context.Context, e Event) error {
+	if s.Type == "gotify" {
+		priority := 5
+		if isUrgent(e.Type) {
+			priority = 8
+		}
+		body, err := json.Marshal(map[string]any{"title": e.Title, "message": e.Message, "priority": priority})
+		if err != nil {
+			return fmt.Errorf("marshal gotify message: %w", err)
+		}
+		return post(ctx, s.Client, s.URL, "application/json", body, nil)
+	}
+
+	headers := map[string]string{"Title": e.Title, "Tags": string(e.Type)}
+	if isUrgent(e.Type) {
+		headers["Priority"] = "high"
+	}
+	return post(ctx, s.Client, s.URL, "text/plain", []byte(e.Message), headers)
+}
+
+// DesktopSink shows a desktop notification via notify-send.
+type DesktopSink struct {
+	Command string // defaults to "notify-send"
+}
+
+// Name returns the sink name for logging.
+func (s *DesktopSink) Name() string { return "desktop" }
+
+// Send runs notify-send with the event title and message.
+func (s *DesktopSink) Send(ctx context.Context, e Event) error {
+	cmd := s.Command
+	if cmd == "" {
+		cmd = "notify-send"
+	}
+	args := []string{"--app-name=ralphex"}
+	if isUrgent(e.Type) {
+		args = append(args, "--urgency=critical")
+	}
+	args = append(args, e.Title, e.Message)
+	if out, err := exec.CommandContext(ctx, cmd, args...).CombinedOutput(); err != nil { //nolint:gosec // command is from config
+		return fmt.Errorf("%s: %w: %s", cmd, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// CommandSink runs a user-defined shell command for each event.
+// the event is passed as JSON on stdin and as RALPHEX_* environment variables.
+type CommandSink struct {
+	Command string
+}
+
+// Name returns the sink name for logging.
+func (s *CommandSink) Name() string { return "command" }
+
+// Send runs the command via sh -c.
+func (s *CommandSink) Send(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+	cmd := exec.CommandContext(ctx, "sh", "-c", s.Command) //nolint:gosec // command is from config
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(os.Environ(),
+		"RALPHEX_EVENT="+string(e.Type),
+		"RALPHEX_TITLE="+e.Title,
+		"RALPHEX_MESSAGE="+e.Message,
+		"RALPHEX_PLAN="+e.Plan,
+		"RALPHEX_BRANCH="+e.Branch,
+		"RALPHEX_PHASE="+e.Phase,
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("run %q: %w: %s", s.Command, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// post sends body to url and treats any non-2xx response as an error.
+func post(ctx context.Context, client *http.Client, url, contentType string, body []byte, headers map[string]string) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// isUrgent returns true for events that need user attention.
+func isUrgent(t EventType) bool {
+	return t == EventFailed || t == EventAborted || t == EventQuestion
+}
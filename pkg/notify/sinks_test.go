@@ -0,0 +1,139 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// capturedRequest holds what a test HTTP server received.
+type capturedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func newCaptureServer(t *testing.T, status int) (*httptest.Server, chan capturedRequest) {
+	t.Helper()
+	ch := make(chan capturedRequest, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		body, _ := io.ReadAll(r.Body)
+		ch <- capturedRequest{header: r.Header.Clone(), body: body}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, ch
+}
+
+func testEvent() Event {
+	return Event{
+		Type:    EventFailed,
+		Title:   "ralphex plan.md: task failed",
+		Message: "FAILED signal received",
+		Plan:    "plan.md",
+		Branch:  "feature",
+		Phase:   "task",
+		Time:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestWebhookSink_Send(t *testing.T) {
+	srv, ch := newCaptureServer(t, http.StatusOK)
+	s := &WebhookSink{URL: srv.URL}
+	require.NoError(t, s.Send(context.Background(), testEvent()))
+
+	req := <-ch
+	assert.Equal(t, "application/json", req.header.Get("Content-Type"))
+	var got Event
+	require.NoError(t, json.Unmarshal(req.body, &got))
+	assert.Equal(t, testEvent(), got)
+}
+
+func TestWebhookSink_Send_ErrorStatus(t *testing.T) {
+	srv, _ := newCaptureServer(t, http.StatusInternalServerError)
+	s := &WebhookSink{URL: srv.URL}
+	err := s.Send(context.Background(), testEvent())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected status 500")
+}
+
+func TestSlackSink_Send(t *testing.T) {
+	srv, ch := newCaptureServer(t, http.StatusOK)
+	s := &SlackSink{URL: srv.URL}
+	require.NoError(t, s.Send(context.Background(), testEvent()))
+
+	req := <-ch
+	var got map[string]string
+	require.NoError(t, json.Unmarshal(req.body, &got))
+	assert.Equal(t, "*ralphex plan.md: task failed*\nFAILED signal received", got["text"])
+}
+
+func TestPushSink_Send(t *testing.T) {
+	t.Run("ntfy", func(t *testing.T) {
+		srv, ch := newCaptureServer(t, http.StatusOK)
+		s := &PushSink{URL: srv.URL}
+		require.NoError(t, s.Send(context.Background(), testEvent()))
+
+		req := <-ch
+		assert.Equal(t, "ralphex plan.md: task failed", req.header.Get("Title"))
+		assert.Equal(t, "failed", req.header.Get("Tags"))
+		assert.Equal(t, "high", req.header.Get("Priority"))
+		assert.Equal(t, "FAILED signal received", string(req.body))
+	})
+
+	t.Run("gotify", func(t *testing.T) {
+		srv, ch := newCaptureServer(t, http.StatusOK)
+		s := &PushSink{URL: srv.URL, Type: "gotify"}
+		e := testEvent()
+		e.Type = EventCompleted
+		require.NoError(t, s.Send(context.Background(), e))
+
+		req := <-ch
+		var got map[string]any
+		require.NoError(t, json.Unmarshal(req.body, &got))
+		assert.Equal(t, "ralphex plan.md: task failed", got["title"])
+		assert.Equal(t, "FAILED signal received", got["message"])
+		assert.InDelta(t, 5, got["priority"], 0)
+	})
+}
+
+func TestCommandSink_Send(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out.txt")
+	s := &CommandSink{Command: `echo "$RALPHEX_EVENT|$RALPHEX_PLAN|$RALPHEX_BRANCH|$RALPHEX_PHASE" > ` + out + ` && cat >> ` + out}
+	require.NoError(t, s.Send(context.Background(), testEvent()))
+
+	data, err := os.ReadFile(out) //nolint:gosec // test file
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "failed|plan.md|feature|task\n")
+	assert.Contains(t, string(data), `"event":"failed"`)
+}
+
+func TestCommandSink_Send_Error(t *testing.T) {
+	s := &CommandSink{Command: "echo boom && exit 3"}
+	err := s.Send(context.Background(), testEvent())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+}
+
+func TestDesktopSink_Send(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "args.txt")
+	script := filepath.Join(dir, "notify-send")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\nprintf '%s\\n' \"$@\" > "+out+"\n"), 0o700)) //nolint:gosec // test script
+
+	s := &DesktopSink{Command: script}
+	require.NoError(t, s.Send(context.Background(), testEvent()))
+
+	data, err := os.ReadFile(out) //nolint:gosec // test file
+	require.NoError(t, err)
+	assert.Equal(t, "--app-name=ralphex\n--urgency=critical\nralphex plan.md: task failed\nFAILED signal received\n", string(data))
+}
@@ -0,0 +1,177 @@
+package processor
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// taskHeaderPattern matches plan task headers like "### Task 1: Add config".
+var taskHeaderPattern = regexp.MustCompile(`^###\s+(?:Task|Iteration)\s+(\d+):\s*(.*)$`)
+
+// autoCommitNote is appended to claude prompts when the runner commits changes itself.
+const autoCommitNote = `
+
+IMPORTANT: ralphex commits your changes automatically after this step.
+Do NOT run git add or git commit yourself, leave all changes uncommitted.`
+
+// commitVarPattern matches the {{COMMIT:message}} prompt variable.
+var commitVarPattern = regexp.MustCompile(`\{\{COMMIT:([^}]*)\}\}`)
+
+// planTask identifies the task claude works on during a task iteration.
+type planTask struct {
+	num   int
+	title string
+}
+
+// autoCommitEnabled reports whether the runner commits changes instead of claude.
+func (r *Runner) autoCommitEnabled() bool {
+	return r.git != nil && r.cfg.AppConfig != nil && r.cfg.AppConfig.AutoCommit
+}
+
+// withCommitNote appends the auto-commit instruction to a prompt when auto-commit is enabled.
+func (r *Runner) withCommitNote(prompt string) string {
+	if !r.autoCommitEnabled() {
+		return prompt
+	}
+	return prompt + autoCommitNote
+}
+
+// expandCommitVars replaces {{COMMIT:message}} with the instruction to commit all changes with message,
+// or, when the runner commits itself, with the instruction to leave the changes uncommitted.
+func (r *Runner) expandCommitVars(prompt string) string {
+	return commitVarPattern.ReplaceAllStringFunc(prompt, func(m string) string {
+		if r.autoCommitEnabled() {
+			return "Do NOT commit, leave all changes uncommitted - ralphex commits them automatically"
+		}
+		return "Commit all changes with message: " + commitVarPattern.FindStringSubmatch(m)[1]
+	})
+}
+
+// activeTask returns the first plan task that still has unchecked items.
+// falls back to the iteration number and the first unchecked item when the plan has no task headers.
+func (r *Runner) activeTask(iteration int) planTask {
+	if !r.autoCommitEnabled() {
+		return planTask{}
+	}
+	content, err := r.readPlan()
+	if err != nil {
+		return planTask{num: iteration}
+	}
+	return findActiveTask(string(content), iteration)
+}
+
+// findActiveTask scans plan content for the first task header followed by an unchecked checkbox.
+func findActiveTask(content string, iteration int) planTask {
+	var current *planTask
+	for line := range strings.SplitSeq(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if m := taskHeaderPattern.FindStringSubmatch(trimmed); m != nil {
+			num, _ := strconv.Atoi(m[1])
+			current = &planTask{num: num, title: strings.TrimSpace(m[2])}
+			continue
+		}
+		if !strings.HasPrefix(trimmed, "- [ ]") {
+			continue
+		}
+		if current != nil {
+			return *current
+		}
+		return planTask{num: iteration, title: strings.TrimSpace(strings.TrimPrefix(trimmed, "- [ ]"))}
+	}
+	return planTask{num: iteration}
+}
+
+// commitTask commits changes made during a task iteration using commit_task_template.
+func (r *Runner) commitTask(iteration int, task planTask) error {
+	if !r.autoCommitEnabled() {
+		return nil
+	}
+	title := task.title
+	if title == "" {
+		title = "iteration " + strconv.Itoa(iteration)
+	}
+	msg := renderCommitMessage(r.cfg.AppConfig.CommitTaskTemplate, map[string]string{
+		"{{TASK_NUM}}":   strconv.Itoa(task.num),
+		"{{TASK_TITLE}}": title,
+		"{{PLAN_NAME}}":  r.planName(),
+		"{{ITERATION}}":  strconv.Itoa(iteration),
+	})
+	return r.commitChanges(msg, "Ralphex-Task: "+strconv.Itoa(task.num))
+}
+
+// commitReview commits fixes made during a review or codex iteration using commit_review_template.
+func (r *Runner) commitReview(phase Phase, iteration int) error {
+	if !r.autoCommitEnabled() {
+		return nil
+	}
+	msg := renderCommitMessage(r.cfg.AppConfig.CommitReviewTemplate, map[string]string{
+		"{{PHASE}}":     string(phase),
+		"{{PLAN_NAME}}": r.planName(),
+		"{{ITERATION}}": strconv.Itoa(iteration),
+	})
+	return r.commitChanges(msg, "Ralphex-Phase: "+string(phase))
+}
+
+// commitChanges stages all changes and commits them if the worktree is dirty.
+// trailers are appended after a blank line when commit_trailers is enabled.
+func (r *Runner) commitChanges(msg string, trailers ...string) error {
+	if err := r.git.StageAll(); err != nil {
+		return fmt.Errorf("auto-commit: stage changes: %w", err)
+	}
+	r.warnDirtySubmodules()
+	dirty, err := r.git.IsDirty()
+	if err != nil {
+		return fmt.Errorf("auto-commit: check worktree: %w", err)
+	}
+	if !dirty {
+		r.log.Print("auto-commit: no changes to commit")
+		return nil
+	}
+
+	subject := msg
+	if r.cfg.AppConfig.CommitTrailers {
+		if name := r.planName(); name != "" {
+			trailers = append([]string{"Ralphex-Plan: " + name}, trailers...)
+		}
+		msg += "\n\n" + strings.Join(trailers, "\n")
+	}
+	if err := r.git.Commit(msg + "\n"); err != nil {
+		return fmt.Errorf("auto-commit: %w", err)
+	}
+	r.log.Print("committed: %s", subject)
+	return nil
+}
+
+// warnDirtySubmodules warns about uncommitted changes inside submodules. a commit records only
+// the commit a submodule has checked out, changes inside it must be committed in the submodule.
+func (r *Runner) warnDirtySubmodules() {
+	dirty, err := r.git.DirtySubmodules()
+	if err != nil {
+		r.log.Print("warning: auto-commit: check submodules: %v", err)
+		return
+	}
+	if len(dirty) > 0 {
+		r.log.Print("warning: auto-commit: uncommitted changes inside submodule %s are not committed, "+
+			"commit them inside the submodule", strings.Join(dirty, ", "))
+	}
+}
+
+// planName returns the plan file base name, or empty string when running without a plan.
+func (r *Runner) planName() string {
+	if r.cfg.PlanFile == "" {
+		return ""
+	}
+	return filepath.Base(r.cfg.PlanFile)
+}
+
+// renderCommitMessage replaces template variables and collapses the result to a single trimmed line.
+func renderCommitMessage(tmpl string, vars map[string]string) string {
+	result := tmpl
+	for k, v := range vars {
+		result = strings.ReplaceAll(result, k, v)
+	}
+	return strings.Join(strings.Fields(result), " ")
+}
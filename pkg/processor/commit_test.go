@@ -0,0 +1,162 @@
+package processor_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/umputun/ralphex/pkg/executor"
+	"github.com/umputun/ralphex/pkg/processor"
+	"github.com/umputun/ralphex/pkg/processor/mocks"
+)
+
+// newMockCommitter creates a git committer mock reporting a dirty worktree on every check.
+func newMockCommitter() *mocks.GitCommitterMock {
+	return &mocks.GitCommitterMock{
+		StageAllFunc:        func() error { return nil },
+		IsDirtyFunc:         func() (bool, error) { return true, nil },
+		CommitFunc:          func(_ string) error { return nil },
+		DirtySubmodulesFunc: func() ([]string, error) { return nil, nil },
+	}
+}
+
+func TestRunner_AutoCommit_FullMode(t *testing.T) {
+	tmpDir := t.TempDir()
+	planFile := filepath.Join(tmpDir, "feature.md")
+	plan := "# Plan\n\n### Task 1: Add config\n- [ ] add field\n\n### Task 2: Wire it\n- [ ] wire\n"
+	require.NoError(t, os.WriteFile(planFile, []byte(plan), 0o600))
+
+	appCfg := testAppConfig(t)
+	appCfg.AutoCommit = true
+
+	var prompts []string
+	claudeResults := []executor.Result{
+		{Output: "task 1 done"},
+		{Output: "task 2 done", Signal: processor.SignalCompleted},
+		{Output: "review done", Signal: processor.SignalReviewDone},
+		{Output: "review done", Signal: processor.SignalReviewDone},
+		{Output: "review done", Signal: processor.SignalReviewDone},
+	}
+	claude := &mocks.ExecutorMock{RunFunc: func(_ context.Context, prompt string) executor.Result {
+		prompts = append(prompts, prompt)
+		res := claudeResults[len(prompts)-1]
+		if len(prompts) == 1 { // simulate claude checking off task 1
+			require.NoError(t, os.WriteFile(planFile, []byte(strings.Replace(plan, "- [ ] add field", "- [x] add field", 1)), 0o600))
+		}
+		if len(prompts) == 2 {
+			require.NoError(t, os.WriteFile(planFile, []byte(strings.ReplaceAll(plan, "- [ ]", "- [x]")), 0o600))
+		}
+		return res
+	}}
+
+	git := newMockCommitter()
+	cfg := processor.Config{Mode: processor.ModeFull, PlanFile: planFile, MaxIterations: 50, IterationDelayMs: 1, AppConfig: appCfg}
+	r := processor.NewWithExecutors(cfg, newMockLogger("progress.txt"), claude, newMockExecutor(nil))
+	r.SetGitCommitter(git)
+	_, err := r.Run(context.Background())
+	require.NoError(t, err)
+
+	var msgs []string
+	for _, c := range git.CommitCalls() {
+		msgs = append(msgs, c.Msg)
+	}
+	require.Len(t, msgs, 6)
+	assert.Equal(t, "feat: task 1 - Add config\n\nRalphex-Plan: feature.md\nRalphex-Task: 1\n", msgs[0])
+	assert.Equal(t, "feat: task 2 - Wire it\n\nRalphex-Plan: feature.md\nRalphex-Task: 2\n", msgs[1])
+	assert.Equal(t, "fix: address review findings\n\nRalphex-Plan: feature.md\nRalphex-Phase: review\n", msgs[2])
+	assert.Equal(t, "fix: address codex findings\n\nRalphex-Plan: feature.md\nRalphex-Phase: codex\n", msgs[4])
+
+	for _, p := range prompts {
+		assert.Contains(t, p, "ralphex commits your changes automatically")
+		assert.NotContains(t, p, "Commit all changes", "prompts must not ask claude to commit")
+		assert.NotContains(t, p, "{{COMMIT:")
+	}
+	assert.Contains(t, prompts[0], "Do NOT commit, leave all changes uncommitted - ralphex commits them automatically")
+}
+
+func TestRunner_AutoCommit_CleanWorktreeAndTemplates(t *testing.T) {
+	tmpDir := t.TempDir()
+	planFile := filepath.Join(tmpDir, "plan.md")
+	require.NoError(t, os.WriteFile(planFile, []byte("# Plan\n- [ ] do something\n"), 0o600))
+
+	appCfg := testAppConfig(t)
+	appCfg.AutoCommit = true
+	appCfg.CommitTaskTemplate = "task({{TASK_NUM}}): {{TASK_TITLE}} [{{PLAN_NAME}} #{{ITERATION}}]"
+	appCfg.CommitTrailers = false
+
+	dirty := []bool{true, false}
+	git := newMockCommitter()
+	git.IsDirtyFunc = func() (bool, error) {
+		d := dirty[0]
+		dirty = dirty[1:]
+		return d, nil
+	}
+
+	claude := newMockExecutor([]executor.Result{{Output: "partial"}, {Output: "nothing changed"}})
+	cfg := processor.Config{Mode: processor.ModeFull, PlanFile: planFile, MaxIterations: 2, IterationDelayMs: 1, AppConfig: appCfg}
+	r := processor.NewWithExecutors(cfg, newMockLogger("progress.txt"), claude, newMockExecutor(nil))
+	r.SetGitCommitter(git)
+	_, err := r.Run(context.Background())
+	require.Error(t, err, "max iterations expected")
+
+	require.Len(t, git.CommitCalls(), 1, "clean worktree should not be committed")
+	assert.Equal(t, "task(1): do something [plan.md #1]\n", git.CommitCalls()[0].Msg)
+	assert.Len(t, git.StageAllCalls(), 2)
+}
+
+func TestRunner_AutoCommit_DirtySubmodules(t *testing.T) {
+	tmpDir := t.TempDir()
+	planFile := filepath.Join(tmpDir, "plan.md")
+	require.NoError(t, os.WriteFile(planFile, []byte("# Plan\n- [ ] do something\n"), 0o600))
+
+	appCfg := testAppConfig(t)
+	appCfg.AutoCommit = true
+
+	git := newMockCommitter()
+	git.DirtySubmodulesFunc = func() ([]string, error) { return []string{"lib", "vendor/proto"}, nil }
+	log := newMockLogger("progress.txt")
+	claude := newMockExecutor([]executor.Result{{Output: "partial"}})
+	cfg := processor.Config{Mode: processor.ModeFull, PlanFile: planFile, MaxIterations: 1, IterationDelayMs: 1, AppConfig: appCfg}
+	r := processor.NewWithExecutors(cfg, log, claude, newMockExecutor(nil))
+	r.SetGitCommitter(git)
+	_, err := r.Run(context.Background())
+	require.Error(t, err, "max iterations expected")
+
+	require.Len(t, git.CommitCalls(), 1, "dirty submodules don't block the commit")
+	var warned bool
+	for _, c := range log.PrintCalls() {
+		if strings.Contains(fmt.Sprintf(c.Format, c.Args...), "uncommitted changes inside submodule lib, vendor/proto are not committed") {
+			warned = true
+		}
+	}
+	assert.True(t, warned, "dirty submodules are reported")
+}
+
+func TestRunner_AutoCommit_Disabled(t *testing.T) {
+	appCfg := testAppConfig(t)
+	git := newMockCommitter()
+
+	var prompts []string
+	claude := &mocks.ExecutorMock{RunFunc: func(_ context.Context, prompt string) executor.Result {
+		prompts = append(prompts, prompt)
+		return executor.Result{Output: "review done", Signal: processor.SignalReviewDone}
+	}}
+	cfg := processor.Config{Mode: processor.ModeReview, MaxIterations: 50, AppConfig: appCfg}
+	r := processor.NewWithExecutors(cfg, newMockLogger("progress.txt"), claude, newMockExecutor(nil))
+	r.SetGitCommitter(git)
+	_, err := r.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.Empty(t, git.StageAllCalls())
+	assert.Empty(t, git.CommitCalls())
+	for _, p := range prompts {
+		assert.NotContains(t, p, "ralphex commits your changes automatically")
+	}
+	assert.Contains(t, prompts[0], "Commit all changes with message: fix: address code review findings")
+}
@@ -0,0 +1,167 @@
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// findingStartPattern matches a list item starting a new finding: "- ", "* " or "1. " / "1) ".
+	findingStartPattern = regexp.MustCompile(`^(?:[-*•]|\d+[.)])\s+`)
+	// findingLocationPattern matches a file:line reference such as pkg/foo.go:42.
+	findingLocationPattern = regexp.MustCompile(`[\w./-]+\.\w+:\d+`)
+)
+
+// reviewerOutput is the raw output of one external reviewer.
+type reviewerOutput struct {
+	name   string
+	output string
+}
+
+// mergedFinding is a de-duplicated finding with every reviewer that raised it.
+type mergedFinding struct {
+	key       string
+	texts     []string // distinct wordings, first one is the primary text
+	reviewers []string
+}
+
+// mergeFindings combines reviewer outputs into one list, merging findings that point at the same
+// file:line (or have the same text when no location is given). each entry is prefixed with the
+// reviewers that reported it, other wordings of the same finding follow on indented lines.
+// returns "NO ISSUES FOUND" when no reviewer reported anything.
+func mergeFindings(outputs []reviewerOutput) string {
+	var merged []*mergedFinding
+	byKey := make(map[string]*mergedFinding)
+	for _, out := range outputs {
+		for _, text := range splitFindings(out.output) {
+			norm := normalizeFinding(text)
+			key := norm
+			if loc := findingLocationPattern.FindString(text); loc != "" {
+				key = strings.ToLower(loc)
+			}
+			f, ok := byKey[key]
+			if !ok {
+				f = &mergedFinding{key: key}
+				byKey[key] = f
+				merged = append(merged, f)
+			}
+			if !containsFold(f.reviewers, out.name) {
+				f.reviewers = append(f.reviewers, out.name)
+			}
+			if !containsNormalized(f.texts, norm) {
+				f.texts = append(f.texts, text)
+			}
+		}
+	}
+
+	if len(merged) == 0 {
+		return noIssuesMarker
+	}
+	var b strings.Builder
+	for _, f := range merged {
+		fmt.Fprintf(&b, "- [%s] %s\n", strings.Join(f.reviewers, ", "), indentContinuation(f.texts[0]))
+		for _, alt := range f.texts[1:] {
+			fmt.Fprintf(&b, "  also reported as: %s\n", indentContinuation(alt))
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// splitFindings splits reviewer output into findings. a finding starts at a non-indented list item
+// or file:line reference and continues until the next one. text before the first marker is a finding
+// of its own unless it only introduces the list (every line ends with a colon), and output without
+// markers is a single finding. output with a line saying no issues were found and no markers has none.
+func splitFindings(output string) []string {
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return nil
+	}
+
+	var findings, preamble, current []string
+	started := false
+	flush := func() {
+		if len(current) > 0 {
+			findings = append(findings, strings.TrimSpace(strings.Join(current, "\n")))
+			current = nil
+		}
+	}
+	for line := range strings.SplitSeq(output, "\n") {
+		indented := strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+		loc := findingLocationPattern.FindStringIndex(line)
+		starts := !indented && (findingStartPattern.MatchString(line) || loc != nil && loc[0] == 0)
+		switch {
+		case starts:
+			flush()
+			started = true
+			current = append(current, findingStartPattern.ReplaceAllString(line, ""))
+		case !started:
+			preamble = append(preamble, line)
+		case strings.TrimSpace(line) != "":
+			current = append(current, strings.TrimSpace(line))
+		}
+	}
+	flush()
+
+	if !started {
+		if hasNoIssuesLine(preamble) {
+			return nil
+		}
+		return []string{output}
+	}
+	if intro := strings.TrimSpace(strings.Join(preamble, "\n")); intro != "" && !isListIntro(preamble) {
+		findings = append([]string{intro}, findings...)
+	}
+	return findings
+}
+
+// hasNoIssuesLine reports whether one of the lines is the no issues marker, ignoring case and a trailing period.
+func hasNoIssuesLine(lines []string) bool {
+	for _, line := range lines {
+		if strings.EqualFold(strings.TrimSuffix(strings.TrimSpace(line), "."), noIssuesMarker) {
+			return true
+		}
+	}
+	return false
+}
+
+// isListIntro reports whether the non-empty lines before the first finding are headings introducing
+// the list, e.g. "Findings:".
+func isListIntro(lines []string) bool {
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasSuffix(line, ":") {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeFinding lowercases text and collapses whitespace for duplicate detection.
+func normalizeFinding(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+// containsNormalized reports whether texts has an entry equal to norm after normalization.
+func containsNormalized(texts []string, norm string) bool {
+	for _, t := range texts {
+		if normalizeFinding(t) == norm {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold reports whether list contains s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// indentContinuation indents every line after the first to keep multi-line findings inside their list item.
+func indentContinuation(text string) string {
+	return strings.ReplaceAll(text, "\n", "\n    ")
+}
@@ -0,0 +1,71 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitFindings(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{name: "empty", output: "  ", want: nil},
+		{name: "no issues", output: "NO ISSUES FOUND", want: nil},
+		{name: "no issues in prose", output: "Looked at everything.\nNO ISSUES FOUND.", want: nil},
+		{name: "list with continuation", output: "Findings:\n- main.go:10 unchecked error\n  from Close\n- util.go:3 dead code",
+			want: []string{"main.go:10 unchecked error\nfrom Close", "util.go:3 dead code"}},
+		{name: "numbered", output: "1. a.go:1 bug\n2) b.go:2 leak", want: []string{"a.go:1 bug", "b.go:2 leak"}},
+		{name: "bare locations", output: "a.go:1 bug\nb.go:2 leak", want: []string{"a.go:1 bug", "b.go:2 leak"}},
+		{name: "free text", output: "The retry loop never stops.", want: []string{"The retry loop never stops."}},
+		{
+			name:   "prose only",
+			output: "No issues found in the handlers.\nThe retry loop in the runner never stops on cancellation.",
+			want:   []string{"No issues found in the handlers.\nThe retry loop in the runner never stops on cancellation."},
+		},
+		{
+			name:   "preamble and list",
+			output: "The retry loop never stops on cancellation.\n\nAlso:\n- a.go:1 bug\n- b.go:2 leak",
+			want:   []string{"The retry loop never stops on cancellation.\n\nAlso:", "a.go:1 bug", "b.go:2 leak"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, splitFindings(tc.output))
+		})
+	}
+}
+
+func TestMergeFindings(t *testing.T) {
+	t.Run("merges by location and attributes reviewers", func(t *testing.T) {
+		got := mergeFindings([]reviewerOutput{
+			{name: "codex", output: "- main.go:10 unchecked error\n- util.go:3 dead code"},
+			{name: "claude:opus", output: "- main.go:10 error from Close is ignored\n- util.go:3 dead code\n- Missing docs"},
+		})
+		want := "- [codex, claude:opus] main.go:10 unchecked error\n" +
+			"  also reported as: main.go:10 error from Close is ignored\n" +
+			"- [codex, claude:opus] util.go:3 dead code\n" +
+			"- [claude:opus] Missing docs"
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("identical text without location", func(t *testing.T) {
+		got := mergeFindings([]reviewerOutput{
+			{name: "a", output: "- Missing  tests"},
+			{name: "b", output: "- missing tests"},
+		})
+		assert.Equal(t, "- [a, b] Missing  tests", got)
+	})
+
+	t.Run("nothing found", func(t *testing.T) {
+		got := mergeFindings([]reviewerOutput{{name: "a", output: "NO ISSUES FOUND"}, {name: "b", output: ""}})
+		assert.Equal(t, noIssuesMarker, got)
+	})
+
+	t.Run("multi-line finding stays in its item", func(t *testing.T) {
+		got := mergeFindings([]reviewerOutput{{name: "a", output: "- a.go:1 bug\n  details here"}})
+		assert.Equal(t, "- [a] a.go:1 bug\n    details here", got)
+	})
+}
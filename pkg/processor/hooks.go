@@ -0,0 +1,150 @@
+package processor
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// HookType identifies a lifecycle point where a user-defined hook can run.
+type HookType string
+
+// hook points, configured via hook_<type> keys in config.
+const (
+	HookPreRun    HookType = "pre_run"    // before the run starts
+	HookPreTask   HookType = "pre_task"   // before each task iteration
+	HookPostTask  HookType = "post_task"  // after each task iteration
+	HookPreReview HookType = "pre_review" // before each claude review phase
+	HookPostPhase HookType = "post_phase" // after each phase completes
+	HookOnFailure HookType = "on_failure" // when the run fails
+	HookPostRun   HookType = "post_run"   // after the run completes successfully
+)
+
+// hookWaitDelay bounds how long we wait for hook output after the process exits,
+// protects against background children keeping the output pipe open.
+const hookWaitDelay = 2 * time.Second
+
+// hookContext carries per-invocation details exposed to hooks as environment variables.
+type hookContext struct {
+	phase Phase
+	task  int   // task iteration, 0 if not in task phase
+	err   error // run error, only for on_failure
+}
+
+// hookCommand returns the configured shell command for the hook, or empty string if not configured.
+func (r *Runner) hookCommand(h HookType) string {
+	if r.cfg.AppConfig == nil {
+		return ""
+	}
+	c := r.cfg.AppConfig
+	switch h {
+	case HookPreRun:
+		return c.HookPreRun
+	case HookPreTask:
+		return c.HookPreTask
+	case HookPostTask:
+		return c.HookPostTask
+	case HookPreReview:
+		return c.HookPreReview
+	case HookPostPhase:
+		return c.HookPostPhase
+	case HookOnFailure:
+		return c.HookOnFailure
+	case HookPostRun:
+		return c.HookPostRun
+	default:
+		return ""
+	}
+}
+
+// runHook executes the configured hook, streaming its output into the logger.
+// a failing hook returns an error only when hook_on_error is "abort", otherwise it is logged as a warning.
+func (r *Runner) runHook(ctx context.Context, h HookType, hc hookContext) error {
+	command := r.hookCommand(h)
+	if command == "" {
+		return nil
+	}
+
+	r.log.Print("running %s hook: %s", h, command)
+	err := r.execHook(ctx, command, r.hookEnv(h, hc))
+	if err == nil {
+		return nil
+	}
+	if r.cfg.AppConfig.HookOnError == "abort" {
+		return fmt.Errorf("%s hook: %w", h, err)
+	}
+	r.log.Print("warning: %s hook failed: %v", h, err)
+	return nil
+}
+
+// runFailureHook runs the on_failure hook after the run failed.
+// uses a context detached from cancellation so the hook still runs after Ctrl+C.
+func (r *Runner) runFailureHook(ctx context.Context, runErr error) {
+	if err := r.runHook(context.WithoutCancel(ctx), HookOnFailure, hookContext{err: runErr}); err != nil {
+		r.log.Print("warning: %v", err)
+	}
+}
+
+// execHook runs command via sh -c with a timeout, sending combined output line by line to the logger.
+// output is read on the calling goroutine because the logger is not goroutine-safe.
+func (r *Runner) execHook(ctx context.Context, command string, env []string) error {
+	if timeoutMs := r.cfg.AppConfig.HookTimeoutMs; timeoutMs > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeoutMs)*time.Millisecond)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command) //nolint:gosec // hook command is from user config
+	cmd.Env = env
+	cmd.WaitDelay = hookWaitDelay
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start hook: %w", err)
+	}
+	waitErr := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		pw.Close()
+		waitErr <- err
+	}()
+
+	scanner := bufio.NewScanner(pr)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		r.log.PrintAligned(scanner.Text())
+	}
+	_, _ = io.Copy(io.Discard, pr) // drain anything left after a scanner error so Wait can finish
+
+	if err := <-waitErr; err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("hook interrupted: %w", ctx.Err())
+		}
+		return fmt.Errorf("hook exited with error: %w", err)
+	}
+	return nil
+}
+
+// hookEnv builds the hook environment: current process env plus RALPHEX_* context variables.
+func (r *Runner) hookEnv(h HookType, hc hookContext) []string {
+	env := append(os.Environ(),
+		"RALPHEX_HOOK="+string(h),
+		"RALPHEX_PLAN_FILE="+r.cfg.PlanFile,
+		"RALPHEX_MODE="+string(r.cfg.Mode),
+		"RALPHEX_PHASE="+string(hc.phase),
+		"RALPHEX_TASK="+strconv.Itoa(hc.task),
+		"RALPHEX_BRANCH="+r.cfg.Branch,
+		"RALPHEX_PROGRESS_FILE="+r.cfg.ProgressPath,
+	)
+	if hc.err != nil {
+		env = append(env, "RALPHEX_ERROR="+hc.err.Error())
+	}
+	return env
+}
@@ -0,0 +1,165 @@
+package processor_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/umputun/ralphex/pkg/executor"
+	"github.com/umputun/ralphex/pkg/processor"
+)
+
+// hookRecorder returns a shell command appending "<hook> <phase> <task>" to a file, and a reader for the file.
+func hookRecorder(t *testing.T) (command string, read func() []string) {
+	t.Helper()
+	out := filepath.Join(t.TempDir(), "hooks.log")
+	command = `echo "$RALPHEX_HOOK $RALPHEX_PHASE $RALPHEX_TASK" >> ` + out
+	read = func() []string {
+		data, err := os.ReadFile(out) //nolint:gosec // test file
+		if os.IsNotExist(err) {
+			return nil
+		}
+		require.NoError(t, err)
+		return strings.Split(strings.TrimSpace(string(data)), "\n")
+	}
+	return command, read
+}
+
+func TestRunner_Hooks_FullMode(t *testing.T) {
+	tmpDir := t.TempDir()
+	planFile := filepath.Join(tmpDir, "plan.md")
+	require.NoError(t, os.WriteFile(planFile, []byte("# Plan\n- [x] Task 1"), 0o600))
+
+	cmd, read := hookRecorder(t)
+	appCfg := testAppConfig(t)
+	appCfg.HookPreRun = cmd
+	appCfg.HookPreTask = cmd
+	appCfg.HookPostTask = cmd
+	appCfg.HookPreReview = cmd
+	appCfg.HookPostPhase = cmd
+	appCfg.HookOnFailure = cmd
+	appCfg.HookPostRun = cmd
+
+	log := newMockLogger("progress.txt")
+	claude := newMockExecutor([]executor.Result{
+		{Output: "task done"}, // iteration 1, not done yet
+		{Output: "task done", Signal: processor.SignalCompleted},
+		{Output: "review done", Signal: processor.SignalReviewDone},
+		{Output: "review done", Signal: processor.SignalReviewDone},
+		{Output: "review done", Signal: processor.SignalReviewDone},
+	})
+	codex := newMockExecutor(nil)
+
+	cfg := processor.Config{Mode: processor.ModeFull, PlanFile: planFile, MaxIterations: 50, IterationDelayMs: 1, AppConfig: appCfg}
+	r := processor.NewWithExecutors(cfg, log, claude, codex)
+	_, err := r.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"pre_run  0",
+		"pre_task task 1",
+		"post_task task 1",
+		"pre_task task 2",
+		"post_task task 2",
+		"post_phase task 0",
+		"pre_review review 0",
+		"post_phase review 0",
+		"post_phase codex 0",
+		"pre_review review 0",
+		"post_phase review 0",
+		"post_run  0",
+	}, read())
+}
+
+func TestRunner_Hooks_EnvAndOutput(t *testing.T) {
+	appCfg := testAppConfig(t)
+	appCfg.HookPreRun = `echo "$RALPHEX_PLAN_FILE|$RALPHEX_MODE|$RALPHEX_BRANCH|$RALPHEX_PROGRESS_FILE"; echo to-stderr >&2`
+
+	log := newMockLogger("progress.txt")
+	claude := newMockExecutor([]executor.Result{
+		{Output: "review done", Signal: processor.SignalReviewDone},
+		{Output: "review done", Signal: processor.SignalReviewDone},
+		{Output: "review done", Signal: processor.SignalReviewDone},
+	})
+	cfg := processor.Config{Mode: processor.ModeReview, PlanFile: "plan.md", Branch: "feature-x",
+		ProgressPath: "progress.txt", MaxIterations: 50, AppConfig: appCfg}
+	r := processor.NewWithExecutors(cfg, log, claude, newMockExecutor(nil))
+	_, err := r.Run(context.Background())
+	require.NoError(t, err)
+
+	var lines []string
+	for _, c := range log.PrintAlignedCalls() {
+		lines = append(lines, c.Text)
+	}
+	assert.Contains(t, lines, "plan.md|review|feature-x|progress.txt")
+	assert.Contains(t, lines, "to-stderr")
+}
+
+func TestRunner_Hooks_FailureWarnContinues(t *testing.T) {
+	appCfg := testAppConfig(t)
+	appCfg.HookPreRun = "exit 3"
+	appCfg.HookOnError = "warn"
+
+	log := newMockLogger("progress.txt")
+	claude := newMockExecutor([]executor.Result{
+		{Output: "review done", Signal: processor.SignalReviewDone},
+		{Output: "review done", Signal: processor.SignalReviewDone},
+		{Output: "review done", Signal: processor.SignalReviewDone},
+	})
+	cfg := processor.Config{Mode: processor.ModeReview, MaxIterations: 50, AppConfig: appCfg}
+	r := processor.NewWithExecutors(cfg, log, claude, newMockExecutor(nil))
+	_, err := r.Run(context.Background())
+	require.NoError(t, err)
+
+	var warned bool
+	for _, c := range log.PrintCalls() {
+		if strings.HasPrefix(c.Format, "warning: %s hook failed") {
+			warned = true
+		}
+	}
+	assert.True(t, warned, "failing hook should be logged as warning")
+}
+
+func TestRunner_Hooks_FailureAbort(t *testing.T) {
+	failCmd, read := hookRecorder(t)
+	appCfg := testAppConfig(t)
+	appCfg.HookPostTask = "exit 3"
+	appCfg.HookOnFailure = `echo "$RALPHEX_ERROR" && ` + failCmd
+	appCfg.HookOnError = "abort"
+
+	tmpDir := t.TempDir()
+	planFile := filepath.Join(tmpDir, "plan.md")
+	require.NoError(t, os.WriteFile(planFile, []byte("# Plan\n- [ ] Task 1"), 0o600))
+
+	log := newMockLogger("progress.txt")
+	claude := newMockExecutor([]executor.Result{{Output: "task done"}})
+	cfg := processor.Config{Mode: processor.ModeFull, PlanFile: planFile, MaxIterations: 50, AppConfig: appCfg}
+	r := processor.NewWithExecutors(cfg, log, claude, newMockExecutor(nil))
+	_, err := r.Run(context.Background())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "post_task hook")
+	assert.Equal(t, []string{"on_failure  0"}, read())
+	assert.Len(t, claude.RunCalls(), 1, "run should stop after failing hook")
+}
+
+func TestRunner_Hooks_Timeout(t *testing.T) {
+	appCfg := testAppConfig(t)
+	appCfg.HookPreRun = "sleep 5"
+	appCfg.HookOnError = "abort"
+	appCfg.HookTimeoutMs = 100
+
+	log := newMockLogger("progress.txt")
+	cfg := processor.Config{Mode: processor.ModeReview, MaxIterations: 50, AppConfig: appCfg}
+	r := processor.NewWithExecutors(cfg, log, newMockExecutor(nil), newMockExecutor(nil))
+	_, err := r.Run(context.Background())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pre_run hook")
+	assert.Contains(t, err.Error(), "deadline exceeded")
+}
@@ -0,0 +1,64 @@
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// GitHeadReader reads the commit HEAD points to, used to record the commits each iteration produced.
+type GitHeadReader interface {
+	HeadHash() (string, error)
+}
+
+// IterationCommits is the commit range produced by one task or review iteration.
+// the changes it made are the diff From..To.
+type IterationCommits struct {
+	Iteration int    `json:"iteration"` // sequential number of the iteration within the run, starting at 1
+	Label     string `json:"label"`     // section label of the iteration, e.g. "task iteration 2"
+	From      string `json:"from"`      // HEAD before the iteration
+	To        string `json:"to"`        // HEAD after the iteration
+}
+
+// iterationCommitsRe matches the String format, the label may contain colons and parentheses.
+var iterationCommitsRe = regexp.MustCompile(`^iteration (\d+) \((.*)\): ([0-9a-f]{7,64})\.\.([0-9a-f]{7,64})$`)
+
+// String formats the commit range for the progress log,
+// e.g. "iteration 3 (claude review 1: critical/major): 1a2b3c...4d5e6f".
+func (c IterationCommits) String() string {
+	return fmt.Sprintf("iteration %d (%s): %s..%s", c.Iteration, c.Label, c.From, c.To)
+}
+
+// ParseIterationCommits parses a commit range formatted by IterationCommits.String.
+func ParseIterationCommits(s string) (IterationCommits, bool) {
+	m := iterationCommitsRe.FindStringSubmatch(s)
+	if m == nil {
+		return IterationCommits{}, false
+	}
+	iteration, err := strconv.Atoi(m[1])
+	if err != nil {
+		return IterationCommits{}, false
+	}
+	return IterationCommits{Iteration: iteration, Label: m[2], From: m[3], To: m[4]}, true
+}
+
+// trackCommits records HEAD at the start of an iteration labeled label. the returned func logs
+// the commits made since, it does nothing if HEAD is unchanged or can't be read.
+func (r *Runner) trackCommits(label string) func() {
+	if r.head == nil {
+		return func() {}
+	}
+	r.iterations++
+	iteration := r.iterations
+	from, err := r.head.HeadHash()
+	if err != nil {
+		return func() {}
+	}
+	return func() {
+		to, err := r.head.HeadHash()
+		if err != nil || to == from {
+			return
+		}
+		r.log.LogCommits(IterationCommits{Iteration: iteration, Label: label, From: from, To: to})
+	}
+}
@@ -0,0 +1,68 @@
+package processor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIterationCommits_String(t *testing.T) {
+	from, to := strings.Repeat("a", 40), strings.Repeat("b", 40)
+	c := IterationCommits{Iteration: 3, Label: "claude review 1: critical/major", From: from, To: to}
+	assert.Equal(t, "iteration 3 (claude review 1: critical/major): "+from+".."+to, c.String())
+
+	parsed, ok := ParseIterationCommits(c.String())
+	require.True(t, ok)
+	assert.Equal(t, c, parsed)
+
+	for _, s := range []string{"", "iteration x (task): a..b", "iteration 1 (task iteration 1): abc", "USAGE: 10 in, 5 out"} {
+		_, ok := ParseIterationCommits(s)
+		assert.False(t, ok, s)
+	}
+}
+
+type fakeHead struct {
+	hashes []string
+	err    error
+	calls  int
+}
+
+func (h *fakeHead) HeadHash() (string, error) {
+	if h.err != nil {
+		return "", h.err
+	}
+	hash := h.hashes[min(h.calls, len(h.hashes)-1)]
+	h.calls++
+	return hash, nil
+}
+
+func TestRunner_trackCommits(t *testing.T) {
+	t.Run("logs changed head", func(t *testing.T) {
+		log := newMockLogger("progress.txt")
+		r := &Runner{log: log, head: &fakeHead{hashes: []string{"aaaaaaa", "bbbbbbb", "bbbbbbb", "bbbbbbb", "bbbbbbb", "ccccccc"}}}
+		r.trackCommits("task iteration 1")()
+		r.trackCommits("task iteration 2")() // nothing committed
+		r.trackCommits("task iteration 3")()
+		assert.Equal(t, []IterationCommits{
+			{Iteration: 1, Label: "task iteration 1", From: "aaaaaaa", To: "bbbbbbb"},
+			{Iteration: 3, Label: "task iteration 3", From: "bbbbbbb", To: "ccccccc"},
+		}, log.commitsCalls)
+	})
+
+	t.Run("no git", func(t *testing.T) {
+		log := newMockLogger("progress.txt")
+		r := &Runner{log: log}
+		r.trackCommits("task iteration 1")()
+		assert.Empty(t, log.commitsCalls)
+	})
+
+	t.Run("head error", func(t *testing.T) {
+		log := newMockLogger("progress.txt")
+		r := &Runner{log: log, head: &fakeHead{err: errors.New("no HEAD")}}
+		r.trackCommits("task iteration 1")()
+		assert.Empty(t, log.commitsCalls)
+	})
+}
@@ -0,0 +1,81 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/umputun/ralphex/pkg/config"
+	"github.com/umputun/ralphex/pkg/executor"
+)
+
+// defaults for waiting out rate, usage and overload limits, overridden by limit_* config values.
+const (
+	defaultLimitRetryCount = 10
+	defaultLimitBackoff    = time.Minute
+	defaultLimitMaxWait    = 6 * time.Hour
+	limitResetMargin       = 30 * time.Second // extra wait after a reported reset time
+)
+
+// limitPolicy controls how runs hitting rate, usage or overload limits are retried.
+type limitPolicy struct {
+	retries int           // max retries per executor call, the budget resets with each call; 0 returns the limit error
+	backoff time.Duration // first wait without a reset time, doubled per retry
+	maxWait time.Duration // cap for a single wait
+}
+
+// newLimitPolicy builds the limit policy from app config, using defaults for unset values.
+func newLimitPolicy(appCfg *config.Config) limitPolicy {
+	p := limitPolicy{retries: defaultLimitRetryCount, backoff: defaultLimitBackoff, maxWait: defaultLimitMaxWait}
+	if appCfg == nil {
+		return p
+	}
+	if appCfg.LimitRetryCountSet {
+		p.retries = appCfg.LimitRetryCount
+	}
+	if appCfg.LimitBackoffMsSet && appCfg.LimitBackoffMs > 0 {
+		p.backoff = time.Duration(appCfg.LimitBackoffMs) * time.Millisecond
+	}
+	if appCfg.LimitMaxWaitMsSet && appCfg.LimitMaxWaitMs > 0 {
+		p.maxWait = time.Duration(appCfg.LimitMaxWaitMs) * time.Millisecond
+	}
+	return p
+}
+
+// wait returns how long to wait before retry number attempt (0-based): until the reported
+// reset time when known, exponential backoff otherwise, capped by maxWait.
+func (p limitPolicy) wait(limitErr *executor.LimitError, attempt int, now time.Time) time.Duration {
+	var d time.Duration
+	if !limitErr.ResetAt.IsZero() && limitErr.ResetAt.After(now) {
+		d = limitErr.ResetAt.Sub(now) + limitResetMargin
+	} else {
+		d = p.backoff << min(attempt, 20) // cap the shift, maxWait bounds the result anyway
+	}
+	return min(d, p.maxWait)
+}
+
+// retryOnLimit re-runs run while result reports a rate, usage or overload limit, sleeping between
+// attempts as the limit policy says. waiting does not count as a failure of the task or iteration.
+// returns the last result once it is not a limit error or retries are exhausted.
+func (r *Runner) retryOnLimit(ctx context.Context, result executor.Result, run func() executor.Result) executor.Result {
+	for attempt := 0; ; attempt++ {
+		var limitErr *executor.LimitError
+		if !errors.As(result.Error, &limitErr) || ctx.Err() != nil {
+			return result
+		}
+		if attempt >= r.limits.retries {
+			result.Error = fmt.Errorf("giving up after %d limit retries: %w", attempt, result.Error)
+			return result
+		}
+
+		wait := r.limits.wait(limitErr, attempt, time.Now())
+		r.log.Print("[WARN] %v, waiting %s before retrying (%d/%d)", limitErr, wait.Round(time.Second), attempt+1, r.limits.retries)
+		select {
+		case <-ctx.Done():
+			return executor.Result{Error: ctx.Err()}
+		case <-time.After(wait):
+		}
+		result = run()
+	}
+}
@@ -0,0 +1,166 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/umputun/ralphex/pkg/config"
+	"github.com/umputun/ralphex/pkg/executor"
+)
+
+// sequenceExecutor returns results in order and counts runs.
+type sequenceExecutor struct {
+	results []executor.Result
+	calls   int
+}
+
+func (s *sequenceExecutor) Run(_ context.Context, _ string) executor.Result {
+	if s.calls >= len(s.results) {
+		return executor.Result{Error: errors.New("no more results")}
+	}
+	res := s.results[s.calls]
+	s.calls++
+	return res
+}
+
+func limitResult(kind executor.LimitKind) executor.Result {
+	return executor.Result{Output: "limited", Error: &executor.LimitError{Kind: kind, Message: "slow down"}}
+}
+
+func TestNewLimitPolicy(t *testing.T) {
+	p := newLimitPolicy(nil)
+	assert.Equal(t, limitPolicy{retries: defaultLimitRetryCount, backoff: defaultLimitBackoff, maxWait: defaultLimitMaxWait}, p)
+
+	p = newLimitPolicy(&config.Config{LimitRetryCount: 0, LimitRetryCountSet: true,
+		LimitBackoffMs: 500, LimitBackoffMsSet: true, LimitMaxWaitMs: 2000, LimitMaxWaitMsSet: true})
+	assert.Equal(t, limitPolicy{retries: 0, backoff: 500 * time.Millisecond, maxWait: 2 * time.Second}, p)
+}
+
+func TestLimitPolicy_Wait(t *testing.T) {
+	p := limitPolicy{retries: 5, backoff: time.Minute, maxWait: time.Hour}
+	now := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+
+	t.Run("exponential backoff without reset time", func(t *testing.T) {
+		l := &executor.LimitError{Kind: executor.LimitOverload}
+		assert.Equal(t, time.Minute, p.wait(l, 0, now))
+		assert.Equal(t, 2*time.Minute, p.wait(l, 1, now))
+		assert.Equal(t, 8*time.Minute, p.wait(l, 3, now))
+		assert.Equal(t, time.Hour, p.wait(l, 10, now))
+		assert.Equal(t, time.Hour, p.wait(l, 100, now))
+	})
+
+	t.Run("until reset time", func(t *testing.T) {
+		l := &executor.LimitError{Kind: executor.LimitUsage, ResetAt: now.Add(20 * time.Minute)}
+		assert.Equal(t, 20*time.Minute+limitResetMargin, p.wait(l, 3, now))
+	})
+
+	t.Run("reset time beyond max wait", func(t *testing.T) {
+		l := &executor.LimitError{Kind: executor.LimitUsage, ResetAt: now.Add(5 * time.Hour)}
+		assert.Equal(t, time.Hour, p.wait(l, 0, now))
+	})
+
+	t.Run("reset time in the past uses backoff", func(t *testing.T) {
+		l := &executor.LimitError{Kind: executor.LimitUsage, ResetAt: now.Add(-time.Minute)}
+		assert.Equal(t, 2*time.Minute, p.wait(l, 1, now))
+	})
+}
+
+func TestRunner_retryOnLimit(t *testing.T) {
+	newRunner := func(retries int) (*Runner, *stubLogger) {
+		log := newMockLogger("progress.txt")
+		r := &Runner{log: log, limits: limitPolicy{retries: retries, backoff: time.Millisecond, maxWait: time.Millisecond}}
+		return r, log
+	}
+
+	t.Run("retries until the limit clears", func(t *testing.T) {
+		r, log := newRunner(3)
+		exec := &sequenceExecutor{results: []executor.Result{
+			limitResult(executor.LimitRate), limitResult(executor.LimitOverload), {Output: "done"},
+		}}
+
+		result := r.runExec(context.Background(), exec, "prompt")
+
+		require.NoError(t, result.Error)
+		assert.Equal(t, "done", result.Output)
+		assert.Equal(t, 3, exec.calls)
+		require.Len(t, log.printCalls, 2)
+		assert.Contains(t, log.printCalls[0].Format, "waiting")
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		r, _ := newRunner(2)
+		exec := &sequenceExecutor{results: []executor.Result{
+			limitResult(executor.LimitRate), limitResult(executor.LimitRate), limitResult(executor.LimitRate),
+		}}
+
+		result := r.runExec(context.Background(), exec, "prompt")
+
+		var limitErr *executor.LimitError
+		require.ErrorAs(t, result.Error, &limitErr)
+		assert.Contains(t, result.Error.Error(), "giving up after 2 limit retries")
+		assert.Equal(t, 3, exec.calls)
+	})
+
+	t.Run("zero retries returns limit error", func(t *testing.T) {
+		r, log := newRunner(0)
+		exec := &sequenceExecutor{results: []executor.Result{limitResult(executor.LimitUsage)}}
+
+		result := r.runExec(context.Background(), exec, "prompt")
+
+		require.Error(t, result.Error)
+		assert.Equal(t, 1, exec.calls)
+		assert.Empty(t, log.printCalls)
+	})
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		r, _ := newRunner(3)
+		exec := &sequenceExecutor{results: []executor.Result{{Error: errors.New("boom")}, {Output: "done"}}}
+
+		result := r.runExec(context.Background(), exec, "prompt")
+
+		require.EqualError(t, result.Error, "boom")
+		assert.Equal(t, 1, exec.calls)
+	})
+
+	t.Run("canceled context stops waiting", func(t *testing.T) {
+		r, _ := newRunner(3)
+		r.limits.backoff, r.limits.maxWait = time.Hour, time.Hour
+		ctx, cancel := context.WithCancel(context.Background())
+		exec := &sequenceExecutor{results: []executor.Result{limitResult(executor.LimitRate), {Output: "done"}}}
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			cancel()
+		}()
+		result := r.runExec(ctx, exec, "prompt")
+
+		require.ErrorIs(t, result.Error, context.Canceled)
+		assert.Equal(t, 1, exec.calls)
+	})
+}
+
+func TestRunner_TaskPhase_LimitIsNotTaskFailure(t *testing.T) {
+	tmpDir := t.TempDir()
+	planFile := filepath.Join(tmpDir, "plan.md")
+	require.NoError(t, os.WriteFile(planFile, []byte("# Plan\n- [x] Task 1"), 0o600))
+
+	appCfg := testAppConfig(t)
+	appCfg.LimitBackoffMs, appCfg.LimitBackoffMsSet = 1, true
+	claude := &sequenceExecutor{results: []executor.Result{
+		limitResult(executor.LimitUsage),
+		{Output: "task done", Signal: SignalCompleted},
+	}}
+
+	cfg := Config{Mode: ModeFull, PlanFile: planFile, MaxIterations: 1, TaskRetryCount: 0, IterationDelayMs: 1, AppConfig: appCfg}
+	r := NewWithExecutors(cfg, newMockLogger("progress.txt"), claude, &sequenceExecutor{})
+
+	require.NoError(t, r.runTaskPhase(context.Background()))
+	assert.Equal(t, 2, claude.calls)
+}
@@ -5,7 +5,9 @@ package mocks
 
 import (
 	"sync"
+	"time"
 
+	"github.com/umputun/ralphex/pkg/executor"
 	"github.com/umputun/ralphex/pkg/processor"
 )
 
@@ -15,12 +17,24 @@ import (
 //
 //		// make and configure a mocked processor.Logger
 //		mockedLogger := &LoggerMock{
+//			LogActivityFunc: func(activity executor.Activity)  {
+//				panic("mock out the LogActivity method")
+//			},
 //			LogAnswerFunc: func(answer string)  {
 //				panic("mock out the LogAnswer method")
 //			},
+//			LogCommitsFunc: func(commits processor.IterationCommits)  {
+//				panic("mock out the LogCommits method")
+//			},
 //			LogQuestionFunc: func(question string, options []string)  {
 //				panic("mock out the LogQuestion method")
 //			},
+//			LogStallFunc: func(idle time.Duration)  {
+//				panic("mock out the LogStall method")
+//			},
+//			LogUsageFunc: func(usage executor.Usage)  {
+//				panic("mock out the LogUsage method")
+//			},
 //			PathFunc: func() string {
 //				panic("mock out the Path method")
 //			},
@@ -46,12 +60,24 @@ import (
 //
 //	}
 type LoggerMock struct {
+	// LogActivityFunc mocks the LogActivity method.
+	LogActivityFunc func(activity executor.Activity)
+
 	// LogAnswerFunc mocks the LogAnswer method.
 	LogAnswerFunc func(answer string)
 
+	// LogCommitsFunc mocks the LogCommits method.
+	LogCommitsFunc func(commits processor.IterationCommits)
+
 	// LogQuestionFunc mocks the LogQuestion method.
 	LogQuestionFunc func(question string, options []string)
 
+	// LogStallFunc mocks the LogStall method.
+	LogStallFunc func(idle time.Duration)
+
+	// LogUsageFunc mocks the LogUsage method.
+	LogUsageFunc func(usage executor.Usage)
+
 	// PathFunc mocks the Path method.
 	PathFunc func() string
 
@@ -72,11 +98,21 @@ type LoggerMock struct {
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// LogActivity holds details about calls to the LogActivity method.
+		LogActivity []struct {
+			// Activity is the activity argument value.
+			Activity executor.Activity
+		}
 		// LogAnswer holds details about calls to the LogAnswer method.
 		LogAnswer []struct {
 			// Answer is the answer argument value.
 			Answer string
 		}
+		// LogCommits holds details about calls to the LogCommits method.
+		LogCommits []struct {
+			// Commits is the commits argument value.
+			Commits processor.IterationCommits
+		}
 		// LogQuestion holds details about calls to the LogQuestion method.
 		LogQuestion []struct {
 			// Question is the question argument value.
@@ -84,6 +120,16 @@ type LoggerMock struct {
 			// Options is the options argument value.
 			Options []string
 		}
+		// LogStall holds details about calls to the LogStall method.
+		LogStall []struct {
+			// Idle is the idle argument value.
+			Idle time.Duration
+		}
+		// LogUsage holds details about calls to the LogUsage method.
+		LogUsage []struct {
+			// Usage is the usage argument value.
+			Usage executor.Usage
+		}
 		// Path holds details about calls to the Path method.
 		Path []struct {
 		}
@@ -117,8 +163,12 @@ type LoggerMock struct {
 			Phase processor.Phase
 		}
 	}
+	lockLogActivity  sync.RWMutex
 	lockLogAnswer    sync.RWMutex
+	lockLogCommits   sync.RWMutex
 	lockLogQuestion  sync.RWMutex
+	lockLogStall     sync.RWMutex
+	lockLogUsage     sync.RWMutex
 	lockPath         sync.RWMutex
 	lockPrint        sync.RWMutex
 	lockPrintAligned sync.RWMutex
@@ -127,6 +177,38 @@ type LoggerMock struct {
 	lockSetPhase     sync.RWMutex
 }
 
+// LogActivity calls LogActivityFunc.
+func (mock *LoggerMock) LogActivity(activity executor.Activity) {
+	if mock.LogActivityFunc == nil {
+		panic("LoggerMock.LogActivityFunc: method is nil but Logger.LogActivity was just called")
+	}
+	callInfo := struct {
+		Activity executor.Activity
+	}{
+		Activity: activity,
+	}
+	mock.lockLogActivity.Lock()
+	mock.calls.LogActivity = append(mock.calls.LogActivity, callInfo)
+	mock.lockLogActivity.Unlock()
+	mock.LogActivityFunc(activity)
+}
+
+// LogActivityCalls gets all the calls that were made to LogActivity.
+// Check the length with:
+//
+//	len(mockedLogger.LogActivityCalls())
+func (mock *LoggerMock) LogActivityCalls() []struct {
+	Activity executor.Activity
+} {
+	var calls []struct {
+		Activity executor.Activity
+	}
+	mock.lockLogActivity.RLock()
+	calls = mock.calls.LogActivity
+	mock.lockLogActivity.RUnlock()
+	return calls
+}
+
 // LogAnswer calls LogAnswerFunc.
 func (mock *LoggerMock) LogAnswer(answer string) {
 	if mock.LogAnswerFunc == nil {
@@ -159,6 +241,38 @@ func (mock *LoggerMock) LogAnswerCalls() []struct {
 	return calls
 }
 
+// LogCommits calls LogCommitsFunc.
+func (mock *LoggerMock) LogCommits(commits processor.IterationCommits) {
+	if mock.LogCommitsFunc == nil {
+		panic("LoggerMock.LogCommitsFunc: method is nil but Logger.LogCommits was just called")
+	}
+	callInfo := struct {
+		Commits processor.IterationCommits
+	}{
+		Commits: commits,
+	}
+	mock.lockLogCommits.Lock()
+	mock.calls.LogCommits = append(mock.calls.LogCommits, callInfo)
+	mock.lockLogCommits.Unlock()
+	mock.LogCommitsFunc(commits)
+}
+
+// LogCommitsCalls gets all the calls that were made to LogCommits.
+// Check the length with:
+//
+//	len(mockedLogger.LogCommitsCalls())
+func (mock *LoggerMock) LogCommitsCalls() []struct {
+	Commits processor.IterationCommits
+} {
+	var calls []struct {
+		Commits processor.IterationCommits
+	}
+	mock.lockLogCommits.RLock()
+	calls = mock.calls.LogCommits
+	mock.lockLogCommits.RUnlock()
+	return calls
+}
+
 // LogQuestion calls LogQuestionFunc.
 func (mock *LoggerMock) LogQuestion(question string, options []string) {
 	if mock.LogQuestionFunc == nil {
@@ -195,6 +309,70 @@ func (mock *LoggerMock) LogQuestionCalls() []struct {
 	return calls
 }
 
+// LogStall calls LogStallFunc.
+func (mock *LoggerMock) LogStall(idle time.Duration) {
+	if mock.LogStallFunc == nil {
+		panic("LoggerMock.LogStallFunc: method is nil but Logger.LogStall was just called")
+	}
+	callInfo := struct {
+		Idle time.Duration
+	}{
+		Idle: idle,
+	}
+	mock.lockLogStall.Lock()
+	mock.calls.LogStall = append(mock.calls.LogStall, callInfo)
+	mock.lockLogStall.Unlock()
+	mock.LogStallFunc(idle)
+}
+
+// LogStallCalls gets all the calls that were made to LogStall.
+// Check the length with:
+//
+//	len(mockedLogger.LogStallCalls())
+func (mock *LoggerMock) LogStallCalls() []struct {
+	Idle time.Duration
+} {
+	var calls []struct {
+		Idle time.Duration
+	}
+	mock.lockLogStall.RLock()
+	calls = mock.calls.LogStall
+	mock.lockLogStall.RUnlock()
+	return calls
+}
+
+// LogUsage calls LogUsageFunc.
+func (mock *LoggerMock) LogUsage(usage executor.Usage) {
+	if mock.LogUsageFunc == nil {
+		panic("LoggerMock.LogUsageFunc: method is nil but Logger.LogUsage was just called")
+	}
+	callInfo := struct {
+		Usage executor.Usage
+	}{
+		Usage: usage,
+	}
+	mock.lockLogUsage.Lock()
+	mock.calls.LogUsage = append(mock.calls.LogUsage, callInfo)
+	mock.lockLogUsage.Unlock()
+	mock.LogUsageFunc(usage)
+}
+
+// LogUsageCalls gets all the calls that were made to LogUsage.
+// Check the length with:
+//
+//	len(mockedLogger.LogUsageCalls())
+func (mock *LoggerMock) LogUsageCalls() []struct {
+	Usage executor.Usage
+} {
+	var calls []struct {
+		Usage executor.Usage
+	}
+	mock.lockLogUsage.RLock()
+	calls = mock.calls.LogUsage
+	mock.lockLogUsage.RUnlock()
+	return calls
+}
+
 // Path calls PathFunc.
 func (mock *LoggerMock) Path() string {
 	if mock.PathFunc == nil {
@@ -0,0 +1,80 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrMaxIterations is wrapped by errors of task and plan loops that ran out of iterations
+// before the completion signal, e.g. "max iterations (50) reached without completion".
+var ErrMaxIterations = errors.New("reached without completion")
+
+// ExecutorError is returned by Run when an executor call failed, Executor names it, "claude" or "codex".
+type ExecutorError struct {
+	Executor string
+	Err      error
+}
+
+// Error returns the executor name and the wrapped error.
+func (e *ExecutorError) Error() string {
+	return e.Executor + " execution: " + e.Err.Error()
+}
+
+// Unwrap returns the executor's error.
+func (e *ExecutorError) Unwrap() error {
+	return e.Err
+}
+
+// OutcomeStatus is the final status of a run.
+type OutcomeStatus string
+
+// outcome statuses.
+const (
+	OutcomeSuccess       OutcomeStatus = "success"        // all phases completed
+	OutcomeFailed        OutcomeStatus = "failed"         // FAILED signal, executor or hook error
+	OutcomeAborted       OutcomeStatus = "aborted"        // canceled, e.g. by Ctrl+C
+	OutcomeMaxIterations OutcomeStatus = "max_iterations" // task or plan loop ran out of iterations
+)
+
+// Outcome summarizes how a run ended. it is returned by Runner.Run and written
+// to the progress file footer, where the dashboard reads it.
+type Outcome struct {
+	Status           OutcomeStatus `json:"status"`
+	Phase            Phase         `json:"phase,omitempty"`    // phase the run ended in
+	Error            string        `json:"error,omitempty"`    // error message of unsuccessful runs
+	Executor         string        `json:"executor,omitempty"` // executor whose error failed the run, "claude" or "codex"
+	TaskIterations   int           `json:"task_iterations,omitempty"`
+	ReviewIterations int           `json:"review_iterations,omitempty"` // claude review iterations, including the first review
+	CodexIterations  int           `json:"codex_iterations,omitempty"`
+	PlanIterations   int           `json:"plan_iterations,omitempty"`
+	Duration         time.Duration `json:"duration"`
+}
+
+// finish sets status, error and duration from the run's error.
+func (o Outcome) finish(ctx context.Context, err error, duration time.Duration) Outcome {
+	o.Duration = duration
+	switch {
+	case err == nil:
+		o.Status = OutcomeSuccess
+		return o
+	case ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		o.Status = OutcomeAborted
+	case errors.Is(err, ErrMaxIterations):
+		o.Status = OutcomeMaxIterations
+	default:
+		o.Status = OutcomeFailed
+	}
+	o.Error = err.Error()
+	var execErr *ExecutorError
+	if errors.As(err, &execErr) {
+		o.Executor = execErr.Executor
+	}
+	return o
+}
+
+// setPhase switches the logger to phase and records it for the outcome.
+func (r *Runner) setPhase(phase Phase) {
+	r.outcome.Phase = phase
+	r.log.SetPhase(phase)
+}
@@ -0,0 +1,70 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/umputun/ralphex/pkg/executor"
+)
+
+// parallelJob is a single executor run scheduled by runParallel.
+type parallelJob struct {
+	name   string
+	exec   Executor
+	prompt string
+}
+
+// parallelRun holds the outcome of a parallel job.
+type parallelRun struct {
+	index   int
+	name    string
+	result  executor.Result
+	elapsed time.Duration
+}
+
+// runParallel starts jobs with at most limit running at once and returns a channel
+// delivering exactly one run per job in completion order. jobs still waiting for a slot
+// when ctx is canceled report the context error without running.
+func runParallel(ctx context.Context, limit int, jobs []parallelJob) <-chan parallelRun {
+	results := make(chan parallelRun, len(jobs))
+	sem := make(chan struct{}, max(1, limit))
+	for i, job := range jobs {
+		go func() {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				results <- parallelRun{index: i, name: job.name, result: executor.Result{Error: ctx.Err()}}
+				return
+			}
+			defer func() { <-sem }()
+			start := time.Now()
+			res := job.exec.Run(ctx, job.prompt)
+			results <- parallelRun{index: i, name: job.name, result: res, elapsed: time.Since(start)}
+		}()
+	}
+	return results
+}
+
+// retryParallelRun re-runs a job that hit a rate, usage or overload limit or stalled on the calling
+// goroutine, so waits and stalls are logged safely. other runs are returned unchanged.
+func (r *Runner) retryParallelRun(ctx context.Context, jobs []parallelJob, run parallelRun) parallelRun {
+	job := jobs[run.index]
+	start := time.Now()
+	run.result = r.recoverRun(ctx, job.prompt, run.result, func(p string) executor.Result { return job.exec.Run(ctx, p) })
+	run.elapsed += time.Since(start)
+	return run
+}
+
+// logParallelRun prints a section with the job name and run time followed by its output or failure.
+func (r *Runner) logParallelRun(kind string, run parallelRun) {
+	r.log.PrintSection(NewGenericSection(fmt.Sprintf("%s %s (%s)", kind, run.name, run.elapsed.Round(time.Second))))
+	if run.result.Error != nil {
+		r.log.Print("[WARN] %s %s failed: %v", kind, run.name, run.result.Error)
+		return
+	}
+	for line := range strings.SplitSeq(strings.TrimSpace(run.result.Output), "\n") {
+		r.log.PrintAligned(line)
+	}
+}
@@ -4,16 +4,20 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/umputun/ralphex/pkg/config"
 )
 
 // agentRefPattern matches {{agent:name}} template syntax
 var agentRefPattern = regexp.MustCompile(`\{\{agent:([a-zA-Z0-9_-]+)\}\}`)
 
-// agentExpansionTemplate is the wrapper for expanded agent references
-const agentExpansionTemplate = `Use the Task tool to launch a general-purpose agent with this prompt:
-"%s"
-
-Report findings only - no positive observations.`
+// prompt names matched against the agent "phases" frontmatter option.
+const (
+	promptTask         = "task"
+	promptReviewFirst  = "review_first"
+	promptReviewSecond = "review_second"
+	promptCodex        = "codex"
+)
 
 // getGoal returns the goal string based on whether a plan file is configured.
 func (r *Runner) getGoal() string {
@@ -42,7 +46,9 @@ func (r *Runner) getProgressFileRef() string {
 // expandAgentReferences replaces {{agent:name}} patterns with Task tool instructions.
 // returns prompt unchanged if AppConfig is nil or no agents are configured.
 // missing agents log a warning and leave the reference as-is for visibility.
-func (r *Runner) expandAgentReferences(prompt string) string {
+// agents restricted to other prompts via the phases option expand to nothing with a warning,
+// empty prompt name disables the check.
+func (r *Runner) expandAgentReferences(prompt, promptName string) string {
 	if r.cfg.AppConfig == nil {
 		return prompt
 	}
@@ -52,67 +58,111 @@ func (r *Runner) expandAgentReferences(prompt string) string {
 	}
 
 	// build agent lookup map
-	agentMap := make(map[string]string, len(agents))
+	agentMap := make(map[string]config.CustomAgent, len(agents))
 	for _, agent := range agents {
-		agentMap[agent.Name] = agent.Prompt
+		agentMap[agent.Name] = agent
 	}
 
 	return agentRefPattern.ReplaceAllStringFunc(prompt, func(match string) string {
 		// extract name directly from match: {{agent:NAME}} -> NAME
 		name := match[8 : len(match)-2] // skip "{{agent:" and "}}"
 
-		agentPrompt, ok := agentMap[name]
+		agent, ok := agentMap[name]
 		if !ok {
 			r.log.Print("[WARN] agent %q not found, leaving reference unexpanded", name)
 			return match
 		}
+		if !agent.AppliesTo(promptName) {
+			r.log.Print("[WARN] agent %q is not enabled for the %s prompt (phases: %s), reference expands to nothing",
+				name, promptName, strings.Join(agent.Phases, ", "))
+			return ""
+		}
 
-		return fmt.Sprintf(agentExpansionTemplate, agentPrompt)
+		return renderAgent(agent)
 	})
 }
 
+// renderAgent builds Task tool instructions for an agent, adding model, tools, files and severity
+// lines only for options set in the agent frontmatter.
+func renderAgent(agent config.CustomAgent) string {
+	var b strings.Builder
+	b.WriteString("Use the Task tool to launch a general-purpose agent")
+	if agent.Model != "" {
+		fmt.Fprintf(&b, " (model: %s)", agent.Model)
+	}
+	fmt.Fprintf(&b, " with this prompt:\n\"%s\"\n\n", agent.Prompt)
+	if len(agent.Tools) > 0 {
+		fmt.Fprintf(&b, "Allow the agent to use only these tools: %s.\n", strings.Join(agent.Tools, ", "))
+	}
+	b.WriteString(renderAgentConstraints(agent))
+	return b.String()
+}
+
+// renderAgentConstraints builds the files and severity lines for an agent.
+func renderAgentConstraints(agent config.CustomAgent) string {
+	var b strings.Builder
+	if len(agent.Files) > 0 {
+		fmt.Fprintf(&b, "Limit the agent to files matching: %s (patterns starting with ! are excluded).\n",
+			strings.Join(agent.Files, ", "))
+	}
+
+	switch agent.Severity {
+	case "critical":
+		b.WriteString("Report critical findings only - no positive observations.")
+	case "major":
+		b.WriteString("Report critical and major findings only - no positive observations.")
+	default:
+		b.WriteString("Report findings only - no positive observations.")
+	}
+	return b.String()
+}
+
 // replacePromptVariables replaces template variables in custom prompts.
-// supported variables: {{PLAN_FILE}}, {{PROGRESS_FILE}}, {{GOAL}}, {{agent:name}}
+// supported variables: {{PLAN_FILE}}, {{PROGRESS_FILE}}, {{GOAL}}, {{SCOPE}}, {{COMMIT:message}}, {{agent:name}}
 // note: {{CODEX_OUTPUT}} is handled separately in buildCodexEvaluationPrompt
-func (r *Runner) replacePromptVariables(prompt string) string {
+// for scoped runs and multi-repo plans the scope and the additional repositories are appended to the prompt.
+func (r *Runner) replacePromptVariables(prompt, promptName string) string {
 	result := prompt
 	result = strings.ReplaceAll(result, "{{PLAN_FILE}}", r.getPlanFileRef())
 	result = strings.ReplaceAll(result, "{{PROGRESS_FILE}}", r.getProgressFileRef())
 	result = strings.ReplaceAll(result, "{{GOAL}}", r.getGoal())
+	result = strings.ReplaceAll(result, "{{SCOPE}}", r.getScopeRef())
+	result = r.expandCommitVars(result)
 
 	// expand agent references
-	result = r.expandAgentReferences(result)
+	result = r.expandAgentReferences(result, promptName)
 
-	return result
+	return r.withRepositoriesNote(r.withScopeNote(result))
 }
 
 // buildTaskPrompt creates the prompt for executing a single task.
 // uses the task prompt loaded from config (either user-provided or embedded default).
 // agent references ({{agent:name}}) are expanded via replacePromptVariables.
+// when auto-commit is enabled, a note telling claude not to commit is appended to this and review prompts.
 func (r *Runner) buildTaskPrompt() string {
-	return r.replacePromptVariables(r.cfg.AppConfig.TaskPrompt)
+	return r.withCommitNote(r.replacePromptVariables(r.cfg.AppConfig.TaskPrompt, promptTask))
 }
 
 // buildFirstReviewPrompt creates the prompt for first review pass - address all findings.
 // uses the loaded prompt template (user-provided or embedded default).
 // agent references ({{agent:name}}) are expanded via replacePromptVariables.
 func (r *Runner) buildFirstReviewPrompt() string {
-	return r.replacePromptVariables(r.cfg.AppConfig.ReviewFirstPrompt)
+	return r.withCommitNote(r.replacePromptVariables(r.cfg.AppConfig.ReviewFirstPrompt, promptReviewFirst))
 }
 
 // buildSecondReviewPrompt creates the prompt for second review pass - critical/major only.
 // uses the second review prompt loaded from config (either user-provided or embedded default).
 // agent references ({{agent:name}}) are expanded via replacePromptVariables.
 func (r *Runner) buildSecondReviewPrompt() string {
-	return r.replacePromptVariables(r.cfg.AppConfig.ReviewSecondPrompt)
+	return r.withCommitNote(r.replacePromptVariables(r.cfg.AppConfig.ReviewSecondPrompt, promptReviewSecond))
 }
 
 // buildCodexEvaluationPrompt creates the prompt for claude to evaluate codex review output.
 // uses the codex prompt loaded from config (either user-provided or embedded default).
 // agent references ({{agent:name}}) are expanded via replacePromptVariables.
 func (r *Runner) buildCodexEvaluationPrompt(codexOutput string) string {
-	prompt := r.replacePromptVariables(r.cfg.AppConfig.CodexPrompt)
-	return strings.ReplaceAll(prompt, "{{CODEX_OUTPUT}}", codexOutput)
+	prompt := r.replacePromptVariables(r.cfg.AppConfig.CodexPrompt, promptCodex)
+	return r.withCommitNote(strings.ReplaceAll(prompt, "{{CODEX_OUTPUT}}", codexOutput))
 }
 
 // buildPlanPrompt creates the prompt for interactive plan creation.
@@ -159,7 +159,7 @@ func TestRunner_replacePromptVariables(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			r := &Runner{cfg: Config{PlanFile: tc.planFile, ProgressPath: tc.progressPath}}
-			result := r.replacePromptVariables(tc.input)
+			result := r.replacePromptVariables(tc.input, "")
 			assert.Equal(t, tc.expected, result)
 		})
 	}
@@ -167,7 +167,7 @@ func TestRunner_replacePromptVariables(t *testing.T) {
 
 func TestRunner_replacePromptVariables_NoGoal(t *testing.T) {
 	r := &Runner{cfg: Config{PlanFile: ""}}
-	result := r.replacePromptVariables("Goal: {{GOAL}}")
+	result := r.replacePromptVariables("Goal: {{GOAL}}", "")
 	assert.Equal(t, "Goal: current branch vs master", result)
 }
 
@@ -198,19 +198,19 @@ func TestRunner_getProgressFileRef(t *testing.T) {
 func TestRunner_replacePromptVariables_Fallbacks(t *testing.T) {
 	t.Run("empty plan file uses fallback", func(t *testing.T) {
 		r := &Runner{cfg: Config{PlanFile: "", ProgressPath: "progress.txt"}}
-		result := r.replacePromptVariables("Plan: {{PLAN_FILE}}")
+		result := r.replacePromptVariables("Plan: {{PLAN_FILE}}", "")
 		assert.Equal(t, "Plan: (no plan file - reviewing current branch)", result)
 	})
 
 	t.Run("empty progress path uses fallback", func(t *testing.T) {
 		r := &Runner{cfg: Config{PlanFile: "test.md", ProgressPath: ""}}
-		result := r.replacePromptVariables("Progress: {{PROGRESS_FILE}}")
+		result := r.replacePromptVariables("Progress: {{PROGRESS_FILE}}", "")
 		assert.Equal(t, "Progress: (no progress file available)", result)
 	})
 
 	t.Run("both empty use fallbacks", func(t *testing.T) {
 		r := &Runner{cfg: Config{PlanFile: "", ProgressPath: ""}}
-		result := r.replacePromptVariables("Plan: {{PLAN_FILE}}, Progress: {{PROGRESS_FILE}}, Goal: {{GOAL}}")
+		result := r.replacePromptVariables("Plan: {{PLAN_FILE}}, Progress: {{PROGRESS_FILE}}, Goal: {{GOAL}}", "")
 		assert.Equal(t, "Plan: (no plan file - reviewing current branch), Progress: (no progress file available), Goal: current branch vs master", result)
 	})
 }
@@ -222,7 +222,7 @@ func TestRunner_expandAgentReferences_SingleAgent(t *testing.T) {
 	r := &Runner{cfg: Config{AppConfig: appCfg}, log: newMockLogger("")}
 
 	prompt := "Check code:\n{{agent:security-scanner}}\nDone."
-	result := r.expandAgentReferences(prompt)
+	result := r.expandAgentReferences(prompt, "")
 
 	assert.Contains(t, result, "Use the Task tool to launch a general-purpose agent with this prompt:")
 	assert.Contains(t, result, "scan for security vulnerabilities")
@@ -240,7 +240,7 @@ func TestRunner_expandAgentReferences_MultipleAgents(t *testing.T) {
 	r := &Runner{cfg: Config{AppConfig: appCfg}, log: newMockLogger("")}
 
 	prompt := "Run {{agent:agent-a}} then {{agent:agent-b}}."
-	result := r.expandAgentReferences(prompt)
+	result := r.expandAgentReferences(prompt, "")
 
 	assert.Contains(t, result, "first agent prompt")
 	assert.Contains(t, result, "second agent prompt")
@@ -256,7 +256,7 @@ func TestRunner_expandAgentReferences_MissingAgent(t *testing.T) {
 	r := &Runner{cfg: Config{AppConfig: appCfg}, log: log}
 
 	prompt := "Run {{agent:missing-agent}} now."
-	result := r.expandAgentReferences(prompt)
+	result := r.expandAgentReferences(prompt, "")
 
 	// missing agent should remain unexpanded
 	assert.Contains(t, result, "{{agent:missing-agent}}")
@@ -272,7 +272,7 @@ func TestRunner_expandAgentReferences_MissingAgent(t *testing.T) {
 func TestRunner_expandAgentReferences_NilAppConfig(t *testing.T) {
 	r := &Runner{cfg: Config{AppConfig: nil}}
 	prompt := "Run {{agent:test}} now."
-	result := r.expandAgentReferences(prompt)
+	result := r.expandAgentReferences(prompt, "")
 	assert.Equal(t, prompt, result)
 }
 
@@ -281,7 +281,7 @@ func TestRunner_expandAgentReferences_EmptySlice(t *testing.T) {
 	r := &Runner{cfg: Config{AppConfig: appCfg}}
 
 	prompt := "Run {{agent:test}} now."
-	result := r.expandAgentReferences(prompt)
+	result := r.expandAgentReferences(prompt, "")
 
 	// empty agents slice, prompt unchanged
 	assert.Equal(t, prompt, result)
@@ -292,7 +292,7 @@ func TestRunner_expandAgentReferences_NilAgentsSlice(t *testing.T) {
 	r := &Runner{cfg: Config{AppConfig: appCfg}}
 
 	prompt := "Run {{agent:some-agent}} now."
-	result := r.expandAgentReferences(prompt)
+	result := r.expandAgentReferences(prompt, "")
 
 	// nil agents slice, prompt unchanged
 	assert.Equal(t, prompt, result)
@@ -305,7 +305,7 @@ func TestRunner_expandAgentReferences_NoReferences(t *testing.T) {
 	r := &Runner{cfg: Config{AppConfig: appCfg}, log: newMockLogger("")}
 
 	prompt := "Plain prompt without agent references."
-	result := r.expandAgentReferences(prompt)
+	result := r.expandAgentReferences(prompt, "")
 
 	assert.Equal(t, prompt, result)
 }
@@ -318,7 +318,7 @@ func TestRunner_expandAgentReferences_MixedVariables(t *testing.T) {
 
 	// test that agent refs work alongside other variables in replacePromptVariables
 	prompt := "Plan: {{PLAN_FILE}}, Goal: {{GOAL}}, Agent: {{agent:reviewer}}"
-	result := r.replacePromptVariables(prompt)
+	result := r.replacePromptVariables(prompt, "")
 
 	assert.Contains(t, result, "Plan: docs/plans/test.md")
 	assert.Contains(t, result, "Goal: implementation of plan at docs/plans/test.md")
@@ -333,7 +333,7 @@ func TestRunner_expandAgentReferences_DuplicateReferences(t *testing.T) {
 	r := &Runner{cfg: Config{AppConfig: appCfg}, log: newMockLogger("")}
 
 	prompt := "First: {{agent:scanner}}\nSecond: {{agent:scanner}}"
-	result := r.expandAgentReferences(prompt)
+	result := r.expandAgentReferences(prompt, "")
 
 	// both references should be expanded
 	assert.NotContains(t, result, "{{agent:scanner}}")
@@ -351,7 +351,7 @@ func TestRunner_expandAgentReferences_SpecialCharactersInPrompt(t *testing.T) {
 	r := &Runner{cfg: Config{AppConfig: appCfg}, log: newMockLogger("")}
 
 	prompt := "Run {{agent:regex-agent}} now."
-	result := r.expandAgentReferences(prompt)
+	result := r.expandAgentReferences(prompt, "")
 
 	// prompt with special characters preserves newlines and tabs
 	assert.NotContains(t, result, "{{agent:regex-agent}}")
@@ -371,7 +371,7 @@ func TestRunner_expandAgentReferences_CaseSensitivity(t *testing.T) {
 	t.Run("lowercase reference does not match uppercase agent", func(t *testing.T) {
 		r := &Runner{cfg: Config{AppConfig: appCfg}, log: newMockLogger("")}
 		prompt := "Run {{agent:scanner}} now."
-		result := r.expandAgentReferences(prompt)
+		result := r.expandAgentReferences(prompt, "")
 
 		assert.Contains(t, result, "{{agent:scanner}}")
 		assert.NotContains(t, result, "uppercase name")
@@ -380,7 +380,7 @@ func TestRunner_expandAgentReferences_CaseSensitivity(t *testing.T) {
 	t.Run("exact case matches", func(t *testing.T) {
 		r := &Runner{cfg: Config{AppConfig: appCfg}, log: newMockLogger("")}
 		prompt := "Run {{agent:Scanner}} now."
-		result := r.expandAgentReferences(prompt)
+		result := r.expandAgentReferences(prompt, "")
 
 		assert.NotContains(t, result, "{{agent:Scanner}}")
 		assert.Contains(t, result, "uppercase name")
@@ -396,7 +396,7 @@ func TestRunner_expandAgentReferences_PercentInPrompt(t *testing.T) {
 	r := &Runner{cfg: Config{AppConfig: appCfg}, log: newMockLogger("")}
 
 	prompt := "Run {{agent:perf}} now."
-	result := r.expandAgentReferences(prompt)
+	result := r.expandAgentReferences(prompt, "")
 
 	assert.Contains(t, result, "80%")
 	assert.Contains(t, result, "90%")
@@ -467,3 +467,51 @@ func TestRunner_buildPlanPrompt(t *testing.T) {
 		assert.Equal(t, "Create plan for: custom feature\nLog: custom-progress.txt", prompt)
 	})
 }
+
+func TestRenderAgent(t *testing.T) {
+	t.Run("plain agent keeps default wrapper", func(t *testing.T) {
+		result := renderAgent(config.CustomAgent{Name: "a", Prompt: "check code"})
+		assert.Equal(t, "Use the Task tool to launch a general-purpose agent with this prompt:\n\"check code\"\n\n"+
+			"Report findings only - no positive observations.", result)
+	})
+
+	t.Run("frontmatter options", func(t *testing.T) {
+		result := renderAgent(config.CustomAgent{Name: "security", Prompt: "check handlers", Model: "opus",
+			Tools: []string{"Read", "Grep"}, Files: []string{"**/*.go", "!**/*_test.go"}, Severity: "major"})
+		assert.Equal(t, "Use the Task tool to launch a general-purpose agent (model: opus) with this prompt:\n\"check handlers\"\n\n"+
+			"Allow the agent to use only these tools: Read, Grep.\n"+
+			"Limit the agent to files matching: **/*.go, !**/*_test.go (patterns starting with ! are excluded).\n"+
+			"Report critical and major findings only - no positive observations.", result)
+	})
+
+	t.Run("critical severity", func(t *testing.T) {
+		result := renderAgent(config.CustomAgent{Name: "a", Prompt: "p", Severity: "critical"})
+		assert.Contains(t, result, "Report critical findings only")
+	})
+}
+
+func TestRunner_expandAgentReferences_Phases(t *testing.T) {
+	appCfg := &config.Config{
+		CustomAgents: []config.CustomAgent{
+			{Name: "docs", Prompt: "check docs", Phases: []string{"review_first"}},
+			{Name: "quality", Prompt: "check quality"},
+		},
+	}
+	log := newMockLogger("")
+	r := &Runner{cfg: Config{AppConfig: appCfg}, log: log}
+	prompt := "{{agent:docs}}\n{{agent:quality}}"
+
+	first := r.expandAgentReferences(prompt, promptReviewFirst)
+	assert.Contains(t, first, "check docs")
+	assert.Contains(t, first, "check quality")
+
+	second := r.expandAgentReferences(prompt, promptReviewSecond)
+	assert.NotContains(t, second, "check docs")
+	assert.NotContains(t, second, "{{agent:docs}}")
+	assert.Contains(t, second, "check quality")
+
+	require.Len(t, log.PrintCalls(), 1, "only the disabled reference warns")
+	warn := log.PrintCalls()[0]
+	assert.Contains(t, warn.Format, "expands to nothing")
+	assert.Equal(t, []any{"docs", promptReviewSecond, "review_first"}, warn.Args)
+}
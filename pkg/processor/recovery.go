@@ -0,0 +1,38 @@
+package processor
+
+import (
+	"context"
+
+	"github.com/umputun/ralphex/pkg/executor"
+)
+
+// runExec runs prompt with exec, waiting out rate, usage and overload limits and retrying stalled runs.
+func (r *Runner) runExec(ctx context.Context, exec Executor, prompt string) executor.Result {
+	call := func(p string) executor.Result { return exec.Run(ctx, p) }
+	return r.recoverRun(ctx, prompt, call(prompt), call)
+}
+
+// recoverRun takes the result of call(prompt) and recovers from transient failures:
+// limit errors are waited out and the same prompt is run again, stalled runs are retried
+// with a note appended to the prompt. other results are returned unchanged.
+// token usage of every attempt is logged.
+func (r *Runner) recoverRun(ctx context.Context, prompt string, result executor.Result,
+	call func(prompt string) executor.Result) executor.Result {
+	r.logUsage(result)
+	logged := func(p string) executor.Result {
+		res := call(p)
+		r.logUsage(res)
+		return res
+	}
+	limited := func(p string, res executor.Result) executor.Result {
+		return r.retryOnLimit(ctx, res, func() executor.Result { return logged(p) })
+	}
+	return r.retryOnStall(ctx, prompt, limited(prompt, result), func(p string) executor.Result { return limited(p, logged(p)) })
+}
+
+// logUsage logs the token usage reported by a run, runs without usage (codex, custom commands) are skipped.
+func (r *Runner) logUsage(result executor.Result) {
+	if !result.Usage.IsZero() {
+		r.log.LogUsage(result.Usage)
+	}
+}
@@ -0,0 +1,42 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// repositoriesNote is appended to claude prompts of a multi-repo plan, %s is the list of additional repositories.
+const repositoriesNote = `
+
+MULTI-REPOSITORY PLAN: the current directory is the primary repository. This plan also covers these repositories:
+%s
+Make changes in whichever of the repositories the work needs. Run git commands for the additional repositories
+with git -C <path>, e.g. git -C <path> diff master...HEAD, and treat the changes in all repositories as one change set.
+Whenever you are asked to commit, commit in every repository you changed, with the same message.`
+
+// withRepositoriesNote appends the list of additional repositories to a prompt for multi-repo plans.
+func (r *Runner) withRepositoriesNote(prompt string) string {
+	if len(r.cfg.Repositories) == 0 {
+		return prompt
+	}
+	list := make([]string, 0, len(r.cfg.Repositories))
+	for _, repo := range r.cfg.Repositories {
+		list = append(list, "- "+repo)
+	}
+	return prompt + fmt.Sprintf(repositoriesNote, strings.Join(list, "\n"))
+}
+
+// repositoriesDiff returns codex instructions running the git command args in each additional repository,
+// so the external review covers the combined diff. empty for single-repo plans.
+func (r *Runner) repositoriesDiff(args string) string {
+	if len(r.cfg.Repositories) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\nThe change spans several repositories, also run:")
+	for _, repo := range r.cfg.Repositories {
+		fmt.Fprintf(&b, "\n- git -C %s %s", repo, args)
+	}
+	b.WriteString("\nReview the combined changes of all repositories.")
+	return b.String()
+}
@@ -0,0 +1,34 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunner_withRepositoriesNote(t *testing.T) {
+	t.Run("single repository", func(t *testing.T) {
+		r := &Runner{cfg: Config{PlanFile: "docs/plans/test.md", AppConfig: testAppConfig(t)}, log: newMockLogger("")}
+		assert.NotContains(t, r.buildTaskPrompt(), "MULTI-REPOSITORY")
+		assert.NotContains(t, r.buildCodexPrompt(true, ""), "git -C")
+	})
+
+	t.Run("additional repositories", func(t *testing.T) {
+		r := &Runner{cfg: Config{PlanFile: "docs/plans/test.md", AppConfig: testAppConfig(t),
+			Repositories: []string{"/src/shared-lib", "/src/proto"}}, log: newMockLogger("")}
+
+		for name, prompt := range map[string]string{
+			"task":         r.buildTaskPrompt(),
+			"first review": r.buildFirstReviewPrompt(),
+			"codex eval":   r.buildCodexEvaluationPrompt("findings"),
+		} {
+			assert.Contains(t, prompt, "MULTI-REPOSITORY PLAN", name)
+			assert.Contains(t, prompt, "\n- /src/shared-lib\n- /src/proto\n", name)
+		}
+
+		first := r.buildCodexPrompt(true, "")
+		assert.Contains(t, first, "Run: git diff master...HEAD\n")
+		assert.Contains(t, first, "- git -C /src/shared-lib diff master...HEAD\n- git -C /src/proto diff master...HEAD\n")
+		assert.Contains(t, r.buildCodexPrompt(false, ""), "- git -C /src/proto diff\n")
+	})
+}
@@ -0,0 +1,124 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/umputun/ralphex/pkg/config"
+)
+
+// noIssuesMarker is printed by review agent processes that found nothing.
+const noIssuesMarker = "NO ISSUES FOUND"
+
+// agentProcessMode returns true when review agents run as separate executor processes.
+func (r *Runner) agentProcessMode() bool {
+	return r.cfg.AppConfig != nil && r.cfg.AppConfig.ReviewAgentMode == "process"
+}
+
+// reviewAgents returns agents referenced by the first review prompt, in reference order.
+// duplicates are dropped, agents restricted to other prompts are skipped, and missing agents log a warning.
+func (r *Runner) reviewAgents() []config.CustomAgent {
+	agentMap := make(map[string]config.CustomAgent, len(r.cfg.AppConfig.CustomAgents))
+	for _, agent := range r.cfg.AppConfig.CustomAgents {
+		agentMap[agent.Name] = agent
+	}
+
+	var agents []config.CustomAgent
+	seen := make(map[string]bool)
+	for _, m := range agentRefPattern.FindAllStringSubmatch(r.cfg.AppConfig.ReviewFirstPrompt, -1) {
+		name := m[1]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		agent, ok := agentMap[name]
+		if !ok {
+			r.log.Print("[WARN] agent %q not found, skipping", name)
+			continue
+		}
+		if agent.AppliesTo(promptReviewFirst) {
+			agents = append(agents, agent)
+		}
+	}
+	return agents
+}
+
+// runFirstReview runs the first review pass. in process mode every referenced agent runs as its own
+// executor process and a single claude session then verifies and fixes the collected findings.
+func (r *Runner) runFirstReview(ctx context.Context) error {
+	if !r.agentProcessMode() {
+		return r.runClaudeReview(ctx, r.buildFirstReviewPrompt())
+	}
+	agents := r.reviewAgents()
+	if len(agents) == 0 {
+		r.log.Print("no review agents referenced, running single review session")
+		return r.runClaudeReview(ctx, r.buildFirstReviewPrompt())
+	}
+
+	runs, err := r.runReviewAgents(ctx, agents)
+	if err != nil {
+		return err
+	}
+	r.log.PrintSection(NewGenericSection("claude review 0: consolidate agent findings"))
+	return r.runClaudeReview(ctx, r.buildConsolidatePrompt(runs))
+}
+
+// runReviewAgents runs agents in parallel, limited by review_agent_concurrency.
+// results are logged on the caller goroutine as they complete because the logger is not goroutine-safe.
+// returned runs are ordered as the agents were referenced.
+func (r *Runner) runReviewAgents(ctx context.Context, agents []config.CustomAgent) ([]parallelRun, error) {
+	limit := max(1, r.cfg.AppConfig.ReviewAgentConcurrency)
+	r.log.Print("running %d review agents as separate processes (concurrency %d)", len(agents), limit)
+
+	jobs := make([]parallelJob, len(agents))
+	for i, agent := range agents {
+		jobs[i] = parallelJob{name: agent.Name, exec: r.agents(agent), prompt: r.buildReviewAgentPrompt(agent)}
+	}
+
+	results := runParallel(ctx, limit, jobs)
+	runs := make([]parallelRun, len(agents))
+	for range agents {
+		run := r.retryParallelRun(ctx, jobs, <-results)
+		runs[run.index] = run
+		r.logParallelRun("review agent", run)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("review agents: %w", err)
+	}
+	return runs, nil
+}
+
+// buildReviewAgentPrompt creates the prompt for a single review agent process.
+// uses the review_agent prompt loaded from config (either user-provided or embedded default).
+// model and tools are not part of the prompt, the agent executor enforces them.
+func (r *Runner) buildReviewAgentPrompt(agent config.CustomAgent) string {
+	instructions := agent.Prompt
+	if extra := renderAgentConstraints(agent); extra != "" {
+		instructions += "\n\n" + extra
+	}
+	prompt := r.replacePromptVariables(r.cfg.AppConfig.ReviewAgentPrompt, promptReviewFirst)
+	prompt = strings.ReplaceAll(prompt, "{{AGENT_NAME}}", agent.Name)
+	return strings.ReplaceAll(prompt, "{{AGENT_PROMPT}}", instructions)
+}
+
+// buildConsolidatePrompt creates the prompt verifying and fixing findings of review agent processes.
+// uses the review_consolidate prompt loaded from config (either user-provided or embedded default).
+func (r *Runner) buildConsolidatePrompt(runs []parallelRun) string {
+	var b strings.Builder
+	for _, run := range runs {
+		fmt.Fprintf(&b, "### Agent: %s\n", run.name)
+		output := strings.TrimSpace(run.result.Output)
+		switch {
+		case run.result.Error != nil:
+			fmt.Fprintf(&b, "(agent failed: %v)\n\n", run.result.Error)
+		case output == "":
+			b.WriteString(noIssuesMarker + "\n\n")
+		default:
+			b.WriteString(output + "\n\n")
+		}
+	}
+	prompt := r.replacePromptVariables(r.cfg.AppConfig.ReviewConsolidatePrompt, promptReviewFirst)
+	return r.withCommitNote(strings.ReplaceAll(prompt, "{{AGENT_FINDINGS}}", strings.TrimSpace(b.String())))
+}
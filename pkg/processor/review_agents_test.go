@@ -0,0 +1,247 @@
+package processor_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/umputun/ralphex/pkg/config"
+	"github.com/umputun/ralphex/pkg/executor"
+	"github.com/umputun/ralphex/pkg/processor"
+	"github.com/umputun/ralphex/pkg/processor/mocks"
+)
+
+// newAgentExecutor creates a concurrency-safe executor mock answering per agent name.
+func newAgentExecutor(outputs map[string]executor.Result) *mocks.ExecutorMock {
+	return &mocks.ExecutorMock{
+		RunFunc: func(_ context.Context, prompt string) executor.Result {
+			for name, res := range outputs {
+				if strings.Contains(prompt, "agent:"+name+"\n") {
+					return res
+				}
+			}
+			return executor.Result{Error: errors.New("unexpected agent prompt")}
+		},
+	}
+}
+
+func processModeConfig(t *testing.T) *config.Config {
+	t.Helper()
+	appCfg := testAppConfig(t)
+	appCfg.ReviewAgentMode = "process"
+	appCfg.ReviewAgentConcurrency = 2
+	appCfg.ReviewFirstPrompt = "review {{GOAL}}\n{{agent:quality}}\n{{agent:testing}}\n{{agent:missing}}\n{{agent:quality}}"
+	appCfg.ReviewAgentPrompt = "agent:{{AGENT_NAME}}\n{{AGENT_PROMPT}}"
+	appCfg.ReviewConsolidatePrompt = "consolidate:\n{{AGENT_FINDINGS}}"
+	appCfg.CustomAgents = []config.CustomAgent{
+		{Name: "quality", Prompt: "check quality", Severity: "major"},
+		{Name: "testing", Prompt: "check tests"},
+		{Name: "docs", Prompt: "check docs"},
+	}
+	return appCfg
+}
+
+func TestRunner_ReviewAgentProcessMode(t *testing.T) {
+	log := newMockLogger("progress.txt")
+	claude := newMockExecutor([]executor.Result{
+		{Output: "fixed", Signal: processor.SignalReviewDone}, // consolidation
+		{Output: "done", Signal: processor.SignalReviewDone},  // pre-codex review loop
+		{Output: "done", Signal: processor.SignalReviewDone},  // post-codex review loop
+	})
+	agents := newAgentExecutor(map[string]executor.Result{
+		"quality": {Output: "main.go:10 unchecked error"},
+		"testing": {Output: "NO ISSUES FOUND"},
+	})
+
+	cfg := processor.Config{Mode: processor.ModeReview, MaxIterations: 50, AppConfig: processModeConfig(t)}
+	r := processor.NewWithExecutors(cfg, log, claude, newMockExecutor(nil))
+	r.SetAgentExecutor(agents)
+	_, err := r.Run(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, agents.RunCalls(), 2, "duplicate and missing references are skipped")
+	prompts := make([]string, 0, 2)
+	for _, c := range agents.RunCalls() {
+		prompts = append(prompts, c.Prompt)
+	}
+	assert.Contains(t, prompts, "agent:quality\ncheck quality\n\nReport critical and major findings only - no positive observations.")
+	assert.Contains(t, prompts, "agent:testing\ncheck tests\n\nReport findings only - no positive observations.")
+
+	consolidate := claude.RunCalls()[0].Prompt
+	assert.Equal(t, "consolidate:\n### Agent: quality\nmain.go:10 unchecked error\n\n### Agent: testing\nNO ISSUES FOUND",
+		consolidate)
+
+	var sections []string
+	for _, c := range log.PrintSectionCalls() {
+		sections = append(sections, c.Section.Label)
+	}
+	assert.Contains(t, sections, "review agent quality (0s)")
+	assert.Contains(t, sections, "review agent testing (0s)")
+	assert.Contains(t, sections, "claude review 0: consolidate agent findings")
+
+	var warned bool
+	for _, c := range log.PrintCalls() {
+		if strings.HasPrefix(c.Format, "[WARN] agent %q not found") {
+			warned = true
+		}
+	}
+	assert.True(t, warned)
+}
+
+func TestRunner_ReviewAgentProcessMode_AgentOptions(t *testing.T) {
+	appCfg := processModeConfig(t)
+	appCfg.CustomAgents[0].Model, appCfg.CustomAgents[0].Tools = "opus", []string{"Read", "Grep"}
+	claude := newMockExecutor([]executor.Result{{Output: "error", Signal: processor.SignalFailed}})
+	agents := newAgentExecutor(map[string]executor.Result{
+		"quality": {Output: "NO ISSUES FOUND"},
+		"testing": {Output: "NO ISSUES FOUND"},
+	})
+
+	cfg := processor.Config{Mode: processor.ModeReview, MaxIterations: 50, AppConfig: appCfg}
+	r := processor.NewWithExecutors(cfg, newMockLogger("progress.txt"), claude, newMockExecutor(nil))
+	r.SetAgentExecutor(agents)
+	var wrapped int32
+	r.WrapExecutors(func(name string, e processor.Executor) processor.Executor {
+		if name == "agent" {
+			atomic.AddInt32(&wrapped, 1)
+		}
+		return e
+	})
+	_, _ = r.Run(context.Background())
+
+	assert.Equal(t, int32(2), wrapped, "each agent executor is wrapped")
+	require.Len(t, agents.RunCalls(), 2)
+	for _, c := range agents.RunCalls() {
+		assert.NotContains(t, c.Prompt, "only these tools", "tools are enforced by the executor, not the prompt")
+		assert.NotContains(t, c.Prompt, "opus")
+	}
+}
+
+func TestRunner_ReviewAgentProcessMode_AgentFailure(t *testing.T) {
+	log := newMockLogger("progress.txt")
+	claude := newMockExecutor([]executor.Result{
+		{Output: "error", Signal: processor.SignalFailed}, // consolidation
+	})
+	agents := newAgentExecutor(map[string]executor.Result{
+		"quality": {Error: errors.New("crashed")},
+		"testing": {Output: "test.go:1 missing case"},
+	})
+
+	cfg := processor.Config{Mode: processor.ModeReview, MaxIterations: 50, AppConfig: processModeConfig(t)}
+	r := processor.NewWithExecutors(cfg, log, claude, newMockExecutor(nil))
+	r.SetAgentExecutor(agents)
+	_, err := r.Run(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "FAILED signal")
+
+	consolidate := claude.RunCalls()[0].Prompt
+	assert.Contains(t, consolidate, "### Agent: quality\n(agent failed: crashed)")
+	assert.Contains(t, consolidate, "### Agent: testing\ntest.go:1 missing case")
+}
+
+func TestRunner_ReviewAgentProcessMode_Concurrency(t *testing.T) {
+	appCfg := processModeConfig(t)
+	appCfg.ReviewAgentConcurrency = 1
+	claude := newMockExecutor([]executor.Result{{Output: "error", Signal: processor.SignalFailed}})
+
+	var running, peak int32
+	var mu sync.Mutex
+	agents := &mocks.ExecutorMock{
+		RunFunc: func(_ context.Context, _ string) executor.Result {
+			n := atomic.AddInt32(&running, 1)
+			mu.Lock()
+			peak = max(peak, n)
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return executor.Result{Output: "ok"}
+		},
+	}
+
+	cfg := processor.Config{Mode: processor.ModeReview, MaxIterations: 50, AppConfig: appCfg}
+	r := processor.NewWithExecutors(cfg, newMockLogger("progress.txt"), claude, newMockExecutor(nil))
+	r.SetAgentExecutor(agents)
+	_, _ = r.Run(context.Background())
+	assert.Len(t, agents.RunCalls(), 2)
+	assert.Equal(t, int32(1), peak)
+}
+
+func TestRunner_ReviewAgentTaskMode(t *testing.T) {
+	appCfg := processModeConfig(t)
+	appCfg.ReviewAgentMode = "task"
+	claude := newMockExecutor([]executor.Result{{Output: "error", Signal: processor.SignalFailed}})
+	agents := newAgentExecutor(nil)
+
+	cfg := processor.Config{Mode: processor.ModeReview, MaxIterations: 50, AppConfig: appCfg}
+	r := processor.NewWithExecutors(cfg, newMockLogger("progress.txt"), claude, newMockExecutor(nil))
+	r.SetAgentExecutor(agents)
+	_, _ = r.Run(context.Background())
+	assert.Empty(t, agents.RunCalls())
+	assert.Contains(t, claude.RunCalls()[0].Prompt, "Use the Task tool to launch a general-purpose agent")
+}
+
+func TestRunner_ExternalReviewers(t *testing.T) {
+	newRunner := func(t *testing.T, claude *mocks.ExecutorMock, reviewers ...processor.Reviewer) (*processor.Runner, *mocks.LoggerMock) {
+		t.Helper()
+		log := newMockLogger("progress.txt")
+		cfg := processor.Config{Mode: processor.ModeCodexOnly, MaxIterations: 50, CodexEnabled: true, AppConfig: testAppConfig(t)}
+		r := processor.NewWithExecutors(cfg, log, claude, newMockExecutor(nil))
+		r.SetReviewers(reviewers)
+		return r, log
+	}
+
+	t.Run("merged findings passed to evaluation", func(t *testing.T) {
+		claude := newMockExecutor([]executor.Result{
+			{Output: "all fixed", Signal: processor.SignalCodexDone}, // codex evaluation
+			{Output: "done", Signal: processor.SignalReviewDone},     // post-codex review loop
+		})
+		r, log := newRunner(t, claude,
+			processor.Reviewer{Name: "codex", Exec: newMockExecutor([]executor.Result{{Output: "- main.go:10 unchecked error"}})},
+			processor.Reviewer{Name: "claude:opus", Exec: newMockExecutor([]executor.Result{{Output: "- main.go:10 error ignored\n- a.go:1 leak"}})},
+		)
+		_, err := r.Run(context.Background())
+		require.NoError(t, err)
+
+		eval := claude.RunCalls()[0].Prompt
+		assert.Contains(t, eval, "- [codex, claude:opus] main.go:10 unchecked error\n  also reported as: main.go:10 error ignored")
+		assert.Contains(t, eval, "- [claude:opus] a.go:1 leak")
+
+		var sections []string
+		for _, c := range log.PrintSectionCalls() {
+			sections = append(sections, c.Section.Label)
+		}
+		assert.Contains(t, sections, "external review codex (0s)")
+		assert.Contains(t, sections, "external review claude:opus (0s)")
+	})
+
+	t.Run("failing reviewer is skipped", func(t *testing.T) {
+		claude := newMockExecutor([]executor.Result{
+			{Output: "all fixed", Signal: processor.SignalCodexDone},
+			{Output: "done", Signal: processor.SignalReviewDone},
+		})
+		r, _ := newRunner(t, claude,
+			processor.Reviewer{Name: "codex", Exec: newMockExecutor(nil)},
+			processor.Reviewer{Name: "claude", Exec: newMockExecutor([]executor.Result{{Output: "a.go:1 leak"}})},
+		)
+		_, err := r.Run(context.Background())
+		require.NoError(t, err)
+		assert.Contains(t, claude.RunCalls()[0].Prompt, "a.go:1 leak")
+	})
+
+	t.Run("all reviewers failing stops the run", func(t *testing.T) {
+		r, _ := newRunner(t, newMockExecutor(nil),
+			processor.Reviewer{Name: "codex", Exec: newMockExecutor(nil)},
+			processor.Reviewer{Name: "claude", Exec: newMockExecutor(nil)},
+		)
+		_, err := r.Run(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "all external reviewers failed")
+	})
+}
@@ -0,0 +1,132 @@
+package processor
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/umputun/ralphex/pkg/config"
+	"github.com/umputun/ralphex/pkg/executor"
+)
+
+// Reviewer is a named executor taking part in the external review phase.
+type Reviewer struct {
+	Name string
+	Exec Executor
+}
+
+// newReviewers builds executors for the external_reviewers config entries.
+// reviewers run in parallel, so executors have no output handler and their output is logged on completion.
+// reviewers whose command is not installed are dropped with a warning. a claude reviewer runs in the
+// container sandbox of the main claude executor, with the additional repositories in repos mounted.
+func newReviewers(appCfg *config.Config, repos []string, debug bool, log Logger) []Reviewer {
+	idleTimeout, _ := stallSettings(appCfg)
+	claudeEnv, codexEnv := envPolicies(appCfg)
+	var reviewers []Reviewer
+	for _, spec := range appCfg.ExternalReviewers {
+		backend, model, _ := strings.Cut(spec, ":")
+		var reviewExec Executor
+		var command string
+		switch backend {
+		case "codex":
+			codexExec := &executor.CodexExecutor{Command: appCfg.CodexCommand, Model: appCfg.CodexModel,
+				ReasoningEffort: appCfg.CodexReasoningEffort, TimeoutMs: appCfg.CodexTimeoutMs, Sandbox: appCfg.CodexSandbox,
+				IdleTimeout: idleTimeout, Env: codexEnv, Debug: debug}
+			if model != "" {
+				codexExec.Model = model
+			}
+			reviewExec, command = codexExec, cmp.Or(appCfg.CodexCommand, "codex")
+		case "claude":
+			claudeExec := &executor.ClaudeExecutor{Command: appCfg.ClaudeCommand, Args: appCfg.ClaudeArgs, Model: model,
+				IdleTimeout: idleTimeout, Container: containerConfig(appCfg, repos), Env: claudeEnv, Debug: debug}
+			reviewExec, command = claudeExec, cmp.Or(appCfg.ClaudeCommand, "claude")
+			if claudeExec.Container != nil {
+				command = claudeExec.Container.Runtime // claude is installed in the image, not on the host
+			}
+		case "command":
+			cmdExec, err := newCommandExecutor(appCfg, spec, codexEnv, nil, debug)
+			if err != nil {
+				log.Print("warning: external reviewer %v, skipping", err)
+				continue
+			}
+			reviewers = append(reviewers, Reviewer{Name: spec, Exec: cmdExec})
+			continue
+		default:
+			log.Print("warning: unknown external reviewer backend %q, skipping", spec)
+			continue
+		}
+		if _, err := exec.LookPath(command); err != nil {
+			log.Print("warning: external reviewer %s not found (%s: %v), skipping", spec, command, err)
+			continue
+		}
+		reviewers = append(reviewers, Reviewer{Name: spec, Exec: reviewExec})
+	}
+	return reviewers
+}
+
+// newCommandExecutor builds a command executor for a "command:<name>" reference to a command_<name> config key.
+// env is the policy of the executor the command stands in for, the stall timeout applies as for builtin executors.
+func newCommandExecutor(appCfg *config.Config, ref string, env executor.EnvPolicy, handler func(string),
+	debug bool) (*executor.CommandExecutor, error) {
+	name, ok := strings.CutPrefix(ref, "command:")
+	if !ok {
+		return nil, fmt.Errorf("%q is not a command:<name> reference", ref)
+	}
+	command, ok := appCfg.CommandExecutors[name]
+	if !ok {
+		return nil, fmt.Errorf("%q refers to undefined command_%s", ref, name)
+	}
+	idleTimeout, _ := stallSettings(appCfg)
+	return &executor.CommandExecutor{Command: command, OutputHandler: handler, IdleTimeout: idleTimeout, Env: env,
+		Debug: debug}, nil
+}
+
+// runExternalReview runs the external review phase reviewers and returns their combined findings.
+// without configured reviewers codex runs alone with streamed output, as before multi-reviewer support.
+// with several reviewers outputs are merged by mergeFindings; a failing reviewer is skipped with a warning
+// and an error is returned only when all of them fail.
+func (r *Runner) runExternalReview(ctx context.Context, prompt string) (string, error) {
+	if len(r.reviewers) == 0 {
+		res := r.runExec(ctx, r.codex, prompt)
+		return res.Output, res.Error
+	}
+
+	jobs := make([]parallelJob, len(r.reviewers))
+	for i, rv := range r.reviewers {
+		jobs[i] = parallelJob{name: rv.Name, exec: rv.Exec, prompt: prompt}
+	}
+	results := runParallel(ctx, len(jobs), jobs)
+	runs := make([]parallelRun, len(jobs))
+	for range jobs {
+		run := r.retryParallelRun(ctx, jobs, <-results)
+		runs[run.index] = run
+		r.logParallelRun("external review", run)
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	var outputs []reviewerOutput
+	var errs []error
+	for _, run := range runs {
+		if run.result.Error != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", run.name, run.result.Error))
+			continue
+		}
+		if strings.TrimSpace(run.result.Output) != "" {
+			outputs = append(outputs, reviewerOutput{name: run.name, output: run.result.Output})
+		}
+	}
+	switch {
+	case len(errs) == len(runs):
+		return "", fmt.Errorf("all external reviewers failed: %w", errors.Join(errs...))
+	case len(outputs) == 0:
+		return "", nil
+	case len(runs) == 1:
+		return outputs[0].output, nil
+	}
+	return mergeFindings(outputs), nil
+}
@@ -0,0 +1,174 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/umputun/ralphex/pkg/config"
+	"github.com/umputun/ralphex/pkg/executor"
+)
+
+func TestNew_CommandExecutors(t *testing.T) {
+	appCfg := testAppConfig(t)
+	appCfg.CommandExecutors = map[string]string{"llm": "my-llm {{PROMPT_FILE}}", "lint": "golangci-lint run"}
+	appCfg.ClaudeExecutor = "command:llm"
+	appCfg.CodexExecutor = "command:lint"
+	appCfg.ClaudeEnvDeny, appCfg.CodexEnvDeny = []string{"AWS_*"}, []string{"GITHUB_TOKEN"}
+	appCfg.StallTimeoutMs = 60000
+
+	r := New(Config{Mode: ModeFull, CodexEnabled: true, AppConfig: appCfg}, newMockLogger(""))
+	claude, ok := r.claude.(*executor.CommandExecutor)
+	require.True(t, ok)
+	assert.Equal(t, "my-llm {{PROMPT_FILE}}", claude.Command)
+	assert.NotNil(t, claude.OutputHandler)
+	assert.Equal(t, []string{"AWS_*"}, claude.Env.Deny, "claude env policy applies to the claude command")
+	assert.Equal(t, time.Minute, claude.IdleTimeout)
+	agents, ok := r.agents(config.CustomAgent{Name: "a"}).(*executor.CommandExecutor)
+	require.True(t, ok)
+	assert.Nil(t, agents.OutputHandler, "agents run in parallel and are logged on completion")
+	assert.Equal(t, claude.Env, agents.Env)
+	assert.Equal(t, time.Minute, agents.IdleTimeout)
+	codex, ok := r.codex.(*executor.CommandExecutor)
+	require.True(t, ok)
+	assert.Equal(t, "golangci-lint run", codex.Command)
+	assert.Equal(t, []string{"GITHUB_TOKEN"}, codex.Env.Deny, "codex env policy applies to the codex command")
+	assert.True(t, r.cfg.CodexEnabled, "command codex is not looked up in PATH")
+
+	t.Run("undefined command keeps builtin executor", func(t *testing.T) {
+		appCfg.ClaudeExecutor = "command:missing"
+		log := newMockLogger("")
+		r := New(Config{Mode: ModeFull, AppConfig: appCfg}, log)
+		_, ok := r.claude.(*executor.ClaudeExecutor)
+		assert.True(t, ok)
+		require.NotEmpty(t, log.printCalls)
+		assert.Contains(t, log.printCalls[0].Format, "claude_executor")
+	})
+}
+
+func TestNew_Container(t *testing.T) {
+	appCfg := testAppConfig(t)
+	appCfg.ContainerRuntime, appCfg.ContainerImage, appCfg.ContainerNetwork = "podman", "acme/claude", "none"
+	appCfg.ContainerEnv = []string{"CLAUDE_CODE_OAUTH_TOKEN"}
+
+	log := newMockLogger("")
+	r := New(Config{Mode: ModeFull, AppConfig: appCfg}, log)
+
+	claude, ok := r.claude.(*executor.ClaudeExecutor)
+	require.True(t, ok)
+	require.NotNil(t, claude.Container)
+	assert.Equal(t, executor.ContainerConfig{Runtime: "podman", Image: "acme/claude", Network: "none",
+		Env: []string{"CLAUDE_CODE_OAUTH_TOKEN"}}, *claude.Container)
+	agents, ok := r.agents(config.CustomAgent{Name: "a"}).(*executor.ClaudeExecutor)
+	require.True(t, ok)
+	assert.Same(t, claude.Container, agents.Container)
+	require.NotEmpty(t, log.printCalls)
+	assert.Contains(t, log.printCalls[0].Format, "container")
+
+	appCfg.ContainerRuntime = ""
+	r = New(Config{Mode: ModeFull, AppConfig: appCfg}, newMockLogger(""))
+	claude, ok = r.claude.(*executor.ClaudeExecutor)
+	require.True(t, ok)
+	assert.Nil(t, claude.Container)
+}
+
+func TestNew_EnvPolicies(t *testing.T) {
+	appCfg := testAppConfig(t)
+	appCfg.EnvFileVars = []string{"SHARED=file", "ONLY_FILE=1"}
+	appCfg.ClaudeEnvAllow, appCfg.ClaudeEnv = []string{"PATH"}, []string{"SHARED=claude"}
+	appCfg.CodexEnvDeny = []string{"AWS_*"}
+
+	r := New(Config{Mode: ModeFull, AppConfig: appCfg}, newMockLogger(""))
+
+	claude, ok := r.claude.(*executor.ClaudeExecutor)
+	require.True(t, ok)
+	assert.Equal(t, executor.EnvPolicy{Allow: []string{"PATH"}, Extra: []string{"SHARED=file", "ONLY_FILE=1", "SHARED=claude"}},
+		claude.Env)
+	codex, ok := r.codex.(*executor.CodexExecutor)
+	require.True(t, ok)
+	assert.Equal(t, executor.EnvPolicy{Deny: []string{"AWS_*"}, Extra: []string{"SHARED=file", "ONLY_FILE=1"}}, codex.Env)
+	agents, ok := r.agents(config.CustomAgent{Name: "a"}).(*executor.ClaudeExecutor)
+	require.True(t, ok)
+	assert.Equal(t, claude.Env, agents.Env)
+}
+
+func TestNew_AgentExecutors(t *testing.T) {
+	appCfg := testAppConfig(t)
+	appCfg.ClaudeArgs = "--dangerously-skip-permissions --output-format stream-json --verbose"
+	r := New(Config{Mode: ModeFull, AppConfig: appCfg}, newMockLogger(""))
+
+	security, ok := r.agents(config.CustomAgent{Name: "security", Model: "opus", Tools: []string{"Read", "Grep"}}).(*executor.ClaudeExecutor)
+	require.True(t, ok)
+	assert.Equal(t, "opus", security.Model)
+	assert.Equal(t, []string{"Read", "Grep"}, security.AllowedTools)
+	assert.Equal(t, appCfg.ClaudeArgs, security.Args)
+
+	plain, ok := r.agents(config.CustomAgent{Name: "plain"}).(*executor.ClaudeExecutor)
+	require.True(t, ok)
+	assert.Empty(t, plain.Model)
+	assert.Empty(t, plain.AllowedTools)
+	assert.NotSame(t, security, plain, "every agent runs on its own executor")
+
+	t.Run("command executor warns about ignored options", func(t *testing.T) {
+		appCfg.CommandExecutors = map[string]string{"llm": "my-llm"}
+		appCfg.ClaudeExecutor = "command:llm"
+		log := newMockLogger("")
+		r := New(Config{Mode: ModeFull, AppConfig: appCfg}, log)
+		_, ok := r.agents(config.CustomAgent{Name: "security", Model: "opus"}).(*executor.CommandExecutor)
+		require.True(t, ok)
+		require.NotEmpty(t, log.printCalls)
+		assert.Contains(t, log.printCalls[len(log.printCalls)-1].Format, "not applied to command executors")
+	})
+}
+
+func TestNewReviewers_Command(t *testing.T) {
+	appCfg := testAppConfig(t)
+	appCfg.CommandExecutors = map[string]string{"lint": "golangci-lint run"}
+	appCfg.ExternalReviewers = []string{"command:lint", "command:nope"}
+
+	log := newMockLogger("")
+	reviewers := newReviewers(appCfg, nil, false, log)
+	require.Len(t, reviewers, 1)
+	assert.Equal(t, "command:lint", reviewers[0].Name)
+	cmdExec, ok := reviewers[0].Exec.(*executor.CommandExecutor)
+	require.True(t, ok)
+	assert.Equal(t, "golangci-lint run", cmdExec.Command)
+	assert.Nil(t, cmdExec.OutputHandler)
+	require.Len(t, log.printCalls, 1)
+	assert.Contains(t, log.printCalls[0].Format, "external reviewer")
+}
+
+func TestNewReviewers_ClaudeModel(t *testing.T) {
+	appCfg := testAppConfig(t)
+	appCfg.ClaudeCommand = "sh" // any installed command passes the PATH lookup
+	appCfg.ExternalReviewers = []string{"claude:opus", "claude"}
+
+	reviewers := newReviewers(appCfg, nil, false, newMockLogger(""))
+	require.Len(t, reviewers, 2)
+	withModel, ok := reviewers[0].Exec.(*executor.ClaudeExecutor)
+	require.True(t, ok)
+	assert.Equal(t, "opus", withModel.Model)
+	assert.Equal(t, appCfg.ClaudeArgs, withModel.Args, "claude args are kept, the executor adds --model")
+	plain, ok := reviewers[1].Exec.(*executor.ClaudeExecutor)
+	require.True(t, ok)
+	assert.Empty(t, plain.Model)
+}
+
+func TestNewReviewers_ClaudeContainer(t *testing.T) {
+	appCfg := testAppConfig(t)
+	appCfg.ClaudeCommand = "claude-not-on-host"
+	appCfg.ContainerRuntime = "sh" // the runtime, not claude, must be installed on the host
+	appCfg.ContainerImage = "ralphex/claude:latest"
+	appCfg.ExternalReviewers = []string{"claude"}
+
+	reviewers := newReviewers(appCfg, []string{"/src/shared-lib"}, false, newMockLogger(""))
+	require.Len(t, reviewers, 1)
+	claudeExec, ok := reviewers[0].Exec.(*executor.ClaudeExecutor)
+	require.True(t, ok)
+	require.NotNil(t, claudeExec.Container, "reviewer runs in the sandbox like the main agent")
+	assert.Equal(t, "sh", claudeExec.Container.Runtime)
+	assert.Equal(t, "ralphex/claude:latest", claudeExec.Container.Image)
+	assert.Equal(t, []string{"/src/shared-lib"}, claudeExec.Container.Repos)
+}
@@ -0,0 +1,64 @@
+package processor
+
+import (
+	"fmt"
+	"time"
+)
+
+// taskSnapshot records repository state before a task attempt.
+// empty head means rollback is not available for the attempt.
+type taskSnapshot struct {
+	head string
+}
+
+// rollbackEnabled reports whether failed task attempts are rolled back before retry.
+func (r *Runner) rollbackEnabled() bool {
+	return r.rollback != nil && r.cfg.AppConfig != nil && r.cfg.AppConfig.TaskRollback
+}
+
+// snapshotTask records HEAD before a task attempt.
+// rollback is skipped if the worktree already has uncommitted changes, resetting would discard them.
+// ralphex's own files (progress logs, .gitignore) don't count, rollback keeps their content.
+func (r *Runner) snapshotTask() taskSnapshot {
+	if !r.rollbackEnabled() {
+		return taskSnapshot{}
+	}
+	changes, err := r.rollback.HasChangesOtherThan(r.cfg.OwnFiles...)
+	if err != nil {
+		r.log.Print("warning: task rollback: %v", err)
+		return taskSnapshot{}
+	}
+	if changes {
+		r.log.Print("warning: uncommitted changes before task attempt, rollback disabled for this attempt")
+		return taskSnapshot{}
+	}
+	head, err := r.rollback.HeadHash()
+	if err != nil {
+		r.log.Print("warning: task rollback: %v", err)
+		return taskSnapshot{}
+	}
+	return taskSnapshot{head: head}
+}
+
+// rollbackTask saves the failed attempt on a side branch and resets to the snapshot, so the retry starts clean.
+// the side branch is named after the branch, time and iteration, so rollbacks within a second don't collide.
+// a failed rollback is logged and the retry continues from the failed state, as without rollback.
+func (r *Runner) rollbackTask(s taskSnapshot, iteration int) {
+	if s.head == "" {
+		return
+	}
+	branch := r.cfg.Branch
+	if branch == "" {
+		branch = "detached"
+	}
+	side := fmt.Sprintf("ralphex/failed/%s/%s-%d", branch, time.Now().Format("20060102-150405"), iteration)
+	msg := fmt.Sprintf("ralphex: failed task attempt (iteration %d)", iteration)
+	saved, err := r.rollback.SaveAndReset(s.head, side, msg, r.cfg.OwnFiles...)
+	if err != nil {
+		r.log.Print("warning: task rollback: %v, retrying without rollback", err)
+		return
+	}
+	if saved {
+		r.log.Print("rolled back failed task attempt, changes saved on branch %s", side)
+	}
+}
@@ -0,0 +1,156 @@
+package processor_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/umputun/ralphex/pkg/executor"
+	"github.com/umputun/ralphex/pkg/processor"
+	"github.com/umputun/ralphex/pkg/processor/mocks"
+)
+
+// newMockRollbacker creates a rollbacker mock with a clean worktree at the given HEAD.
+func newMockRollbacker(head string) *mocks.GitRollbackerMock {
+	return &mocks.GitRollbackerMock{
+		HeadHashFunc:            func() (string, error) { return head, nil },
+		HasChangesOtherThanFunc: func(...string) (bool, error) { return false, nil },
+		SaveAndResetFunc:        func(_, _, _ string, _ ...string) (bool, error) { return true, nil },
+	}
+}
+
+func TestRunner_TaskRollback(t *testing.T) {
+	newRunner := func(t *testing.T, appCfgRollback bool, results []executor.Result) (*processor.Runner, *mocks.LoggerMock) {
+		t.Helper()
+		planFile := filepath.Join(t.TempDir(), "plan.md")
+		require.NoError(t, os.WriteFile(planFile, []byte("# Plan\n- [x] done\n"), 0o600))
+		appCfg := testAppConfig(t)
+		appCfg.TaskRollback = appCfgRollback
+		log := newMockLogger("progress.txt")
+		cfg := processor.Config{Mode: processor.ModeFull, PlanFile: planFile, Branch: "feature", MaxIterations: 10,
+			IterationDelayMs: 1, TaskRetryCount: 1, AppConfig: appCfg, OwnFiles: []string{".gitignore", "progress.txt"}}
+		return processor.NewWithExecutors(cfg, log, newMockExecutor(results), newMockExecutor(nil)), log
+	}
+
+	t.Run("rolls back before retry", func(t *testing.T) {
+		r, log := newRunner(t, true, []executor.Result{
+			{Output: "broken", Signal: processor.SignalFailed},
+			{Output: "done", Signal: processor.SignalCompleted},
+		})
+		rb := newMockRollbacker("abc123")
+		r.SetGitRollbacker(rb)
+		_, err := r.Run(context.Background())
+		require.Error(t, err, "review phase has no mock results")
+
+		require.Len(t, rb.SaveAndResetCalls(), 1)
+		call := rb.SaveAndResetCalls()[0]
+		assert.Equal(t, "abc123", call.Target)
+		assert.True(t, strings.HasPrefix(call.SideBranch, "ralphex/failed/feature/"))
+		assert.True(t, strings.HasSuffix(call.SideBranch, "-1"), "iteration keeps names unique within a second")
+		assert.Equal(t, "ralphex: failed task attempt (iteration 1)", call.Msg)
+		assert.Equal(t, []string{".gitignore", "progress.txt"}, call.Keep, "own files survive the reset")
+		require.NotEmpty(t, rb.HasChangesOtherThanCalls())
+		assert.Equal(t, []string{".gitignore", "progress.txt"}, rb.HasChangesOtherThanCalls()[0].FilePaths,
+			"own files don't count as uncommitted changes")
+
+		var rolledBack bool
+		for _, c := range log.PrintCalls() {
+			if strings.HasPrefix(c.Format, "rolled back failed task attempt") {
+				rolledBack = true
+			}
+		}
+		assert.True(t, rolledBack)
+	})
+
+	t.Run("no rollback when attempts exhausted", func(t *testing.T) {
+		r, _ := newRunner(t, true, []executor.Result{
+			{Output: "broken", Signal: processor.SignalFailed},
+			{Output: "broken again", Signal: processor.SignalFailed},
+		})
+		rb := newMockRollbacker("abc123")
+		r.SetGitRollbacker(rb)
+		_, err := r.Run(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "FAILED signal")
+		assert.Len(t, rb.SaveAndResetCalls(), 1, "only the retried attempt is rolled back, final state is kept")
+	})
+
+	t.Run("failed rollback continues the retry", func(t *testing.T) {
+		r, log := newRunner(t, true, []executor.Result{
+			{Output: "broken", Signal: processor.SignalFailed},
+			{Output: "done", Signal: processor.SignalCompleted},
+		})
+		rb := newMockRollbacker("abc123")
+		rb.SaveAndResetFunc = func(_, _, _ string, _ ...string) (bool, error) {
+			return false, errors.New("stage changes: big.bin is tracked by git LFS")
+		}
+		r.SetGitRollbacker(rb)
+		_, err := r.Run(context.Background())
+		require.Error(t, err, "review phase has no mock results")
+		assert.NotContains(t, err.Error(), "task rollback")
+
+		var warned, retried bool
+		for _, c := range log.PrintCalls() {
+			msg := fmt.Sprintf(c.Format, c.Args...)
+			warned = warned || strings.Contains(msg, "warning: task rollback: stage changes: big.bin is tracked by git LFS")
+			retried = retried || msg == "task failed, retrying..."
+		}
+		assert.True(t, warned)
+		assert.True(t, retried)
+	})
+
+	t.Run("skipped with dirty worktree", func(t *testing.T) {
+		r, _ := newRunner(t, true, []executor.Result{
+			{Output: "broken", Signal: processor.SignalFailed},
+			{Output: "done", Signal: processor.SignalCompleted},
+		})
+		rb := newMockRollbacker("abc123")
+		rb.HasChangesOtherThanFunc = func(...string) (bool, error) { return true, nil }
+		r.SetGitRollbacker(rb)
+		_, _ = r.Run(context.Background())
+		assert.Empty(t, rb.SaveAndResetCalls())
+	})
+
+	t.Run("snapshot taken after pre_task hook", func(t *testing.T) {
+		planFile := filepath.Join(t.TempDir(), "plan.md")
+		require.NoError(t, os.WriteFile(planFile, []byte("# Plan\n- [x] done\n"), 0o600))
+		marker := filepath.Join(t.TempDir(), "hook-ran")
+		appCfg := testAppConfig(t)
+		appCfg.TaskRollback = true
+		appCfg.HookPreTask = "touch " + marker
+		cfg := processor.Config{Mode: processor.ModeFull, PlanFile: planFile, MaxIterations: 10,
+			IterationDelayMs: 1, TaskRetryCount: 1, AppConfig: appCfg}
+		r := processor.NewWithExecutors(cfg, newMockLogger("progress.txt"), newMockExecutor([]executor.Result{
+			{Output: "broken", Signal: processor.SignalFailed},
+			{Output: "done", Signal: processor.SignalCompleted},
+		}), newMockExecutor(nil))
+		rb := newMockRollbacker("abc123")
+		rb.HasChangesOtherThanFunc = func(...string) (bool, error) {
+			_, err := os.Stat(marker)
+			assert.NoError(t, err, "pre_task hook runs before the snapshot")
+			return false, nil
+		}
+		r.SetGitRollbacker(rb)
+		_, _ = r.Run(context.Background())
+		assert.NotEmpty(t, rb.HasChangesOtherThanCalls())
+	})
+
+	t.Run("disabled in config", func(t *testing.T) {
+		r, _ := newRunner(t, false, []executor.Result{
+			{Output: "broken", Signal: processor.SignalFailed},
+			{Output: "done", Signal: processor.SignalCompleted},
+		})
+		rb := newMockRollbacker("abc123")
+		r.SetGitRollbacker(rb)
+		_, _ = r.Run(context.Background())
+		assert.Empty(t, rb.HeadHashCalls())
+		assert.Empty(t, rb.SaveAndResetCalls())
+	})
+}
@@ -2,6 +2,7 @@
 package processor
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -33,6 +34,10 @@ type Config struct {
 	PlanFile         string         // path to plan file (required for full mode)
 	PlanDescription  string         // plan description for interactive plan creation mode
 	ProgressPath     string         // path to progress file
+	Branch           string         // current git branch (exposed to hooks)
+	Repositories     []string       // root paths of additional repositories of a multi-repo plan
+	Scope            []string       // repository subdirectories tasks and reviews are limited to, relative to the root
+	OwnFiles         []string       // files ralphex itself writes (progress logs, .gitignore), not task changes
 	Mode             Mode           // execution mode
 	MaxIterations    int            // maximum iterations for task phase
 	Debug            bool           // enable debug output
@@ -46,12 +51,20 @@ type Config struct {
 //go:generate moq -out mocks/executor.go -pkg mocks -skip-ensure -fmt goimports . Executor
 //go:generate moq -out mocks/logger.go -pkg mocks -skip-ensure -fmt goimports . Logger
 //go:generate moq -out mocks/input_collector.go -pkg mocks -skip-ensure -fmt goimports . InputCollector
+//go:generate moq -out mocks/git_committer.go -pkg mocks -skip-ensure -fmt goimports . GitCommitter
+//go:generate moq -out mocks/git_rollbacker.go -pkg mocks -skip-ensure -fmt goimports . GitRollbacker
+//go:generate moq -out mocks/session_resumer.go -pkg mocks -skip-ensure -fmt goimports . SessionResumer
 
 // Executor runs CLI commands and returns results.
 type Executor interface {
 	Run(ctx context.Context, prompt string) executor.Result
 }
 
+// SessionResumer is implemented by executors able to continue an earlier session with a new prompt.
+type SessionResumer interface {
+	Resume(ctx context.Context, sessionID, prompt string) executor.Result
+}
+
 // Logger provides logging functionality.
 type Logger interface {
 	SetPhase(phase Phase)
@@ -61,6 +74,10 @@ type Logger interface {
 	PrintAligned(text string)
 	LogQuestion(question string, options []string)
 	LogAnswer(answer string)
+	LogActivity(activity executor.Activity)
+	LogStall(idle time.Duration)
+	LogUsage(usage executor.Usage)
+	LogCommits(commits IterationCommits)
 	Path() string
 }
 
@@ -69,30 +86,67 @@ type InputCollector interface {
 	AskQuestion(ctx context.Context, question string, options []string) (string, error)
 }
 
+// GitCommitter stages and commits worktree changes for runner-managed commits.
+type GitCommitter interface {
+	StageAll() error
+	IsDirty() (bool, error)
+	Commit(msg string) error
+	DirtySubmodules() ([]string, error)
+}
+
+// GitRollbacker snapshots and restores repository state around task attempts.
+type GitRollbacker interface {
+	HeadHash() (string, error)
+	HasChangesOtherThan(filePaths ...string) (bool, error)
+	SaveAndReset(target, sideBranch, msg string, keep ...string) (bool, error)
+}
+
 // Runner orchestrates the execution loop.
 type Runner struct {
 	cfg            Config
 	log            Logger
 	claude         Executor
 	codex          Executor
+	agents         func(agent config.CustomAgent) Executor // executor of a review agent process, output is logged by the runner
+	reviewers      []Reviewer                              // external review phase reviewers, empty means codex alone
 	inputCollector InputCollector
+	git            GitCommitter
+	rollback       GitRollbacker
+	head           GitHeadReader // records the commits of each iteration, nil to skip
 	iterationDelay time.Duration
 	taskRetryCount int
+	limits         limitPolicy
+	stallRetries   int
+	outcome        Outcome // phase and iteration counts of the current run
+	iterations     int     // iterations with tracked commits in the current run
 }
 
 // New creates a new Runner with the given configuration.
 // If codex is enabled but the binary is not found in PATH, it is automatically disabled with a warning.
 func New(cfg Config, log Logger) *Runner {
+	// stalled claude/codex processes are killed after idleTimeout without output
+	idleTimeout, _ := stallSettings(cfg.AppConfig)
+	claudeEnv, codexEnv := envPolicies(cfg.AppConfig)
+
 	// build claude executor with config values
 	claudeExec := &executor.ClaudeExecutor{
 		OutputHandler: func(text string) {
 			log.PrintAligned(text)
 		},
-		Debug: cfg.Debug,
+		ActivityHandler: func(activity executor.Activity) {
+			log.LogActivity(activity)
+		},
+		IdleTimeout: idleTimeout,
+		Env:         claudeEnv,
+		Debug:       cfg.Debug,
 	}
 	if cfg.AppConfig != nil {
 		claudeExec.Command = cfg.AppConfig.ClaudeCommand
 		claudeExec.Args = cfg.AppConfig.ClaudeArgs
+		claudeExec.Container = containerConfig(cfg.AppConfig, cfg.Repositories)
+	}
+	if c := claudeExec.Container; c != nil {
+		log.Print("claude runs in %s container %s, network: %s", c.Runtime, c.Image, cmp.Or(c.Network, "default"))
 	}
 
 	// build codex executor with config values
@@ -100,7 +154,9 @@ func New(cfg Config, log Logger) *Runner {
 		OutputHandler: func(text string) {
 			log.PrintAligned(text)
 		},
-		Debug: cfg.Debug,
+		IdleTimeout: idleTimeout,
+		Env:         codexEnv,
+		Debug:       cfg.Debug,
 	}
 	if cfg.AppConfig != nil {
 		codexExec.Command = cfg.AppConfig.CodexCommand
@@ -110,8 +166,54 @@ func New(cfg Config, log Logger) *Runner {
 		codexExec.Sandbox = cfg.AppConfig.CodexSandbox
 	}
 
+	// configured external reviewers replace the single codex reviewer
+	var reviewers []Reviewer
+	if cfg.CodexEnabled && cfg.AppConfig != nil && len(cfg.AppConfig.ExternalReviewers) > 0 {
+		reviewers = newReviewers(cfg.AppConfig, cfg.Repositories, cfg.Debug, log)
+		if len(reviewers) == 0 {
+			log.Print("warning: no external reviewers available, disabling codex review phase")
+			cfg.CodexEnabled = false
+		}
+	}
+
+	// review agent processes run in parallel, their output is logged once each agent completes.
+	// each agent gets its own claude process with the model and tools of its frontmatter
+	var claudeRunner, codexRunner Executor = claudeExec, codexExec
+	agentRunner := func(agent config.CustomAgent) Executor {
+		return &executor.ClaudeExecutor{Command: claudeExec.Command, Args: claudeExec.Args, Model: agent.Model,
+			AllowedTools: agent.Tools, IdleTimeout: idleTimeout, Container: claudeExec.Container, Env: claudeEnv,
+			Debug: cfg.Debug}
+	}
+
+	// command executors configured via claude_executor / codex_executor replace the builtin ones
+	if cfg.AppConfig != nil && cfg.AppConfig.ClaudeExecutor != "" {
+		if cmdExec, err := newCommandExecutor(cfg.AppConfig, cfg.AppConfig.ClaudeExecutor, claudeEnv, log.PrintAligned,
+			cfg.Debug); err != nil {
+			log.Print("warning: claude_executor %v, using claude", err)
+		} else {
+			claudeRunner = cmdExec
+			cmdAgent := &executor.CommandExecutor{Command: cmdExec.Command, IdleTimeout: cmdExec.IdleTimeout,
+				Env: cmdExec.Env, Debug: cfg.Debug}
+			agentRunner = func(agent config.CustomAgent) Executor {
+				if agent.Model != "" || len(agent.Tools) > 0 {
+					log.Print("[WARN] agent %q: model and tools options are not applied to command executors", agent.Name)
+				}
+				return cmdAgent
+			}
+		}
+	}
+	codexIsCommand := false
+	if cfg.AppConfig != nil && cfg.AppConfig.CodexExecutor != "" {
+		if cmdExec, err := newCommandExecutor(cfg.AppConfig, cfg.AppConfig.CodexExecutor, codexEnv, log.PrintAligned,
+			cfg.Debug); err != nil {
+			log.Print("warning: codex_executor %v, using codex", err)
+		} else {
+			codexRunner, codexIsCommand = cmdExec, true
+		}
+	}
+
 	// auto-disable codex if the binary is not installed
-	if cfg.CodexEnabled {
+	if cfg.CodexEnabled && len(reviewers) == 0 && !codexIsCommand {
 		codexCmd := codexExec.Command
 		if codexCmd == "" {
 			codexCmd = "codex"
@@ -122,7 +224,27 @@ func New(cfg Config, log Logger) *Runner {
 		}
 	}
 
-	return NewWithExecutors(cfg, log, claudeExec, codexExec)
+	r := NewWithExecutors(cfg, log, claudeRunner, codexRunner)
+	r.agents = agentRunner
+	r.reviewers = reviewers
+	return r
+}
+
+// containerConfig returns the container sandbox for claude, nil when container_runtime is not set.
+// the additional repositories of a multi-repo plan are mounted next to the working directory.
+func containerConfig(appCfg *config.Config, repos []string) *executor.ContainerConfig {
+	if appCfg.ContainerRuntime == "" {
+		return nil
+	}
+	return &executor.ContainerConfig{
+		Runtime: appCfg.ContainerRuntime,
+		Image:   appCfg.ContainerImage,
+		Network: appCfg.ContainerNetwork,
+		Env:     appCfg.ContainerEnv,
+		Mounts:  appCfg.ContainerMounts,
+		Args:    appCfg.ContainerArgs,
+		Repos:   repos,
+	}
 }
 
 // NewWithExecutors creates a new Runner with custom executors (for testing).
@@ -142,13 +264,18 @@ func NewWithExecutors(cfg Config, log Logger, claude, codex Executor) *Runner {
 		retryCount = cfg.TaskRetryCount
 	}
 
+	_, stallRetries := stallSettings(cfg.AppConfig)
+
 	return &Runner{
 		cfg:            cfg,
 		log:            log,
 		claude:         claude,
 		codex:          codex,
+		agents:         func(config.CustomAgent) Executor { return claude },
 		iterationDelay: iterDelay,
 		taskRetryCount: retryCount,
+		limits:         newLimitPolicy(cfg.AppConfig),
+		stallRetries:   stallRetries,
 	}
 }
 
@@ -157,8 +284,68 @@ func (r *Runner) SetInputCollector(c InputCollector) {
 	r.inputCollector = c
 }
 
+// SetAgentExecutor sets the executor used for all review agent processes.
+// it must be safe for concurrent use, agents run in parallel.
+func (r *Runner) SetAgentExecutor(e Executor) {
+	r.agents = func(config.CustomAgent) Executor { return e }
+}
+
+// SetReviewers sets the reviewers run in parallel in the external review phase.
+// with no reviewers the codex executor runs alone.
+func (r *Runner) SetReviewers(reviewers []Reviewer) {
+	r.reviewers = reviewers
+}
+
+// SetGitCommitter sets the git committer used when auto_commit is enabled.
+func (r *Runner) SetGitCommitter(g GitCommitter) {
+	r.git = g
+}
+
+// SetGitRollbacker sets the git rollbacker used when task_rollback is enabled.
+func (r *Runner) SetGitRollbacker(g GitRollbacker) {
+	r.rollback = g
+}
+
+// SetGitHeadReader sets the reader used to record the commit range of each task and review iteration.
+func (r *Runner) SetGitHeadReader(g GitHeadReader) {
+	r.head = g
+}
+
+// WrapExecutors replaces the claude, codex, review agent and external reviewer executors with
+// the executors wrap returns for them, e.g. to trace their calls. name is "claude", "codex",
+// "agent" or the reviewer name.
+func (r *Runner) WrapExecutors(wrap func(name string, e Executor) Executor) {
+	r.claude = wrap("claude", r.claude)
+	r.codex = wrap("codex", r.codex)
+	agents := r.agents
+	r.agents = func(agent config.CustomAgent) Executor { return wrap("agent", agents(agent)) }
+	for i := range r.reviewers {
+		r.reviewers[i].Exec = wrap(r.reviewers[i].Name, r.reviewers[i].Exec)
+	}
+}
+
 // Run executes the main loop based on configured mode.
-func (r *Runner) Run(ctx context.Context) error {
+// pre_run and post_run hooks wrap the mode execution, on_failure runs if anything fails.
+// the returned outcome describes how the run ended and is set for failed runs too.
+func (r *Runner) Run(ctx context.Context) (Outcome, error) {
+	start := time.Now()
+	r.outcome = Outcome{}
+	r.iterations = 0
+	err := r.runHook(ctx, HookPreRun, hookContext{})
+	if err == nil {
+		err = r.runMode(ctx)
+	}
+	if err == nil {
+		err = r.runHook(ctx, HookPostRun, hookContext{})
+	}
+	if err != nil {
+		r.runFailureHook(ctx, err)
+	}
+	return r.outcome.finish(ctx, err, time.Since(start)), err
+}
+
+// runMode dispatches to the configured execution mode.
+func (r *Runner) runMode(ctx context.Context) error {
 	switch r.cfg.Mode {
 	case ModeFull:
 		return r.runFull(ctx)
@@ -180,39 +367,18 @@ func (r *Runner) runFull(ctx context.Context) error {
 	}
 
 	// phase 1: task execution
-	r.log.SetPhase(PhaseTask)
+	r.setPhase(PhaseTask)
 	r.log.PrintRaw("starting task execution phase\n")
 
 	if err := r.runTaskPhase(ctx); err != nil {
 		return fmt.Errorf("task phase: %w", err)
 	}
-
-	// phase 2: first review pass - address ALL findings
-	r.log.SetPhase(PhaseReview)
-	r.log.PrintSection(NewGenericSection("claude review 0: all findings"))
-
-	if err := r.runClaudeReview(ctx, r.buildFirstReviewPrompt()); err != nil {
-		return fmt.Errorf("first review: %w", err)
-	}
-
-	// phase 2.1: claude review loop (critical/major) before codex
-	if err := r.runClaudeReviewLoop(ctx); err != nil {
-		return fmt.Errorf("pre-codex review loop: %w", err)
-	}
-
-	// phase 2.5: codex external review loop
-	r.log.SetPhase(PhaseCodex)
-	r.log.PrintSection(NewGenericSection("codex external review"))
-
-	if err := r.runCodexLoop(ctx); err != nil {
-		return fmt.Errorf("codex loop: %w", err)
+	if err := r.runHook(ctx, HookPostPhase, hookContext{phase: PhaseTask}); err != nil {
+		return err
 	}
 
-	// phase 3: claude review loop (critical/major) after codex
-	r.log.SetPhase(PhaseReview)
-
-	if err := r.runClaudeReviewLoop(ctx); err != nil {
-		return fmt.Errorf("post-codex review loop: %w", err)
+	if err := r.runReviewPipeline(ctx); err != nil {
+		return err
 	}
 
 	r.log.Print("all phases completed successfully")
@@ -221,57 +387,82 @@ func (r *Runner) runFull(ctx context.Context) error {
 
 // runReviewOnly executes only the review pipeline: review → codex → review.
 func (r *Runner) runReviewOnly(ctx context.Context) error {
-	// phase 1: first review
-	r.log.SetPhase(PhaseReview)
-	r.log.PrintSection(NewGenericSection("claude review 0: all findings"))
+	if err := r.runReviewPipeline(ctx); err != nil {
+		return err
+	}
+
+	r.log.Print("review phases completed successfully")
+	return nil
+}
+
+// runReviewPipeline executes first review, pre-codex review loop, codex loop and post-codex review loop.
+func (r *Runner) runReviewPipeline(ctx context.Context) error {
+	// first review pass - address ALL findings
+	r.setPhase(PhaseReview)
+	if err := r.runHook(ctx, HookPreReview, hookContext{phase: PhaseReview}); err != nil {
+		return err
+	}
+	section := NewGenericSection("claude review 0: all findings")
+	r.log.PrintSection(section)
+	r.outcome.ReviewIterations++
 
-	if err := r.runClaudeReview(ctx, r.buildFirstReviewPrompt()); err != nil {
+	logCommits := r.trackCommits(section.Label)
+	if err := r.runFirstReview(ctx); err != nil {
 		return fmt.Errorf("first review: %w", err)
 	}
+	logCommits()
 
-	// phase 1.1: claude review loop (critical/major) before codex
+	// claude review loop (critical/major) before codex
 	if err := r.runClaudeReviewLoop(ctx); err != nil {
 		return fmt.Errorf("pre-codex review loop: %w", err)
 	}
-
-	// phase 2: codex external review loop
-	r.log.SetPhase(PhaseCodex)
-	r.log.PrintSection(NewGenericSection("codex external review"))
-
-	if err := r.runCodexLoop(ctx); err != nil {
-		return fmt.Errorf("codex loop: %w", err)
+	if err := r.runHook(ctx, HookPostPhase, hookContext{phase: PhaseReview}); err != nil {
+		return err
 	}
 
-	// phase 3: claude review loop (critical/major) after codex
-	r.log.SetPhase(PhaseReview)
+	return r.runCodexPipeline(ctx)
+}
 
-	if err := r.runClaudeReviewLoop(ctx); err != nil {
-		return fmt.Errorf("post-codex review loop: %w", err)
+// runCodexOnly executes only the codex pipeline: codex → review.
+func (r *Runner) runCodexOnly(ctx context.Context) error {
+	if err := r.runCodexPipeline(ctx); err != nil {
+		return err
 	}
 
-	r.log.Print("review phases completed successfully")
+	r.log.Print("codex phases completed successfully")
 	return nil
 }
 
-// runCodexOnly executes only the codex pipeline: codex → review.
-func (r *Runner) runCodexOnly(ctx context.Context) error {
-	// phase 1: codex external review loop
-	r.log.SetPhase(PhaseCodex)
-	r.log.PrintSection(NewGenericSection("codex external review"))
+// runCodexPipeline executes codex external review loop followed by claude review loop.
+func (r *Runner) runCodexPipeline(ctx context.Context) error {
+	// codex external review loop
+	r.setPhase(PhaseCodex)
+	section := NewGenericSection("codex external review")
+	r.log.PrintSection(section)
 
+	logCommits := r.trackCommits(section.Label)
 	if err := r.runCodexLoop(ctx); err != nil {
 		return fmt.Errorf("codex loop: %w", err)
 	}
+	// codex re-reviews uncommitted changes between iterations, so fixes are committed once at the end
+	if err := r.commitReview(PhaseCodex, 0); err != nil {
+		return err
+	}
+	logCommits()
+	if err := r.runHook(ctx, HookPostPhase, hookContext{phase: PhaseCodex}); err != nil {
+		return err
+	}
 
-	// phase 2: claude review loop (critical/major) after codex
-	r.log.SetPhase(PhaseReview)
+	// claude review loop (critical/major) after codex
+	r.setPhase(PhaseReview)
+	if err := r.runHook(ctx, HookPreReview, hookContext{phase: PhaseReview}); err != nil {
+		return err
+	}
 
 	if err := r.runClaudeReviewLoop(ctx); err != nil {
 		return fmt.Errorf("post-codex review loop: %w", err)
 	}
-
-	r.log.Print("codex phases completed successfully")
-	return nil
+	return r.runHook(ctx, HookPostPhase, hookContext{phase: PhaseReview})
 }
 
 // runTaskPhase executes tasks until completion or max iterations.
@@ -287,12 +478,32 @@ func (r *Runner) runTaskPhase(ctx context.Context) error {
 		default:
 		}
 
-		r.log.PrintSection(NewTaskIterationSection(i))
+		section := NewTaskIterationSection(i)
+		r.log.PrintSection(section)
+		r.outcome.TaskIterations++
 
-		result := r.claude.Run(ctx, prompt)
+		logCommits := r.trackCommits(section.Label)
+		if err := r.runHook(ctx, HookPreTask, hookContext{phase: PhaseTask, task: i}); err != nil {
+			return err
+		}
+		snapshot := r.snapshotTask() // after the hook, its changes are part of the starting state
+
+		task := r.activeTask(i) // resolve before the run, claude checks the task off while working
+		result := r.runExec(ctx, r.claude, prompt)
 		if result.Error != nil {
-			return fmt.Errorf("claude execution: %w", result.Error)
+			return &ExecutorError{Executor: "claude", Err: result.Error}
+		}
+
+		if err := r.runHook(ctx, HookPostTask, hookContext{phase: PhaseTask, task: i}); err != nil {
+			return err
+		}
+
+		if result.Signal != SignalFailed {
+			if err := r.commitTask(i, task); err != nil {
+				return err
+			}
 		}
+		logCommits()
 
 		if result.Signal == SignalCompleted {
 			// verify plan actually has no uncompleted checkboxes
@@ -306,6 +517,7 @@ func (r *Runner) runTaskPhase(ctx context.Context) error {
 
 		if result.Signal == SignalFailed {
 			if retryCount < r.taskRetryCount {
+				r.rollbackTask(snapshot, i)
 				r.log.Print("task failed, retrying...")
 				retryCount++
 				time.Sleep(r.iterationDelay)
@@ -319,14 +531,14 @@ func (r *Runner) runTaskPhase(ctx context.Context) error {
 		time.Sleep(r.iterationDelay)
 	}
 
-	return fmt.Errorf("max iterations (%d) reached without completion", r.cfg.MaxIterations)
+	return fmt.Errorf("max iterations (%d) %w", r.cfg.MaxIterations, ErrMaxIterations)
 }
 
 // runClaudeReview runs Claude review with the given prompt until REVIEW_DONE.
 func (r *Runner) runClaudeReview(ctx context.Context, prompt string) error {
-	result := r.claude.Run(ctx, prompt)
+	result := r.runExec(ctx, r.claude, prompt)
 	if result.Error != nil {
-		return fmt.Errorf("claude execution: %w", result.Error)
+		return &ExecutorError{Executor: "claude", Err: result.Error}
 	}
 
 	if result.Signal == SignalFailed {
@@ -337,13 +549,14 @@ func (r *Runner) runClaudeReview(ctx context.Context, prompt string) error {
 		r.log.Print("warning: first review pass did not complete cleanly, continuing...")
 	}
 
-	return nil
+	return r.commitReview(PhaseReview, 0)
 }
 
 // runClaudeReviewLoop runs claude review iterations using second review prompt.
 func (r *Runner) runClaudeReviewLoop(ctx context.Context) error {
 	// review iterations = 10% of max_iterations (min 3)
 	maxReviewIterations := max(3, r.cfg.MaxIterations/10)
+	sess := &claudeSession{phase: sessionPhaseReview}
 
 	for i := 1; i <= maxReviewIterations; i++ {
 		select {
@@ -352,17 +565,25 @@ func (r *Runner) runClaudeReviewLoop(ctx context.Context) error {
 		default:
 		}
 
-		r.log.PrintSection(NewClaudeReviewSection(i, ": critical/major"))
+		section := NewClaudeReviewSection(i, ": critical/major")
+		r.log.PrintSection(section)
+		r.outcome.ReviewIterations++
+		logCommits := r.trackCommits(section.Label)
 
-		result := r.claude.Run(ctx, r.buildSecondReviewPrompt())
+		result := r.runClaudeSession(ctx, sess, r.buildSecondReviewPrompt())
 		if result.Error != nil {
-			return fmt.Errorf("claude execution: %w", result.Error)
+			return &ExecutorError{Executor: "claude", Err: result.Error}
 		}
 
 		if result.Signal == SignalFailed {
 			return errors.New("review failed (FAILED signal received)")
 		}
 
+		if err := r.commitReview(PhaseReview, i); err != nil {
+			return err
+		}
+		logCommits()
+
 		if IsReviewDone(result.Signal) {
 			r.log.Print("claude review complete - no more findings")
 			return nil
@@ -388,6 +609,7 @@ func (r *Runner) runCodexLoop(ctx context.Context) error {
 	maxCodexIterations := max(3, r.cfg.MaxIterations/5)
 
 	var claudeResponse string // first iteration has no prior response
+	sess := &claudeSession{phase: sessionPhaseCodex}
 
 	for i := 1; i <= maxCodexIterations; i++ {
 		select {
@@ -397,30 +619,31 @@ func (r *Runner) runCodexLoop(ctx context.Context) error {
 		}
 
 		r.log.PrintSection(NewCodexIterationSection(i))
+		r.outcome.CodexIterations++
 
-		// run codex analysis
-		codexResult := r.codex.Run(ctx, r.buildCodexPrompt(i == 1, claudeResponse))
-		if codexResult.Error != nil {
-			return fmt.Errorf("codex execution: %w", codexResult.Error)
+		// run codex analysis, or all external reviewers in parallel when configured
+		codexOutput, err := r.runExternalReview(ctx, r.buildCodexPrompt(i == 1, claudeResponse))
+		if err != nil {
+			return &ExecutorError{Executor: "codex", Err: err}
 		}
 
-		if codexResult.Output == "" {
+		if codexOutput == "" {
 			r.log.Print("codex review returned no output, skipping...")
 			break
 		}
 
 		// show codex findings summary before Claude evaluation
-		r.showCodexSummary(codexResult.Output)
+		r.showCodexSummary(codexOutput)
 
 		// pass codex output to claude for evaluation and fixing
-		r.log.SetPhase(PhaseClaudeEval)
+		r.setPhase(PhaseClaudeEval)
 		r.log.PrintSection(NewClaudeEvalSection())
-		claudeResult := r.claude.Run(ctx, r.buildCodexEvaluationPrompt(codexResult.Output))
+		claudeResult := r.runClaudeSession(ctx, sess, r.buildCodexEvaluationPrompt(codexOutput))
 
 		// restore codex phase for next iteration
-		r.log.SetPhase(PhaseCodex)
+		r.setPhase(PhaseCodex)
 		if claudeResult.Error != nil {
-			return fmt.Errorf("claude execution: %w", claudeResult.Error)
+			return &ExecutorError{Executor: "claude", Err: claudeResult.Error}
 		}
 
 		claudeResponse = claudeResult.Output
@@ -454,10 +677,10 @@ The code implements the plan at: %s
 	// different diff command based on iteration
 	var diffInstruction, diffDescription string
 	if isFirst {
-		diffInstruction = "Run: git diff master...HEAD"
+		diffInstruction = "Run: git diff master...HEAD" + r.scopePathspec() + r.repositoriesDiff("diff master...HEAD")
 		diffDescription = "code changes between master and HEAD branch"
 	} else {
-		diffInstruction = "Run: git diff"
+		diffInstruction = "Run: git diff" + r.scopePathspec() + r.repositoriesDiff("diff")
 		diffDescription = "uncommitted changes (Claude's fixes from previous iteration)"
 	}
 
@@ -493,15 +716,9 @@ If Claude's arguments are invalid, explain why the issues still exist.`, basePro
 // hasUncompletedTasks checks if plan file has any uncompleted checkboxes.
 // Checks both original path and completed/ subdirectory.
 func (r *Runner) hasUncompletedTasks() bool {
-	// try original path first
-	content, err := os.ReadFile(r.cfg.PlanFile)
+	content, err := r.readPlan()
 	if err != nil {
-		// try completed/ subdirectory as fallback
-		completedPath := filepath.Join(filepath.Dir(r.cfg.PlanFile), "completed", filepath.Base(r.cfg.PlanFile))
-		content, err = os.ReadFile(completedPath) //nolint:gosec // planFile from CLI args
-		if err != nil {
-			return true // assume incomplete if can't read from either location
-		}
+		return true // assume incomplete if can't read from either location
 	}
 
 	// look for uncompleted checkbox pattern: [ ] (not [x])
@@ -514,6 +731,18 @@ func (r *Runner) hasUncompletedTasks() bool {
 	return false
 }
 
+// readPlan reads the plan file, falling back to the completed/ subdirectory.
+func (r *Runner) readPlan() ([]byte, error) {
+	// try original path first
+	content, err := os.ReadFile(r.cfg.PlanFile)
+	if err == nil {
+		return content, nil
+	}
+	// try completed/ subdirectory as fallback
+	completedPath := filepath.Join(filepath.Dir(r.cfg.PlanFile), "completed", filepath.Base(r.cfg.PlanFile))
+	return os.ReadFile(completedPath) //nolint:gosec // planFile from CLI args
+}
+
 // showCodexSummary displays a condensed summary of codex output before Claude evaluation.
 // extracts text until first code block or 500 chars, whichever is shorter.
 func (r *Runner) showCodexSummary(output string) {
@@ -553,7 +782,7 @@ func (r *Runner) runPlanCreation(ctx context.Context) error {
 		return errors.New("input collector required for plan mode")
 	}
 
-	r.log.SetPhase(PhasePlan)
+	r.setPhase(PhasePlan)
 	r.log.PrintRaw("starting interactive plan creation\n")
 	r.log.Print("plan request: %s", r.cfg.PlanDescription)
 
@@ -568,11 +797,12 @@ func (r *Runner) runPlanCreation(ctx context.Context) error {
 		}
 
 		r.log.PrintSection(NewPlanIterationSection(i))
+		r.outcome.PlanIterations++
 
 		prompt := r.buildPlanPrompt()
-		result := r.claude.Run(ctx, prompt)
+		result := r.runExec(ctx, r.claude, prompt)
 		if result.Error != nil {
-			return fmt.Errorf("claude execution: %w", result.Error)
+			return &ExecutorError{Executor: "claude", Err: result.Error}
 		}
 
 		if result.Signal == SignalFailed {
@@ -611,5 +841,5 @@ func (r *Runner) runPlanCreation(ctx context.Context) error {
 		time.Sleep(r.iterationDelay)
 	}
 
-	return fmt.Errorf("max plan iterations (%d) reached without completion", maxPlanIterations)
+	return fmt.Errorf("max plan iterations (%d) %w", maxPlanIterations, ErrMaxIterations)
 }
@@ -51,6 +51,10 @@ func newMockLogger(path string) *mocks.LoggerMock {
 		PrintAlignedFunc: func(_ string) {},
 		LogQuestionFunc:  func(_ string, _ []string) {},
 		LogAnswerFunc:    func(_ string) {},
+		LogActivityFunc:  func(_ executor.Activity) {},
+		LogStallFunc:     func(_ time.Duration) {},
+		LogUsageFunc:     func(_ executor.Usage) {},
+		LogCommitsFunc:   func(_ processor.IterationCommits) {},
 		PathFunc:         func() string { return path },
 	}
 }
@@ -61,7 +65,7 @@ func TestRunner_Run_UnknownMode(t *testing.T) {
 	codex := newMockExecutor(nil)
 
 	r := processor.NewWithExecutors(processor.Config{Mode: "invalid"}, log, claude, codex)
-	err := r.Run(context.Background())
+	_, err := r.Run(context.Background())
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "unknown mode")
@@ -73,7 +77,7 @@ func TestRunner_RunFull_NoPlanFile(t *testing.T) {
 	codex := newMockExecutor(nil)
 
 	r := processor.NewWithExecutors(processor.Config{Mode: processor.ModeFull}, log, claude, codex)
-	err := r.Run(context.Background())
+	_, err := r.Run(context.Background())
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "plan file required")
@@ -99,10 +103,57 @@ func TestRunner_RunFull_Success(t *testing.T) {
 
 	cfg := processor.Config{Mode: processor.ModeFull, PlanFile: planFile, MaxIterations: 50, CodexEnabled: true, AppConfig: testAppConfig(t)}
 	r := processor.NewWithExecutors(cfg, log, claude, codex)
-	err := r.Run(context.Background())
+	outcome, err := r.Run(context.Background())
 
 	require.NoError(t, err)
 	assert.Len(t, codex.RunCalls(), 1)
+	assert.Equal(t, processor.OutcomeSuccess, outcome.Status)
+	assert.Equal(t, processor.PhaseReview, outcome.Phase)
+	assert.Empty(t, outcome.Error)
+	assert.Equal(t, 1, outcome.TaskIterations)
+	assert.Equal(t, 3, outcome.ReviewIterations, "first review and one iteration before and after codex")
+	assert.Equal(t, 1, outcome.CodexIterations)
+	assert.Positive(t, outcome.Duration)
+}
+
+// headSequence returns hashes in order, repeating the last one.
+type headSequence struct {
+	hashes []string
+	calls  int
+}
+
+func (h *headSequence) HeadHash() (string, error) {
+	hash := h.hashes[min(h.calls, len(h.hashes)-1)]
+	h.calls++
+	return hash, nil
+}
+
+func TestRunner_RunFull_LogsIterationCommits(t *testing.T) {
+	tmpDir := t.TempDir()
+	planFile := filepath.Join(tmpDir, "plan.md")
+	require.NoError(t, os.WriteFile(planFile, []byte("# Plan\n- [x] Task 1"), 0o600))
+
+	log := newMockLogger("progress.txt")
+	claude := newMockExecutor([]executor.Result{
+		{Output: "task done", Signal: processor.SignalCompleted},    // task phase commits
+		{Output: "review done", Signal: processor.SignalReviewDone}, // first review, nothing committed
+		{Output: "fixed", Signal: processor.SignalReviewDone},       // pre-codex review loop commits
+		{Output: "review done", Signal: processor.SignalReviewDone}, // post-codex review loop
+	})
+
+	a, b, c := strings.Repeat("a", 40), strings.Repeat("b", 40), strings.Repeat("c", 40)
+	cfg := processor.Config{Mode: processor.ModeFull, PlanFile: planFile, MaxIterations: 50, AppConfig: testAppConfig(t)}
+	r := processor.NewWithExecutors(cfg, log, claude, nil)
+	// HEAD is read before and after each iteration
+	r.SetGitHeadReader(&headSequence{hashes: []string{a, b, b, b, b, c}})
+	_, err := r.Run(context.Background())
+	require.NoError(t, err)
+
+	calls := log.LogCommitsCalls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, processor.IterationCommits{Iteration: 1, Label: "task iteration 1", From: a, To: b}, calls[0].Commits)
+	assert.Equal(t, processor.IterationCommits{Iteration: 3, Label: "claude review 1: critical/major", From: b, To: c},
+		calls[1].Commits)
 }
 
 func TestRunner_RunFull_NoCodexFindings(t *testing.T) {
@@ -123,7 +174,7 @@ func TestRunner_RunFull_NoCodexFindings(t *testing.T) {
 
 	cfg := processor.Config{Mode: processor.ModeFull, PlanFile: planFile, MaxIterations: 50, CodexEnabled: true, AppConfig: testAppConfig(t)}
 	r := processor.NewWithExecutors(cfg, log, claude, codex)
-	err := r.Run(context.Background())
+	_, err := r.Run(context.Background())
 
 	require.NoError(t, err)
 }
@@ -142,7 +193,7 @@ func TestRunner_RunReviewOnly_Success(t *testing.T) {
 
 	cfg := processor.Config{Mode: processor.ModeReview, MaxIterations: 50, CodexEnabled: true, AppConfig: testAppConfig(t)}
 	r := processor.NewWithExecutors(cfg, log, claude, codex)
-	err := r.Run(context.Background())
+	_, err := r.Run(context.Background())
 
 	require.NoError(t, err)
 	assert.Len(t, codex.RunCalls(), 1)
@@ -160,12 +211,38 @@ func TestRunner_RunCodexOnly_Success(t *testing.T) {
 
 	cfg := processor.Config{Mode: processor.ModeCodexOnly, MaxIterations: 50, CodexEnabled: true, AppConfig: testAppConfig(t)}
 	r := processor.NewWithExecutors(cfg, log, claude, codex)
-	err := r.Run(context.Background())
+	_, err := r.Run(context.Background())
 
 	require.NoError(t, err)
 	assert.Len(t, codex.RunCalls(), 1)
 }
 
+func TestRunner_WrapExecutors(t *testing.T) {
+	log := newMockLogger("progress.txt")
+	claude := newMockExecutor([]executor.Result{
+		{Output: "done", Signal: processor.SignalCodexDone},         // codex evaluation
+		{Output: "review done", Signal: processor.SignalReviewDone}, // post-codex review loop
+	})
+	codex := newMockExecutor([]executor.Result{{Output: "found issue"}})
+
+	cfg := processor.Config{Mode: processor.ModeCodexOnly, MaxIterations: 50, CodexEnabled: true, AppConfig: testAppConfig(t)}
+	r := processor.NewWithExecutors(cfg, log, claude, codex)
+	r.SetReviewers([]processor.Reviewer{{Name: "gemini", Exec: codex}})
+	var wrapped, calls []string
+	r.WrapExecutors(func(name string, e processor.Executor) processor.Executor {
+		wrapped = append(wrapped, name)
+		return &mocks.ExecutorMock{RunFunc: func(ctx context.Context, prompt string) executor.Result {
+			calls = append(calls, name)
+			return e.Run(ctx, prompt)
+		}}
+	})
+	_, err := r.Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"claude", "codex", "gemini"}, wrapped, "agent executors are wrapped as review agents start")
+	assert.Equal(t, []string{"gemini", "claude", "claude"}, calls, "reviewers replace codex in the external review")
+}
+
 func TestRunner_RunCodexOnly_NoFindings(t *testing.T) {
 	log := newMockLogger("progress.txt")
 	claude := newMockExecutor([]executor.Result{
@@ -177,7 +254,7 @@ func TestRunner_RunCodexOnly_NoFindings(t *testing.T) {
 
 	cfg := processor.Config{Mode: processor.ModeCodexOnly, MaxIterations: 50, CodexEnabled: true, AppConfig: testAppConfig(t)}
 	r := processor.NewWithExecutors(cfg, log, claude, codex)
-	err := r.Run(context.Background())
+	_, err := r.Run(context.Background())
 
 	require.NoError(t, err)
 }
@@ -191,7 +268,7 @@ func TestRunner_CodexDisabled_SkipsCodexPhase(t *testing.T) {
 
 	cfg := processor.Config{Mode: processor.ModeCodexOnly, MaxIterations: 50, CodexEnabled: false, AppConfig: testAppConfig(t)}
 	r := processor.NewWithExecutors(cfg, log, claude, codex)
-	err := r.Run(context.Background())
+	_, err := r.Run(context.Background())
 
 	require.NoError(t, err)
 	assert.Empty(t, codex.RunCalls(), "codex should not be called when disabled")
@@ -211,7 +288,7 @@ func TestRunner_TaskPhase_FailedSignal(t *testing.T) {
 
 	cfg := processor.Config{Mode: processor.ModeFull, PlanFile: planFile, MaxIterations: 10, AppConfig: testAppConfig(t)}
 	r := processor.NewWithExecutors(cfg, log, claude, codex)
-	err := r.Run(context.Background())
+	_, err := r.Run(context.Background())
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "FAILED signal")
@@ -232,10 +309,15 @@ func TestRunner_TaskPhase_MaxIterations(t *testing.T) {
 
 	cfg := processor.Config{Mode: processor.ModeFull, PlanFile: planFile, MaxIterations: 3, AppConfig: testAppConfig(t)}
 	r := processor.NewWithExecutors(cfg, log, claude, codex)
-	err := r.Run(context.Background())
+	outcome, err := r.Run(context.Background())
 
 	require.Error(t, err)
-	assert.Contains(t, err.Error(), "max iterations")
+	assert.Contains(t, err.Error(), "max iterations (3) reached without completion")
+	require.ErrorIs(t, err, processor.ErrMaxIterations)
+	assert.Equal(t, processor.OutcomeMaxIterations, outcome.Status)
+	assert.Equal(t, processor.PhaseTask, outcome.Phase)
+	assert.Equal(t, 3, outcome.TaskIterations)
+	assert.Equal(t, err.Error(), outcome.Error)
 }
 
 func TestRunner_TaskPhase_ContextCanceled(t *testing.T) {
@@ -252,10 +334,11 @@ func TestRunner_TaskPhase_ContextCanceled(t *testing.T) {
 
 	cfg := processor.Config{Mode: processor.ModeFull, PlanFile: planFile, MaxIterations: 10, AppConfig: testAppConfig(t)}
 	r := processor.NewWithExecutors(cfg, log, claude, codex)
-	err := r.Run(ctx)
+	outcome, err := r.Run(ctx)
 
 	require.Error(t, err)
-	assert.ErrorIs(t, err, context.Canceled)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, processor.OutcomeAborted, outcome.Status)
 }
 
 func TestRunner_ClaudeReview_FailedSignal(t *testing.T) {
@@ -267,10 +350,13 @@ func TestRunner_ClaudeReview_FailedSignal(t *testing.T) {
 
 	cfg := processor.Config{Mode: processor.ModeReview, MaxIterations: 50, AppConfig: testAppConfig(t)}
 	r := processor.NewWithExecutors(cfg, log, claude, codex)
-	err := r.Run(context.Background())
+	outcome, err := r.Run(context.Background())
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "FAILED signal")
+	assert.Equal(t, processor.OutcomeFailed, outcome.Status)
+	assert.Equal(t, processor.PhaseReview, outcome.Phase)
+	assert.Empty(t, outcome.Executor, "a FAILED signal is not an executor error")
 }
 
 func TestRunner_CodexPhase_Error(t *testing.T) {
@@ -285,11 +371,12 @@ func TestRunner_CodexPhase_Error(t *testing.T) {
 
 	cfg := processor.Config{Mode: processor.ModeReview, MaxIterations: 50, CodexEnabled: true, AppConfig: testAppConfig(t)}
 	r := processor.NewWithExecutors(cfg, log, claude, codex)
-	err := r.Run(context.Background())
+	outcome, err := r.Run(context.Background())
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "codex")
 	assert.Len(t, codex.RunCalls(), 1, "codex should be called once")
+	assert.Equal(t, "codex", outcome.Executor)
 }
 
 func TestRunner_ClaudeExecution_Error(t *testing.T) {
@@ -305,10 +392,15 @@ func TestRunner_ClaudeExecution_Error(t *testing.T) {
 
 	cfg := processor.Config{Mode: processor.ModeFull, PlanFile: planFile, MaxIterations: 10, AppConfig: testAppConfig(t)}
 	r := processor.NewWithExecutors(cfg, log, claude, codex)
-	err := r.Run(context.Background())
+	outcome, err := r.Run(context.Background())
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "claude execution")
+	var execErr *processor.ExecutorError
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, "claude", execErr.Executor)
+	assert.Equal(t, processor.OutcomeFailed, outcome.Status)
+	assert.Equal(t, "claude", outcome.Executor)
 }
 
 func TestRunner_ConfigValues(t *testing.T) {
@@ -432,7 +524,7 @@ func TestRunner_TaskRetryCount_UsedCorrectly(t *testing.T) {
 		AppConfig:        testAppConfig(t),
 	}
 	r := processor.NewWithExecutors(cfg, log, claude, codex)
-	err := r.Run(context.Background())
+	_, err := r.Run(context.Background())
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "FAILED signal")
@@ -472,7 +564,7 @@ func TestRunner_RunPlan_Success(t *testing.T) {
 	}
 	r := processor.NewWithExecutors(cfg, log, claude, codex)
 	r.SetInputCollector(inputCollector)
-	err := r.Run(context.Background())
+	_, err := r.Run(context.Background())
 
 	require.NoError(t, err)
 	assert.Len(t, claude.RunCalls(), 1)
@@ -502,7 +594,7 @@ func TestRunner_RunPlan_WithQuestion(t *testing.T) {
 	}
 	r := processor.NewWithExecutors(cfg, log, claude, codex)
 	r.SetInputCollector(inputCollector)
-	err := r.Run(context.Background())
+	_, err := r.Run(context.Background())
 
 	require.NoError(t, err)
 	assert.Len(t, claude.RunCalls(), 2)
@@ -520,7 +612,7 @@ func TestRunner_RunPlan_NoPlanDescription(t *testing.T) {
 	cfg := processor.Config{Mode: processor.ModePlan, AppConfig: testAppConfig(t)}
 	r := processor.NewWithExecutors(cfg, log, claude, codex)
 	r.SetInputCollector(inputCollector)
-	err := r.Run(context.Background())
+	_, err := r.Run(context.Background())
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "plan description required")
@@ -534,7 +626,7 @@ func TestRunner_RunPlan_NoInputCollector(t *testing.T) {
 	cfg := processor.Config{Mode: processor.ModePlan, PlanDescription: "test", AppConfig: testAppConfig(t)}
 	r := processor.NewWithExecutors(cfg, log, claude, codex)
 	// don't set input collector
-	err := r.Run(context.Background())
+	_, err := r.Run(context.Background())
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "input collector required")
@@ -557,7 +649,7 @@ func TestRunner_RunPlan_FailedSignal(t *testing.T) {
 	}
 	r := processor.NewWithExecutors(cfg, log, claude, codex)
 	r.SetInputCollector(inputCollector)
-	err := r.Run(context.Background())
+	_, err := r.Run(context.Background())
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "FAILED signal")
@@ -585,7 +677,7 @@ func TestRunner_RunPlan_MaxIterations(t *testing.T) {
 	}
 	r := processor.NewWithExecutors(cfg, log, claude, codex)
 	r.SetInputCollector(inputCollector)
-	err := r.Run(context.Background())
+	_, err := r.Run(context.Background())
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "max plan iterations")
@@ -609,7 +701,7 @@ func TestRunner_RunPlan_ContextCanceled(t *testing.T) {
 	}
 	r := processor.NewWithExecutors(cfg, log, claude, codex)
 	r.SetInputCollector(inputCollector)
-	err := r.Run(ctx)
+	_, err := r.Run(ctx)
 
 	require.Error(t, err)
 	assert.ErrorIs(t, err, context.Canceled)
@@ -632,7 +724,7 @@ func TestRunner_RunPlan_ClaudeError(t *testing.T) {
 	}
 	r := processor.NewWithExecutors(cfg, log, claude, codex)
 	r.SetInputCollector(inputCollector)
-	err := r.Run(context.Background())
+	_, err := r.Run(context.Background())
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "claude execution")
@@ -663,7 +755,7 @@ func TestRunner_RunPlan_InputCollectorError(t *testing.T) {
 	}
 	r := processor.NewWithExecutors(cfg, log, claude, codex)
 	r.SetInputCollector(inputCollector)
-	err := r.Run(context.Background())
+	_, err := r.Run(context.Background())
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "collect answer")
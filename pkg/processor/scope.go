@@ -0,0 +1,50 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// scopeNote is appended to claude prompts of a scoped run, %[1]s lists the scope directories
+// and %[2]s is the git pathspec limiting diffs to them.
+const scopeNote = `
+
+SCOPE: this run is limited to these directories, relative to the repository root:
+%[1]s
+Only change files inside them unless the plan explicitly says otherwise. Limit git diff and git log to them
+by appending the pathspec, e.g. git diff master...HEAD -- %[2]s
+Run the plan's validation commands from inside the scoped directory, e.g. cd "$(git rev-parse --show-toplevel)/<dir>" first.`
+
+// getScopeRef returns the scope directories for the {{SCOPE}} prompt variable.
+func (r *Runner) getScopeRef() string {
+	if len(r.cfg.Scope) == 0 {
+		return "(whole repository)"
+	}
+	return strings.Join(r.cfg.Scope, ", ")
+}
+
+// scopePathspec returns the git pathspec of the scope directories, prefixed with " -- ".
+// paths use the ":/" magic so they are relative to the repository root from any directory.
+// empty for unscoped runs.
+func (r *Runner) scopePathspec() string {
+	if len(r.cfg.Scope) == 0 {
+		return ""
+	}
+	specs := make([]string, 0, len(r.cfg.Scope))
+	for _, dir := range r.cfg.Scope {
+		specs = append(specs, ":/"+dir)
+	}
+	return " -- " + strings.Join(specs, " ")
+}
+
+// withScopeNote appends the scope directories and instructions to a prompt for scoped runs.
+func (r *Runner) withScopeNote(prompt string) string {
+	if len(r.cfg.Scope) == 0 {
+		return prompt
+	}
+	list := make([]string, 0, len(r.cfg.Scope))
+	for _, dir := range r.cfg.Scope {
+		list = append(list, "- "+dir)
+	}
+	return prompt + fmt.Sprintf(scopeNote, strings.Join(list, "\n"), strings.TrimPrefix(r.scopePathspec(), " -- "))
+}
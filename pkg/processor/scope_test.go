@@ -0,0 +1,35 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunner_withScopeNote(t *testing.T) {
+	t.Run("unscoped", func(t *testing.T) {
+		r := &Runner{cfg: Config{PlanFile: "docs/plans/test.md", AppConfig: testAppConfig(t)}, log: newMockLogger("")}
+		assert.NotContains(t, r.buildTaskPrompt(), "SCOPE:")
+		assert.Contains(t, r.buildCodexPrompt(true, ""), "Run: git diff master...HEAD\n")
+		assert.Equal(t, "scope: (whole repository)", r.replacePromptVariables("scope: {{SCOPE}}", ""))
+	})
+
+	t.Run("scoped", func(t *testing.T) {
+		r := &Runner{cfg: Config{PlanFile: "docs/plans/test.md", AppConfig: testAppConfig(t),
+			Scope: []string{"services/api", "libs/common"}}, log: newMockLogger("")}
+
+		for name, prompt := range map[string]string{
+			"task":          r.buildTaskPrompt(),
+			"second review": r.buildSecondReviewPrompt(),
+			"codex eval":    r.buildCodexEvaluationPrompt("findings"),
+		} {
+			assert.Contains(t, prompt, "SCOPE: this run is limited", name)
+			assert.Contains(t, prompt, "\n- services/api\n- libs/common\n", name)
+			assert.Contains(t, prompt, "git diff master...HEAD -- :/services/api :/libs/common\n", name)
+		}
+		assert.Contains(t, r.replacePromptVariables("scope: {{SCOPE}}", ""), "scope: services/api, libs/common")
+
+		assert.Contains(t, r.buildCodexPrompt(true, ""), "Run: git diff master...HEAD -- :/services/api :/libs/common\n")
+		assert.Contains(t, r.buildCodexPrompt(false, ""), "Run: git diff -- :/services/api :/libs/common\n")
+	})
+}
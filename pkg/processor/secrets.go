@@ -0,0 +1,155 @@
+package processor
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/umputun/ralphex/pkg/config"
+	"github.com/umputun/ralphex/pkg/executor"
+)
+
+// secretNameRe matches names of environment variables whose values are treated as secrets.
+var secretNameRe = regexp.MustCompile(`(?i)KEY|TOKEN|SECRET|PASSWORD|PASSWD|CREDENTIAL|AUTH`)
+
+const (
+	secretMask      = "***"
+	minSecretLength = 6 // shorter values are too likely to appear in regular output
+)
+
+// envPolicies builds the claude and codex environment policies from app config.
+// env_file variables are added first, so per-executor extra variables override them.
+func envPolicies(appCfg *config.Config) (claude, codex executor.EnvPolicy) {
+	if appCfg == nil {
+		return claude, codex
+	}
+	claude = executor.EnvPolicy{Allow: appCfg.ClaudeEnvAllow, Deny: appCfg.ClaudeEnvDeny,
+		Extra: slices.Concat(appCfg.EnvFileVars, appCfg.ClaudeEnv)}
+	codex = executor.EnvPolicy{Allow: appCfg.CodexEnvAllow, Deny: appCfg.CodexEnvDeny,
+		Extra: slices.Concat(appCfg.EnvFileVars, appCfg.CodexEnv)}
+	return claude, codex
+}
+
+// SecretMasker replaces secret values in text with a mask.
+type SecretMasker struct {
+	replacer *strings.Replacer
+}
+
+// NewSecretMasker collects secrets from env and the executor environment settings of appCfg:
+// values of variables with secret-looking names or listed in mask_env, and all env_file values.
+func NewSecretMasker(appCfg *config.Config, env []string) *SecretMasker {
+	var maskNames, extra, fileVars []string
+	if appCfg != nil {
+		maskNames, fileVars = appCfg.MaskEnv, appCfg.EnvFileVars
+		extra = slices.Concat(appCfg.ClaudeEnv, appCfg.CodexEnv)
+	}
+
+	var secrets []string
+	add := func(value string) {
+		if len(value) >= minSecretLength && !slices.Contains(secrets, value) {
+			secrets = append(secrets, value)
+		}
+	}
+	for _, kv := range slices.Concat(env, extra) {
+		name, value, _ := strings.Cut(kv, "=")
+		if secretNameRe.MatchString(name) || slices.Contains(maskNames, name) {
+			add(value)
+		}
+	}
+	for _, kv := range fileVars {
+		_, value, _ := strings.Cut(kv, "=")
+		add(value)
+	}
+	if len(secrets) == 0 {
+		return &SecretMasker{}
+	}
+
+	// longer secrets first, so a secret containing another one is masked as a whole
+	slices.SortFunc(secrets, func(a, b string) int { return cmp.Compare(len(b), len(a)) })
+	pairs := make([]string, 0, 2*len(secrets))
+	for _, s := range secrets {
+		pairs = append(pairs, s, secretMask)
+	}
+	return &SecretMasker{replacer: strings.NewReplacer(pairs...)}
+}
+
+// Mask returns text with all known secret values replaced.
+func (m *SecretMasker) Mask(text string) string {
+	if m == nil || m.replacer == nil {
+		return text
+	}
+	return m.replacer.Replace(text)
+}
+
+// MaskOutcome returns o with secrets masked in its error message.
+func (m *SecretMasker) MaskOutcome(o Outcome) Outcome {
+	o.Error = m.Mask(o.Error)
+	return o
+}
+
+// MaskingLogger wraps a Logger and redacts secrets from all text before passing it on,
+// so they reach neither the console, the progress file nor the web dashboard.
+type MaskingLogger struct {
+	inner  Logger
+	masker *SecretMasker
+}
+
+// NewMaskingLogger creates a logger masking secrets known to masker.
+func NewMaskingLogger(inner Logger, masker *SecretMasker) *MaskingLogger {
+	return &MaskingLogger{inner: inner, masker: masker}
+}
+
+// SetPhase forwards to the inner logger.
+func (l *MaskingLogger) SetPhase(phase Phase) { l.inner.SetPhase(phase) }
+
+// Print formats and masks the message, then forwards it.
+func (l *MaskingLogger) Print(format string, args ...any) {
+	l.inner.Print("%s", l.masker.Mask(fmt.Sprintf(format, args...)))
+}
+
+// PrintRaw formats and masks the message, then forwards it.
+func (l *MaskingLogger) PrintRaw(format string, args ...any) {
+	l.inner.PrintRaw("%s", l.masker.Mask(fmt.Sprintf(format, args...)))
+}
+
+// PrintSection forwards the section with a masked label.
+func (l *MaskingLogger) PrintSection(section Section) {
+	section.Label = l.masker.Mask(section.Label)
+	l.inner.PrintSection(section)
+}
+
+// PrintAligned masks and forwards text.
+func (l *MaskingLogger) PrintAligned(text string) { l.inner.PrintAligned(l.masker.Mask(text)) }
+
+// LogQuestion masks and forwards the question and options.
+func (l *MaskingLogger) LogQuestion(question string, options []string) {
+	masked := make([]string, len(options))
+	for i, o := range options {
+		masked[i] = l.masker.Mask(o)
+	}
+	l.inner.LogQuestion(l.masker.Mask(question), masked)
+}
+
+// LogAnswer masks and forwards the answer.
+func (l *MaskingLogger) LogAnswer(answer string) { l.inner.LogAnswer(l.masker.Mask(answer)) }
+
+// LogActivity forwards the activity with a masked detail.
+func (l *MaskingLogger) LogActivity(activity executor.Activity) {
+	activity.Detail = l.masker.Mask(activity.Detail)
+	l.inner.LogActivity(activity)
+}
+
+// LogStall forwards to the inner logger.
+func (l *MaskingLogger) LogStall(idle time.Duration) { l.inner.LogStall(idle) }
+
+// LogUsage forwards to the inner logger.
+func (l *MaskingLogger) LogUsage(usage executor.Usage) { l.inner.LogUsage(usage) }
+
+// LogCommits forwards to the inner logger, commit hashes and section labels carry no secrets.
+func (l *MaskingLogger) LogCommits(commits IterationCommits) { l.inner.LogCommits(commits) }
+
+// Path returns the inner logger's progress file path.
+func (l *MaskingLogger) Path() string { return l.inner.Path() }
@@ -0,0 +1,54 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/umputun/ralphex/pkg/executor"
+)
+
+// phases accepted by claude_session_resume.
+const (
+	sessionPhaseReview = "review"
+	sessionPhaseCodex  = "codex"
+)
+
+// claudeSession tracks the claude session continued across iterations of one phase loop.
+type claudeSession struct {
+	phase string
+	id    string
+}
+
+// sessionResumeEnabled returns true when claude_session_resume lists the phase.
+func (r *Runner) sessionResumeEnabled(phase string) bool {
+	return r.cfg.AppConfig != nil && slices.Contains(r.cfg.AppConfig.ClaudeSessionResume, phase)
+}
+
+// runClaudeSession runs prompt with claude, continuing the session of an earlier iteration
+// in the same phase loop when enabled and the executor supports it. a failed resume
+// (e.g. an expired session) falls back to a fresh session, unless it failed on a usage limit or stalled.
+func (r *Runner) runClaudeSession(ctx context.Context, sess *claudeSession, prompt string) executor.Result {
+	resumer, ok := r.claude.(SessionResumer)
+	if !ok || sess.id == "" || !r.sessionResumeEnabled(sess.phase) {
+		result := r.runExec(ctx, r.claude, prompt)
+		sess.id = result.SessionID
+		return result
+	}
+
+	r.log.Print("continuing claude session %s", sess.id)
+	resume := func(p string) executor.Result { return resumer.Resume(ctx, sess.id, p) }
+	result := r.recoverRun(ctx, prompt, resume(prompt), resume)
+	var limitErr *executor.LimitError
+	var stallErr *executor.StallError
+	if result.Error != nil && ctx.Err() == nil && !errors.As(result.Error, &limitErr) && !errors.As(result.Error, &stallErr) {
+		r.log.Print("[WARN] resuming claude session %s failed: %v, starting fresh session", sess.id, result.Error)
+		result = r.runExec(ctx, r.claude, prompt)
+		sess.id = result.SessionID
+		return result
+	}
+	if result.SessionID != "" {
+		sess.id = result.SessionID
+	}
+	return result
+}
@@ -0,0 +1,72 @@
+package processor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/umputun/ralphex/pkg/executor"
+	"github.com/umputun/ralphex/pkg/processor"
+	"github.com/umputun/ralphex/pkg/processor/mocks"
+)
+
+// resumableExecutor combines executor and session resumer mocks.
+type resumableExecutor struct {
+	*mocks.ExecutorMock
+	*mocks.SessionResumerMock
+}
+
+func TestRunner_ClaudeSessionResume(t *testing.T) {
+	newRunner := func(t *testing.T, phases []string, claude processor.Executor) *processor.Runner {
+		t.Helper()
+		appCfg := testAppConfig(t)
+		appCfg.ClaudeSessionResume = phases
+		cfg := processor.Config{Mode: processor.ModeCodexOnly, MaxIterations: 50, IterationDelayMs: 1, AppConfig: appCfg}
+		return processor.NewWithExecutors(cfg, newMockLogger("progress.txt"), claude, newMockExecutor(nil))
+	}
+
+	t.Run("review iterations continue the session", func(t *testing.T) {
+		runs := newMockExecutor([]executor.Result{{Output: "fixed", SessionID: "s1"}})
+		resumer := &mocks.SessionResumerMock{ResumeFunc: func(_ context.Context, _, _ string) executor.Result {
+			return executor.Result{Output: "done", Signal: processor.SignalReviewDone, SessionID: "s1"}
+		}}
+		r := newRunner(t, []string{"review"}, resumableExecutor{runs, resumer})
+		_, err := r.Run(context.Background())
+		require.NoError(t, err)
+
+		assert.Len(t, runs.RunCalls(), 1)
+		require.Len(t, resumer.ResumeCalls(), 1)
+		assert.Equal(t, "s1", resumer.ResumeCalls()[0].SessionID)
+	})
+
+	t.Run("fresh sessions when phase not enabled", func(t *testing.T) {
+		runs := newMockExecutor([]executor.Result{
+			{Output: "fixed", SessionID: "s1"},
+			{Output: "done", Signal: processor.SignalReviewDone, SessionID: "s2"},
+		})
+		resumer := &mocks.SessionResumerMock{}
+		r := newRunner(t, []string{"codex"}, resumableExecutor{runs, resumer})
+		_, err := r.Run(context.Background())
+		require.NoError(t, err)
+		assert.Len(t, runs.RunCalls(), 2)
+		assert.Empty(t, resumer.ResumeCalls())
+	})
+
+	t.Run("failed resume falls back to fresh session", func(t *testing.T) {
+		runs := newMockExecutor([]executor.Result{
+			{Output: "fixed", SessionID: "s1"},
+			{Output: "done", Signal: processor.SignalReviewDone, SessionID: "s2"},
+		})
+		resumer := &mocks.SessionResumerMock{ResumeFunc: func(_ context.Context, _, _ string) executor.Result {
+			return executor.Result{Error: errors.New("no conversation found")}
+		}}
+		r := newRunner(t, []string{"review"}, resumableExecutor{runs, resumer})
+		_, err := r.Run(context.Background())
+		require.NoError(t, err)
+		assert.Len(t, resumer.ResumeCalls(), 1)
+		assert.Len(t, runs.RunCalls(), 2)
+	})
+}
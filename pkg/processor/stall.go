@@ -0,0 +1,52 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/umputun/ralphex/pkg/config"
+	"github.com/umputun/ralphex/pkg/executor"
+)
+
+// defaultStallRetryCount is used when stall_retry_count is not configured.
+const defaultStallRetryCount = 2
+
+// stallNote is appended to the prompt of a run retried after its process stalled.
+const stallNote = `
+
+NOTE: a previous attempt at this step was killed after producing no output for %s, most likely while waiting on a stuck tool call. Check the current state of the repository before continuing. Avoid commands that can block indefinitely (interactive prompts, watch modes, servers in the foreground) and use timeouts for long-running commands.`
+
+// stallSettings returns the idle timeout for claude/codex executors and the number of stall retries.
+// the timeout is zero (disabled) without app config.
+func stallSettings(appCfg *config.Config) (timeout time.Duration, retries int) {
+	retries = defaultStallRetryCount
+	if appCfg == nil {
+		return 0, retries
+	}
+	if appCfg.StallRetryCountSet {
+		retries = appCfg.StallRetryCount
+	}
+	return time.Duration(appCfg.StallTimeoutMs) * time.Millisecond, retries
+}
+
+// retryOnStall records a stalled run in the progress log and re-runs it with stallNote appended to
+// prompt, up to the configured number of stall retries. returns the last result once it did not stall
+// or retries are exhausted.
+func (r *Runner) retryOnStall(ctx context.Context, prompt string, result executor.Result,
+	run func(prompt string) executor.Result) executor.Result {
+	for attempt := 0; ; attempt++ {
+		var stallErr *executor.StallError
+		if !errors.As(result.Error, &stallErr) || ctx.Err() != nil {
+			return result
+		}
+		r.log.LogStall(stallErr.Idle)
+		if attempt >= r.stallRetries {
+			result.Error = fmt.Errorf("giving up after %d stall retries: %w", attempt, result.Error)
+			return result
+		}
+		r.log.Print("[WARN] retrying stalled run with a note (%d/%d)", attempt+1, r.stallRetries)
+		result = run(prompt + fmt.Sprintf(stallNote, stallErr.Idle))
+	}
+}
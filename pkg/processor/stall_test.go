@@ -0,0 +1,122 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/umputun/ralphex/pkg/config"
+	"github.com/umputun/ralphex/pkg/executor"
+)
+
+// promptExecutor returns results in order and records prompts.
+type promptExecutor struct {
+	results []executor.Result
+	prompts []string
+}
+
+func (p *promptExecutor) Run(_ context.Context, prompt string) executor.Result {
+	if len(p.prompts) >= len(p.results) {
+		return executor.Result{Error: errors.New("no more results")}
+	}
+	p.prompts = append(p.prompts, prompt)
+	return p.results[len(p.prompts)-1]
+}
+
+func stallResult() executor.Result {
+	return executor.Result{Output: "partial", Error: &executor.StallError{Idle: time.Minute}}
+}
+
+func TestStallSettings(t *testing.T) {
+	timeout, retries := stallSettings(nil)
+	assert.Zero(t, timeout)
+	assert.Equal(t, defaultStallRetryCount, retries)
+
+	timeout, retries = stallSettings(&config.Config{StallTimeoutMs: 5000, StallTimeoutMsSet: true,
+		StallRetryCount: 0, StallRetryCountSet: true})
+	assert.Equal(t, 5*time.Second, timeout)
+	assert.Zero(t, retries)
+}
+
+func TestRunner_retryOnStall(t *testing.T) {
+	t.Run("retries with note", func(t *testing.T) {
+		log := newMockLogger("progress.txt")
+		r := &Runner{log: log, stallRetries: 2}
+		exec := &promptExecutor{results: []executor.Result{stallResult(), {Output: "done"}}}
+
+		result := r.runExec(context.Background(), exec, "do task")
+
+		require.NoError(t, result.Error)
+		assert.Equal(t, "done", result.Output)
+		require.Len(t, exec.prompts, 2)
+		assert.Equal(t, "do task", exec.prompts[0])
+		assert.Contains(t, exec.prompts[1], "do task")
+		assert.Contains(t, exec.prompts[1], "killed after producing no output for 1m0s")
+		require.Len(t, log.printCalls, 1)
+		assert.Contains(t, log.printCalls[0].Format, "retrying stalled run")
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		r := &Runner{log: newMockLogger("progress.txt"), stallRetries: 1}
+		exec := &promptExecutor{results: []executor.Result{stallResult(), stallResult(), {Output: "done"}}}
+
+		result := r.runExec(context.Background(), exec, "do task")
+
+		var stallErr *executor.StallError
+		require.ErrorAs(t, result.Error, &stallErr)
+		assert.Contains(t, result.Error.Error(), "giving up after 1 stall retries")
+		assert.Len(t, exec.prompts, 2)
+	})
+
+	t.Run("limit after stall is waited out", func(t *testing.T) {
+		r := &Runner{log: newMockLogger("progress.txt"), stallRetries: 2,
+			limits: limitPolicy{retries: 2, backoff: time.Millisecond, maxWait: time.Millisecond}}
+		exec := &promptExecutor{results: []executor.Result{stallResult(), limitResult(executor.LimitRate), {Output: "done"}}}
+
+		result := r.runExec(context.Background(), exec, "do task")
+
+		require.NoError(t, result.Error)
+		require.Len(t, exec.prompts, 3)
+		assert.Equal(t, exec.prompts[1], exec.prompts[2], "limit retry keeps the stall note")
+	})
+}
+
+func TestRunner_recoverRun_LogsUsage(t *testing.T) {
+	log := newMockLogger("progress.txt")
+	r := &Runner{log: log, stallRetries: 1}
+	stalled := stallResult()
+	stalled.Usage = executor.Usage{InputTokens: 10, OutputTokens: 1}
+	exec := &promptExecutor{results: []executor.Result{stalled, {Output: "done", Usage: executor.Usage{InputTokens: 20, OutputTokens: 2}}}}
+
+	result := r.runExec(context.Background(), exec, "do task")
+
+	require.NoError(t, result.Error)
+	assert.Equal(t, []executor.Usage{{InputTokens: 10, OutputTokens: 1}, {InputTokens: 20, OutputTokens: 2}}, log.usageCalls,
+		"every attempt is logged")
+
+	log = newMockLogger("progress.txt")
+	r.log = log
+	r.runExec(context.Background(), &promptExecutor{results: []executor.Result{{Output: "codex"}}}, "review")
+	assert.Empty(t, log.usageCalls, "runs without usage are not logged")
+}
+
+func TestRunner_TaskPhase_StallIsRetried(t *testing.T) {
+	tmpDir := t.TempDir()
+	planFile := filepath.Join(tmpDir, "plan.md")
+	require.NoError(t, os.WriteFile(planFile, []byte("# Plan\n- [x] Task 1"), 0o600))
+
+	claude := &promptExecutor{results: []executor.Result{stallResult(), {Output: "task done", Signal: SignalCompleted}}}
+
+	cfg := Config{Mode: ModeFull, PlanFile: planFile, MaxIterations: 1, TaskRetryCount: 0, IterationDelayMs: 1,
+		AppConfig: testAppConfig(t)}
+	r := NewWithExecutors(cfg, newMockLogger("progress.txt"), claude, &promptExecutor{})
+
+	require.NoError(t, r.runTaskPhase(context.Background()))
+	assert.Len(t, claude.prompts, 2)
+}
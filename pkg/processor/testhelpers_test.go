@@ -2,10 +2,12 @@ package processor
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/umputun/ralphex/pkg/config"
+	"github.com/umputun/ralphex/pkg/executor"
 )
 
 // testAppConfig loads config with embedded defaults for testing.
@@ -19,8 +21,10 @@ func testAppConfig(t *testing.T) *config.Config {
 // stubLogger is a simple logger stub for internal tests that need to test
 // methods on Runner without using the mock package (to avoid import cycles).
 type stubLogger struct {
-	path       string
-	printCalls []printCall
+	path         string
+	printCalls   []printCall
+	usageCalls   []executor.Usage
+	commitsCalls []IterationCommits
 }
 
 type printCall struct {
@@ -37,6 +41,10 @@ func (s *stubLogger) PrintSection(_ Section)           {}
 func (s *stubLogger) PrintAligned(_ string)            {}
 func (s *stubLogger) LogQuestion(_ string, _ []string) {}
 func (s *stubLogger) LogAnswer(_ string)               {}
+func (s *stubLogger) LogActivity(_ executor.Activity)  {}
+func (s *stubLogger) LogStall(_ time.Duration)         {}
+func (s *stubLogger) LogUsage(u executor.Usage)        { s.usageCalls = append(s.usageCalls, u) }
+func (s *stubLogger) LogCommits(c IterationCommits)    { s.commitsCalls = append(s.commitsCalls, c) }
 func (s *stubLogger) Path() string                     { return s.path }
 func (s *stubLogger) PrintCalls() []printCall          { return s.printCalls }
 
@@ -15,6 +15,7 @@ import (
 	"golang.org/x/term"
 
 	"github.com/umputun/ralphex/pkg/config"
+	"github.com/umputun/ralphex/pkg/executor"
 	"github.com/umputun/ralphex/pkg/processor"
 )
 
@@ -126,10 +127,12 @@ func (c *Colors) Signal() *color.Color { return c.signal }
 // Logger writes timestamped output to both file and stdout.
 type Logger struct {
 	file      *os.File
+	records   *recordWriter // machine-readable event file next to the progress log
 	stdout    io.Writer
 	startTime time.Time
 	phase     Phase
 	colors    *Colors
+	outcome   *processor.Outcome // written to the footer on Close, nil if the run did not report one
 }
 
 // Config holds logger configuration.
@@ -171,8 +174,17 @@ func NewLogger(cfg Config, colors *Colors) (*Logger, error) {
 	}
 	registerActiveLock(f.Name())
 
+	records, err := newRecordWriter(progressPath)
+	if err != nil {
+		_ = unlockFile(f)
+		unregisterActiveLock(f.Name())
+		f.Close()
+		return nil, err
+	}
+
 	l := &Logger{
 		file:      f,
+		records:   records,
 		stdout:    os.Stdout,
 		startTime: time.Now(),
 		phase:     PhaseTask,
@@ -190,6 +202,7 @@ func NewLogger(cfg Config, colors *Colors) (*Logger, error) {
 	l.writeFile("Mode: %s\n", cfg.Mode)
 	l.writeFile("Started: %s\n", time.Now().Format("2006-01-02 15:04:05"))
 	l.writeFile("%s\n\n", strings.Repeat("-", 60))
+	l.records.write(Record{Time: l.startTime, Type: RecordHeader, Plan: planStr, Branch: cfg.Branch, Mode: cfg.Mode})
 
 	return l, nil
 }
@@ -204,6 +217,9 @@ func (l *Logger) Path() string {
 
 // SetPhase sets the current execution phase for color coding.
 func (l *Logger) SetPhase(phase Phase) {
+	if phase != l.phase {
+		l.records.write(Record{Type: RecordPhase, Phase: phase})
+	}
 	l.phase = phase
 }
 
@@ -217,6 +233,7 @@ func (l *Logger) Print(format string, args ...any) {
 
 	// write to file without color
 	l.writeFile("[%s] %s\n", timestamp, msg)
+	l.writeRecord(Record{Type: RecordOutput, Text: msg})
 
 	// write to stdout with color
 	phaseColor := l.colors.ForPhase(l.phase)
@@ -230,6 +247,7 @@ func (l *Logger) PrintRaw(format string, args ...any) {
 	msg := fmt.Sprintf(format, args...)
 	l.writeFile("%s", msg)
 	l.writeStdout("%s", msg)
+	l.writeRecord(Record{Type: RecordOutput, Text: msg})
 }
 
 // PrintSection writes a section header without timestamp in yellow.
@@ -238,6 +256,8 @@ func (l *Logger) PrintSection(section processor.Section) {
 	header := fmt.Sprintf("\n--- %s ---\n", section.Label)
 	l.writeFile("%s", header)
 	l.writeStdout("%s", l.colors.Warn().Sprint(header))
+	l.writeRecord(Record{Type: RecordSection, Text: section.Label, Section: sectionKinds[section.Type],
+		Iteration: section.Iteration})
 }
 
 // getTerminalWidth returns terminal width, using COLUMNS env var or syscall.
@@ -315,6 +335,13 @@ func (l *Logger) PrintAligned(text string) {
 		return
 	}
 
+	l.writeRecord(Record{Type: RecordOutput, Text: text})
+	for line := range strings.SplitSeq(text, "\n") {
+		if sig := extractSignal(line); sig != "" {
+			l.writeRecord(Record{Type: RecordSignal, Signal: sig})
+		}
+	}
+
 	phaseColor := l.colors.ForPhase(l.phase)
 
 	// wrap text to terminal width
@@ -415,6 +442,7 @@ func (l *Logger) Error(format string, args ...any) {
 	timestamp := time.Now().Format(timestampFormat)
 
 	l.writeFile("[%s] ERROR: %s\n", timestamp, msg)
+	l.writeRecord(Record{Type: RecordOutput, Text: "ERROR: " + msg})
 
 	tsStr := l.colors.Timestamp().Sprintf("[%s]", timestamp)
 	errStr := l.colors.Error().Sprintf("ERROR: %s", msg)
@@ -427,6 +455,7 @@ func (l *Logger) Warn(format string, args ...any) {
 	timestamp := time.Now().Format(timestampFormat)
 
 	l.writeFile("[%s] WARN: %s\n", timestamp, msg)
+	l.writeRecord(Record{Type: RecordOutput, Text: "WARN: " + msg})
 
 	tsStr := l.colors.Timestamp().Sprintf("[%s]", timestamp)
 	warnStr := l.colors.Warn().Sprintf("WARN: %s", msg)
@@ -440,6 +469,7 @@ func (l *Logger) LogQuestion(question string, options []string) {
 
 	l.writeFile("[%s] QUESTION: %s\n", timestamp, question)
 	l.writeFile("[%s] OPTIONS: %s\n", timestamp, strings.Join(options, ", "))
+	l.writeRecord(Record{Type: RecordQuestion, Text: question, Options: options})
 
 	tsStr := l.colors.Timestamp().Sprintf("[%s]", timestamp)
 	questionStr := l.colors.Info().Sprintf("QUESTION: %s", question)
@@ -454,18 +484,82 @@ func (l *Logger) LogAnswer(answer string) {
 	timestamp := time.Now().Format(timestampFormat)
 
 	l.writeFile("[%s] ANSWER: %s\n", timestamp, answer)
+	l.writeRecord(Record{Type: RecordAnswer, Text: answer})
 
 	tsStr := l.colors.Timestamp().Sprintf("[%s]", timestamp)
 	answerStr := l.colors.Info().Sprintf("ANSWER: %s", answer)
 	l.writeStdout("%s %s\n", tsStr, answerStr)
 }
 
+// LogActivity logs a compact tool-use line such as "Edit pkg/foo.go" or "Bash: go test ./...".
+// format: ACTIVITY: <activity>
+func (l *Logger) LogActivity(activity executor.Activity) {
+	timestamp := time.Now().Format(timestampFormat)
+
+	l.writeFile("[%s] ACTIVITY: %s\n", timestamp, activity)
+	l.writeRecord(Record{Type: RecordActivity, Tool: activity.Tool, Detail: activity.Detail})
+
+	tsStr := l.colors.Timestamp().Sprintf("[%s]", timestamp)
+	activityStr := l.colors.Info().Sprintf("> %s", activity)
+	l.writeStdout("%s %s\n", tsStr, activityStr)
+}
+
+// LogStall logs that claude or codex produced no output for idle and was killed.
+// format: STALL: no output for <idle>, process killed
+func (l *Logger) LogStall(idle time.Duration) {
+	timestamp := time.Now().Format(timestampFormat)
+
+	l.writeFile("[%s] STALL: no output for %s, process killed\n", timestamp, idle)
+	l.writeRecord(Record{Type: RecordStall, Duration: idle})
+
+	tsStr := l.colors.Timestamp().Sprintf("[%s]", timestamp)
+	stallStr := l.colors.Warn().Sprintf("STALL: no output for %s, process killed", idle)
+	l.writeStdout("%s %s\n", tsStr, stallStr)
+}
+
+// LogUsage logs token usage and cost reported by a claude run.
+// format: USAGE: <input> in, <output> out[, <cached> cached][, $<cost>]
+func (l *Logger) LogUsage(usage executor.Usage) {
+	timestamp := time.Now().Format(timestampFormat)
+
+	l.writeFile("[%s] USAGE: %s\n", timestamp, usage)
+	l.writeRecord(Record{Type: RecordUsage, Usage: &usage})
+
+	tsStr := l.colors.Timestamp().Sprintf("[%s]", timestamp)
+	usageStr := l.colors.Info().Sprintf("USAGE: %s", usage)
+	l.writeStdout("%s %s\n", tsStr, usageStr)
+}
+
+// LogCommits logs the commit range produced by a task or review iteration.
+// format: COMMITS: iteration <n> (<section label>): <from>..<to>
+func (l *Logger) LogCommits(commits processor.IterationCommits) {
+	timestamp := time.Now().Format(timestampFormat)
+
+	l.writeFile("[%s] COMMITS: %s\n", timestamp, commits)
+	l.writeRecord(Record{Type: RecordCommits, Commits: &commits})
+
+	tsStr := l.colors.Timestamp().Sprintf("[%s]", timestamp)
+	commitsStr := l.colors.Info().Sprintf("COMMITS: %s %s..%s", commits.Label, shortHash(commits.From), shortHash(commits.To))
+	l.writeStdout("%s %s\n", tsStr, commitsStr)
+}
+
+// shortHash abbreviates a commit hash for display.
+func shortHash(hash string) string {
+	return hash[:min(len(hash), 7)]
+}
+
 // Elapsed returns formatted elapsed time since start.
 func (l *Logger) Elapsed() string {
 	return humanize.RelTime(l.startTime, time.Now(), "", "")
 }
 
+// SetOutcome sets the run outcome written to the footer on Close.
+func (l *Logger) SetOutcome(outcome processor.Outcome) {
+	l.outcome = &outcome
+}
+
 // Close writes footer, releases the file lock, and closes the progress file.
+// with an outcome set, the footer includes it as key-value lines after "Completed:".
 func (l *Logger) Close() error {
 	if l.file == nil {
 		return nil
@@ -473,6 +567,9 @@ func (l *Logger) Close() error {
 
 	l.writeFile("\n%s\n", strings.Repeat("-", 60))
 	l.writeFile("Completed: %s (%s)\n", time.Now().Format("2006-01-02 15:04:05"), l.Elapsed())
+	l.writeOutcome()
+	l.writeRecord(Record{Type: RecordOutcome, Duration: time.Since(l.startTime), Outcome: l.outcome})
+	recordsErr := l.records.close()
 
 	// release file lock before closing
 	_ = unlockFile(l.file)
@@ -481,7 +578,7 @@ func (l *Logger) Close() error {
 	if err := l.file.Close(); err != nil {
 		return fmt.Errorf("close progress file: %w", err)
 	}
-	return nil
+	return recordsErr
 }
 
 func (l *Logger) writeFile(format string, args ...any) {
@@ -490,6 +587,46 @@ func (l *Logger) writeFile(format string, args ...any) {
 	}
 }
 
+// writeOutcome writes the outcome footer lines, counts are omitted when zero.
+func (l *Logger) writeOutcome() {
+	o := l.outcome
+	if o == nil {
+		return
+	}
+	l.writeFile("Outcome: %s\n", o.Status)
+	if o.Phase != "" {
+		l.writeFile("Phase: %s\n", o.Phase)
+	}
+	if o.Error != "" {
+		l.writeFile("Error: %s\n", strings.Join(strings.Fields(o.Error), " "))
+	}
+	if o.Executor != "" {
+		l.writeFile("Executor: %s\n", o.Executor)
+	}
+	for _, c := range []struct {
+		name  string
+		count int
+	}{
+		{"Task iterations", o.TaskIterations},
+		{"Review iterations", o.ReviewIterations},
+		{"Codex iterations", o.CodexIterations},
+		{"Plan iterations", o.PlanIterations},
+	} {
+		if c.count > 0 {
+			l.writeFile("%s: %d\n", c.name, c.count)
+		}
+	}
+	l.writeFile("Duration: %s\n", o.Duration.Round(time.Second))
+}
+
+// writeRecord appends r to the event file, stamped with the current phase.
+func (l *Logger) writeRecord(r Record) {
+	if r.Phase == "" {
+		r.Phase = l.phase
+	}
+	l.records.write(r)
+}
+
 func (l *Logger) writeStdout(format string, args ...any) {
 	fmt.Fprintf(l.stdout, format, args...)
 }
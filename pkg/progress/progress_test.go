@@ -6,12 +6,14 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/umputun/ralphex/pkg/config"
+	"github.com/umputun/ralphex/pkg/executor"
 	"github.com/umputun/ralphex/pkg/processor"
 )
 
@@ -322,6 +324,33 @@ func TestLogger_Close(t *testing.T) {
 	assert.Contains(t, string(content), strings.Repeat("-", 60))
 }
 
+func TestLogger_CloseWithOutcome(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	l, err := NewLogger(Config{Mode: "full", Branch: "test", NoColor: true}, testColors())
+	require.NoError(t, err)
+	l.stdout = &bytes.Buffer{}
+
+	outcome := processor.Outcome{Status: processor.OutcomeFailed, Phase: processor.PhaseReview,
+		Error: "first review:\nclaude execution: exit status 1", Executor: "claude", TaskIterations: 3, ReviewIterations: 1,
+		Duration: 90*time.Second + 300*time.Millisecond}
+	l.SetOutcome(outcome)
+	require.NoError(t, l.Close())
+
+	content, err := os.ReadFile(l.Path())
+	require.NoError(t, err)
+	footer := string(content)[strings.Index(string(content), "Completed:"):]
+	_, footer, _ = strings.Cut(footer, "\n")
+	assert.Equal(t, "Outcome: failed\nPhase: review\nError: first review: claude execution: exit status 1\n"+
+		"Executor: claude\nTask iterations: 3\n"+
+		"Review iterations: 1\nDuration: 1m30s\n", footer)
+
+	records := readRecords(t, l.Path())
+	last := records[len(records)-1]
+	assert.Equal(t, RecordOutcome, last.Type)
+	assert.Equal(t, &outcome, last.Outcome)
+}
+
 func TestGetProgressFilename(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -723,6 +752,92 @@ func TestLogger_LogAnswer(t *testing.T) {
 	assert.Contains(t, buf.String(), "ANSWER: Redis")
 }
 
+func TestLogger_LogActivity(t *testing.T) {
+	tmpDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	require.NoError(t, os.Chdir(tmpDir))
+	defer func() { _ = os.Chdir(origDir) }()
+
+	l, err := NewLogger(Config{PlanFile: "docs/plans/test.md", Mode: "full", Branch: "main", NoColor: true}, testColors())
+	require.NoError(t, err)
+	defer func() { _ = l.Close() }()
+
+	var buf bytes.Buffer
+	l.stdout = &buf
+
+	l.LogActivity(executor.Activity{Tool: "Edit", Detail: "pkg/foo.go"})
+	l.LogActivity(executor.Activity{Tool: "Bash", Detail: "go test ./..."})
+
+	content, err := os.ReadFile(l.Path())
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "] ACTIVITY: Edit pkg/foo.go\n")
+	assert.Contains(t, string(content), "] ACTIVITY: Bash: go test ./...\n")
+
+	assert.Contains(t, buf.String(), "> Edit pkg/foo.go")
+	assert.Contains(t, buf.String(), "> Bash: go test ./...")
+}
+
+func TestLogger_LogStall(t *testing.T) {
+	tmpDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	require.NoError(t, os.Chdir(tmpDir))
+	defer func() { _ = os.Chdir(origDir) }()
+
+	l, err := NewLogger(Config{PlanFile: "docs/plans/test.md", Mode: "full", Branch: "main", NoColor: true}, testColors())
+	require.NoError(t, err)
+	defer func() { _ = l.Close() }()
+
+	var buf bytes.Buffer
+	l.stdout = &buf
+
+	l.LogStall(30 * time.Minute)
+
+	content, err := os.ReadFile(l.Path())
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "] STALL: no output for 30m0s, process killed\n")
+	assert.Contains(t, buf.String(), "STALL: no output for 30m0s, process killed")
+}
+
+func TestLogger_LogUsage(t *testing.T) {
+	tmpDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	require.NoError(t, os.Chdir(tmpDir))
+	defer func() { _ = os.Chdir(origDir) }()
+
+	l, err := NewLogger(Config{PlanFile: "docs/plans/test.md", Mode: "full", Branch: "main", NoColor: true}, testColors())
+	require.NoError(t, err)
+	defer func() { _ = l.Close() }()
+
+	var buf bytes.Buffer
+	l.stdout = &buf
+
+	l.LogUsage(executor.Usage{InputTokens: 1200, OutputTokens: 350, CostUSD: 0.042})
+
+	content, err := os.ReadFile(l.Path())
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "] USAGE: 1200 in, 350 out, $0.0420\n")
+	assert.Contains(t, buf.String(), "USAGE: 1200 in, 350 out, $0.0420")
+}
+
+func TestLogger_LogCommits(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	l, err := NewLogger(Config{PlanFile: "docs/plans/test.md", Mode: "full", Branch: "main", NoColor: true}, testColors())
+	require.NoError(t, err)
+	defer func() { _ = l.Close() }()
+
+	var buf bytes.Buffer
+	l.stdout = &buf
+
+	from, to := strings.Repeat("a", 40), strings.Repeat("b", 40)
+	l.LogCommits(processor.IterationCommits{Iteration: 2, Label: "task iteration 2", From: from, To: to})
+
+	content, err := os.ReadFile(l.Path())
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "] COMMITS: iteration 2 (task iteration 2): "+from+".."+to+"\n")
+	assert.Contains(t, buf.String(), "COMMITS: task iteration 2 aaaaaaa..bbbbbbb")
+}
+
 func TestLogger_PlanModeFilename(t *testing.T) {
 	tmpDir := t.TempDir()
 	origDir, _ := os.Getwd()
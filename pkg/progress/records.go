@@ -0,0 +1,136 @@
+package progress
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/umputun/ralphex/pkg/executor"
+	"github.com/umputun/ralphex/pkg/processor"
+)
+
+// RecordType identifies the kind of a progress event record.
+type RecordType string
+
+// record types written to the progress event file.
+const (
+	RecordHeader   RecordType = "header"   // run metadata, always the first record
+	RecordPhase    RecordType = "phase"    // execution phase change
+	RecordSection  RecordType = "section"  // section header
+	RecordOutput   RecordType = "output"   // output text, including errors and warnings
+	RecordSignal   RecordType = "signal"   // <<<RALPHEX:...>>> signal found in output
+	RecordQuestion RecordType = "question" // plan mode question with options
+	RecordAnswer   RecordType = "answer"   // plan mode answer
+	RecordActivity RecordType = "activity" // claude tool call
+	RecordStall    RecordType = "stall"    // process killed after producing no output
+	RecordUsage    RecordType = "usage"    // token usage and cost of a claude run
+	RecordCommits  RecordType = "commits"  // commit range produced by a task or review iteration
+	RecordOutcome  RecordType = "outcome"  // run end, always the last record
+)
+
+// Record is a single line of the progress event file (progress-*.jsonl), the machine-readable
+// counterpart of the text progress log. fields not used by a record type are omitted.
+type Record struct {
+	Time      time.Time                   `json:"ts"`
+	Type      RecordType                  `json:"type"`
+	Phase     processor.Phase             `json:"phase,omitempty"`
+	Text      string                      `json:"text,omitempty"`      // output text, section label, question or answer
+	Section   string                      `json:"section,omitempty"`   // section kind, see SectionKind
+	Iteration int                         `json:"iteration,omitempty"` // section iteration number
+	Signal    string                      `json:"signal,omitempty"`    // raw signal name, e.g. ALL_TASKS_DONE
+	Options   []string                    `json:"options,omitempty"`   // question options
+	Tool      string                      `json:"tool,omitempty"`      // activity tool name
+	Detail    string                      `json:"detail,omitempty"`    // activity detail
+	Duration  time.Duration               `json:"duration,omitempty"`  // stall idle time or total run time, in nanoseconds
+	Usage     *executor.Usage             `json:"usage,omitempty"`
+	Commits   *processor.IterationCommits `json:"commits,omitempty"`
+	Outcome   *processor.Outcome          `json:"outcome,omitempty"` // outcome: run result, nil if not reported
+	Plan      string                      `json:"plan,omitempty"`    // header: plan file
+	Branch    string                      `json:"branch,omitempty"`  // header: git branch
+	Mode      string                      `json:"mode,omitempty"`    // header: execution mode
+}
+
+// section kinds stored in Record.Section, a generic section has no kind.
+var sectionKinds = map[processor.SectionType]string{
+	processor.SectionTaskIteration:  "task",
+	processor.SectionClaudeReview:   "claude_review",
+	processor.SectionCodexIteration: "codex",
+	processor.SectionClaudeEval:     "claude_eval",
+	processor.SectionPlanIteration:  "plan",
+}
+
+// SectionKind returns the section type a section record was written for.
+func (r Record) SectionKind() processor.SectionType {
+	for typ, kind := range sectionKinds {
+		if kind == r.Section {
+			return typ
+		}
+	}
+	return processor.SectionGeneric
+}
+
+// Activity returns the tool call of an activity record.
+func (r Record) Activity() executor.Activity {
+	return executor.Activity{Tool: r.Tool, Detail: r.Detail}
+}
+
+// EventsPath returns the progress event file path for a progress log path,
+// e.g. progress-my-plan.jsonl for progress-my-plan.txt.
+func EventsPath(progressPath string) string {
+	return strings.TrimSuffix(progressPath, ".txt") + ".jsonl"
+}
+
+// ParseRecord decodes a single line of the progress event file.
+func ParseRecord(line []byte) (Record, error) {
+	var r Record
+	if err := json.Unmarshal(line, &r); err != nil {
+		return Record{}, fmt.Errorf("parse progress record: %w", err)
+	}
+	if r.Type == "" {
+		return Record{}, fmt.Errorf("parse progress record: missing type")
+	}
+	return r, nil
+}
+
+// recordWriter appends records to the progress event file, one JSON object per line.
+// write errors are ignored like for the text log, the event file is best-effort.
+type recordWriter struct {
+	file *os.File
+}
+
+// newRecordWriter creates the event file next to the progress log.
+func newRecordWriter(progressPath string) (*recordWriter, error) {
+	f, err := os.Create(EventsPath(progressPath)) //nolint:gosec // path derived from plan filename
+	if err != nil {
+		return nil, fmt.Errorf("create progress event file: %w", err)
+	}
+	return &recordWriter{file: f}, nil
+}
+
+// write appends r, setting its time if empty.
+func (w *recordWriter) write(r Record) {
+	if w == nil || w.file == nil {
+		return
+	}
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return
+	}
+	_, _ = w.file.Write(append(data, '\n'))
+}
+
+// close closes the event file.
+func (w *recordWriter) close() error {
+	if w == nil || w.file == nil {
+		return nil
+	}
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("close progress event file: %w", err)
+	}
+	return nil
+}
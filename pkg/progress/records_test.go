@@ -0,0 +1,106 @@
+package progress
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/umputun/ralphex/pkg/executor"
+	"github.com/umputun/ralphex/pkg/processor"
+)
+
+// readRecords parses all records of the event file next to the progress log at path.
+func readRecords(t *testing.T, path string) []Record {
+	t.Helper()
+	data, err := os.ReadFile(EventsPath(path)) //nolint:gosec // test file
+	require.NoError(t, err)
+	var records []Record
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		rec, err := ParseRecord(scanner.Bytes())
+		require.NoError(t, err, scanner.Text())
+		records = append(records, rec)
+	}
+	return records
+}
+
+func TestLogger_Records(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	l, err := NewLogger(Config{PlanFile: "docs/plans/test.md", Mode: "full", Branch: "main", NoColor: true}, testColors())
+	require.NoError(t, err)
+	l.stdout = &bytes.Buffer{}
+
+	l.PrintSection(processor.NewTaskIterationSection(1))
+	l.Print("starting task")
+	l.PrintAligned("done\n<<<RALPHEX:ALL_TASKS_DONE>>>\n")
+	l.LogActivity(executor.Activity{Tool: "Edit", Detail: "pkg/foo.go"})
+	l.SetPhase(processor.PhaseReview)
+	l.PrintSection(processor.NewClaudeReviewSection(2, ": critical"))
+	l.Error("boom")
+	l.LogStall(5 * time.Minute)
+	l.LogUsage(executor.Usage{InputTokens: 10, OutputTokens: 5})
+	l.LogCommits(processor.IterationCommits{Iteration: 2, Label: "claude review 2: critical", From: "aaaaaaa", To: "bbbbbbb"})
+	l.LogQuestion("which db?", []string{"pg", "sqlite"})
+	l.LogAnswer("pg")
+	path := l.Path()
+	require.NoError(t, l.Close())
+
+	records := readRecords(t, path)
+	types := make([]RecordType, 0, len(records))
+	for _, r := range records {
+		types = append(types, r.Type)
+		assert.False(t, r.Time.IsZero())
+	}
+	assert.Equal(t, []RecordType{RecordHeader, RecordSection, RecordOutput, RecordOutput, RecordSignal, RecordActivity,
+		RecordPhase, RecordSection, RecordOutput, RecordStall, RecordUsage, RecordCommits, RecordQuestion, RecordAnswer, RecordOutcome}, types)
+
+	assert.Equal(t, Record{Time: records[0].Time, Type: RecordHeader, Plan: "docs/plans/test.md", Branch: "main", Mode: "full"},
+		records[0])
+	assert.Equal(t, "task iteration 1", records[1].Text)
+	assert.Equal(t, processor.SectionTaskIteration, records[1].SectionKind())
+	assert.Equal(t, 1, records[1].Iteration)
+	assert.Equal(t, processor.PhaseTask, records[1].Phase)
+	assert.Equal(t, "done\n<<<RALPHEX:ALL_TASKS_DONE>>>", records[3].Text)
+	assert.Equal(t, "ALL_TASKS_DONE", records[4].Signal)
+	assert.Equal(t, executor.Activity{Tool: "Edit", Detail: "pkg/foo.go"}, records[5].Activity())
+	assert.Equal(t, processor.PhaseReview, records[6].Phase)
+	assert.Equal(t, processor.SectionClaudeReview, records[7].SectionKind())
+	assert.Equal(t, processor.PhaseReview, records[7].Phase)
+	assert.Equal(t, "ERROR: boom", records[8].Text)
+	assert.Equal(t, 5*time.Minute, records[9].Duration)
+	assert.Equal(t, &executor.Usage{InputTokens: 10, OutputTokens: 5}, records[10].Usage)
+	assert.Equal(t, &processor.IterationCommits{Iteration: 2, Label: "claude review 2: critical", From: "aaaaaaa", To: "bbbbbbb"},
+		records[11].Commits)
+	assert.Equal(t, []string{"pg", "sqlite"}, records[12].Options)
+	assert.Equal(t, "pg", records[13].Text)
+	assert.Positive(t, records[14].Duration)
+}
+
+func TestEventsPath(t *testing.T) {
+	assert.Equal(t, "progress-my-plan.jsonl", EventsPath("progress-my-plan.txt"))
+	assert.Equal(t, "/tmp/progress.jsonl", EventsPath("/tmp/progress.txt"))
+}
+
+func TestParseRecord(t *testing.T) {
+	rec, err := ParseRecord([]byte(`{"ts":"2026-01-22T10:30:00Z","type":"section","text":"codex iteration 2","section":"codex","iteration":2}`))
+	require.NoError(t, err)
+	assert.Equal(t, RecordSection, rec.Type)
+	assert.Equal(t, processor.SectionCodexIteration, rec.SectionKind())
+	assert.Equal(t, 2, rec.Iteration)
+
+	rec, err = ParseRecord([]byte(`{"type":"section","text":"custom"}`))
+	require.NoError(t, err)
+	assert.Equal(t, processor.SectionGeneric, rec.SectionKind())
+
+	_, err = ParseRecord([]byte(`{"type":"output","text":`))
+	require.Error(t, err)
+
+	_, err = ParseRecord([]byte(`{"text":"no type"}`))
+	require.ErrorContains(t, err, "missing type")
+}
@@ -0,0 +1,73 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+
+	"github.com/umputun/ralphex/pkg/executor"
+	"github.com/umputun/ralphex/pkg/processor"
+)
+
+// tracedExecutor records executor calls as spans.
+type tracedExecutor struct {
+	name  string
+	inner processor.Executor
+	log   *Logger
+}
+
+// Run runs the prompt with the wrapped executor inside a "<name> run" span.
+func (e *tracedExecutor) Run(ctx context.Context, prompt string) executor.Result {
+	span := e.log.startCall(e.name+" run", String("ralphex.executor", e.name), Int("ralphex.prompt.size", len(prompt)))
+	result := e.inner.Run(ctx, prompt)
+	endCall(span, result)
+	return result
+}
+
+// tracedResumer is a tracedExecutor for executors implementing processor.SessionResumer.
+type tracedResumer struct {
+	*tracedExecutor
+	resumer processor.SessionResumer
+}
+
+// Resume continues the session with the wrapped executor inside a "<name> resume" span.
+func (e *tracedResumer) Resume(ctx context.Context, sessionID, prompt string) executor.Result {
+	span := e.log.startCall(e.name+" resume", String("ralphex.executor", e.name), Int("ralphex.prompt.size", len(prompt)),
+		String("ralphex.session.resumed", sessionID))
+	result := e.resumer.Resume(ctx, sessionID, prompt)
+	endCall(span, result)
+	return result
+}
+
+// endCall records the result of an executor call on span and ends it.
+func endCall(span *Span, result executor.Result) {
+	attrs := []Attr{Int("ralphex.output.size", len(result.Output))}
+	if result.Signal != "" {
+		attrs = append(attrs, String("ralphex.signal", result.Signal))
+	}
+	if result.SessionID != "" {
+		attrs = append(attrs, String("ralphex.session.id", result.SessionID))
+	}
+	if !result.Usage.IsZero() {
+		attrs = append(attrs, Int("gen_ai.usage.input_tokens", result.Usage.InputTokens),
+			Int("gen_ai.usage.output_tokens", result.Usage.OutputTokens),
+			Int("ralphex.usage.cache_tokens", result.Usage.CacheReadTokens+result.Usage.CacheCreationTokens),
+			Float("ralphex.usage.cost_usd", result.Usage.CostUSD))
+	}
+	attrs = append(attrs, Int("process.exit.code", exitCode(result.Error)))
+	span.SetAttributes(attrs...)
+	span.End(result.Error)
+}
+
+// exitCode returns the exit status of the executor process: 0 without error, the process exit
+// code if it exited with one, -1 for other errors, e.g. a killed or never started process.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
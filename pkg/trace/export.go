@@ -0,0 +1,190 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// OTLP span kind and status codes.
+const (
+	spanKindInternal = 1
+	statusCodeOK     = 1
+	statusCodeError  = 2
+)
+
+// serviceName is the service.name resource attribute of exported spans.
+const serviceName = "ralphex"
+
+// exportRequest is the OTLP/JSON ExportTraceServiceRequest.
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope      `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              int        `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	Status            spanStatus `json:"status"`
+}
+
+type spanStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+// anyValue holds exactly one of its fields. 64-bit integers are strings in OTLP/JSON.
+type anyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+}
+
+// encode returns spans as an OTLP/JSON export request.
+func (t *Tracer) encode(spans []*Span) ([]byte, error) {
+	out := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		status := spanStatus{Code: statusCodeOK}
+		if s.errMsg != "" {
+			status = spanStatus{Code: statusCodeError, Message: s.errMsg}
+		}
+		out = append(out, otlpSpan{
+			TraceID:           t.traceID,
+			SpanID:            s.spanID,
+			ParentSpanID:      s.parentID,
+			Name:              s.name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+			Attributes:        keyValues(s.attrs),
+			Status:            status,
+		})
+	}
+	req := exportRequest{ResourceSpans: []resourceSpans{{
+		Resource:   resource{Attributes: keyValues([]Attr{String("service.name", serviceName)})},
+		ScopeSpans: []scopeSpans{{Scope: scope{Name: serviceName}, Spans: out}},
+	}}}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal spans: %w", err)
+	}
+	return data, nil
+}
+
+// keyValues converts attributes to OTLP key-values. values of unsupported types are formatted as strings.
+func keyValues(attrs []Attr) []keyValue {
+	result := make([]keyValue, 0, len(attrs))
+	for _, a := range attrs {
+		var v anyValue
+		switch val := a.Value.(type) {
+		case string:
+			v.StringValue = &val
+		case int:
+			s := strconv.Itoa(val)
+			v.IntValue = &s
+		case float64:
+			v.DoubleValue = &val
+		case bool:
+			v.BoolValue = &val
+		default:
+			s := fmt.Sprint(val)
+			v.StringValue = &s
+		}
+		result = append(result, keyValue{Key: a.Key, Value: v})
+	}
+	return result
+}
+
+// FileExporter appends export requests to a file, one per line.
+type FileExporter struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// Name returns the exporter name.
+func (e *FileExporter) Name() string { return "file" }
+
+// Export appends data as a line, creating the file and its directory if needed.
+func (e *FileExporter) Export(_ context.Context, data []byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(e.Path), 0o750); err != nil {
+		return fmt.Errorf("create trace directory: %w", err)
+	}
+	f, err := os.OpenFile(e.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open trace file: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write trace file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close trace file: %w", err)
+	}
+	return nil
+}
+
+// HTTPExporter posts export requests to an OTLP/HTTP traces endpoint, e.g. http://localhost:4318/v1/traces.
+type HTTPExporter struct {
+	URL    string
+	Client *http.Client
+}
+
+// Name returns the exporter name.
+func (e *HTTPExporter) Name() string { return "otlp http" }
+
+// Export posts data as JSON.
+func (e *HTTPExporter) Export(ctx context.Context, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.URL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post spans: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("post spans: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package trace
+
+import (
+	"sync"
+	"time"
+
+	"github.com/umputun/ralphex/pkg/executor"
+	"github.com/umputun/ralphex/pkg/processor"
+)
+
+// sectionTypes names section types for the ralphex.section.type attribute, same as the progress event file.
+var sectionTypes = map[processor.SectionType]string{
+	processor.SectionGeneric:        "generic",
+	processor.SectionTaskIteration:  "task",
+	processor.SectionClaudeReview:   "claude_review",
+	processor.SectionCodexIteration: "codex",
+	processor.SectionClaudeEval:     "claude_eval",
+	processor.SectionPlanIteration:  "plan",
+}
+
+// Logger wraps a processor.Logger and records the run as spans: the run span, a span per phase
+// and a span per section (task, review or codex iteration). executors wrapped with WrapExecutor
+// record their calls as spans of the current section. all calls are forwarded to the inner logger.
+//
+// Thread safety: logging methods must be called from a single goroutine, same as the loggers
+// it wraps. wrapped executors may run concurrently, e.g. review agents.
+type Logger struct {
+	inner  processor.Logger
+	tracer *Tracer
+
+	mu        sync.Mutex
+	run       *Span
+	phase     *Span
+	section   *Span
+	phaseName processor.Phase
+	stalls    int // stalls in the current section
+}
+
+// NewLogger creates a logger that wraps inner and records spans with tracer.
+// the run span starts right away, with plan, branch and mode attached.
+func NewLogger(inner processor.Logger, tracer *Tracer, plan, branch string, mode processor.Mode) *Logger {
+	run := tracer.Start(nil, "ralphex run", String("ralphex.plan", plan), String("ralphex.branch", branch),
+		String("ralphex.mode", string(mode)))
+	return &Logger{inner: inner, tracer: tracer, run: run}
+}
+
+// SetPhase forwards the phase, ending the current phase span and starting a new one.
+func (l *Logger) SetPhase(phase processor.Phase) {
+	l.inner.SetPhase(phase)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if phase == l.phaseName && l.phase != nil {
+		return
+	}
+	l.endSectionLocked()
+	if l.phase != nil {
+		l.phase.End(nil)
+	}
+	l.phaseName = phase
+	l.phase = l.tracer.Start(l.run, "phase "+string(phase), String("ralphex.phase", string(phase)))
+}
+
+// Print forwards to the inner logger.
+func (l *Logger) Print(format string, args ...any) {
+	l.inner.Print(format, args...)
+}
+
+// PrintRaw forwards to the inner logger.
+func (l *Logger) PrintRaw(format string, args ...any) {
+	l.inner.PrintRaw(format, args...)
+}
+
+// PrintSection forwards the section, ending the current section span and starting one for section.
+func (l *Logger) PrintSection(section processor.Section) {
+	l.inner.PrintSection(section)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.endSectionLocked()
+	attrs := []Attr{String("ralphex.section.type", sectionTypes[section.Type])}
+	if section.Iteration > 0 {
+		attrs = append(attrs, Int("ralphex.iteration", section.Iteration))
+	}
+	if section.Type == processor.SectionTaskIteration {
+		attrs = append(attrs, Int("ralphex.task", section.Iteration))
+	}
+	l.section = l.tracer.Start(l.parentLocked(), section.Label, attrs...)
+}
+
+// PrintAligned forwards to the inner logger.
+func (l *Logger) PrintAligned(text string) {
+	l.inner.PrintAligned(text)
+}
+
+// LogQuestion forwards to the inner logger.
+func (l *Logger) LogQuestion(question string, options []string) {
+	l.inner.LogQuestion(question, options)
+}
+
+// LogAnswer forwards to the inner logger.
+func (l *Logger) LogAnswer(answer string) {
+	l.inner.LogAnswer(answer)
+}
+
+// LogActivity forwards to the inner logger.
+func (l *Logger) LogActivity(activity executor.Activity) {
+	l.inner.LogActivity(activity)
+}
+
+// LogStall forwards and counts the stall on the current section span.
+func (l *Logger) LogStall(idle time.Duration) {
+	l.inner.LogStall(idle)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.section != nil {
+		l.stalls++
+		l.section.SetAttributes(Int("ralphex.stalls", l.stalls))
+	}
+}
+
+// LogUsage forwards to the inner logger. usage is recorded on executor call spans.
+func (l *Logger) LogUsage(usage executor.Usage) {
+	l.inner.LogUsage(usage)
+}
+
+// LogCommits forwards and records the commit range on the current section span.
+func (l *Logger) LogCommits(commits processor.IterationCommits) {
+	l.inner.LogCommits(commits)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.section != nil {
+		l.section.SetAttributes(String("ralphex.commits.from", commits.From), String("ralphex.commits.to", commits.To))
+	}
+}
+
+// Path returns the inner logger's progress file path.
+func (l *Logger) Path() string {
+	return l.inner.Path()
+}
+
+// Finish ends all open spans, records the outcome on the run span and exports the remaining spans.
+// must be called exactly once after the runner returns.
+func (l *Logger) Finish(outcome processor.Outcome, runErr error) {
+	l.mu.Lock()
+	l.endSectionLocked()
+	if l.phase != nil {
+		l.phase.End(nil)
+		l.phase = nil
+	}
+	l.mu.Unlock()
+
+	l.run.SetAttributes(String("ralphex.outcome", string(outcome.Status)),
+		Int("ralphex.task_iterations", outcome.TaskIterations),
+		Int("ralphex.review_iterations", outcome.ReviewIterations),
+		Int("ralphex.codex_iterations", outcome.CodexIterations))
+	if outcome.PlanIterations > 0 {
+		l.run.SetAttributes(Int("ralphex.plan_iterations", outcome.PlanIterations))
+	}
+	l.run.End(runErr)
+	l.tracer.Close()
+}
+
+// WrapExecutor returns an executor recording each call of e as a span of the current section,
+// named after name, e.g. "claude". executors able to resume sessions stay able to.
+func (l *Logger) WrapExecutor(name string, e processor.Executor) processor.Executor {
+	traced := &tracedExecutor{name: name, inner: e, log: l}
+	if resumer, ok := e.(processor.SessionResumer); ok {
+		return &tracedResumer{tracedExecutor: traced, resumer: resumer}
+	}
+	return traced
+}
+
+// endSectionLocked ends the current section span, the caller holds l.mu.
+func (l *Logger) endSectionLocked() {
+	if l.section != nil {
+		l.section.End(nil)
+		l.section = nil
+	}
+	l.stalls = 0
+}
+
+// parentLocked returns the innermost open span, the caller holds l.mu.
+func (l *Logger) parentLocked() *Span {
+	switch {
+	case l.section != nil:
+		return l.section
+	case l.phase != nil:
+		return l.phase
+	default:
+		return l.run
+	}
+}
+
+// startCall starts the span of an executor call.
+func (l *Logger) startCall(name string, attrs ...Attr) *Span {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.tracer.Start(l.parentLocked(), name, attrs...)
+}
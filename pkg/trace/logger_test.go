@@ -0,0 +1,170 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/umputun/ralphex/pkg/executor"
+	"github.com/umputun/ralphex/pkg/processor"
+	"github.com/umputun/ralphex/pkg/processor/mocks"
+)
+
+func newTestLogger() (*Logger, *recordingExporter, *mocks.LoggerMock) {
+	inner := &mocks.LoggerMock{
+		SetPhaseFunc:     func(processor.Phase) {},
+		PrintFunc:        func(string, ...any) {},
+		PrintRawFunc:     func(string, ...any) {},
+		PrintSectionFunc: func(processor.Section) {},
+		PrintAlignedFunc: func(string) {},
+		LogQuestionFunc:  func(string, []string) {},
+		LogAnswerFunc:    func(string) {},
+		LogActivityFunc:  func(executor.Activity) {},
+		LogStallFunc:     func(time.Duration) {},
+		LogUsageFunc:     func(executor.Usage) {},
+		LogCommitsFunc:   func(processor.IterationCommits) {},
+		PathFunc:         func() string { return "progress-plan.txt" },
+	}
+	exp := &recordingExporter{}
+	return NewLogger(inner, NewTracer(exp), "docs/plans/plan.md", "feature", processor.ModeFull), exp, inner
+}
+
+func TestLogger_Spans(t *testing.T) {
+	l, exp, inner := newTestLogger()
+	claude := l.WrapExecutor("claude", &mocks.ExecutorMock{RunFunc: func(context.Context, string) executor.Result {
+		return executor.Result{Output: "done", Signal: "COMPLETED", SessionID: "s1",
+			Usage: executor.Usage{InputTokens: 100, OutputTokens: 20, CacheReadTokens: 5, CostUSD: 0.25}}
+	}})
+	codex := l.WrapExecutor("codex", &mocks.ExecutorMock{RunFunc: func(context.Context, string) executor.Result {
+		return executor.Result{Error: errors.New("codex not found")}
+	}})
+
+	l.SetPhase(processor.PhaseTask)
+	l.SetPhase(processor.PhaseTask) // same phase keeps the span
+	l.PrintSection(processor.NewTaskIterationSection(3))
+	l.LogStall(time.Minute)
+	l.LogStall(time.Minute)
+	l.LogCommits(processor.IterationCommits{From: "aaa", To: "bbb"})
+	claude.Run(context.Background(), "prompt")
+	l.SetPhase(processor.PhaseCodex)
+	l.PrintSection(processor.NewCodexIterationSection(1))
+	codex.Run(context.Background(), "review")
+	l.Finish(processor.Outcome{Status: processor.OutcomeFailed, TaskIterations: 1, CodexIterations: 1}, errors.New("codex failed"))
+
+	assert.Len(t, inner.SetPhaseCalls(), 3)
+	assert.Len(t, inner.PrintSectionCalls(), 2)
+	assert.Len(t, inner.LogStallCalls(), 2)
+	assert.Len(t, inner.LogCommitsCalls(), 1)
+
+	spans := exp.spans()
+	require.Len(t, spans, 7)
+	run, task, codexPhase := spans["ralphex run"], spans["phase task"], spans["phase codex"]
+	iter, call := spans["task iteration 3"], spans["claude run"]
+	codexIter, codexCall := spans["codex iteration 1"], spans["codex run"]
+
+	assert.Equal(t, run.SpanID, task.ParentSpanID)
+	assert.Equal(t, run.SpanID, codexPhase.ParentSpanID)
+	assert.Equal(t, task.SpanID, iter.ParentSpanID)
+	assert.Equal(t, iter.SpanID, call.ParentSpanID)
+	assert.Equal(t, codexPhase.SpanID, codexIter.ParentSpanID)
+	assert.Equal(t, codexIter.SpanID, codexCall.ParentSpanID)
+
+	assert.Equal(t, "docs/plans/plan.md", attr(run, "ralphex.plan"))
+	assert.Equal(t, "feature", attr(run, "ralphex.branch"))
+	assert.Equal(t, "full", attr(run, "ralphex.mode"))
+	assert.Equal(t, "failed", attr(run, "ralphex.outcome"))
+	assert.Equal(t, "1", attr(run, "ralphex.codex_iterations"))
+	assert.Empty(t, attr(run, "ralphex.plan_iterations"))
+	assert.Equal(t, spanStatus{Code: statusCodeError, Message: "codex failed"}, run.Status)
+
+	assert.Equal(t, "task", attr(task, "ralphex.phase"))
+	assert.Equal(t, "task", attr(iter, "ralphex.section.type"))
+	assert.Equal(t, "3", attr(iter, "ralphex.task"))
+	assert.Equal(t, "2", attr(iter, "ralphex.stalls"))
+	assert.Equal(t, "aaa", attr(iter, "ralphex.commits.from"))
+	assert.Equal(t, "bbb", attr(iter, "ralphex.commits.to"))
+	assert.Equal(t, "codex", attr(codexIter, "ralphex.section.type"))
+	assert.Empty(t, attr(codexIter, "ralphex.task"))
+	assert.Empty(t, attr(codexIter, "ralphex.stalls"), "stalls are counted per section")
+
+	assert.Equal(t, "claude", attr(call, "ralphex.executor"))
+	assert.Equal(t, "6", attr(call, "ralphex.prompt.size"))
+	assert.Equal(t, "4", attr(call, "ralphex.output.size"))
+	assert.Equal(t, "COMPLETED", attr(call, "ralphex.signal"))
+	assert.Equal(t, "s1", attr(call, "ralphex.session.id"))
+	assert.Equal(t, "100", attr(call, "gen_ai.usage.input_tokens"))
+	assert.Equal(t, "20", attr(call, "gen_ai.usage.output_tokens"))
+	assert.Equal(t, "5", attr(call, "ralphex.usage.cache_tokens"))
+	assert.Equal(t, "0.25", attr(call, "ralphex.usage.cost_usd"))
+	assert.Equal(t, "0", attr(call, "process.exit.code"))
+	assert.Equal(t, spanStatus{Code: statusCodeOK}, call.Status)
+
+	assert.Equal(t, "-1", attr(codexCall, "process.exit.code"))
+	assert.Empty(t, attr(codexCall, "gen_ai.usage.input_tokens"))
+	assert.Equal(t, spanStatus{Code: statusCodeError, Message: "codex not found"}, codexCall.Status)
+}
+
+func TestLogger_Passthrough(t *testing.T) {
+	l, _, inner := newTestLogger()
+	l.Print("a %d", 1)
+	l.PrintRaw("b")
+	l.PrintAligned("c")
+	l.LogQuestion("q?", []string{"x"})
+	l.LogAnswer("x")
+	l.LogActivity(executor.Activity{})
+	l.LogUsage(executor.Usage{InputTokens: 1})
+	assert.Equal(t, "progress-plan.txt", l.Path())
+	l.Finish(processor.Outcome{Status: processor.OutcomeSuccess}, nil)
+
+	assert.Len(t, inner.PrintCalls(), 1)
+	assert.Len(t, inner.PrintRawCalls(), 1)
+	assert.Len(t, inner.PrintAlignedCalls(), 1)
+	assert.Len(t, inner.LogQuestionCalls(), 1)
+	assert.Len(t, inner.LogAnswerCalls(), 1)
+	assert.Len(t, inner.LogActivityCalls(), 1)
+	assert.Len(t, inner.LogUsageCalls(), 1)
+}
+
+func TestLogger_WrapExecutor_Resumer(t *testing.T) {
+	l, exp, _ := newTestLogger()
+	plain := l.WrapExecutor("codex", &mocks.ExecutorMock{})
+	_, ok := plain.(processor.SessionResumer)
+	assert.False(t, ok, "plain executor stays plain")
+
+	resumable := struct {
+		*mocks.ExecutorMock
+		*mocks.SessionResumerMock
+	}{
+		&mocks.ExecutorMock{},
+		&mocks.SessionResumerMock{ResumeFunc: func(_ context.Context, sessionID, _ string) executor.Result {
+			return executor.Result{SessionID: sessionID, Output: "ok"}
+		}},
+	}
+	wrapped := l.WrapExecutor("claude", resumable)
+	resumer, ok := wrapped.(processor.SessionResumer)
+	require.True(t, ok, "resumer stays a resumer")
+
+	l.SetPhase(processor.PhaseReview)
+	res := resumer.Resume(context.Background(), "s1", "fix it")
+	assert.Equal(t, "ok", res.Output)
+	l.Finish(processor.Outcome{Status: processor.OutcomeSuccess}, nil)
+
+	call := exp.spans()["claude resume"]
+	assert.Equal(t, "s1", attr(call, "ralphex.session.resumed"))
+	assert.Equal(t, exp.spans()["phase review"].SpanID, call.ParentSpanID, "calls outside sections belong to the phase")
+}
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, 0, exitCode(nil))
+	assert.Equal(t, -1, exitCode(errors.New("killed")))
+
+	err := exec.Command("sh", "-c", "exit 3").Run()
+	require.Error(t, err)
+	assert.Equal(t, 3, exitCode(fmt.Errorf("claude: %w", err)))
+}
@@ -0,0 +1,239 @@
+// Package trace records OpenTelemetry-style spans of a run and exports them as OTLP/JSON.
+// a run span contains phase spans, which contain section (iteration) spans, which contain
+// the claude and codex calls made in them. spans are exported to a file, one OTLP/JSON export
+// request per line, and/or posted to an OTLP/HTTP collector.
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/umputun/ralphex/pkg/config"
+)
+
+// defaultTimeout limits how long a single export may take.
+const defaultTimeout = 10 * time.Second
+
+// batchSize is the number of ended spans buffered before they are exported.
+// spans are also exported whenever a root span or one of its children, e.g. a phase, ends.
+const batchSize = 64
+
+// queueSize is the number of pending batches buffered before new batches are dropped.
+const queueSize = 16
+
+// Exporter delivers encoded OTLP/JSON export requests to a single destination.
+type Exporter interface {
+	Name() string
+	Export(ctx context.Context, data []byte) error
+}
+
+// Attr is a span attribute. values are string, int, float64 or bool.
+type Attr struct {
+	Key   string
+	Value any
+}
+
+// String returns a string attribute.
+func String(key, value string) Attr { return Attr{Key: key, Value: value} }
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attr { return Attr{Key: key, Value: value} }
+
+// Float returns a floating point attribute.
+func Float(key string, value float64) Attr { return Attr{Key: key, Value: value} }
+
+// Bool returns a boolean attribute.
+func Bool(key string, value bool) Attr { return Attr{Key: key, Value: value} }
+
+// Tracer creates spans and exports them once they end.
+// exporting is asynchronous so slow collectors never block the execution loop.
+// Close exports the remaining spans and waits for in-flight exports.
+// Tracer is safe for concurrent use, review agents run in parallel.
+type Tracer struct {
+	mu        sync.Mutex
+	traceID   string
+	exporters []Exporter
+	pending   []*Span
+	queue     chan []*Span
+	done      chan struct{}
+	timeout   time.Duration
+	closed    bool
+	mask      func(string) string // applied to span error messages, nil keeps them as is
+}
+
+// New creates a Tracer with exporters built from application config.
+// returns a disabled tracer if neither trace_file nor trace_endpoint is set.
+func New(cfg *config.Config) *Tracer {
+	var exporters []Exporter
+	if cfg != nil && cfg.TraceFile != "" {
+		exporters = append(exporters, &FileExporter{Path: cfg.TraceFile})
+	}
+	if cfg != nil && cfg.TraceEndpoint != "" {
+		exporters = append(exporters, &HTTPExporter{URL: cfg.TraceEndpoint + "/v1/traces",
+			Client: &http.Client{Timeout: defaultTimeout}})
+	}
+	return NewTracer(exporters...)
+}
+
+// NewTracer creates a Tracer exporting to exporters. without exporters spans are discarded.
+func NewTracer(exporters ...Exporter) *Tracer {
+	t := &Tracer{traceID: newID(16), exporters: exporters, timeout: defaultTimeout}
+	if len(exporters) > 0 {
+		t.queue = make(chan []*Span, queueSize)
+		t.done = make(chan struct{})
+		go t.worker()
+	}
+	return t
+}
+
+// SetMasker sets the func applied to span error messages before export, e.g. to redact secrets.
+// error messages come straight from executors and the run, bypassing the masking logger.
+func (t *Tracer) SetMasker(mask func(string) string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.mask = mask
+}
+
+// Enabled returns true if the tracer has at least one exporter.
+func (t *Tracer) Enabled() bool {
+	return len(t.exporters) > 0
+}
+
+// Start starts a span named name. a nil parent starts a root span.
+func (t *Tracer) Start(parent *Span, name string, attrs ...Attr) *Span {
+	s := &Span{tracer: t, spanID: newID(8), name: name, start: time.Now(), attrs: attrs}
+	s.flush = parent == nil || parent.parentID == ""
+	if parent != nil {
+		s.parentID = parent.spanID
+	}
+	return s
+}
+
+// Close exports the spans ended so far and waits for all exports to finish.
+// spans ended after Close are discarded.
+func (t *Tracer) Close() {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return
+	}
+	t.closed = true
+	t.flushLocked()
+	t.mu.Unlock()
+	if t.queue != nil {
+		close(t.queue)
+		<-t.done
+	}
+}
+
+// end records an ended span and exports buffered spans if flush is set or the batch is full.
+func (t *Tracer) end(s *Span, flush bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed || t.queue == nil {
+		return
+	}
+	t.pending = append(t.pending, s)
+	if flush || len(t.pending) >= batchSize {
+		t.flushLocked()
+	}
+}
+
+// flushLocked queues buffered spans for export, the caller holds t.mu.
+func (t *Tracer) flushLocked() {
+	if len(t.pending) == 0 || t.queue == nil {
+		return
+	}
+	select {
+	case t.queue <- t.pending:
+	default:
+		log.Printf("[WARN] trace export queue full, dropping %d spans", len(t.pending))
+	}
+	t.pending = nil
+}
+
+// worker exports queued batches to all exporters until the queue is closed.
+func (t *Tracer) worker() {
+	defer close(t.done)
+	for spans := range t.queue {
+		data, err := t.encode(spans)
+		if err != nil {
+			log.Printf("[WARN] failed to encode %d spans: %v", len(spans), err)
+			continue
+		}
+		for _, e := range t.exporters {
+			ctx, cancel := context.WithTimeout(context.Background(), t.timeout)
+			if err := e.Export(ctx, data); err != nil {
+				log.Printf("[WARN] %s trace export failed: %v", e.Name(), err)
+			}
+			cancel()
+		}
+	}
+}
+
+// Span is a timed operation of the run. spans are created with Tracer.Start and exported after End.
+type Span struct {
+	tracer   *Tracer
+	spanID   string
+	parentID string
+	name     string
+	start    time.Time
+	end      time.Time
+	attrs    []Attr
+	errMsg   string
+	ended    bool
+	flush    bool // export right after the span ends, set for the run and phase spans
+}
+
+// SetAttributes adds attributes to the span, replacing attributes with the same key.
+// attributes set after End are ignored.
+func (s *Span) SetAttributes(attrs ...Attr) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	if s.ended {
+		return
+	}
+	for _, a := range attrs {
+		replaced := false
+		for i := range s.attrs {
+			if s.attrs[i].Key == a.Key {
+				s.attrs[i], replaced = a, true
+				break
+			}
+		}
+		if !replaced {
+			s.attrs = append(s.attrs, a)
+		}
+	}
+}
+
+// End ends the span, with error status if err is not nil. ending a span again does nothing.
+func (s *Span) End(err error) {
+	s.tracer.mu.Lock()
+	if s.ended {
+		s.tracer.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	if err != nil {
+		s.errMsg = err.Error()
+		if s.tracer.mask != nil {
+			s.errMsg = s.tracer.mask(s.errMsg)
+		}
+	}
+	s.tracer.mu.Unlock()
+	s.tracer.end(s, s.flush)
+}
+
+// newID returns a random hex-encoded id of n bytes, 16 for trace ids and 8 for span ids.
+func newID(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b) // never returns an error
+	return hex.EncodeToString(b)
+}
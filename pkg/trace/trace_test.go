@@ -0,0 +1,227 @@
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/umputun/ralphex/pkg/config"
+)
+
+// recordingExporter collects decoded export requests.
+type recordingExporter struct {
+	mu       sync.Mutex
+	requests []exportRequest
+	err      error
+}
+
+func (e *recordingExporter) Name() string { return "recording" }
+
+func (e *recordingExporter) Export(_ context.Context, data []byte) error {
+	var req exportRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.requests = append(e.requests, req)
+	return e.err
+}
+
+// spans returns all exported spans by name.
+func (e *recordingExporter) spans() map[string]otlpSpan {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	result := map[string]otlpSpan{}
+	for _, req := range e.requests {
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				for _, s := range ss.Spans {
+					result[s.Name] = s
+				}
+			}
+		}
+	}
+	return result
+}
+
+// attr returns the value of the span attribute key formatted as a string, empty if not set.
+func attr(s otlpSpan, key string) string {
+	for _, kv := range s.Attributes {
+		if kv.Key != key {
+			continue
+		}
+		switch {
+		case kv.Value.StringValue != nil:
+			return *kv.Value.StringValue
+		case kv.Value.IntValue != nil:
+			return *kv.Value.IntValue
+		case kv.Value.BoolValue != nil && *kv.Value.BoolValue:
+			return "true"
+		case kv.Value.BoolValue != nil:
+			return "false"
+		case kv.Value.DoubleValue != nil:
+			data, _ := json.Marshal(*kv.Value.DoubleValue)
+			return string(data)
+		}
+	}
+	return ""
+}
+
+func TestNew(t *testing.T) {
+	t.Run("disabled without config", func(t *testing.T) {
+		tr := New(&config.Config{})
+		assert.False(t, tr.Enabled())
+		s := tr.Start(nil, "run")
+		s.SetAttributes(String("k", "v"))
+		s.End(nil)
+		tr.Close()
+	})
+
+	t.Run("file and endpoint", func(t *testing.T) {
+		tr := New(&config.Config{TraceFile: "/tmp/trace.jsonl", TraceEndpoint: "http://localhost:4318"})
+		defer tr.Close()
+		require.Len(t, tr.exporters, 2)
+		assert.Equal(t, "/tmp/trace.jsonl", tr.exporters[0].(*FileExporter).Path)
+		assert.Equal(t, "http://localhost:4318/v1/traces", tr.exporters[1].(*HTTPExporter).URL)
+		assert.Len(t, tr.traceID, 32)
+	})
+}
+
+func TestTracer_Spans(t *testing.T) {
+	exp := &recordingExporter{}
+	tr := NewTracer(exp)
+
+	run := tr.Start(nil, "run", String("ralphex.plan", "plan.md"))
+	phase := tr.Start(run, "phase task")
+	iter := tr.Start(phase, "task iteration 1", Int("ralphex.task", 1))
+	iter.SetAttributes(Int("ralphex.stalls", 1), Bool("flag", true))
+	iter.SetAttributes(Int("ralphex.stalls", 2), Float("cost", 0.5))
+	iter.End(errors.New("boom"))
+	iter.End(nil) // second end is ignored
+	iter.SetAttributes(String("late", "ignored"))
+	phase.End(nil)
+	run.End(nil)
+	tr.Close()
+
+	spans := exp.spans()
+	require.Len(t, spans, 3)
+	assert.Empty(t, spans["run"].ParentSpanID)
+	assert.Equal(t, spans["run"].SpanID, spans["phase task"].ParentSpanID)
+	assert.Equal(t, spans["phase task"].SpanID, spans["task iteration 1"].ParentSpanID)
+	for _, s := range spans {
+		assert.Equal(t, tr.traceID, s.TraceID)
+		assert.Equal(t, spanKindInternal, s.Kind)
+		assert.Len(t, s.SpanID, 16)
+	}
+
+	it := spans["task iteration 1"]
+	assert.Equal(t, spanStatus{Code: statusCodeError, Message: "boom"}, it.Status)
+	assert.Equal(t, "1", attr(it, "ralphex.task"))
+	assert.Equal(t, "2", attr(it, "ralphex.stalls"))
+	assert.Equal(t, "true", attr(it, "flag"))
+	assert.Equal(t, "0.5", attr(it, "cost"))
+	assert.Empty(t, attr(it, "late"))
+	assert.Len(t, it.Attributes, 4)
+	assert.Equal(t, spanStatus{Code: statusCodeOK}, spans["run"].Status)
+	assert.Equal(t, "plan.md", attr(spans["run"], "ralphex.plan"))
+	assert.NotEmpty(t, it.StartTimeUnixNano)
+	assert.NotEmpty(t, it.EndTimeUnixNano)
+
+	require.NotEmpty(t, exp.requests)
+	res := exp.requests[0].ResourceSpans[0]
+	assert.Equal(t, "ralphex", *res.Resource.Attributes[0].Value.StringValue)
+	assert.Equal(t, "ralphex", res.ScopeSpans[0].Scope.Name)
+}
+
+func TestTracer_SetMasker(t *testing.T) {
+	exp := &recordingExporter{}
+	tr := NewTracer(exp)
+	tr.SetMasker(func(s string) string { return strings.ReplaceAll(s, "secret-value", "***") })
+	tr.Start(nil, "run").End(errors.New("failed with secret-value"))
+	tr.Close()
+
+	assert.Equal(t, spanStatus{Code: statusCodeError, Message: "failed with ***"}, exp.spans()["run"].Status)
+}
+
+func TestTracer_Batching(t *testing.T) {
+	exp := &recordingExporter{}
+	tr := NewTracer(exp)
+	run := tr.Start(nil, "run")
+	phase := tr.Start(run, "phase task")
+	iter := tr.Start(phase, "iteration")
+	for range batchSize - 1 {
+		tr.Start(iter, "call").End(nil)
+	}
+	iter.End(nil) // fills the batch
+	tr.Start(iter, "late call").End(nil)
+	tr.Close()
+	run.End(nil) // ended after close, discarded
+
+	exp.mu.Lock()
+	defer exp.mu.Unlock()
+	require.Len(t, exp.requests, 2, "full batch and remaining spans on close")
+	assert.Len(t, exp.requests[0].ResourceSpans[0].ScopeSpans[0].Spans, batchSize)
+	assert.Len(t, exp.requests[1].ResourceSpans[0].ScopeSpans[0].Spans, 1)
+}
+
+func TestTracer_ExportError(t *testing.T) {
+	failing := &recordingExporter{err: errors.New("collector down")}
+	ok := &recordingExporter{}
+	tr := NewTracer(failing, ok)
+	tr.Start(nil, "run").End(nil)
+	tr.Close()
+	tr.Close() // second close does nothing
+	assert.Len(t, ok.spans(), 1, "failing exporter doesn't affect others")
+}
+
+func TestFileExporter_Export(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "trace.jsonl")
+	e := &FileExporter{Path: path}
+	require.NoError(t, e.Export(context.Background(), []byte(`{"a":1}`)))
+	require.NoError(t, e.Export(context.Background(), []byte(`{"a":2}`)))
+
+	data, err := os.ReadFile(path) //nolint:gosec // test file
+	require.NoError(t, err)
+	assert.Equal(t, "{\"a\":1}\n{\"a\":2}\n", string(data))
+
+	t.Run("unwritable path", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, os.WriteFile(file, nil, 0o600))
+		e := &FileExporter{Path: filepath.Join(file, "trace.jsonl")}
+		require.Error(t, e.Export(context.Background(), []byte("{}")))
+	})
+}
+
+func TestHTTPExporter_Export(t *testing.T) {
+	var gotBody, gotType, gotPath string
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody, gotType, gotPath = string(body), r.Header.Get("Content-Type"), r.URL.Path
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	e := &HTTPExporter{URL: srv.URL + "/v1/traces", Client: srv.Client()}
+	require.NoError(t, e.Export(context.Background(), []byte(`{"resourceSpans":[]}`)))
+	assert.JSONEq(t, `{"resourceSpans":[]}`, gotBody)
+	assert.Equal(t, "application/json", gotType)
+	assert.Equal(t, "/v1/traces", gotPath)
+
+	status = http.StatusBadRequest
+	err := e.Export(context.Background(), []byte("{}"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected status 400")
+}
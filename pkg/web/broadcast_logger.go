@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
+	"github.com/umputun/ralphex/pkg/executor"
 	"github.com/umputun/ralphex/pkg/processor"
 )
 
@@ -110,6 +112,30 @@ func (b *BroadcastLogger) LogAnswer(answer string) {
 	b.broadcast(NewOutputEvent(b.phase, "ANSWER: "+answer))
 }
 
+// LogActivity logs a tool-use activity line and broadcasts it as an activity event.
+func (b *BroadcastLogger) LogActivity(activity executor.Activity) {
+	b.inner.LogActivity(activity)
+	b.broadcast(NewActivityEvent(b.phase, activity))
+}
+
+// LogStall logs a killed stalled process and broadcasts it as a stall event.
+func (b *BroadcastLogger) LogStall(idle time.Duration) {
+	b.inner.LogStall(idle)
+	b.broadcast(NewStallEvent(b.phase, idle))
+}
+
+// LogUsage logs token usage of a claude run and broadcasts it as a usage event.
+func (b *BroadcastLogger) LogUsage(usage executor.Usage) {
+	b.inner.LogUsage(usage)
+	b.broadcast(NewUsageEvent(b.phase, usage))
+}
+
+// LogCommits logs the commit range of an iteration and broadcasts it as a commits event.
+func (b *BroadcastLogger) LogCommits(commits processor.IterationCommits) {
+	b.inner.LogCommits(commits)
+	b.broadcast(NewCommitsEvent(b.phase, commits))
+}
+
 // Path returns the progress file path.
 func (b *BroadcastLogger) Path() string {
 	return b.inner.Path()
@@ -2,10 +2,12 @@ package web
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/umputun/ralphex/pkg/executor"
 	"github.com/umputun/ralphex/pkg/processor"
 	"github.com/umputun/ralphex/pkg/processor/mocks"
 )
@@ -129,6 +131,63 @@ func TestBroadcastLogger_PrintAligned(t *testing.T) {
 	assert.Equal(t, "aligned text", mockLogger.PrintAlignedCalls()[0].Text)
 }
 
+func TestBroadcastLogger_LogActivity(t *testing.T) {
+	mockLogger := &mocks.LoggerMock{
+		LogActivityFunc: func(executor.Activity) {},
+	}
+	session := NewSession("test", "/tmp/test.txt")
+	defer session.Close()
+	bl := NewBroadcastLogger(mockLogger, session)
+
+	bl.LogActivity(executor.Activity{Tool: "Bash", Detail: "go test ./..."})
+
+	require.Len(t, mockLogger.LogActivityCalls(), 1)
+	assert.Equal(t, "Bash", mockLogger.LogActivityCalls()[0].Activity.Tool)
+}
+
+func TestBroadcastLogger_LogStall(t *testing.T) {
+	mockLogger := &mocks.LoggerMock{
+		LogStallFunc: func(time.Duration) {},
+	}
+	session := NewSession("test", "/tmp/test.txt")
+	defer session.Close()
+	bl := NewBroadcastLogger(mockLogger, session)
+
+	bl.LogStall(time.Minute)
+
+	require.Len(t, mockLogger.LogStallCalls(), 1)
+	assert.Equal(t, time.Minute, mockLogger.LogStallCalls()[0].Idle)
+}
+
+func TestBroadcastLogger_LogUsage(t *testing.T) {
+	mockLogger := &mocks.LoggerMock{
+		LogUsageFunc: func(executor.Usage) {},
+	}
+	session := NewSession("test", "/tmp/test.txt")
+	defer session.Close()
+	bl := NewBroadcastLogger(mockLogger, session)
+
+	bl.LogUsage(executor.Usage{InputTokens: 5, OutputTokens: 1})
+
+	require.Len(t, mockLogger.LogUsageCalls(), 1)
+	assert.Equal(t, 5, mockLogger.LogUsageCalls()[0].Usage.InputTokens)
+}
+
+func TestBroadcastLogger_LogCommits(t *testing.T) {
+	mockLogger := &mocks.LoggerMock{
+		LogCommitsFunc: func(processor.IterationCommits) {},
+	}
+	session := NewSession("test", "/tmp/test.txt")
+	defer session.Close()
+	bl := NewBroadcastLogger(mockLogger, session)
+
+	commits := processor.IterationCommits{Iteration: 1, Label: "task iteration 1", From: "1a2b3c4", To: "4d5e6f7"}
+	bl.LogCommits(commits)
+
+	require.Len(t, mockLogger.LogCommitsCalls(), 1)
+	assert.Equal(t, commits, mockLogger.LogCommitsCalls()[0].Commits)
+}
+
 func TestBroadcastLogger_Path(t *testing.T) {
 	mockLogger := &mocks.LoggerMock{
 		PathFunc: func() string { return "/test/progress.txt" },
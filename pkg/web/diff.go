@@ -0,0 +1,143 @@
+package web
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/umputun/ralphex/pkg/git"
+	"github.com/umputun/ralphex/pkg/processor"
+	"github.com/umputun/ralphex/pkg/progress"
+)
+
+// IterationDiff is the /api/sessions/{id}/diff response for a single iteration.
+type IterationDiff struct {
+	processor.IterationCommits
+	Diff string `json:"diff"` // unified diff From..To
+}
+
+// handleSessionDiff serves the changes of a session's iteration.
+// with ?iteration=N returns the iteration's commit range and unified diff, read from the git repository
+// the progress file is in. without it, lists the iterations that have recorded commits.
+func (s *Server) handleSessionDiff(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	sessionID := r.PathValue("id")
+	session := s.sessionByID(sessionID)
+	if session == nil {
+		http.Error(w, "session not found: "+sessionID, http.StatusNotFound)
+		return
+	}
+
+	iterations := loadIterationCommits(session.Path)
+	param := r.URL.Query().Get("iteration")
+	if param == "" {
+		writeJSON(w, iterations)
+		return
+	}
+
+	iteration, err := strconv.Atoi(param)
+	if err != nil || iteration < 1 {
+		http.Error(w, fmt.Sprintf("invalid iteration %q", param), http.StatusBadRequest)
+		return
+	}
+	var commits *processor.IterationCommits
+	for i := range iterations {
+		if iterations[i].Iteration == iteration {
+			commits = &iterations[i]
+			break
+		}
+	}
+	if commits == nil {
+		http.Error(w, fmt.Sprintf("no commits recorded for iteration %d", iteration), http.StatusNotFound)
+		return
+	}
+
+	// sessions run in the repository root, where the progress file is written
+	repo, err := git.Open(filepath.Dir(session.Path))
+	if err != nil {
+		log.Printf("[WARN] failed to open repository for session %s: %v", sessionID, err)
+		http.Error(w, "unable to open git repository of session", http.StatusInternalServerError)
+		return
+	}
+	diff, err := repo.Diff(commits.From, commits.To)
+	if err != nil {
+		log.Printf("[WARN] failed to diff iteration %d of session %s: %v", iteration, sessionID, err)
+		http.Error(w, "unable to diff iteration: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, IterationDiff{IterationCommits: *commits, Diff: diff})
+}
+
+// sessionByID returns the session with id. in single-session mode the id is ignored, like for /events.
+func (s *Server) sessionByID(id string) *Session {
+	if s.sm == nil {
+		return s.session
+	}
+	return s.sm.Get(id)
+}
+
+// writeJSON writes v as a JSON response.
+func writeJSON(w http.ResponseWriter, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("[WARN] failed to encode response: %v", err)
+		http.Error(w, "unable to encode response", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+// loadIterationCommits returns the commit ranges recorded in the progress file at path, in order.
+// the progress event file is used when present, otherwise the COMMITS lines of the text log.
+func loadIterationCommits(path string) []processor.IterationCommits {
+	commits := []processor.IterationCommits{}
+	if hasEventsFile(path) {
+		f, err := os.Open(progress.EventsPath(path)) //nolint:gosec // path from user-controlled glob pattern, acceptable for session discovery
+		if err == nil {
+			defer f.Close()
+			scanner := bufio.NewScanner(f)
+			scanner.Buffer(make([]byte, 0, 64*1024), maxScannerBuffer)
+			for scanner.Scan() {
+				rec, err := progress.ParseRecord(scanner.Bytes())
+				if err == nil && rec.Type == progress.RecordCommits && rec.Commits != nil {
+					commits = append(commits, *rec.Commits)
+				}
+			}
+			return commits
+		}
+	}
+
+	f, err := os.Open(path) //nolint:gosec // path from user-controlled glob pattern, acceptable for session discovery
+	if err != nil {
+		return commits
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxScannerBuffer)
+	for scanner.Scan() {
+		m := timestampRegex.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		text, ok := strings.CutPrefix(m[2], commitsPrefix)
+		if !ok {
+			continue
+		}
+		if c, ok := processor.ParseIterationCommits(text); ok {
+			commits = append(commits, c)
+		}
+	}
+	return commits
+}
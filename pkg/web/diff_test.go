@@ -0,0 +1,158 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/umputun/ralphex/pkg/git"
+	"github.com/umputun/ralphex/pkg/processor"
+	"github.com/umputun/ralphex/pkg/progress"
+)
+
+func TestServer_HandleSessionDiff(t *testing.T) {
+	dir := t.TempDir()
+	_, err := gogit.PlainInit(dir, false)
+	require.NoError(t, err)
+	repo, err := git.Open(dir)
+	require.NoError(t, err)
+
+	commit := func(content string) string {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte(content), 0o600))
+		require.NoError(t, repo.Add("main.go"))
+		require.NoError(t, repo.Commit("update"))
+		hash, err := repo.HeadHash()
+		require.NoError(t, err)
+		return hash
+	}
+	from := commit("package main\n")
+	to := commit("package main\n\nfunc main() {}\n")
+
+	progressPath := filepath.Join(dir, "progress-diff.txt")
+	content := fmt.Sprintf(`# Ralphex Progress Log
+Plan: docs/plan.md
+Branch: main
+Mode: full
+Started: 2026-01-22 10:00:00
+------------------------------------------------------------
+
+--- task iteration 1 ---
+[26-01-22 10:00:01] working
+[26-01-22 10:00:02] COMMITS: iteration 1 (task iteration 1): %s..%s
+[26-01-22 10:00:03] COMMITS: iteration 2 (task iteration 2): %s..%s
+`, from, to, "ab12cd3", "ef45ab6")
+	require.NoError(t, os.WriteFile(progressPath, []byte(content), 0o600))
+
+	sm := NewSessionManager()
+	defer sm.Close()
+	_, err = sm.Discover(dir)
+	require.NoError(t, err)
+	srv, err := NewServerWithSessions(ServerConfig{Port: 8080}, sm)
+	require.NoError(t, err)
+	sessionID := sessionIDFromPath(progressPath)
+
+	get := func(t *testing.T, method, id, query string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, "/api/sessions/"+id+"/diff"+query, http.NoBody)
+		req.SetPathValue("id", id)
+		w := httptest.NewRecorder()
+		srv.handleSessionDiff(w, req)
+		return w
+	}
+
+	t.Run("lists iterations", func(t *testing.T) {
+		w := get(t, http.MethodGet, sessionID, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		var iterations []processor.IterationCommits
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &iterations))
+		require.Len(t, iterations, 2)
+		assert.Equal(t, processor.IterationCommits{Iteration: 1, Label: "task iteration 1", From: from, To: to}, iterations[0])
+		assert.Equal(t, 2, iterations[1].Iteration)
+	})
+
+	t.Run("iteration diff", func(t *testing.T) {
+		w := get(t, http.MethodGet, sessionID, "?iteration=1")
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp IterationDiff
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 1, resp.Iteration)
+		assert.Equal(t, from, resp.From)
+		assert.Equal(t, to, resp.To)
+		assert.Contains(t, resp.Diff, "diff --git a/main.go b/main.go\n")
+		assert.Contains(t, resp.Diff, "+func main() {}\n")
+	})
+
+	t.Run("unknown commits", func(t *testing.T) {
+		w := get(t, http.MethodGet, sessionID, "?iteration=2")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "unable to diff iteration")
+	})
+
+	t.Run("missing iteration", func(t *testing.T) {
+		w := get(t, http.MethodGet, sessionID, "?iteration=3")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "no commits recorded for iteration 3")
+	})
+
+	t.Run("invalid iteration", func(t *testing.T) {
+		w := get(t, http.MethodGet, sessionID, "?iteration=first")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = get(t, http.MethodGet, sessionID, "?iteration=0")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("unknown session", func(t *testing.T) {
+		w := get(t, http.MethodGet, "nope", "?iteration=1")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		w := get(t, http.MethodPost, sessionID, "")
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
+
+func TestLoadIterationCommits(t *testing.T) {
+	commits := processor.IterationCommits{Iteration: 1, Label: "claude review 0: all findings", From: "1a2b3c4", To: "4d5e6f7"}
+
+	t.Run("text log", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "progress-text.txt")
+		content := "# Ralphex Progress Log\n" +
+			"[26-01-22 10:00:01] COMMITS: " + commits.String() + "\n" +
+			"[26-01-22 10:00:02] COMMITS: garbage\n" +
+			"COMMITS: iteration 2 (untimed): 1a2b3c4..4d5e6f7\n"
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		assert.Equal(t, []processor.IterationCommits{commits}, loadIterationCommits(path))
+	})
+
+	t.Run("events file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "progress-events.txt")
+		require.NoError(t, os.WriteFile(path, []byte("# Ralphex Progress Log\n"), 0o600))
+		lines := []progress.Record{
+			{Type: progress.RecordOutput, Text: "working"},
+			{Type: progress.RecordCommits, Commits: &commits},
+		}
+		var data []byte
+		for _, rec := range lines {
+			line, err := json.Marshal(rec)
+			require.NoError(t, err)
+			data = append(append(data, line...), '\n')
+		}
+		require.NoError(t, os.WriteFile(progress.EventsPath(path), data, 0o600))
+		assert.Equal(t, []processor.IterationCommits{commits}, loadIterationCommits(path))
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		assert.Empty(t, loadIterationCommits(filepath.Join(t.TempDir(), "progress-missing.txt")))
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/tmaxmax/go-sse"
+	"github.com/umputun/ralphex/pkg/executor"
 	"github.com/umputun/ralphex/pkg/processor"
 )
 
@@ -23,6 +24,10 @@ const (
 	EventTypeTaskStart      EventType = "task_start"      // task execution started
 	EventTypeTaskEnd        EventType = "task_end"        // task execution ended
 	EventTypeIterationStart EventType = "iteration_start" // review/codex iteration started
+	EventTypeActivity       EventType = "activity"        // claude tool call (edit, bash, task, ...)
+	EventTypeStall          EventType = "stall"           // claude/codex killed after producing no output
+	EventTypeUsage          EventType = "usage"           // token usage and cost of a claude run
+	EventTypeCommits        EventType = "commits"         // commit range produced by a task or review iteration
 )
 
 // Event represents a single event to be streamed to web clients.
@@ -35,6 +40,11 @@ type Event struct {
 	Signal       string          `json:"signal,omitempty"`
 	TaskNum      int             `json:"task_num,omitempty"`      // 1-based task index from plan (matches plan.tasks[].number)
 	IterationNum int             `json:"iteration_num,omitempty"` // 1-based iteration index for review/codex phases
+	Tool         string          `json:"tool,omitempty"`          // activity events: tool name
+	File         string          `json:"file,omitempty"`          // activity events: file touched by the tool
+	Modifies     bool            `json:"modifies,omitempty"`      // activity events: tool changes the file
+	FromCommit   string          `json:"from_commit,omitempty"`   // commits events: HEAD before the iteration
+	ToCommit     string          `json:"to_commit,omitempty"`     // commits events: HEAD after the iteration
 }
 
 // NewOutputEvent creates an output event with current timestamp.
@@ -122,6 +132,54 @@ func NewIterationStartEvent(phase processor.Phase, iterationNum int, text string
 	}
 }
 
+// NewActivityEvent creates a tool-use activity event.
+func NewActivityEvent(phase processor.Phase, activity executor.Activity) Event {
+	return Event{
+		Type:      EventTypeActivity,
+		Phase:     phase,
+		Text:      activity.String(),
+		Tool:      activity.Tool,
+		File:      activity.File(),
+		Modifies:  activity.Modifies(),
+		Timestamp: time.Now(),
+	}
+}
+
+// NewStallEvent creates an event for a process killed after idle time without output.
+func NewStallEvent(phase processor.Phase, idle time.Duration) Event {
+	return Event{
+		Type:      EventTypeStall,
+		Phase:     phase,
+		Text:      fmt.Sprintf("%sno output for %s, process killed", stallPrefix, idle),
+		Timestamp: time.Now(),
+	}
+}
+
+// NewUsageEvent creates an event for token usage and cost reported by a claude run.
+func NewUsageEvent(phase processor.Phase, usage executor.Usage) Event {
+	return Event{
+		Type:      EventTypeUsage,
+		Phase:     phase,
+		Text:      usagePrefix + usage.String(),
+		Timestamp: time.Now(),
+	}
+}
+
+// NewCommitsEvent creates an event for the commit range of an iteration.
+// IterationNum is the run-wide iteration number used by the diff endpoint.
+func NewCommitsEvent(phase processor.Phase, commits processor.IterationCommits) Event {
+	return Event{
+		Type:         EventTypeCommits,
+		Phase:        phase,
+		Text:         commitsPrefix + commits.String(),
+		Timestamp:    time.Now(),
+		IterationNum: commits.Iteration,
+		Section:      commits.Label,
+		FromCommit:   commits.From,
+		ToCommit:     commits.To,
+	}
+}
+
 // MarshalJSON implements json.Marshaler for SSE streaming.
 // this allows Event to be used directly with json.Marshal.
 func (e Event) MarshalJSON() ([]byte, error) {
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/umputun/ralphex/pkg/executor"
 	"github.com/umputun/ralphex/pkg/processor"
 )
 
@@ -128,6 +129,8 @@ func TestEventType_Constants(t *testing.T) {
 	assert.Equal(t, EventTypeTaskStart, EventType("task_start"))
 	assert.Equal(t, EventTypeTaskEnd, EventType("task_end"))
 	assert.Equal(t, EventTypeIterationStart, EventType("iteration_start"))
+	assert.Equal(t, EventTypeActivity, EventType("activity"))
+	assert.Equal(t, EventTypeStall, EventType("stall"))
 }
 
 func TestNewTaskStartEvent(t *testing.T) {
@@ -164,6 +167,33 @@ func TestNewIterationStartEvent(t *testing.T) {
 	assert.Zero(t, e.TaskNum)
 }
 
+func TestNewActivityEvent(t *testing.T) {
+	t.Run("file tool", func(t *testing.T) {
+		e := NewActivityEvent(processor.PhaseTask, executor.Activity{Tool: "Write", Detail: "pkg/foo.go"})
+
+		assert.Equal(t, EventTypeActivity, e.Type)
+		assert.Equal(t, processor.PhaseTask, e.Phase)
+		assert.Equal(t, "Write pkg/foo.go", e.Text)
+		assert.Equal(t, "Write", e.Tool)
+		assert.Equal(t, "pkg/foo.go", e.File)
+		assert.True(t, e.Modifies)
+	})
+
+	t.Run("non-file tool", func(t *testing.T) {
+		e := NewActivityEvent(processor.PhaseReview, executor.Activity{Tool: "Task", Detail: "quality agent"})
+
+		assert.Equal(t, "Task: quality agent", e.Text)
+		assert.Equal(t, "Task", e.Tool)
+		assert.Empty(t, e.File)
+		assert.False(t, e.Modifies)
+
+		data, err := json.Marshal(e)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"tool":"Task"`)
+		assert.NotContains(t, string(data), `"file"`)
+	})
+}
+
 func TestEvent_JSON_TaskAndIterationFields(t *testing.T) {
 	t.Run("task event includes task_num", func(t *testing.T) {
 		e := NewTaskStartEvent(processor.PhaseTask, 7, "task iteration 7")
@@ -255,3 +285,31 @@ func TestEvent_ToSSEMessage(t *testing.T) {
 		assert.Contains(t, string(data), "task_start")
 	})
 }
+
+func TestNewStallEvent(t *testing.T) {
+	e := NewStallEvent(processor.PhaseCodex, 30*time.Minute)
+
+	assert.Equal(t, EventTypeStall, e.Type)
+	assert.Equal(t, processor.PhaseCodex, e.Phase)
+	assert.Equal(t, "STALL: no output for 30m0s, process killed", e.Text)
+}
+
+func TestNewUsageEvent(t *testing.T) {
+	e := NewUsageEvent(processor.PhaseTask, executor.Usage{InputTokens: 1200, OutputTokens: 350, CostUSD: 0.5})
+
+	assert.Equal(t, EventTypeUsage, e.Type)
+	assert.Equal(t, processor.PhaseTask, e.Phase)
+	assert.Equal(t, "USAGE: 1200 in, 350 out, $0.5000", e.Text)
+}
+
+func TestNewCommitsEvent(t *testing.T) {
+	e := NewCommitsEvent(processor.PhaseTask, processor.IterationCommits{Iteration: 2, Label: "task iteration 2", From: "1a2b3c4", To: "4d5e6f7"})
+
+	assert.Equal(t, EventTypeCommits, e.Type)
+	assert.Equal(t, processor.PhaseTask, e.Phase)
+	assert.Equal(t, "COMMITS: iteration 2 (task iteration 2): 1a2b3c4..4d5e6f7", e.Text)
+	assert.Equal(t, 2, e.IterationNum)
+	assert.Equal(t, "task iteration 2", e.Section)
+	assert.Equal(t, "1a2b3c4", e.FromCommit)
+	assert.Equal(t, "4d5e6f7", e.ToCommit)
+}
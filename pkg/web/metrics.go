@@ -0,0 +1,371 @@
+package web
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/umputun/ralphex/pkg/executor"
+	"github.com/umputun/ralphex/pkg/processor"
+)
+
+// taskDurationBuckets are the upper bounds, in seconds, of the task duration histogram.
+var taskDurationBuckets = []float64{60, 300, 600, 1200, 1800, 3600, 7200}
+
+// iterationSectionRegex matches section names of task, review, codex and plan iterations.
+var iterationSectionRegex = regexp.MustCompile(`(?i)^(task iteration|claude review|codex iteration|plan iteration) \d+`)
+
+// sessionMetrics are the metrics of one session, collected from the events of its progress file.
+type sessionMetrics struct {
+	iterations    map[processor.Phase]int // iteration sections per phase
+	taskDurations []time.Duration         // durations of finished tasks
+	signals       map[string]int          // signal counts, e.g. COMPLETED, FAILED, REVIEW_DONE
+	errors        int                     // ERROR lines
+	stalls        int                     // processes killed for producing no output
+	usage         executor.Usage          // summed token usage and cost, cache tokens are in CacheReadTokens
+	outcome       processor.Outcome       // final outcome of completed runs, empty Status if unknown
+
+	taskStart time.Time // start of the running task, zero if none
+}
+
+// newSessionMetrics creates empty session metrics.
+func newSessionMetrics() *sessionMetrics {
+	return &sessionMetrics{iterations: map[processor.Phase]int{}, signals: map[string]int{}}
+}
+
+// Publish updates the metrics with a session event.
+func (m *sessionMetrics) Publish(e Event) error {
+	switch e.Type {
+	case EventTypeSection:
+		// task ends with the next task or when the run moves on to another phase
+		if iterationSectionRegex.MatchString(e.Section) {
+			m.iterations[e.Phase]++
+		}
+		if e.Phase != processor.PhaseTask {
+			m.endTask(e.Timestamp)
+		}
+	case EventTypeTaskStart:
+		m.endTask(e.Timestamp)
+		m.taskStart = e.Timestamp
+	case EventTypeTaskEnd:
+		m.endTask(e.Timestamp)
+	case EventTypeSignal:
+		if e.Signal != "" {
+			m.signals[e.Signal]++
+		}
+	case EventTypeError:
+		m.errors++
+	case EventTypeStall:
+		m.stalls++
+	case EventTypeUsage:
+		if u, ok := parseUsage(e.Text); ok {
+			m.usage.InputTokens += u.InputTokens
+			m.usage.OutputTokens += u.OutputTokens
+			m.usage.CacheReadTokens += u.CacheReadTokens
+			m.usage.CostUSD += u.CostUSD
+		}
+	default:
+		// other events don't affect metrics
+	}
+	return nil
+}
+
+// endTask records the duration of the running task, if any.
+func (m *sessionMetrics) endTask(ts time.Time) {
+	if m.taskStart.IsZero() {
+		return
+	}
+	if ts.After(m.taskStart) {
+		m.taskDurations = append(m.taskDurations, ts.Sub(m.taskStart))
+	}
+	m.taskStart = time.Time{}
+}
+
+// executorError returns the executor that failed the run, "claude" or "codex", or empty if the run
+// didn't fail because of an executor error.
+func (m *sessionMetrics) executorError() string {
+	if m.outcome.Status != processor.OutcomeFailed {
+		return ""
+	}
+	return m.outcome.Executor
+}
+
+// parseUsage parses a "USAGE: 1200 in, 350 out, 8000 cached, $0.0420" line written by LogUsage.
+// cached tokens are returned in CacheReadTokens.
+func parseUsage(text string) (executor.Usage, bool) {
+	s, ok := strings.CutPrefix(text, usagePrefix)
+	if !ok {
+		return executor.Usage{}, false
+	}
+	var u executor.Usage
+	for part := range strings.SplitSeq(s, ", ") {
+		if cost, ok := strings.CutPrefix(part, "$"); ok {
+			v, err := strconv.ParseFloat(cost, 64)
+			if err != nil {
+				return executor.Usage{}, false
+			}
+			u.CostUSD = v
+			continue
+		}
+		num, unit, _ := strings.Cut(part, " ")
+		n, err := strconv.Atoi(num)
+		if err != nil {
+			return executor.Usage{}, false
+		}
+		switch unit {
+		case "in":
+			u.InputTokens = n
+		case "out":
+			u.OutputTokens = n
+		case "cached":
+			u.CacheReadTokens = n
+		default:
+			return executor.Usage{}, false
+		}
+	}
+	return u, true
+}
+
+// cachedMetrics are the metrics of a progress file, valid while the file's size and time are unchanged.
+type cachedMetrics struct {
+	modTime time.Time
+	size    int64
+	metrics *sessionMetrics
+}
+
+// metricsSession is a session with its state and metrics at scrape time.
+type metricsSession struct {
+	id       string
+	active   bool
+	modified time.Time // progress file modification time, the last time the session wrote anything
+	metrics  *sessionMetrics
+}
+
+// handleMetrics serves session metrics in the Prometheus text exposition format.
+// metrics are collected from the progress files of all sessions, running and completed.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	writeMetrics(w, s.collectMetrics())
+}
+
+// collectMetrics returns the metrics of all sessions sorted by id.
+// progress files are only re-read when they changed since the previous scrape.
+func (s *Server) collectMetrics() []metricsSession {
+	var sessions []*Session
+	if s.sm != nil {
+		sessions = s.sm.All()
+	} else if s.session != nil {
+		sessions = []*Session{s.session}
+	}
+
+	s.metricsMu.Lock()
+	defer s.metricsMu.Unlock()
+	cache := make(map[string]cachedMetrics, len(sessions))
+	result := make([]metricsSession, 0, len(sessions))
+	for _, session := range sessions {
+		info, err := os.Stat(session.Path)
+		if err != nil {
+			continue
+		}
+		entry, ok := s.metricsCache[session.Path]
+		if !ok || !entry.modTime.Equal(info.ModTime()) || entry.size != info.Size() {
+			entry = cachedMetrics{modTime: info.ModTime(), size: info.Size(), metrics: loadSessionMetrics(session.Path)}
+		}
+		cache[session.Path] = entry
+
+		active := session.GetState() == SessionStateActive
+		if s.sm == nil {
+			// single-session mode doesn't track state, the running executor holds the lock
+			active, _ = IsActive(session.Path)
+		}
+		result = append(result, metricsSession{id: session.ID, active: active, modified: info.ModTime(), metrics: entry.metrics})
+	}
+	s.metricsCache = cache // drops sessions that are gone
+
+	sort.Slice(result, func(i, j int) bool { return result[i].id < result[j].id })
+	return result
+}
+
+// loadSessionMetrics collects the metrics of the progress file at path.
+func loadSessionMetrics(path string) *sessionMetrics {
+	m := newSessionMetrics()
+	loadProgressFileIntoSession(path, m)
+	if outcome, ok := ParseProgressOutcome(path); ok {
+		m.outcome = outcome
+	}
+	return m
+}
+
+// writeMetrics writes the metrics of sessions in the Prometheus text exposition format.
+func writeMetrics(w io.Writer, sessions []metricsSession) {
+	mw := &metricsWriter{w: w}
+
+	var active int
+	for _, s := range sessions {
+		if s.active {
+			active++
+		}
+	}
+	mw.family("ralphex_sessions", "gauge", "Number of sessions by state.")
+	mw.sample("ralphex_sessions", active, "state", string(SessionStateActive))
+	mw.sample("ralphex_sessions", len(sessions)-active, "state", string(SessionStateCompleted))
+
+	mw.family("ralphex_session_active", "gauge", "Whether the session is running, 1 if active.")
+	for _, s := range sessions {
+		mw.sample("ralphex_session_active", boolValue(s.active), "session", s.id)
+	}
+
+	mw.family("ralphex_session_last_update_timestamp_seconds", "gauge", "Unix time the session last wrote to its progress file.")
+	for _, s := range sessions {
+		mw.sample("ralphex_session_last_update_timestamp_seconds", int(s.modified.Unix()), "session", s.id)
+	}
+
+	mw.family("ralphex_session_outcome", "gauge", "Final status of completed sessions, 1 for the status the run ended with.")
+	for _, s := range sessions {
+		if o := s.metrics.outcome; o.Status != "" {
+			mw.sample("ralphex_session_outcome", 1, "session", s.id, "status", string(o.Status), "phase", string(o.Phase))
+		}
+	}
+
+	mw.family("ralphex_session_duration_seconds", "gauge", "Total duration of completed sessions.")
+	for _, s := range sessions {
+		if o := s.metrics.outcome; o.Status != "" {
+			mw.sample("ralphex_session_duration_seconds", o.Duration.Seconds(), "session", s.id)
+		}
+	}
+
+	mw.family("ralphex_iterations_total", "counter", "Iterations per phase, codex iterations are phase=\"codex\".")
+	for _, s := range sessions {
+		for _, phase := range sortedKeys(s.metrics.iterations) {
+			mw.sample("ralphex_iterations_total", s.metrics.iterations[phase], "session", s.id, "phase", string(phase))
+		}
+	}
+
+	mw.family("ralphex_task_duration_seconds", "histogram", "Duration of finished tasks.")
+	for _, s := range sessions {
+		var sum float64
+		counts := make([]int, len(taskDurationBuckets))
+		for _, d := range s.metrics.taskDurations {
+			sum += d.Seconds()
+			for i, le := range taskDurationBuckets {
+				if d.Seconds() <= le {
+					counts[i]++
+				}
+			}
+		}
+		for i, le := range taskDurationBuckets {
+			mw.sample("ralphex_task_duration_seconds_bucket", counts[i], "session", s.id, "le", strconv.FormatFloat(le, 'g', -1, 64))
+		}
+		mw.sample("ralphex_task_duration_seconds_bucket", len(s.metrics.taskDurations), "session", s.id, "le", "+Inf")
+		mw.sample("ralphex_task_duration_seconds_sum", sum, "session", s.id)
+		mw.sample("ralphex_task_duration_seconds_count", len(s.metrics.taskDurations), "session", s.id)
+	}
+
+	mw.family("ralphex_signals_total", "counter", "Signals emitted by claude and codex, e.g. COMPLETED, FAILED, REVIEW_DONE.")
+	for _, s := range sessions {
+		for _, signal := range sortedKeys(s.metrics.signals) {
+			mw.sample("ralphex_signals_total", s.metrics.signals[signal], "session", s.id, "signal", signal)
+		}
+	}
+
+	mw.family("ralphex_errors_total", "counter", "ERROR lines in the progress log.")
+	for _, s := range sessions {
+		mw.sample("ralphex_errors_total", s.metrics.errors, "session", s.id)
+	}
+
+	mw.family("ralphex_stalls_total", "counter", "Claude and codex processes killed for producing no output.")
+	for _, s := range sessions {
+		mw.sample("ralphex_stalls_total", s.metrics.stalls, "session", s.id)
+	}
+
+	mw.family("ralphex_executor_errors_total", "counter", "Runs failed by a claude or codex execution error.")
+	for _, s := range sessions {
+		if name := s.metrics.executorError(); name != "" {
+			mw.sample("ralphex_executor_errors_total", 1, "session", s.id, "executor", name)
+		}
+	}
+
+	mw.family("ralphex_tokens_total", "counter", "Tokens reported by claude runs, by type.")
+	for _, s := range sessions {
+		if s.metrics.usage.IsZero() {
+			continue
+		}
+		mw.sample("ralphex_tokens_total", s.metrics.usage.InputTokens, "session", s.id, "type", "input")
+		mw.sample("ralphex_tokens_total", s.metrics.usage.OutputTokens, "session", s.id, "type", "output")
+		mw.sample("ralphex_tokens_total", s.metrics.usage.CacheReadTokens, "session", s.id, "type", "cache")
+	}
+
+	mw.family("ralphex_cost_usd_total", "counter", "Cost in USD reported by claude runs.")
+	for _, s := range sessions {
+		if !s.metrics.usage.IsZero() {
+			mw.sample("ralphex_cost_usd_total", s.metrics.usage.CostUSD, "session", s.id)
+		}
+	}
+}
+
+// metricsWriter writes metric families and samples in the Prometheus text exposition format.
+type metricsWriter struct {
+	w io.Writer
+}
+
+// family writes the HELP and TYPE lines of a metric.
+func (mw *metricsWriter) family(name, typ, help string) {
+	_, _ = fmt.Fprintf(mw.w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// sample writes a sample of metric name. labels are name/value pairs.
+func (mw *metricsWriter) sample(name string, value any, labels ...string) {
+	var sb strings.Builder
+	sb.WriteString(name)
+	if len(labels) > 0 {
+		sb.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			fmt.Fprintf(&sb, "%s=\"%s\"", labels[i], labelValueReplacer.Replace(labels[i+1]))
+		}
+		sb.WriteByte('}')
+	}
+	switch v := value.(type) {
+	case int:
+		sb.WriteString(" " + strconv.Itoa(v))
+	case float64:
+		sb.WriteString(" " + strconv.FormatFloat(v, 'g', -1, 64))
+	}
+	sb.WriteByte('\n')
+	_, _ = io.WriteString(mw.w, sb.String())
+}
+
+// labelValueReplacer escapes label values, the exposition format only escapes backslash, quote and newline.
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// boolValue returns 1 for true and 0 for false.
+func boolValue(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys[K ~string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
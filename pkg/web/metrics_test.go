@@ -0,0 +1,230 @@
+package web
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/umputun/ralphex/pkg/executor"
+	"github.com/umputun/ralphex/pkg/processor"
+)
+
+func TestParseUsage(t *testing.T) {
+	tests := []struct {
+		text string
+		want executor.Usage
+		ok   bool
+	}{
+		{text: "USAGE: 1200 in, 350 out", want: executor.Usage{InputTokens: 1200, OutputTokens: 350}, ok: true},
+		{text: "USAGE: 10 in, 5 out, 8000 cached, $0.0420",
+			want: executor.Usage{InputTokens: 10, OutputTokens: 5, CacheReadTokens: 8000, CostUSD: 0.042}, ok: true},
+		{text: "USAGE: 10 in, 5 out, $1.5000", want: executor.Usage{InputTokens: 10, OutputTokens: 5, CostUSD: 1.5}, ok: true},
+		{text: "USAGE: lots in"},
+		{text: "USAGE: 10 tokens"},
+		{text: "USAGE: 10 in, $free"},
+		{text: "10 in, 5 out"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.text, func(t *testing.T) {
+			u, ok := parseUsage(tc.text)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.want, u)
+		})
+	}
+}
+
+func TestSessionMetrics_Publish(t *testing.T) {
+	ts := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+	at := func(e Event, offset time.Duration) Event {
+		e.Timestamp = ts.Add(offset)
+		return e
+	}
+
+	m := newSessionMetrics()
+	events := []Event{
+		at(NewTaskStartEvent(processor.PhaseTask, 1, "task iteration 1"), 0),
+		at(NewSectionEvent(processor.PhaseTask, "task iteration 1"), 0),
+		at(NewUsageEvent(processor.PhaseTask, executor.Usage{InputTokens: 100, OutputTokens: 10, CostUSD: 0.25}), time.Minute),
+		at(NewTaskStartEvent(processor.PhaseTask, 2, "task iteration 2"), 2*time.Minute),
+		at(NewSectionEvent(processor.PhaseTask, "task iteration 2"), 2*time.Minute),
+		at(NewSignalEvent(processor.PhaseTask, "COMPLETED"), 3*time.Minute),
+		at(NewErrorEvent(processor.PhaseTask, "ERROR: something"), 4*time.Minute),
+		at(NewSectionEvent(processor.PhaseReview, "claude review 0: all findings"), 12*time.Minute),
+		at(NewStallEvent(processor.PhaseReview, time.Minute), 13*time.Minute),
+		at(NewSignalEvent(processor.PhaseReview, "REVIEW_DONE"), 14*time.Minute),
+		at(NewSectionEvent(processor.PhaseCodex, "codex external review"), 15*time.Minute),
+		at(NewSectionEvent(processor.PhaseCodex, "codex iteration 1"), 15*time.Minute),
+		at(NewUsageEvent(processor.PhaseCodex, executor.Usage{InputTokens: 50, OutputTokens: 5, CacheReadTokens: 7}), 16*time.Minute),
+		at(NewOutputEvent(processor.PhaseCodex, "output"), 20*time.Minute),
+	}
+	for _, e := range events {
+		require.NoError(t, m.Publish(e))
+	}
+
+	assert.Equal(t, map[processor.Phase]int{processor.PhaseTask: 2, processor.PhaseReview: 1, processor.PhaseCodex: 1}, m.iterations)
+	assert.Equal(t, []time.Duration{2 * time.Minute, 10 * time.Minute}, m.taskDurations, "second task ends with the review")
+	assert.Equal(t, map[string]int{"COMPLETED": 1, "REVIEW_DONE": 1}, m.signals)
+	assert.Equal(t, 1, m.errors)
+	assert.Equal(t, 1, m.stalls)
+	assert.Equal(t, executor.Usage{InputTokens: 150, OutputTokens: 15, CacheReadTokens: 7, CostUSD: 0.25}, m.usage)
+}
+
+func TestSessionMetrics_executorError(t *testing.T) {
+	tests := []struct {
+		outcome processor.Outcome
+		want    string
+	}{
+		{outcome: processor.Outcome{Status: processor.OutcomeFailed, Error: "task phase: claude execution: exit status 1",
+			Executor: "claude"}, want: "claude"},
+		{outcome: processor.Outcome{Status: processor.OutcomeFailed, Error: "codex execution: timeout", Executor: "codex"},
+			want: "codex"},
+		{outcome: processor.Outcome{Status: processor.OutcomeFailed, Error: "review failed (FAILED signal received)"}},
+		// error text alone doesn't name the executor
+		{outcome: processor.Outcome{Status: processor.OutcomeFailed, Error: "pre_task hook: claude execution: mocked"}},
+		{outcome: processor.Outcome{Status: processor.OutcomeAborted, Error: "claude execution: context canceled",
+			Executor: "claude"}},
+		{outcome: processor.Outcome{Status: processor.OutcomeSuccess}},
+	}
+	for _, tc := range tests {
+		m := newSessionMetrics()
+		m.outcome = tc.outcome
+		assert.Equal(t, tc.want, m.executorError(), tc.outcome.Error)
+	}
+}
+
+func TestWriteMetrics(t *testing.T) {
+	m := newSessionMetrics()
+	m.signals["FAILED"] = 1
+	m.taskDurations = []time.Duration{30 * time.Second, 20 * time.Minute, 3 * time.Hour}
+
+	var buf bytes.Buffer
+	modified := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+	writeMetrics(&buf, []metricsSession{{id: `odd "id"\` + "\n", active: true, modified: modified, metrics: m}})
+	out := buf.String()
+
+	assert.Contains(t, out, "# HELP ralphex_sessions Number of sessions by state.\n# TYPE ralphex_sessions gauge\n")
+	assert.Contains(t, out, `ralphex_sessions{state="active"} 1`+"\n")
+	assert.Contains(t, out, `ralphex_sessions{state="completed"} 0`+"\n")
+	assert.Contains(t, out, `ralphex_session_last_update_timestamp_seconds{session="odd \"id\"\\\n"} 1769076000`+"\n")
+	assert.Contains(t, out, `ralphex_signals_total{session="odd \"id\"\\\n",signal="FAILED"} 1`+"\n")
+	assert.Contains(t, out, `ralphex_task_duration_seconds_bucket{session="odd \"id\"\\\n",le="60"} 1`+"\n")
+	assert.Contains(t, out, `ralphex_task_duration_seconds_bucket{session="odd \"id\"\\\n",le="1200"} 2`+"\n")
+	assert.Contains(t, out, `ralphex_task_duration_seconds_bucket{session="odd \"id\"\\\n",le="+Inf"} 3`+"\n")
+	assert.Contains(t, out, `ralphex_task_duration_seconds_sum{session="odd \"id\"\\\n"} 12030`+"\n")
+	assert.NotContains(t, out, "ralphex_tokens_total{", "no usage reported")
+	assert.NotContains(t, out, "ralphex_session_outcome{", "no outcome")
+}
+
+func TestServer_HandleMetrics(t *testing.T) {
+	tmpDir := t.TempDir()
+	progressPath := filepath.Join(tmpDir, "progress-metrics.txt")
+	content := `# Ralphex Progress Log
+Plan: docs/plan.md
+Branch: main
+Mode: full
+Started: 2026-01-22 10:00:00
+------------------------------------------------------------
+
+--- task iteration 1 ---
+[26-01-22 10:00:00] working
+[26-01-22 10:00:30] USAGE: 1200 in, 350 out, 8000 cached, $0.0420
+[26-01-22 10:01:00] <<<RALPHEX:ALL_TASKS_DONE>>>
+
+--- claude review 0: all findings ---
+[26-01-22 10:05:00] ERROR: claude execution: exit status 1
+------------------------------------------------------------
+Completed: 2026-01-22 10:05:00 (5 minutes)
+Outcome: failed
+Phase: review
+Error: first review: claude execution: exit status 1
+Executor: claude
+Task iterations: 1
+Duration: 5m0s
+`
+	require.NoError(t, os.WriteFile(progressPath, []byte(content), 0o600))
+
+	sm := NewSessionManager()
+	defer sm.Close()
+	_, err := sm.Discover(tmpDir)
+	require.NoError(t, err)
+	srv, err := NewServerWithSessions(ServerConfig{Port: 8080}, sm)
+	require.NoError(t, err)
+	id := sessionIDFromPath(progressPath)
+
+	scrape := func(t *testing.T) string {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody)
+		w := httptest.NewRecorder()
+		srv.handleMetrics(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", w.Header().Get("Content-Type"))
+		return w.Body.String()
+	}
+
+	out := scrape(t)
+	for _, want := range []string{
+		`ralphex_sessions{state="active"} 0`,
+		`ralphex_sessions{state="completed"} 1`,
+		`ralphex_session_active{session="` + id + `"} 0`,
+		`ralphex_session_outcome{session="` + id + `",status="failed",phase="review"} 1`,
+		`ralphex_session_duration_seconds{session="` + id + `"} 300`,
+		`ralphex_iterations_total{session="` + id + `",phase="review"} 1`,
+		`ralphex_iterations_total{session="` + id + `",phase="task"} 1`,
+		`ralphex_task_duration_seconds_count{session="` + id + `"} 1`,
+		`ralphex_signals_total{session="` + id + `",signal="COMPLETED"} 1`,
+		`ralphex_errors_total{session="` + id + `"} 1`,
+		`ralphex_stalls_total{session="` + id + `"} 0`,
+		`ralphex_executor_errors_total{session="` + id + `",executor="claude"} 1`,
+		`ralphex_tokens_total{session="` + id + `",type="input"} 1200`,
+		`ralphex_tokens_total{session="` + id + `",type="cache"} 8000`,
+		`ralphex_cost_usd_total{session="` + id + `"} 0.042`,
+	} {
+		assert.Contains(t, out, want+"\n")
+	}
+
+	t.Run("reuses metrics of unchanged files", func(t *testing.T) {
+		cached := srv.metricsCache[progressPath].metrics
+		scrape(t)
+		assert.Same(t, cached, srv.metricsCache[progressPath].metrics)
+
+		f, err := os.OpenFile(progressPath, os.O_APPEND|os.O_WRONLY, 0o600)
+		require.NoError(t, err)
+		_, err = f.WriteString("[26-01-22 10:06:00] <<<RALPHEX:FAILED>>>\n")
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		assert.Contains(t, scrape(t), `ralphex_signals_total{session="`+id+`",signal="FAILED"} 1`+"\n")
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/metrics", http.NoBody)
+		w := httptest.NewRecorder()
+		srv.handleMetrics(w, req)
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
+
+func TestServer_HandleMetrics_SingleSession(t *testing.T) {
+	progressPath := filepath.Join(t.TempDir(), "progress-single.txt")
+	content := "# Ralphex Progress Log\nPlan: docs/plan.md\nBranch: main\nMode: full\nStarted: 2026-01-22 10:00:00\n" +
+		"------------------------------------------------------------\n\n--- task iteration 1 ---\n[26-01-22 10:00:00] working\n"
+	require.NoError(t, os.WriteFile(progressPath, []byte(content), 0o600))
+
+	session := NewSession("main", progressPath)
+	defer session.Close()
+	srv, err := NewServer(ServerConfig{Port: 8080}, session)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody)
+	w := httptest.NewRecorder()
+	srv.handleMetrics(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `ralphex_iterations_total{session="main",phase="task"} 1`+"\n")
+	assert.Contains(t, w.Body.String(), `ralphex_session_active{session="main"} 0`+"\n", "progress file isn't locked")
+}
@@ -37,8 +37,10 @@ type Task struct {
 
 // Plan represents a parsed plan file.
 type Plan struct {
-	Title string `json:"title"`
-	Tasks []Task `json:"tasks"`
+	Title        string   `json:"title"`
+	Tasks        []Task   `json:"tasks"`
+	Repositories []string `json:"repositories,omitempty"` // additional repositories from the "## Repositories" section
+	Scope        []string `json:"scope,omitempty"`        // repository subdirectories from the "Scope:" line
 }
 
 // patterns for parsing plan markdown.
@@ -46,6 +48,9 @@ var (
 	taskHeaderPattern = regexp.MustCompile(`^###\s+(?:Task|Iteration)\s+(\d+):\s*(.*)$`)
 	checkboxPattern   = regexp.MustCompile(`^-\s+\[([ xX])\]\s*(.*)$`)
 	titlePattern      = regexp.MustCompile(`^#\s+(.*)$`)
+	reposHeadPattern  = regexp.MustCompile(`(?i)^##\s+repositories\s*$`)
+	repoItemPattern   = regexp.MustCompile(`^[-*]\s+(\S+)`)
+	scopePattern      = regexp.MustCompile(`(?i)^\**scope(?::\**|\**:)\s*(.+)$`)
 )
 
 // ParsePlan parses a plan markdown file into a structured Plan.
@@ -56,10 +61,26 @@ func ParsePlan(content string) (*Plan, error) {
 
 	scanner := bufio.NewScanner(strings.NewReader(content))
 	var currentTask *Task
+	inRepos := false
 
 	for scanner.Scan() {
 		line := scanner.Text()
 
+		// collect additional repositories, one list item per path, until the next header
+		if reposHeadPattern.MatchString(line) {
+			inRepos = true
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			inRepos = false
+		}
+		if inRepos {
+			if matches := repoItemPattern.FindStringSubmatch(line); matches != nil {
+				plan.Repositories = append(plan.Repositories, strings.Trim(matches[1], "`"))
+			}
+			continue
+		}
+
 		// check for plan title (first h1)
 		if plan.Title == "" {
 			if matches := titlePattern.FindStringSubmatch(line); matches != nil {
@@ -68,6 +89,14 @@ func ParsePlan(content string) (*Plan, error) {
 			}
 		}
 
+		// the first "Scope: a, b" line outside task sections limits the plan to repository subdirectories
+		if currentTask == nil && plan.Scope == nil {
+			if matches := scopePattern.FindStringSubmatch(strings.TrimSpace(line)); matches != nil {
+				plan.Scope = parseScope(matches[1])
+				continue
+			}
+		}
+
 		// check for task header
 		if matches := taskHeaderPattern.FindStringSubmatch(line); matches != nil {
 			// save previous task if exists
@@ -130,6 +159,18 @@ func (p *Plan) JSON() ([]byte, error) {
 	return data, nil
 }
 
+// parseScope splits a comma or space separated list of paths, dropping backticks around them.
+func parseScope(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+	result := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f = strings.Trim(f, "`"); f != "" {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
 // parseTaskNum extracts task number from string.
 func parseTaskNum(s string) (int, error) {
 	n, err := strconv.Atoi(s)
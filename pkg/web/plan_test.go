@@ -48,6 +48,31 @@ Some description here.
 		assert.Equal(t, TaskStatusPending, plan.Tasks[1].Status) // all unchecked
 	})
 
+	t.Run("parses repositories section", func(t *testing.T) {
+		content := "# Plan\n\n## Repositories\n- ../shared-lib\n- `../proto` - protobuf definitions\n\n" +
+			"not a list item\n\n## Overview\n- not/a/repo\n\n### Task 1: First\n- [ ] item\n"
+		plan, err := ParsePlan(content)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"../shared-lib", "../proto"}, plan.Repositories)
+		require.Len(t, plan.Tasks, 1)
+		assert.Len(t, plan.Tasks[0].Checkboxes, 1)
+	})
+
+	t.Run("parses scope line", func(t *testing.T) {
+		tests := map[string][]string{
+			"Scope: services/api, libs/common\n":  {"services/api", "libs/common"},
+			"**Scope:** `services/api` libs/x\n":  {"services/api", "libs/x"},
+			"scope: services/api\nScope: other\n": {"services/api"},
+			"Scope of work is small\n":            nil,
+			"### Task 1: x\nScope: inside/task\n": nil,
+		}
+		for content, want := range tests {
+			plan, err := ParsePlan("# Plan\n" + content)
+			require.NoError(t, err)
+			assert.Equal(t, want, plan.Scope, content)
+		}
+	})
+
 	t.Run("parses iteration headers as tasks", func(t *testing.T) {
 		content := `# Plan
 
@@ -0,0 +1,168 @@
+package web
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/umputun/ralphex/pkg/processor"
+	"github.com/umputun/ralphex/pkg/progress"
+)
+
+// recordConverter turns progress event file records into dashboard events.
+// it tracks the phase and current task to emit the same boundary events as BroadcastLogger,
+// so a session loaded from the event file looks like one streamed live.
+type recordConverter struct {
+	phase       processor.Phase
+	currentTask int
+}
+
+// newRecordConverter creates a converter starting in phase.
+func newRecordConverter(phase processor.Phase) *recordConverter {
+	return &recordConverter{phase: phase}
+}
+
+// events returns the dashboard events for rec, timestamped with the record's time.
+// header and outcome records carry run metadata and produce no events.
+func (c *recordConverter) events(rec progress.Record) []Event {
+	var events []Event
+	phase := cmp.Or(rec.Phase, c.phase)
+
+	switch rec.Type {
+	case progress.RecordPhase:
+		// leaving task phase with an active task ends the task
+		if c.phase == processor.PhaseTask && rec.Phase != processor.PhaseTask && c.currentTask > 0 {
+			events = append(events, NewTaskEndEvent(c.phase, c.currentTask, fmt.Sprintf("task %d completed", c.currentTask)))
+			c.currentTask = 0
+		}
+		c.phase = rec.Phase
+	case progress.RecordSection:
+		switch rec.SectionKind() {
+		case processor.SectionTaskIteration:
+			if c.currentTask > 0 {
+				events = append(events, NewTaskEndEvent(phase, c.currentTask, fmt.Sprintf("task %d completed", c.currentTask)))
+			}
+			c.currentTask = rec.Iteration
+			events = append(events, NewTaskStartEvent(phase, rec.Iteration, rec.Text))
+		case processor.SectionClaudeReview, processor.SectionCodexIteration:
+			events = append(events, NewIterationStartEvent(phase, rec.Iteration, rec.Text))
+		default:
+			// no boundary events for other sections
+		}
+		events = append(events, NewSectionEvent(phase, rec.Text))
+	case progress.RecordOutput:
+		e := NewOutputEvent(phase, rec.Text)
+		// signals come as separate records, keep error and warning detection
+		if t := detectEventType(rec.Text); t != EventTypeSignal {
+			e.Type = t
+		}
+		events = append(events, e)
+	case progress.RecordSignal:
+		events = append(events, NewSignalEvent(phase, normalizeTokenSignal(rec.Signal)))
+	case progress.RecordQuestion:
+		events = append(events, NewOutputEvent(phase, "QUESTION: "+rec.Text),
+			NewOutputEvent(phase, "OPTIONS: "+strings.Join(rec.Options, ", ")))
+	case progress.RecordAnswer:
+		events = append(events, NewOutputEvent(phase, "ANSWER: "+rec.Text))
+	case progress.RecordActivity:
+		events = append(events, NewActivityEvent(phase, rec.Activity()))
+	case progress.RecordStall:
+		events = append(events, NewStallEvent(phase, rec.Duration))
+	case progress.RecordUsage:
+		if rec.Usage != nil {
+			events = append(events, NewUsageEvent(phase, *rec.Usage))
+		}
+	case progress.RecordCommits:
+		if rec.Commits != nil {
+			events = append(events, NewCommitsEvent(phase, *rec.Commits))
+		}
+	default:
+		// header, outcome and unknown records produce no events
+	}
+
+	for i := range events {
+		events[i].Timestamp = rec.Time
+	}
+	return events
+}
+
+// hasEventsFile returns true if the progress log at path has a progress event file next to it.
+// logs written by older versions have only the text file.
+func hasEventsFile(path string) bool {
+	info, err := os.Stat(progress.EventsPath(path))
+	return err == nil && !info.IsDir()
+}
+
+// loadRecordsIntoSession reads the progress event file for the progress log at path and publishes
+// its events to the session. malformed lines, e.g. a partially written last record, are skipped.
+// only a failure to open the file is returned, read errors after events were published are logged.
+func loadRecordsIntoSession(path string, session eventPublisher) error {
+	f, err := os.Open(progress.EventsPath(path)) //nolint:gosec // path from user-controlled glob pattern, acceptable for session discovery
+	if err != nil {
+		return fmt.Errorf("open event file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	buf := make([]byte, 0, 64*1024)
+	scanner.Buffer(buf, maxScannerBuffer)
+	conv := newRecordConverter(processor.PhaseTask)
+	for scanner.Scan() {
+		rec, err := progress.ParseRecord(scanner.Bytes())
+		if err != nil {
+			continue
+		}
+		for _, e := range conv.events(rec) {
+			if err := session.Publish(e); err != nil {
+				log.Printf("[WARN] failed to publish %s event: %v", e.Type, err)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Printf("[WARN] failed to read progress event file for %s: %v", path, err)
+	}
+	return nil
+}
+
+// parseRecordsHeader reads session metadata from the header record of the progress event file.
+func parseRecordsHeader(path string) (SessionMetadata, error) {
+	f, err := os.Open(progress.EventsPath(path)) //nolint:gosec // path from user-controlled glob pattern, acceptable for session discovery
+	if err != nil {
+		return SessionMetadata{}, fmt.Errorf("open event file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxScannerBuffer)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return SessionMetadata{}, fmt.Errorf("scan event file: %w", err)
+		}
+		return SessionMetadata{}, fmt.Errorf("empty event file")
+	}
+	rec, err := progress.ParseRecord(scanner.Bytes())
+	if err != nil {
+		return SessionMetadata{}, err
+	}
+	if rec.Type != progress.RecordHeader {
+		return SessionMetadata{}, fmt.Errorf("first event record is %q, not a header", rec.Type)
+	}
+	return SessionMetadata{PlanPath: rec.Plan, Branch: rec.Branch, Mode: rec.Mode, StartTime: rec.Time}, nil
+}
+
+// parseRecordsOutcome reads the run outcome from the last record of the progress event file.
+func parseRecordsOutcome(path string) (processor.Outcome, bool) {
+	tail, err := readTail(progress.EventsPath(path), outcomeTailSize)
+	if err != nil {
+		return processor.Outcome{}, false
+	}
+	lines := strings.Split(strings.TrimRight(string(tail), "\n"), "\n")
+	rec, err := progress.ParseRecord([]byte(lines[len(lines)-1]))
+	if err != nil || rec.Type != progress.RecordOutcome || rec.Outcome == nil {
+		return processor.Outcome{}, false
+	}
+	return *rec.Outcome, true
+}
@@ -0,0 +1,193 @@
+package web
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/umputun/ralphex/pkg/executor"
+	"github.com/umputun/ralphex/pkg/processor"
+	"github.com/umputun/ralphex/pkg/progress"
+)
+
+// writeRecords writes a progress log at path with a header and its event file with records.
+func writeRecords(t *testing.T, path string, records ...progress.Record) {
+	t.Helper()
+	text := "# Ralphex Progress Log\nPlan: text-plan.md\nBranch: text\nMode: full\nStarted: 2026-01-22 10:00:00\n" +
+		strings.Repeat("-", 60) + "\n\n[26-01-22 10:00:01] from text log\n"
+	require.NoError(t, os.WriteFile(path, []byte(text), 0o600))
+
+	var lines []string
+	for _, r := range records {
+		data, err := json.Marshal(r)
+		require.NoError(t, err)
+		lines = append(lines, string(data))
+	}
+	require.NoError(t, os.WriteFile(progress.EventsPath(path), []byte(strings.Join(lines, "\n")+"\n"), 0o600))
+}
+
+func TestRecordConverter_Events(t *testing.T) {
+	ts := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+	c := newRecordConverter(processor.PhaseTask)
+	convert := func(r progress.Record) []Event {
+		r.Time = ts
+		return c.events(r)
+	}
+	types := func(events []Event) []EventType {
+		res := make([]EventType, 0, len(events))
+		for _, e := range events {
+			assert.Equal(t, ts, e.Timestamp)
+			res = append(res, e.Type)
+		}
+		return res
+	}
+
+	assert.Empty(t, convert(progress.Record{Type: progress.RecordHeader, Plan: "plan.md"}))
+
+	events := convert(progress.Record{Type: progress.RecordSection, Phase: processor.PhaseTask, Text: "task iteration 1",
+		Section: "task", Iteration: 1})
+	assert.Equal(t, []EventType{EventTypeTaskStart, EventTypeSection}, types(events))
+	assert.Equal(t, 1, events[0].TaskNum)
+	assert.Equal(t, "task iteration 1", events[1].Section)
+
+	events = convert(progress.Record{Type: progress.RecordSection, Text: "task iteration 2", Section: "task", Iteration: 2})
+	assert.Equal(t, []EventType{EventTypeTaskEnd, EventTypeTaskStart, EventTypeSection}, types(events))
+	assert.Equal(t, 1, events[0].TaskNum)
+	assert.Equal(t, 2, events[1].TaskNum)
+
+	events = convert(progress.Record{Type: progress.RecordOutput, Text: "done <<<RALPHEX:ALL_TASKS_DONE>>>"})
+	assert.Equal(t, []EventType{EventTypeOutput}, types(events), "signal comes as its own record")
+	events = convert(progress.Record{Type: progress.RecordSignal, Signal: "ALL_TASKS_DONE"})
+	assert.Equal(t, []EventType{EventTypeSignal}, types(events))
+	assert.Equal(t, "COMPLETED", events[0].Signal)
+	assert.Equal(t, []EventType{EventTypeError}, types(convert(progress.Record{Type: progress.RecordOutput, Text: "ERROR: boom"})))
+
+	events = convert(progress.Record{Type: progress.RecordActivity, Tool: "Edit", Detail: "pkg/foo.go"})
+	assert.Equal(t, []EventType{EventTypeActivity}, types(events))
+	assert.Equal(t, "pkg/foo.go", events[0].File)
+	assert.True(t, events[0].Modifies)
+
+	events = convert(progress.Record{Type: progress.RecordPhase, Phase: processor.PhaseReview})
+	assert.Equal(t, []EventType{EventTypeTaskEnd}, types(events))
+	assert.Equal(t, 2, events[0].TaskNum)
+	assert.Equal(t, processor.PhaseReview, c.phase)
+
+	events = convert(progress.Record{Type: progress.RecordSection, Text: "claude review 1", Section: "claude_review", Iteration: 1})
+	assert.Equal(t, []EventType{EventTypeIterationStart, EventTypeSection}, types(events))
+	assert.Equal(t, processor.PhaseReview, events[0].Phase)
+
+	events = convert(progress.Record{Type: progress.RecordStall, Duration: 5 * time.Minute})
+	assert.Equal(t, "STALL: no output for 5m0s, process killed", events[0].Text)
+	events = convert(progress.Record{Type: progress.RecordUsage, Usage: &executor.Usage{InputTokens: 10, OutputTokens: 5}})
+	assert.Equal(t, []EventType{EventTypeUsage}, types(events))
+	assert.Equal(t, "USAGE: 10 in, 5 out", events[0].Text)
+
+	events = convert(progress.Record{Type: progress.RecordCommits,
+		Commits: &processor.IterationCommits{Iteration: 1, Label: "task iteration 1", From: "1a2b3c4", To: "4d5e6f7"}})
+	assert.Equal(t, []EventType{EventTypeCommits}, types(events))
+	assert.Equal(t, "4d5e6f7", events[0].ToCommit)
+
+	events = convert(progress.Record{Type: progress.RecordQuestion, Text: "which db?", Options: []string{"pg", "sqlite"}})
+	assert.Equal(t, "QUESTION: which db?", events[0].Text)
+	assert.Equal(t, "OPTIONS: pg, sqlite", events[1].Text)
+	assert.Equal(t, "ANSWER: pg", convert(progress.Record{Type: progress.RecordAnswer, Text: "pg"})[0].Text)
+
+	assert.Empty(t, convert(progress.Record{Type: progress.RecordOutcome, Duration: time.Minute}))
+}
+
+func TestParseProgressHeader_EventsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress-test.txt")
+	start := time.Date(2026, 1, 22, 10, 30, 0, 0, time.Local)
+	writeRecords(t, path, progress.Record{Time: start, Type: progress.RecordHeader, Plan: "docs/plan.md", Branch: "main",
+		Mode: "review"})
+
+	meta, err := ParseProgressHeader(path)
+	require.NoError(t, err)
+	assert.Equal(t, "docs/plan.md", meta.PlanPath)
+	assert.Equal(t, "main", meta.Branch)
+	assert.Equal(t, "review", meta.Mode)
+	assert.True(t, start.Equal(meta.StartTime))
+
+	// event file without a header falls back to the text log
+	writeRecords(t, path, progress.Record{Type: progress.RecordOutput, Text: "no header"})
+	meta, err = ParseProgressHeader(path)
+	require.NoError(t, err)
+	assert.Equal(t, "text-plan.md", meta.PlanPath)
+}
+
+func TestLoadProgressFileIntoSession_EventsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress-test.txt")
+	writeRecords(t, path,
+		progress.Record{Type: progress.RecordHeader, Plan: "docs/plan.md"},
+		progress.Record{Type: progress.RecordOutput, Text: "from event file"},
+	)
+	// a partially written last record is skipped
+	f, err := os.OpenFile(progress.EventsPath(path), os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"type":"output","te`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	session := NewSession("test", path)
+	defer session.Close()
+	require.NoError(t, loadRecordsIntoSession(path, session))
+	loadProgressFileIntoSession(path, session)
+
+	require.Error(t, loadRecordsIntoSession(filepath.Join(t.TempDir(), "progress-missing.txt"), session))
+}
+
+func TestParseProgressOutcome(t *testing.T) {
+	header := "# Ralphex Progress Log\nPlan: docs/plan.md\nBranch: main\nMode: full\nStarted: 2026-01-22 10:00:00\n" +
+		strings.Repeat("-", 60) + "\n\n[26-01-22 10:00:01] working\n\n" + strings.Repeat("-", 60) + "\n"
+
+	t.Run("text footer", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "progress-test.txt")
+		footer := "Completed: 2026-01-22 10:32:00 (32 minutes)\nOutcome: max_iterations\nPhase: task\n" +
+			"Error: task phase: max iterations (50) reached without completion\nTask iterations: 50\n" +
+			"Review iterations: 2\nCodex iterations: 1\nPlan iterations: 4\nDuration: 32m0s\n"
+		require.NoError(t, os.WriteFile(path, []byte(header+footer), 0o600))
+
+		outcome, ok := ParseProgressOutcome(path)
+		require.True(t, ok)
+		assert.Equal(t, processor.Outcome{Status: processor.OutcomeMaxIterations, Phase: processor.PhaseTask,
+			Error: "task phase: max iterations (50) reached without completion", TaskIterations: 50, ReviewIterations: 2,
+			CodexIterations: 1, PlanIterations: 4, Duration: 32 * time.Minute}, outcome)
+	})
+
+	t.Run("old footer without outcome", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "progress-test.txt")
+		require.NoError(t, os.WriteFile(path, []byte(header+"Completed: 2026-01-22 10:32:00 (32 minutes)\n"), 0o600))
+		_, ok := ParseProgressOutcome(path)
+		assert.False(t, ok)
+	})
+
+	t.Run("running session", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "progress-test.txt")
+		require.NoError(t, os.WriteFile(path, []byte("[26-01-22 10:00:01] Outcome: success\n"), 0o600))
+		_, ok := ParseProgressOutcome(path)
+		assert.False(t, ok)
+	})
+
+	t.Run("event file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "progress-test.txt")
+		want := processor.Outcome{Status: processor.OutcomeSuccess, Phase: processor.PhaseReview, ReviewIterations: 3,
+			Duration: time.Minute}
+		writeRecords(t, path, progress.Record{Type: progress.RecordHeader},
+			progress.Record{Type: progress.RecordOutcome, Duration: time.Minute, Outcome: &want})
+
+		outcome, ok := ParseProgressOutcome(path)
+		require.True(t, ok)
+		assert.Equal(t, want, outcome)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, ok := ParseProgressOutcome(filepath.Join(t.TempDir(), "progress-missing.txt"))
+		assert.False(t, ok)
+	})
+}
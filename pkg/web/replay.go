@@ -0,0 +1,197 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tmaxmax/go-sse"
+)
+
+// replay limits.
+const (
+	maxReplaySpeed = 1000            // fastest playback speed multiplier
+	maxReplayDelay = 3 * time.Second // longest pause between two replayed events, skips idle stretches
+)
+
+// SSE event types of replay control messages. session events are sent typeless like on the live stream,
+// so the dashboard's onmessage handler renders them unchanged.
+const (
+	replayInfoType = "replay"     // first message, describes the replay
+	replayEndType  = "replay_end" // last message, sent after all events were replayed
+)
+
+// replayOptions controls a session replay, parsed from /events query parameters.
+type replayOptions struct {
+	speed   float64 // playback speed multiplier, 1 replays with the original timing
+	from    int     // index of the first event replayed with timing, earlier events are sent at once
+	section int     // 1-based section to seek to, overrides from; 0 if not set
+}
+
+// parseReplayOptions parses replay=<speed>, from=<event index> and section=<number>.
+// speed accepts an optional "x" suffix, e.g. replay=10x, an empty value means 1x.
+func parseReplayOptions(q url.Values) (replayOptions, error) {
+	opts := replayOptions{speed: 1}
+	if v := strings.TrimSuffix(q.Get("replay"), "x"); v != "" {
+		speed, err := strconv.ParseFloat(v, 64)
+		if err != nil || speed <= 0 || speed > maxReplaySpeed {
+			return replayOptions{}, fmt.Errorf("invalid replay speed %q, expected a number between 0 and %d", q.Get("replay"), maxReplaySpeed)
+		}
+		opts.speed = speed
+	}
+	if v := q.Get("from"); v != "" {
+		from, err := strconv.Atoi(v)
+		if err != nil || from < 0 {
+			return replayOptions{}, fmt.Errorf("invalid replay position %q", v)
+		}
+		opts.from = from
+	}
+	if v := q.Get("section"); v != "" {
+		section, err := strconv.Atoi(v)
+		if err != nil || section < 1 {
+			return replayOptions{}, fmt.Errorf("invalid replay section %q", v)
+		}
+		opts.section = section
+	}
+	return opts, nil
+}
+
+// replaySection is a seek target of a replay.
+type replaySection struct {
+	Index int    `json:"index"` // index of the section's first event
+	Title string `json:"title"`
+}
+
+// replayInfo is the payload of the first replay message.
+type replayInfo struct {
+	Total    int             `json:"total"` // number of session events
+	Speed    float64         `json:"speed"`
+	From     int             `json:"from"` // index timed playback starts at
+	Sections []replaySection `json:"sections"`
+}
+
+// eventList collects loaded events instead of publishing them.
+type eventList []Event
+
+// Publish appends the event.
+func (l *eventList) Publish(event Event) error {
+	*l = append(*l, event)
+	return nil
+}
+
+// replaySections returns the sections of events in order. a section starts at the task_start or
+// iteration_start event preceding its section event, so seeking to it keeps the task boundary.
+func replaySections(events []Event) []replaySection {
+	var sections []replaySection
+	for i, e := range events {
+		if e.Type != EventTypeSection {
+			continue
+		}
+		start := i
+		for start > 0 && (events[start-1].Type == EventTypeTaskStart || events[start-1].Type == EventTypeIterationStart) {
+			start--
+		}
+		sections = append(sections, replaySection{Index: start, Title: e.Text})
+	}
+	return sections
+}
+
+// replayDelay returns how long to wait between events with timestamps prev and next at speed.
+// out of order timestamps replay without delay, long gaps are capped at maxReplayDelay.
+func replayDelay(prev, next time.Time, speed float64) time.Duration {
+	if prev.IsZero() || !next.After(prev) {
+		return 0
+	}
+	return min(time.Duration(float64(next.Sub(prev))/speed), maxReplayDelay)
+}
+
+// handleReplay streams the session's events over SSE with their original relative timing.
+// every connection replays from the start: events before the seek position are sent at once,
+// later ones are spaced by their timestamps divided by the speed. the dashboard pauses by closing
+// the stream and resumes by reconnecting with from set to the number of events it received.
+func (s *Server) handleReplay(w http.ResponseWriter, r *http.Request, session *Session) {
+	opts, err := parseReplayOptions(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var events eventList
+	loadProgressFileIntoSession(session.Path, &events)
+	sections := replaySections(events)
+
+	from := min(opts.from, len(events))
+	if opts.section > 0 {
+		if opts.section > len(sections) {
+			http.Error(w, fmt.Sprintf("section %d not found, session has %d sections", opts.section, len(sections)),
+				http.StatusBadRequest)
+			return
+		}
+		from = sections[opts.section-1].Index
+	}
+
+	sess, err := sse.Upgrade(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	log.Printf("[SSE] replay started: session=%s, speed=%gx, from=%d/%d", session.ID, opts.speed, from, len(events))
+
+	info := replayInfo{Total: len(events), Speed: opts.speed, From: from, Sections: sections}
+	if err := sendReplayMessage(sess, replayInfoType, info); err != nil {
+		return
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for i, e := range events {
+		if i > from {
+			if delay := replayDelay(events[i-1].Timestamp, e.Timestamp, opts.speed); delay > 0 {
+				timer.Reset(delay)
+				select {
+				case <-r.Context().Done():
+					return
+				case <-timer.C:
+				}
+			}
+		}
+		msg := e.ToSSEMessage()
+		msg.ID = sse.ID(strconv.Itoa(i + 1)) // number of events received, the position to resume from
+		if err := sess.Send(msg); err != nil {
+			return
+		}
+		// events before the seek position go out in one batch
+		if i >= from-1 {
+			if err := sess.Flush(); err != nil {
+				return
+			}
+		}
+	}
+
+	if err := sendReplayMessage(sess, replayEndType, map[string]int{"total": len(events)}); err != nil {
+		return
+	}
+	log.Printf("[SSE] replay finished: session=%s", session.ID)
+}
+
+// sendReplayMessage sends a replay control message of type typ with data encoded as JSON.
+func sendReplayMessage(sess *sse.Session, typ string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshal replay message: %w", err)
+	}
+	msg := &sse.Message{Type: sse.Type(typ)}
+	msg.AppendData(string(payload))
+	if err := sess.Send(msg); err != nil {
+		return fmt.Errorf("send replay message: %w", err)
+	}
+	if err := sess.Flush(); err != nil {
+		return fmt.Errorf("flush replay message: %w", err)
+	}
+	return nil
+}
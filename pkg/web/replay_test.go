@@ -0,0 +1,168 @@
+package web
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/umputun/ralphex/pkg/processor"
+)
+
+func TestParseReplayOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    replayOptions
+		wantErr string
+	}{
+		{name: "empty speed", query: "replay=", want: replayOptions{speed: 1}},
+		{name: "speed", query: "replay=10", want: replayOptions{speed: 10}},
+		{name: "speed with x suffix", query: "replay=100x", want: replayOptions{speed: 100}},
+		{name: "fractional speed", query: "replay=0.5", want: replayOptions{speed: 0.5}},
+		{name: "from and section", query: "replay=1&from=42&section=3", want: replayOptions{speed: 1, from: 42, section: 3}},
+		{name: "zero speed", query: "replay=0", wantErr: "invalid replay speed"},
+		{name: "too fast", query: "replay=5000", wantErr: "invalid replay speed"},
+		{name: "bad speed", query: "replay=fast", wantErr: "invalid replay speed"},
+		{name: "negative from", query: "replay=1&from=-1", wantErr: "invalid replay position"},
+		{name: "zero section", query: "replay=1&section=0", wantErr: "invalid replay section"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tc.query)
+			require.NoError(t, err)
+			opts, err := parseReplayOptions(q)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, opts)
+		})
+	}
+}
+
+func TestReplaySections(t *testing.T) {
+	events := []Event{
+		NewOutputEvent(processor.PhaseTask, "starting"),
+		NewTaskStartEvent(processor.PhaseTask, 1, "task iteration 1"),
+		NewSectionEvent(processor.PhaseTask, "task iteration 1"),
+		NewOutputEvent(processor.PhaseTask, "working"),
+		NewTaskEndEvent(processor.PhaseTask, 1, "task 1 completed"),
+		NewSectionEvent(processor.PhaseReview, "claude review 0: all findings"),
+		NewOutputEvent(processor.PhaseReview, "reviewing"),
+		NewIterationStartEvent(processor.PhaseCodex, 1, "codex iteration 1"),
+		NewSectionEvent(processor.PhaseCodex, "codex iteration 1"),
+	}
+	assert.Equal(t, []replaySection{
+		{Index: 1, Title: "task iteration 1"},
+		{Index: 5, Title: "claude review 0: all findings"},
+		{Index: 7, Title: "codex iteration 1"},
+	}, replaySections(events))
+	assert.Empty(t, replaySections(nil))
+}
+
+func TestReplayDelay(t *testing.T) {
+	ts := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, 2*time.Second, replayDelay(ts, ts.Add(2*time.Second), 1))
+	assert.Equal(t, 200*time.Millisecond, replayDelay(ts, ts.Add(2*time.Second), 10))
+	assert.Equal(t, maxReplayDelay, replayDelay(ts, ts.Add(time.Hour), 100), "long gaps are capped")
+	assert.Zero(t, replayDelay(ts, ts.Add(-time.Second), 1), "out of order")
+	assert.Zero(t, replayDelay(ts, ts, 1))
+	assert.Zero(t, replayDelay(time.Time{}, ts, 1))
+}
+
+func TestServer_HandleReplay(t *testing.T) {
+	tmpDir := t.TempDir()
+	progressPath := filepath.Join(tmpDir, "progress-replay.txt")
+	content := `# Ralphex Progress Log
+Plan: docs/plan.md
+Branch: main
+Mode: full
+Started: 2026-01-22 10:00:00
+------------------------------------------------------------
+
+--- task iteration 1 ---
+[26-01-22 10:00:01] first
+[26-01-22 10:00:02] second
+
+--- claude review 0: all findings ---
+[26-01-22 10:00:03] reviewing
+`
+	require.NoError(t, os.WriteFile(progressPath, []byte(content), 0o600))
+
+	sm := NewSessionManager()
+	defer sm.Close()
+	_, err := sm.Discover(tmpDir)
+	require.NoError(t, err)
+	srv, err := NewServerWithSessions(ServerConfig{Port: 8080}, sm)
+	require.NoError(t, err)
+	sessionID := sessionIDFromPath(progressPath)
+
+	replay := func(t *testing.T, query string) *http.Response {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/events?session="+sessionID+"&"+query, http.NoBody)
+		w := httptest.NewRecorder()
+		srv.handleEvents(w, req)
+		return w.Result()
+	}
+
+	t.Run("replays all events", func(t *testing.T) {
+		resp := replay(t, "replay=1000x")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		body := readBody(t, resp)
+		assert.True(t, strings.HasPrefix(body, "event: replay\ndata: {\"total\":6,\"speed\":1000,\"from\":0,"), body)
+		assert.Contains(t, body, `"sections":[{"index":0,"title":"task iteration 1"},{"index":4,"title":"claude review 0: all findings"}]`)
+		assert.Contains(t, body, "id: 1\n")
+		assert.Contains(t, body, "id: 6\n")
+		assert.Less(t, strings.Index(body, `"text":"first"`), strings.Index(body, `"text":"reviewing"`))
+		assert.True(t, strings.HasSuffix(body, "event: replay_end\ndata: {\"total\":6}\n\n"), body)
+	})
+
+	t.Run("seeks to section", func(t *testing.T) {
+		resp := replay(t, "replay=1000&section=2")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body := readBody(t, resp)
+		assert.Contains(t, body, `"from":4,`)
+		assert.Contains(t, body, `"text":"first"`, "events before the seek position are sent too")
+	})
+
+	t.Run("from past the end", func(t *testing.T) {
+		resp := replay(t, "replay=1&from=100")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, readBody(t, resp), `"from":6,`)
+	})
+
+	t.Run("unknown section", func(t *testing.T) {
+		resp := replay(t, "replay=1&section=3")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, readBody(t, resp), "section 3 not found, session has 2 sections")
+	})
+
+	t.Run("invalid speed", func(t *testing.T) {
+		resp := replay(t, "replay=-1")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
@@ -14,6 +14,8 @@ import (
 	"sort"
 	"sync"
 	"time"
+
+	"github.com/umputun/ralphex/pkg/processor"
 )
 
 //go:embed templates static
@@ -38,6 +40,10 @@ type Server struct {
 	// plan caching - set after first successful load (single-session mode)
 	planMu    sync.Mutex
 	planCache *Plan
+
+	// per-session metrics of the previous /metrics scrape, keyed by progress file path
+	metricsMu    sync.Mutex
+	metricsCache map[string]cachedMetrics
 }
 
 // NewServer creates a new web server for single-session mode (direct execution).
@@ -80,6 +86,8 @@ func (s *Server) Start(ctx context.Context) error {
 	mux.HandleFunc("/events", s.handleEvents)
 	mux.HandleFunc("/api/plan", s.handlePlan)
 	mux.HandleFunc("/api/sessions", s.handleSessions)
+	mux.HandleFunc("/api/sessions/{id}/diff", s.handleSessionDiff)
+	mux.HandleFunc("/metrics", s.handleMetrics)
 
 	// static files
 	staticFS, err := fs.Sub(embeddedFS, "static")
@@ -267,6 +275,7 @@ func loadPlanWithFallback(path string) (*Plan, error) {
 
 // handleEvents serves the SSE stream.
 // in multi-session mode, accepts ?session=<id> query parameter.
+// with ?replay=<speed> the session's events are replayed with their original timing instead, see handleReplay.
 func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
 	sessionID := r.URL.Query().Get("session")
 	log.Printf("[SSE] connection request: session=%s", sessionID)
@@ -279,6 +288,12 @@ func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if r.URL.Query().Has("replay") {
+		s.handleReplay(w, r, session)
+		log.Printf("[SSE] connection closed: session=%s", sessionID)
+		return
+	}
+
 	// delegate to go-sse Server which handles:
 	// - SSE protocol (headers, event formatting)
 	// - Connection management
@@ -325,6 +340,10 @@ type SessionInfo struct {
 	Mode         string    `json:"mode,omitempty"`
 	StartTime    time.Time `json:"startTime"`
 	LastModified time.Time `json:"lastModified"`
+	// Outcome is the final status of completed runs (success, failed, aborted, max_iterations), empty if unknown.
+	Outcome      processor.OutcomeStatus `json:"outcome,omitempty"`
+	OutcomePhase processor.Phase         `json:"outcomePhase,omitempty"`
+	OutcomeError string                  `json:"outcomeError,omitempty"`
 }
 
 // handleSessions returns a list of all discovered sessions.
@@ -372,6 +391,9 @@ func (s *Server) handleSessions(w http.ResponseWriter, r *http.Request) {
 			Mode:         meta.Mode,
 			StartTime:    meta.StartTime,
 			LastModified: session.GetLastModified(),
+			Outcome:      meta.Outcome.Status,
+			OutcomePhase: meta.Outcome.Phase,
+			OutcomeError: meta.Outcome.Error,
 		})
 	}
 
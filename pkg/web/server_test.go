@@ -397,6 +397,13 @@ Mode: full
 Started: 2026-01-22 10:30:00
 ------------------------------------------------------------
 [10:30:00] Starting execution
+
+------------------------------------------------------------
+Completed: 2026-01-22 10:40:00 (10 minutes)
+Outcome: aborted
+Phase: codex
+Error: codex loop: context canceled
+Duration: 10m0s
 `
 		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "progress-test-plan.txt"), []byte(progressContent), 0o600))
 
@@ -432,6 +439,9 @@ Started: 2026-01-22 10:30:00
 		assert.Equal(t, "docs/plans/test-plan.md", sessions[0].PlanPath)
 		assert.Equal(t, "feature-branch", sessions[0].Branch)
 		assert.Equal(t, "full", sessions[0].Mode)
+		assert.Equal(t, processor.OutcomeAborted, sessions[0].Outcome)
+		assert.Equal(t, processor.PhaseCodex, sessions[0].OutcomePhase)
+		assert.Equal(t, "codex loop: context canceled", sessions[0].OutcomeError)
 	})
 
 	t.Run("rejects non-GET methods", func(t *testing.T) {
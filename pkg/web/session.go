@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/tmaxmax/go-sse"
+
+	"github.com/umputun/ralphex/pkg/processor"
 )
 
 // DefaultReplayerSize is the maximum number of events to keep for replay to late-joining clients.
@@ -52,10 +54,11 @@ const (
 
 // SessionMetadata holds parsed information from progress file header.
 type SessionMetadata struct {
-	PlanPath  string    // path to plan file (from "Plan:" header line)
-	Branch    string    // git branch (from "Branch:" header line)
-	Mode      string    // execution mode: full, review, codex-only (from "Mode:" header line)
-	StartTime time.Time // start time (from "Started:" header line)
+	PlanPath  string            // path to plan file (from "Plan:" header line)
+	Branch    string            // git branch (from "Branch:" header line)
+	Mode      string            // execution mode: full, review, codex-only (from "Mode:" header line)
+	StartTime time.Time         // start time (from "Started:" header line)
+	Outcome   processor.Outcome // run outcome from the footer of completed sessions, empty Status if unknown
 }
 
 // defaultTopic is the SSE topic used for all events within a session.
@@ -175,11 +175,16 @@ func (m *SessionManager) updateSession(session *Session) error {
 		loadProgressFileIntoSession(session.Path, session)
 	}
 
-	// parse metadata from file header
+	// parse metadata from file header, and the outcome footer once the run is over
 	meta, err := ParseProgressHeader(session.Path)
 	if err != nil {
 		return fmt.Errorf("parse header: %w", err)
 	}
+	if newState == SessionStateCompleted {
+		if outcome, ok := ParseProgressOutcome(session.Path); ok {
+			meta.Outcome = outcome
+		}
+	}
 	session.SetMetadata(meta)
 
 	// update last modified time
@@ -390,7 +395,15 @@ func IsActive(path string) (bool, error) {
 //	Mode: full
 //	Started: 2026-01-22 10:30:00
 //	------------------------------------------------------------
+//
+// when the progress event file exists, its header record is used instead.
 func ParseProgressHeader(path string) (SessionMetadata, error) {
+	if hasEventsFile(path) {
+		if meta, err := parseRecordsHeader(path); err == nil {
+			return meta, nil
+		}
+	}
+
 	f, err := os.Open(path) //nolint:gosec // path from user-controlled glob pattern, acceptable for session discovery
 	if err != nil {
 		return SessionMetadata{}, fmt.Errorf("open file: %w", err)
@@ -433,10 +446,106 @@ func ParseProgressHeader(path string) (SessionMetadata, error) {
 	return meta, nil
 }
 
+// outcomeTailSize is how much of the end of a progress file is read to find the outcome footer.
+const outcomeTailSize = 16 * 1024
+
+// ParseProgressOutcome reads the run outcome from the footer of a completed progress file:
+//
+//	------------------------------------------------------------
+//	Completed: 2026-01-22 11:02:00 (32 minutes)
+//	Outcome: failed
+//	Phase: review
+//	Error: claude execution: claude exited with error: exit status 1
+//	Executor: claude
+//	Task iterations: 3
+//	Duration: 32m0s
+//
+// the outcome record of the progress event file is used when present. returns false for
+// running sessions, logs of older versions and runs that ended without reporting an outcome.
+func ParseProgressOutcome(path string) (processor.Outcome, bool) {
+	if hasEventsFile(path) {
+		if outcome, ok := parseRecordsOutcome(path); ok {
+			return outcome, true
+		}
+	}
+
+	tail, err := readTail(path, outcomeTailSize)
+	if err != nil {
+		return processor.Outcome{}, false
+	}
+	idx := strings.LastIndex(string(tail), "\nCompleted: ")
+	if idx < 0 {
+		return processor.Outcome{}, false
+	}
+
+	var outcome processor.Outcome
+	for line := range strings.SplitSeq(string(tail[idx+1:]), "\n") {
+		key, val, found := strings.Cut(line, ": ")
+		if !found {
+			continue
+		}
+		n, _ := strconv.Atoi(val)
+		switch key {
+		case "Outcome":
+			outcome.Status = processor.OutcomeStatus(val)
+		case "Phase":
+			outcome.Phase = processor.Phase(val)
+		case "Error":
+			outcome.Error = val
+		case "Executor":
+			outcome.Executor = val
+		case "Task iterations":
+			outcome.TaskIterations = n
+		case "Review iterations":
+			outcome.ReviewIterations = n
+		case "Codex iterations":
+			outcome.CodexIterations = n
+		case "Plan iterations":
+			outcome.PlanIterations = n
+		case "Duration":
+			if d, err := time.ParseDuration(val); err == nil {
+				outcome.Duration = d
+			}
+		}
+	}
+	return outcome, outcome.Status != ""
+}
+
+// readTail returns up to size bytes from the end of the file at path.
+func readTail(path string, size int64) ([]byte, error) {
+	f, err := os.Open(path) //nolint:gosec // path from user-controlled glob pattern, acceptable for session discovery
+	if err != nil {
+		return nil, fmt.Errorf("open file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("stat file: %w", err)
+	}
+	offset := max(info.Size()-size, 0)
+	buf := make([]byte, info.Size()-offset)
+	if _, err := f.ReadAt(buf, offset); err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+	return buf, nil
+}
+
+// eventPublisher receives the events loaded from a progress file, a Session or a replay's event list.
+type eventPublisher interface {
+	Publish(event Event) error
+}
+
 // loadProgressFileIntoSession reads a progress file and publishes events to the session's SSE server.
-// used for completed sessions that were discovered after they finished.
+// the progress event file (progress-*.jsonl) is used when present, text parsing is the fallback.
+// used for completed sessions that were discovered after they finished, and to load sessions for replay.
 // errors are silently ignored since this is best-effort loading.
-func loadProgressFileIntoSession(path string, session *Session) {
+func loadProgressFileIntoSession(path string, session eventPublisher) {
+	// prefer the progress event file, the text log is parsed for sessions of older versions
+	if hasEventsFile(path) && loadRecordsIntoSession(path, session) == nil {
+		return
+	}
+
 	f, err := os.Open(path) //nolint:gosec // path from user-controlled glob pattern, acceptable for session discovery
 	if err != nil {
 		return
@@ -507,6 +616,8 @@ func loadProgressFileIntoSession(path string, session *Session) {
 				event.Signal = sig
 				event.Type = EventTypeSignal
 			}
+			applyActivity(&event)
+			applyCommits(&event)
 
 			_ = session.Publish(event)
 			continue
@@ -541,7 +652,7 @@ func phaseFromSection(name string) processor.Phase {
 
 // emitPendingSection publishes section and task_start events for a pending section.
 // task_start is emitted before section for task iteration sections.
-func emitPendingSection(session *Session, sectionName string, phase processor.Phase, ts time.Time) {
+func emitPendingSection(session eventPublisher, sectionName string, phase processor.Phase, ts time.Time) {
 	// emit task_start event for task iteration sections
 	if matches := taskIterationRegex.FindStringSubmatch(sectionName); matches != nil {
 		taskNum, err := strconv.Atoi(matches[1])
@@ -39,6 +39,15 @@
     const projectCopyBtn = document.getElementById('project-copy');
     const planNameEl = document.getElementById('plan-name');
     const branchNameEl = document.getElementById('branch-name');
+    const replayControls = document.getElementById('replay-controls');
+    const replayToggle = document.getElementById('replay-toggle');
+    const replaySpeed = document.getElementById('replay-speed');
+    const replaySection = document.getElementById('replay-section');
+    const replayExit = document.getElementById('replay-exit');
+    const diffOverlay = document.getElementById('diff-overlay');
+    const diffTitle = document.getElementById('diff-title');
+    const diffContent = document.getElementById('diff-content');
+    const diffCloseBtn = document.getElementById('diff-close');
 
     // SSE reconnection constants
     var SSE_INITIAL_RECONNECT_MS = 1000;
@@ -47,6 +56,14 @@
     // session polling interval
     var SESSION_POLL_INTERVAL_MS = 5000;
 
+    // badge labels for final run outcomes of completed sessions
+    var OUTCOME_LABELS = {
+        success: 'success',
+        failed: 'failed',
+        aborted: 'aborted',
+        max_iterations: 'max iter'
+    };
+
     // view mode constants
     var VIEW_MODE = {
         RECENT: 'recent',
@@ -99,6 +116,19 @@
         isFirstConnect: true,
         resetOnNextEvent: false,
 
+        // replay of a completed session, see connect() and startReplay()
+        replay: {
+            active: false, // events come from a timed replay instead of the session stream
+            paused: false,
+            finished: false,
+            position: 0, // number of replayed events received, the position to resume from
+            sections: [] // seek targets from the replay info message
+        },
+
+        // iteration diff shown in the diff overlay
+        diff: null,
+        diffView: localStorage.getItem('diffView') === 'split' ? 'split' : 'unified',
+
         // event batching state for performance
         eventQueue: [],
         isProcessingQueue: false,
@@ -389,8 +419,14 @@
         duration.className = 'section-duration';
         duration.textContent = '';
 
+        // tool-use counter, filled by recordActivity
+        const activityCount = document.createElement('span');
+        activityCount.className = 'section-activity';
+        activityCount.textContent = '';
+
         summary.appendChild(phaseLabel);
         summary.appendChild(title);
+        summary.appendChild(activityCount);
         summary.appendChild(duration);
 
         // track user toggle on click (setTimeout lets browser update details.open first)
@@ -403,6 +439,15 @@
         const content = document.createElement('div');
         content.className = 'section-content';
 
+        // files modified in this iteration, shown once the first edit arrives
+        const files = document.createElement('div');
+        files.className = 'section-files hidden';
+        const filesLabel = document.createElement('span');
+        filesLabel.className = 'section-files-label';
+        filesLabel.textContent = 'files touched:';
+        files.appendChild(filesLabel);
+        content.appendChild(files);
+
         details.appendChild(summary);
         details.appendChild(content);
 
@@ -644,6 +689,56 @@
         }
     }
 
+    // record a tool-use activity on the current section: bump the tool counter in the
+    // summary and add modified files to the section's file-touch list
+    function recordActivity(event) {
+        if (!state.currentSection) return;
+        var section = state.currentSection;
+        var count = (parseInt(section.dataset.activityCount, 10) || 0) + 1;
+        section.dataset.activityCount = count;
+        var countEl = section.querySelector('.section-activity');
+        if (countEl) {
+            countEl.textContent = count + (count === 1 ? ' tool call' : ' tool calls');
+        }
+
+        if (!event.file || !event.modifies) return;
+        var files = section.querySelector('.section-files');
+        if (!files) return;
+        var exists = Array.prototype.some.call(files.querySelectorAll('.section-file'), function(el) {
+            return el.textContent === event.file;
+        });
+        if (exists) return;
+        var fileEl = document.createElement('span');
+        fileEl.className = 'section-file';
+        fileEl.textContent = event.file;
+        files.appendChild(fileEl);
+        files.classList.remove('hidden');
+    }
+
+    // abbreviate a commit hash for display
+    function shortHash(hash) {
+        return (hash || '').substring(0, 7);
+    }
+
+    // create output line for an iteration's commit range, with a button opening its diff
+    function createCommitsLine(event) {
+        var line = createOutputLine({
+            timestamp: event.timestamp,
+            phase: event.phase,
+            type: event.type,
+            text: 'COMMITS: ' + event.section + ' ' + shortHash(event.from_commit) + '..' + shortHash(event.to_commit)
+        });
+        var btn = document.createElement('button');
+        btn.className = 'diff-btn';
+        btn.textContent = 'View diff';
+        btn.title = 'Show the changes committed in ' + event.section;
+        btn.addEventListener('click', function() {
+            openDiff(event.iteration_num);
+        });
+        line.appendChild(btn);
+        return line;
+    }
+
     // render event to output
     function renderEvent(event) {
         var eventTimestamp = new Date(event.timestamp).getTime();
@@ -704,8 +799,12 @@
                 output.appendChild(line);
             }
         } else {
+            if (event.type === 'activity') {
+                recordActivity(event);
+            }
+
             // create output line
-            var line = createOutputLine(event);
+            var line = event.type === 'commits' ? createCommitsLine(event) : createOutputLine(event);
 
             // add to current section or root output
             if (state.currentSection) {
@@ -728,14 +827,37 @@
             state.resetOnNextEvent = true;
         }
 
-        var url = '/events';
+        var params = [];
         if (state.currentSessionId) {
-            url += '?session=' + encodeURIComponent(state.currentSessionId);
+            params.push('session=' + encodeURIComponent(state.currentSessionId));
+        }
+        if (state.replay.active) {
+            // the server resends events before the position at once, so the output is rebuilt on reconnect
+            params.push('replay=' + encodeURIComponent(replaySpeed.value));
+            params.push('from=' + state.replay.position);
         }
+        var url = '/events' + (params.length ? '?' + params.join('&') : '');
 
         var source = new EventSource(url);
         state.currentEventSource = source;
 
+        source.addEventListener('replay', function(e) {
+            try {
+                var info = JSON.parse(e.data);
+                state.replay.sections = info.sections || [];
+                renderReplaySections();
+            } catch (err) {
+                console.error('parse error:', err);
+            }
+        });
+
+        source.addEventListener('replay_end', function() {
+            source.close();
+            state.currentEventSource = null;
+            state.replay.finished = true;
+            updateReplayControls();
+        });
+
         source.onopen = function() {
             // reset backoff and first-connect flag on successful connection
             state.reconnectDelay = SSE_INITIAL_RECONNECT_MS;
@@ -745,6 +867,9 @@
         source.onmessage = function(e) {
             try {
                 var event = JSON.parse(e.data);
+                if (state.replay.active && e.lastEventId) {
+                    state.replay.position = parseInt(e.lastEventId, 10);
+                }
                 if (state.resetOnNextEvent) {
                     resetOutputState();
                     state.resetOnNextEvent = false;
@@ -1006,6 +1131,7 @@
             .then(function(sessions) {
                 state.sessions = sessions;
                 renderSessionList(sessions);
+                updateReplayControls();
                 // auto-select first session if none is currently selected
                 if (!state.currentSessionId && sessions.length > 0) {
                     selectSession(sessions[0].id);
@@ -1211,6 +1337,16 @@
         topRow.appendChild(indicator);
         topRow.appendChild(name);
 
+        // final run outcome badge for completed sessions
+        if (session.state !== 'active' && OUTCOME_LABELS[session.outcome]) {
+            var outcome = document.createElement('span');
+            outcome.className = 'session-outcome ' + session.outcome;
+            outcome.textContent = OUTCOME_LABELS[session.outcome];
+            var details = [session.outcomePhase ? 'phase: ' + session.outcomePhase : '', session.outcomeError || ''];
+            outcome.title = details.filter(Boolean).join('\n');
+            topRow.appendChild(outcome);
+        }
+
         var timeSpan = document.createElement('span');
         timeSpan.className = 'session-time session-time-top';
         timeSpan.textContent = formatRelativeTime(session.lastModified);
@@ -1280,13 +1416,7 @@
         });
 
         // find session data
-        var session = null;
-        for (var i = 0; i < state.sessions.length; i++) {
-            if (state.sessions[i].id === sessionId) {
-                session = state.sessions[i];
-                break;
-            }
-        }
+        var session = findSession(sessionId);
 
         // update header info
         if (session) {
@@ -1346,6 +1476,7 @@
 
         // reset output state
         resetOutputState();
+        resetReplayState();
         state.isFirstConnect = true;
         state.reconnectDelay = SSE_INITIAL_RECONNECT_MS;
         state.pendingScrollRestore = true; // restore scroll position after events load
@@ -1354,6 +1485,272 @@
         connect();
     }
 
+    // find session data by id
+    function findSession(sessionId) {
+        for (var i = 0; i < state.sessions.length; i++) {
+            if (state.sessions[i].id === sessionId) {
+                return state.sessions[i];
+            }
+        }
+        return null;
+    }
+
+    // replay controls are shown for completed sessions
+    function updateReplayControls() {
+        if (!replayControls) return;
+        var session = findSession(state.currentSessionId);
+        var canReplay = !!session && session.state !== 'active';
+        replayControls.classList.toggle('is-hidden', !canReplay);
+        replayControls.classList.toggle('replaying', state.replay.active);
+        if (!state.replay.active || state.replay.finished) {
+            replayToggle.textContent = 'Replay';
+        } else {
+            replayToggle.textContent = state.replay.paused ? 'Resume' : 'Pause';
+        }
+        replaySection.disabled = !state.replay.active;
+        if (replayExit) {
+            replayExit.classList.toggle('is-hidden', !state.replay.active);
+        }
+    }
+
+    // fill the seek select with the replay's sections
+    function renderReplaySections() {
+        clearElement(replaySection);
+        var placeholder = document.createElement('option');
+        placeholder.value = '';
+        placeholder.textContent = 'Seek to section';
+        replaySection.appendChild(placeholder);
+        state.replay.sections.forEach(function(section, i) {
+            var opt = document.createElement('option');
+            opt.value = String(i);
+            opt.textContent = section.title;
+            replaySection.appendChild(opt);
+        });
+    }
+
+    function resetReplayState() {
+        state.replay.active = false;
+        state.replay.paused = false;
+        state.replay.finished = false;
+        state.replay.position = 0;
+        state.replay.sections = [];
+        if (replaySection) {
+            clearElement(replaySection);
+        }
+        updateReplayControls();
+    }
+
+    // (re)open the replay stream at position, rebuilding the output from the start
+    function playReplay(position) {
+        if (state.currentEventSource) {
+            state.currentEventSource.close();
+            state.currentEventSource = null;
+        }
+        resetOutputState();
+        state.replay.active = true;
+        state.replay.paused = false;
+        state.replay.finished = false;
+        state.replay.position = position;
+        state.isFirstConnect = true;
+        state.reconnectDelay = SSE_INITIAL_RECONNECT_MS;
+        state.autoScroll = true;
+        updateReplayControls();
+        connect();
+    }
+
+    // play, pause or resume the replay of the current session
+    function toggleReplay() {
+        if (!replayControls || replayControls.classList.contains('is-hidden')) return;
+        if (!state.replay.active || state.replay.finished) {
+            playReplay(0);
+            return;
+        }
+        if (state.replay.paused) {
+            playReplay(state.replay.position);
+            return;
+        }
+        // pause by closing the stream, resume reconnects from the last received event
+        if (state.currentEventSource) {
+            state.currentEventSource.close();
+            state.currentEventSource = null;
+        }
+        state.replay.paused = true;
+        updateReplayControls();
+    }
+
+    // leave replay and show the whole session again
+    function exitReplay() {
+        if (!state.replay.active) return;
+        reconnectToSession(state.currentSessionId);
+    }
+
+    // fetch and show the diff of a run iteration
+    function openDiff(iteration) {
+        if (!diffOverlay) return;
+        var sessionId = state.currentSessionId || 'main';
+        state.diff = null;
+        diffTitle.textContent = 'Loading diff...';
+        clearElement(diffContent);
+        diffOverlay.classList.add('visible');
+
+        fetch('/api/sessions/' + encodeURIComponent(sessionId) + '/diff?iteration=' + iteration)
+            .then(function(response) {
+                if (!response.ok) {
+                    return response.text().then(function(text) {
+                        throw new Error(text.trim() || response.statusText);
+                    });
+                }
+                return response.json();
+            })
+            .then(function(data) {
+                state.diff = data;
+                diffTitle.textContent = data.label + ' ' + shortHash(data.from) + '..' + shortHash(data.to);
+                renderDiff();
+            })
+            .catch(function(err) {
+                diffTitle.textContent = 'Diff unavailable';
+                clearElement(diffContent);
+                diffContent.appendChild(createPlanMessage(err.message));
+            });
+    }
+
+    function closeDiff() { if (diffOverlay) diffOverlay.classList.remove('visible'); }
+    function isDiffVisible() { return diffOverlay && diffOverlay.classList.contains('visible'); }
+
+    function setDiffView(view) {
+        state.diffView = view;
+        localStorage.setItem('diffView', view);
+        renderDiff();
+    }
+
+    // parse a unified diff into files with hunks of numbered lines
+    function parseDiff(text) {
+        var files = [];
+        var file = null;
+        var hunk = null;
+        var oldNo = 0;
+        var newNo = 0;
+        (text || '').split('\n').forEach(function(raw) {
+            if (raw.indexOf('diff --git ') === 0) {
+                file = { name: raw.replace(/^diff --git a\/(.*) b\/.*$/, '$1'), binary: false, hunks: [] };
+                files.push(file);
+                hunk = null;
+                return;
+            }
+            if (!file) return;
+            var m = raw.match(/^@@ -(\d+)(?:,\d+)? \+(\d+)(?:,\d+)? @@/);
+            if (m) {
+                oldNo = parseInt(m[1], 10);
+                newNo = parseInt(m[2], 10);
+                hunk = { header: raw, lines: [] };
+                file.hunks.push(hunk);
+                return;
+            }
+            if (!hunk) {
+                // file header lines before the first hunk
+                if (raw.indexOf('+++ b/') === 0) file.name = raw.substring(6);
+                if (raw.indexOf('Binary files') === 0) file.binary = true;
+                return;
+            }
+            var op = raw.charAt(0);
+            var content = raw.substring(1);
+            if (op === '+') {
+                hunk.lines.push({ op: 'add', newNo: newNo++, text: content });
+            } else if (op === '-') {
+                hunk.lines.push({ op: 'del', oldNo: oldNo++, text: content });
+            } else if (op === ' ') {
+                hunk.lines.push({ op: 'ctx', oldNo: oldNo++, newNo: newNo++, text: content });
+            }
+        });
+        return files;
+    }
+
+    function createDiffCell(className, text) {
+        var td = document.createElement('td');
+        td.className = className;
+        td.textContent = text === undefined ? '' : String(text);
+        return td;
+    }
+
+    // create a table row from [className, text] cell pairs
+    function createDiffRow(rowClass, cells) {
+        var tr = document.createElement('tr');
+        tr.className = rowClass;
+        cells.forEach(function(c) { tr.appendChild(createDiffCell(c[0], c[1])); });
+        return tr;
+    }
+
+    function appendUnifiedHunk(table, hunk) {
+        table.appendChild(createDiffRow('diff-hunk', [['diff-num', ''], ['diff-num', ''], ['diff-code', hunk.header]]));
+        hunk.lines.forEach(function(l) {
+            var sign = l.op === 'add' ? '+' : (l.op === 'del' ? '-' : ' ');
+            table.appendChild(createDiffRow('diff-' + l.op, [['diff-num', l.oldNo], ['diff-num', l.newNo], ['diff-code', sign + l.text]]));
+        });
+    }
+
+    // split view pairs each run of deleted lines with the following run of added lines
+    function appendSplitHunk(table, hunk) {
+        table.appendChild(createDiffRow('diff-hunk', [['diff-num', ''], ['diff-code', hunk.header], ['diff-num', ''], ['diff-code', '']]));
+        var i = 0;
+        var lines = hunk.lines;
+        while (i < lines.length) {
+            if (lines[i].op === 'ctx') {
+                var c = lines[i++];
+                table.appendChild(createDiffRow('diff-ctx', [['diff-num', c.oldNo], ['diff-code', c.text], ['diff-num', c.newNo], ['diff-code', c.text]]));
+                continue;
+            }
+            var dels = [];
+            var adds = [];
+            while (i < lines.length && lines[i].op === 'del') dels.push(lines[i++]);
+            while (i < lines.length && lines[i].op === 'add') adds.push(lines[i++]);
+            for (var j = 0; j < Math.max(dels.length, adds.length); j++) {
+                var d = dels[j];
+                var a = adds[j];
+                table.appendChild(createDiffRow('diff-change', [
+                    ['diff-num', d ? d.oldNo : ''], ['diff-code' + (d ? ' diff-del' : ' diff-empty'), d ? d.text : ''],
+                    ['diff-num', a ? a.newNo : ''], ['diff-code' + (a ? ' diff-add' : ' diff-empty'), a ? a.text : '']
+                ]));
+            }
+        }
+    }
+
+    // render the loaded diff in the current view mode
+    function renderDiff() {
+        if (!diffOverlay) return;
+        diffOverlay.querySelectorAll('.diff-view-btn').forEach(function(btn) {
+            btn.classList.toggle('active', btn.dataset.view === state.diffView);
+        });
+        if (!state.diff) return;
+        clearElement(diffContent);
+        var files = parseDiff(state.diff.diff);
+        if (files.length === 0) {
+            diffContent.appendChild(createPlanMessage('No changes'));
+            return;
+        }
+        files.forEach(function(file) {
+            var fileEl = document.createElement('div');
+            fileEl.className = 'diff-file';
+            var name = document.createElement('div');
+            name.className = 'diff-file-name';
+            name.textContent = file.name;
+            fileEl.appendChild(name);
+            if (file.binary) {
+                fileEl.appendChild(createPlanMessage('Binary file changed'));
+            }
+            var table = document.createElement('table');
+            table.className = 'diff-table ' + state.diffView;
+            file.hunks.forEach(function(hunk) {
+                if (state.diffView === 'split') {
+                    appendSplitHunk(table, hunk);
+                } else {
+                    appendUnifiedHunk(table, hunk);
+                }
+            });
+            fileEl.appendChild(table);
+            diffContent.appendChild(fileEl);
+        });
+    }
+
     // fetch plan for a specific session
     function fetchPlanForSession(sessionId) {
         var url = '/api/plan';
@@ -1572,20 +1969,24 @@
             return;
         }
 
-        // Escape closes help, or clears search
+        // Escape closes help or the diff, or clears search
         if (e.key === 'Escape') {
             if (isHelpVisible()) {
                 hideHelp();
                 return;
             }
+            if (isDiffVisible()) {
+                closeDiff();
+                return;
+            }
             searchInput.value = '';
             searchInput.blur();
             handleSearch();
             return;
         }
 
-        // ignore other shortcuts when help is visible
-        if (isHelpVisible()) return;
+        // ignore other shortcuts when help or the diff is visible
+        if (isHelpVisible() || isDiffVisible()) return;
 
         // '/' focuses search (unless already in input)
         if (e.key === '/' && document.activeElement !== searchInput) {
@@ -1640,6 +2041,12 @@
             collapseAllSections();
         }
 
+        // 'r' plays, pauses or resumes the replay of a completed session (unless in input)
+        if (e.key === 'r' && document.activeElement !== searchInput) {
+            e.preventDefault();
+            toggleReplay();
+        }
+
         // Space or Enter toggles focused section expand/collapse (unless in input)
         if ((e.key === ' ' || e.key === 'Enter') && document.activeElement !== searchInput) {
             if (state.focusedSectionIndex >= 0) {
@@ -1883,6 +2290,40 @@
     expandAllBtn.addEventListener('click', expandAllSections);
     collapseAllBtn.addEventListener('click', collapseAllSections);
 
+    // diff overlay handlers (with null checks for SSR/test environments)
+    if (diffOverlay) {
+        diffCloseBtn.addEventListener('click', closeDiff);
+        diffOverlay.addEventListener('click', function(e) {
+            if (e.target === diffOverlay) {
+                closeDiff();
+            }
+        });
+        diffOverlay.querySelectorAll('.diff-view-btn').forEach(function(btn) {
+            btn.addEventListener('click', function() {
+                setDiffView(btn.dataset.view);
+            });
+        });
+    }
+
+    // replay control handlers (with null checks for SSR/test environments)
+    if (replayControls) {
+        replayToggle.addEventListener('click', toggleReplay);
+        replayExit.addEventListener('click', exitReplay);
+        // a new speed applies from the current position
+        replaySpeed.addEventListener('change', function() {
+            if (state.replay.active && !state.replay.paused && !state.replay.finished) {
+                playReplay(state.replay.position);
+            }
+        });
+        // seeking replays earlier events at once and continues with timing from the section
+        replaySection.addEventListener('change', function() {
+            var section = state.replay.sections[parseInt(replaySection.value, 10)];
+            if (section) {
+                playReplay(section.index);
+            }
+        });
+    }
+
     // help modal handlers (with null checks for SSR/test environments)
     if (helpBtn) {
         helpBtn.addEventListener('click', showHelp);
@@ -11,7 +11,9 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/umputun/ralphex/pkg/executor"
 	"github.com/umputun/ralphex/pkg/processor"
+	"github.com/umputun/ralphex/pkg/progress"
 )
 
 // TailerConfig holds configuration for the Tailer.
@@ -29,7 +31,8 @@ func DefaultTailerConfig() TailerConfig {
 }
 
 // Tailer watches a progress file and emits events for new lines.
-// it parses progress file format (timestamps, sections) into Event structs.
+// it reads the progress event file (progress-*.jsonl) when present and falls back to
+// parsing the text format (timestamps, sections) for logs written by older versions.
 type Tailer struct {
 	mu       sync.Mutex
 	path     string
@@ -43,7 +46,8 @@ type Tailer struct {
 	doneCh   chan struct{}
 	eventCh  chan Event
 	phase    processor.Phase
-	inHeader bool // true until we pass the header separator
+	inHeader bool             // true until we pass the header separator
+	records  *recordConverter // set when tailing the progress event file
 }
 
 // NewTailer creates a new Tailer for the given progress file.
@@ -84,7 +88,13 @@ func (t *Tailer) Start(fromStart bool) error {
 		return nil
 	}
 
-	f, err := os.Open(t.path)
+	path := t.path
+	if hasEventsFile(t.path) {
+		path = progress.EventsPath(t.path)
+		t.records = newRecordConverter(t.phase)
+	}
+
+	f, err := os.Open(path)
 	if err != nil {
 		return fmt.Errorf("open file: %w", err)
 	}
@@ -208,15 +218,30 @@ func (t *Tailer) readNewLines() {
 			continue
 		}
 
-		// parse line and emit event
-		event := t.parseLine(line)
-		if event != nil {
-			select {
-			case t.eventCh <- *event:
-			default:
-				// channel full, drop event
+		if t.records != nil {
+			rec, err := progress.ParseRecord([]byte(line))
+			if err != nil {
+				continue
+			}
+			for _, event := range t.records.events(rec) {
+				t.emit(event)
 			}
+			continue
 		}
+
+		// parse line and emit event
+		if event := t.parseLine(line); event != nil {
+			t.emit(*event)
+		}
+	}
+}
+
+// emit sends event to the events channel, dropping it if the channel is full.
+func (t *Tailer) emit(event Event) {
+	select {
+	case t.eventCh <- event:
+	default:
+		// channel full, drop event
 	}
 }
 
@@ -229,6 +254,14 @@ var sectionRegex = regexp.MustCompile(`^--- (.+) ---$`)
 // task iteration regex: task iteration N (extracts the number)
 var taskIterationRegex = regexp.MustCompile(`(?i)^task iteration (\d+)$`)
 
+// prefixes of typed lines written by progress.Logger.
+const (
+	activityPrefix = "ACTIVITY: " // tool-use lines from LogActivity
+	stallPrefix    = "STALL: "    // killed stalled processes from LogStall
+	usagePrefix    = "USAGE: "    // token usage of claude runs from LogUsage
+	commitsPrefix  = "COMMITS: "  // commit ranges of iterations from LogCommits
+)
+
 // parseLine parses a progress file line and returns an Event.
 // returns nil for lines that should be skipped (header lines).
 func (t *Tailer) parseLine(line string) *Event {
@@ -280,6 +313,8 @@ func (t *Tailer) parseLine(line string) *Event {
 			event.Signal = sig
 			event.Type = EventTypeSignal
 		}
+		applyActivity(&event)
+		applyCommits(&event)
 
 		return &event
 	}
@@ -312,10 +347,47 @@ func detectEventType(text string) EventType {
 	if extractSignalFromText(text) != "" {
 		return EventTypeSignal
 	}
+	if strings.HasPrefix(text, stallPrefix) {
+		return EventTypeStall
+	}
+	if strings.HasPrefix(text, usagePrefix) {
+		return EventTypeUsage
+	}
 
 	return EventTypeOutput
 }
 
+// applyCommits turns a "COMMITS: iteration <n> (<label>): <from>..<to>" progress line into a commits event.
+// other events are left unchanged.
+func applyCommits(event *Event) {
+	text, ok := strings.CutPrefix(event.Text, commitsPrefix)
+	if !ok {
+		return
+	}
+	commits, ok := processor.ParseIterationCommits(text)
+	if !ok {
+		return
+	}
+	ts := event.Timestamp
+	*event = NewCommitsEvent(event.Phase, commits)
+	event.Timestamp = ts
+}
+
+// applyActivity turns an "ACTIVITY: <tool> <detail>" progress line into an activity event.
+// other events are left unchanged.
+func applyActivity(event *Event) {
+	text, ok := strings.CutPrefix(event.Text, activityPrefix)
+	if !ok {
+		return
+	}
+	activity := executor.ParseActivity(text)
+	event.Type = EventTypeActivity
+	event.Text = activity.String()
+	event.Tool = activity.Tool
+	event.File = activity.File()
+	event.Modifies = activity.Modifies()
+}
+
 // extractSignalFromText extracts normalized signal name from <<<RALPHEX:SIGNAL>>> format
 // or plain signal markers like ALL_TASKS_DONE, TASK_FAILED, REVIEW_DONE.
 // returns "COMPLETED" for ALL_TASKS_DONE, "FAILED" for TASK_FAILED, or raw signal for unknown tokens.
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/umputun/ralphex/pkg/processor"
+	"github.com/umputun/ralphex/pkg/progress"
 )
 
 func TestNewTailer(t *testing.T) {
@@ -50,6 +51,48 @@ func TestTailer_ParseLine(t *testing.T) {
 		assert.Equal(t, 22, event.Timestamp.Day())
 	})
 
+	t.Run("parses activity line", func(t *testing.T) {
+		event := tailer.parseLine("[26-01-22 10:30:46] ACTIVITY: Edit pkg/foo.go")
+
+		require.NotNil(t, event)
+		assert.Equal(t, EventTypeActivity, event.Type)
+		assert.Equal(t, "Edit pkg/foo.go", event.Text)
+		assert.Equal(t, "Edit", event.Tool)
+		assert.Equal(t, "pkg/foo.go", event.File)
+		assert.True(t, event.Modifies)
+
+		event = tailer.parseLine("[26-01-22 10:30:47] ACTIVITY: Bash: go test ./...")
+		require.NotNil(t, event)
+		assert.Equal(t, EventTypeActivity, event.Type)
+		assert.Equal(t, "Bash", event.Tool)
+		assert.Equal(t, "Bash: go test ./...", event.Text)
+		assert.Empty(t, event.File)
+		assert.False(t, event.Modifies)
+	})
+
+	t.Run("parses commits line", func(t *testing.T) {
+		event := tailer.parseLine("[26-01-22 10:30:46] COMMITS: iteration 2 (task iteration 2): 1a2b3c4..4d5e6f7")
+
+		require.NotNil(t, event)
+		assert.Equal(t, EventTypeCommits, event.Type)
+		assert.Equal(t, 2, event.IterationNum)
+		assert.Equal(t, "1a2b3c4", event.FromCommit)
+		assert.Equal(t, "4d5e6f7", event.ToCommit)
+		assert.Equal(t, 30, event.Timestamp.Minute())
+
+		event = tailer.parseLine("[26-01-22 10:30:47] COMMITS: not a range")
+		require.NotNil(t, event)
+		assert.Equal(t, EventTypeOutput, event.Type)
+	})
+
+	t.Run("parses stall line", func(t *testing.T) {
+		event := tailer.parseLine("[26-01-22 10:30:46] STALL: no output for 30m0s, process killed")
+
+		require.NotNil(t, event)
+		assert.Equal(t, EventTypeStall, event.Type)
+		assert.Equal(t, "STALL: no output for 30m0s, process killed", event.Text)
+	})
+
 	t.Run("parses section header", func(t *testing.T) {
 		event := tailer.parseLine("--- task iteration 1 ---")
 
@@ -325,6 +368,44 @@ Started: 2026-01-22 10:30:00
 	})
 }
 
+func TestTailer_EventsFile(t *testing.T) {
+	progressFile := filepath.Join(t.TempDir(), "progress-test.txt")
+	writeRecords(t, progressFile,
+		progress.Record{Type: progress.RecordHeader, Plan: "docs/plan.md"},
+		progress.Record{Type: progress.RecordSection, Text: "task iteration 1", Section: "task", Iteration: 1},
+	)
+
+	tailer := NewTailer(progressFile, TailerConfig{PollInterval: 10 * time.Millisecond})
+	require.NoError(t, tailer.Start(true))
+	defer tailer.Stop()
+
+	next := func() Event {
+		select {
+		case event := <-tailer.Events():
+			return event
+		case <-time.After(time.Second):
+			t.Fatal("expected event")
+			return Event{}
+		}
+	}
+	assert.Equal(t, EventTypeTaskStart, next().Type, "events come from the event file, not the text log")
+	assert.Equal(t, EventTypeSection, next().Type)
+
+	// a record written in two parts is emitted once complete
+	f, err := os.OpenFile(progress.EventsPath(progressFile), os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.WriteString(`{"ts":"2026-01-22T10:00:00Z","type":"activ`)
+	require.NoError(t, err)
+	time.Sleep(50 * time.Millisecond)
+	_, err = f.WriteString(`ity","tool":"Bash","detail":"go test ./..."}` + "\n")
+	require.NoError(t, err)
+
+	event := next()
+	assert.Equal(t, EventTypeActivity, event.Type)
+	assert.Equal(t, "Bash: go test ./...", event.Text)
+}
+
 func TestTailer_Stop(t *testing.T) {
 	t.Run("stop before start is safe", func(t *testing.T) {
 		tailer := NewTailer("/nonexistent", DefaultTailerConfig())